@@ -20,16 +20,19 @@ import (
 	"k8s.io/client-go/rest"
 
 	"github.com/kubevirt/vm-import-operator/pkg/apis"
+	v2vv1alpha1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1alpha1"
 	"github.com/kubevirt/vm-import-operator/pkg/controller"
 
 	netv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	"github.com/kubevirt/vm-import-operator/pkg/metrics"
+	"github.com/kubevirt/vm-import-operator/pkg/webhook"
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
 	kubemetrics "github.com/operator-framework/operator-sdk/pkg/kube-metrics"
 	"github.com/operator-framework/operator-sdk/pkg/leader"
 	"github.com/operator-framework/operator-sdk/pkg/log/zap"
 	sdkVersion "github.com/operator-framework/operator-sdk/version"
 	"github.com/spf13/pflag"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kubevirtv1 "kubevirt.io/client-go/api/v1"
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
@@ -97,6 +100,8 @@ func main() {
 	mgr, err := manager.New(cfg, manager.Options{
 		Namespace:          namespace,
 		MetricsBindAddress: fmt.Sprintf("%s:%d", metrics.MetricsHost, metrics.MetricsPort),
+		Port:               webhook.Port,
+		CertDir:            webhook.CertDir,
 	})
 	if err != nil {
 		log.Error(err, "")
@@ -111,6 +116,18 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Setup Scheme for the former version of the resources, converted by the webhook
+	if err := v2vv1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
+	// Setup Scheme for custom resource definitions, whose conversion webhook is configured by the controller
+	if err := extv1.AddToScheme(mgr.GetScheme()); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
 	// Setup Scheme for kubevirt resources
 	if err := kubevirtv1.AddToScheme(mgr.GetScheme()); err != nil {
 		log.Error(err, "")
@@ -148,6 +165,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Setup the validating webhooks
+	if err := addWebhooks(mgr); err != nil {
+		log.Error(err, "")
+		if os.Getenv("DEV_LOCAL_DEBUG") == "" {
+			os.Exit(1)
+		}
+	}
+
 	if err = serveCRMetrics(cfg); err != nil {
 		log.Info("Could not generate and serve custom resource metrics", "error", err.Error())
 	}
@@ -161,6 +186,12 @@ func main() {
 	}
 }
 
+// addWebhooks registers the validating webhooks, served with the certificate the operator mounts in the certificate
+// directory
+func addWebhooks(mgr manager.Manager) error {
+	return webhook.AddToManager(mgr)
+}
+
 // serveCRMetrics gets the Operator/CustomResource GVKs and generates metrics based on those types.
 // It serves those metrics on "http://metricsHost:operatorMetricsPort".
 func serveCRMetrics(cfg *rest.Config) error {
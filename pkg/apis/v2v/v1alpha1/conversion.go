@@ -0,0 +1,422 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	"github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConversionDataAnnotation holds the fields of the v1beta1 spec and status of a resource read as v1alpha1 that can't
+// be represented in v1alpha1, so that they are restored when the resource is written back
+const ConversionDataAnnotation = "vmimport.v2v.kubevirt.io/conversion-data"
+
+// ConvertTo converts the VirtualMachineImport to v1beta1, restoring the fields that only exist in v1beta1
+func (src *VirtualMachineImport) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.VirtualMachineImport)
+
+	restored := &v1beta1.VirtualMachineImport{}
+	found, err := unmarshalConversionData(&src.ObjectMeta, restored)
+	if err != nil {
+		return err
+	}
+	if found {
+		dst.Spec = restored.Spec
+		dst.Status = restored.Status
+	}
+	convertObjectMetaTo(&src.ObjectMeta, &dst.ObjectMeta)
+
+	// imports of a Provider don't have credentials in v1alpha1
+	if src.Spec.ProviderCredentialsSecret.Name != "" || dst.Spec.Provider == nil {
+		dst.Spec.ProviderCredentialsSecret = convertObjectIdentifierTo(&src.Spec.ProviderCredentialsSecret)
+	}
+	dst.Spec.ResourceMapping = nil
+	if src.Spec.ResourceMapping != nil {
+		dst.Spec.ResourceMapping = convertObjectIdentifierTo(src.Spec.ResourceMapping)
+	}
+	if src.Spec.Source.Ovirt != nil {
+		if dst.Spec.Source.Ovirt == nil {
+			dst.Spec.Source.Ovirt = &v1beta1.VirtualMachineImportOvirtSourceSpec{}
+		}
+		convertOvirtSourceTo(src.Spec.Source.Ovirt, dst.Spec.Source.Ovirt)
+	} else {
+		dst.Spec.Source.Ovirt = nil
+	}
+	dst.Spec.TargetVMName = copyString(src.Spec.TargetVMName)
+	dst.Spec.StartVM = nil
+	if src.Spec.StartVM != nil {
+		startVM := *src.Spec.StartVM
+		dst.Spec.StartVM = &startVM
+	}
+
+	dst.Status.TargetVMName = src.Status.TargetVMName
+	dst.Status.Conditions = nil
+	for _, condition := range src.Status.Conditions {
+		dst.Status.Conditions = append(dst.Status.Conditions, v1beta1.VirtualMachineImportCondition{
+			Type:               v1beta1.VirtualMachineImportConditionType(condition.Type),
+			Status:             condition.Status,
+			Reason:             copyString(condition.Reason),
+			Message:            copyString(condition.Message),
+			LastHeartbeatTime:  copyTime(condition.LastHeartbeatTime),
+			LastTransitionTime: copyTime(condition.LastTransitionTime),
+		})
+	}
+	restoredDVs := dst.Status.DataVolumes
+	dst.Status.DataVolumes = nil
+	for _, dv := range src.Status.DataVolumes {
+		dst.Status.DataVolumes = append(dst.Status.DataVolumes, convertDataVolumeItemTo(dv, restoredDVs))
+	}
+	return nil
+}
+
+// ConvertFrom converts the VirtualMachineImport from v1beta1, keeping the fields that don't exist in v1alpha1 in the
+// conversion data annotation
+func (dst *VirtualMachineImport) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.VirtualMachineImport)
+
+	convertObjectMetaFrom(&src.ObjectMeta, &dst.ObjectMeta)
+
+	dst.Spec = VirtualMachineImportSpec{}
+	if src.Spec.ProviderCredentialsSecret != nil {
+		dst.Spec.ProviderCredentialsSecret = *convertObjectIdentifierFrom(src.Spec.ProviderCredentialsSecret)
+	}
+	if src.Spec.ResourceMapping != nil {
+		dst.Spec.ResourceMapping = convertObjectIdentifierFrom(src.Spec.ResourceMapping)
+	}
+	if src.Spec.Source.Ovirt != nil {
+		dst.Spec.Source.Ovirt = convertOvirtSourceFrom(src.Spec.Source.Ovirt)
+	}
+	dst.Spec.TargetVMName = copyString(src.Spec.TargetVMName)
+	if src.Spec.StartVM != nil {
+		startVM := *src.Spec.StartVM
+		dst.Spec.StartVM = &startVM
+	}
+
+	dst.Status = VirtualMachineImportStatus{TargetVMName: src.Status.TargetVMName}
+	for _, condition := range src.Status.Conditions {
+		dst.Status.Conditions = append(dst.Status.Conditions, VirtualMachineImportCondition{
+			Type:               VirtualMachineImportConditionType(condition.Type),
+			Status:             condition.Status,
+			Reason:             copyString(condition.Reason),
+			Message:            copyString(condition.Message),
+			LastHeartbeatTime:  copyTime(condition.LastHeartbeatTime),
+			LastTransitionTime: copyTime(condition.LastTransitionTime),
+		})
+	}
+	for _, dv := range src.Status.DataVolumes {
+		dst.Status.DataVolumes = append(dst.Status.DataVolumes, DataVolumeItem{Name: dv.Name})
+	}
+
+	converted := &v1beta1.VirtualMachineImport{}
+	err := dst.ConvertTo(converted)
+	if err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(converted.Spec, src.Spec) && equality.Semantic.DeepEqual(converted.Status, src.Status) {
+		return nil
+	}
+	return marshalConversionData(&dst.ObjectMeta, virtualMachineImportConversionData(src))
+}
+
+// virtualMachineImportConversionData returns the spec and the status of the import without the fields v1alpha1
+// represents. The sources of the storage mappings and the names of the data volumes are kept to match the items
+// again.
+func virtualMachineImportConversionData(src *v1beta1.VirtualMachineImport) *v1beta1.VirtualMachineImport {
+	data := &v1beta1.VirtualMachineImport{Spec: *src.Spec.DeepCopy(), Status: *src.Status.DeepCopy()}
+	data.Spec.ProviderCredentialsSecret = nil
+	data.Spec.ResourceMapping = nil
+	if ovirt := data.Spec.Source.Ovirt; ovirt != nil {
+		ovirt.VM = v1beta1.VirtualMachineImportOvirtSourceVMSpec{}
+		ovirt.Mappings = ovirtMappingsConversionData(ovirt.Mappings)
+		if equality.Semantic.DeepEqual(*ovirt, v1beta1.VirtualMachineImportOvirtSourceSpec{}) {
+			data.Spec.Source.Ovirt = nil
+		}
+	}
+	data.Spec.TargetVMName = nil
+	data.Spec.StartVM = nil
+
+	data.Status.TargetVMName = ""
+	data.Status.Conditions = nil
+	var dvs []v1beta1.DataVolumeItem
+	for _, dv := range data.Status.DataVolumes {
+		if !equality.Semantic.DeepEqual(dv, v1beta1.DataVolumeItem{Name: dv.Name}) {
+			dvs = append(dvs, dv)
+		}
+	}
+	data.Status.DataVolumes = dvs
+	return data
+}
+
+// ConvertTo converts the ResourceMapping to v1beta1, restoring the mappings that only exist in v1beta1
+func (src *ResourceMapping) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.ResourceMapping)
+
+	restored := &v1beta1.ResourceMapping{}
+	found, err := unmarshalConversionData(&src.ObjectMeta, restored)
+	if err != nil {
+		return err
+	}
+	if found {
+		dst.Spec = restored.Spec
+	}
+	convertObjectMetaTo(&src.ObjectMeta, &dst.ObjectMeta)
+
+	if src.Spec.OvirtMappings != nil {
+		if dst.Spec.OvirtMappings == nil {
+			dst.Spec.OvirtMappings = &v1beta1.OvirtMappings{}
+		}
+		convertOvirtMappingsTo(src.Spec.OvirtMappings, dst.Spec.OvirtMappings)
+	} else {
+		dst.Spec.OvirtMappings = nil
+	}
+	return nil
+}
+
+// ConvertFrom converts the ResourceMapping from v1beta1, keeping the mappings that don't exist in v1alpha1 in the
+// conversion data annotation
+func (dst *ResourceMapping) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.ResourceMapping)
+
+	convertObjectMetaFrom(&src.ObjectMeta, &dst.ObjectMeta)
+
+	dst.Spec = ResourceMappingSpec{}
+	if src.Spec.OvirtMappings != nil {
+		dst.Spec.OvirtMappings = convertOvirtMappingsFrom(src.Spec.OvirtMappings)
+	}
+
+	converted := &v1beta1.ResourceMapping{}
+	err := dst.ConvertTo(converted)
+	if err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(converted.Spec, src.Spec) {
+		return nil
+	}
+	data := &v1beta1.ResourceMapping{Spec: *src.Spec.DeepCopy()}
+	data.Spec.OvirtMappings = ovirtMappingsConversionData(data.Spec.OvirtMappings)
+	return marshalConversionData(&dst.ObjectMeta, data)
+}
+
+// ovirtMappingsConversionData returns the mappings without the fields v1alpha1 represents, or nil when nothing is left
+func ovirtMappingsConversionData(mappings *v1beta1.OvirtMappings) *v1beta1.OvirtMappings {
+	if mappings == nil {
+		return nil
+	}
+	mappings.NetworkMappings = nil
+	mappings.StorageMappings = storageMappingsConversionData(mappings.StorageMappings)
+	mappings.DiskMappings = storageMappingsConversionData(mappings.DiskMappings)
+	if equality.Semantic.DeepEqual(*mappings, v1beta1.OvirtMappings{}) {
+		return nil
+	}
+	return mappings
+}
+
+// storageMappingsConversionData returns the storage mappings without their targets, or nil when the mappings have
+// nothing else than their source and target. The sources are kept to match the mappings again.
+func storageMappingsConversionData(items *[]v1beta1.StorageResourceMappingItem) *[]v1beta1.StorageResourceMappingItem {
+	if items == nil {
+		return nil
+	}
+	needed := false
+	for i := range *items {
+		(*items)[i].Target = v1beta1.ObjectIdentifier{}
+		needed = needed || !equality.Semantic.DeepEqual((*items)[i], v1beta1.StorageResourceMappingItem{Source: (*items)[i].Source})
+	}
+	if !needed {
+		return nil
+	}
+	return items
+}
+
+func convertOvirtSourceTo(src *VirtualMachineImportOvirtSourceSpec, dst *v1beta1.VirtualMachineImportOvirtSourceSpec) {
+	dst.VM = v1beta1.VirtualMachineImportOvirtSourceVMSpec{
+		ID:   copyString(src.VM.ID),
+		Name: copyString(src.VM.Name),
+	}
+	if src.VM.Cluster != nil {
+		dst.VM.Cluster = &v1beta1.VirtualMachineImportOvirtSourceVMClusterSpec{
+			ID:   copyString(src.VM.Cluster.ID),
+			Name: copyString(src.VM.Cluster.Name),
+		}
+	}
+	if src.Mappings != nil {
+		if dst.Mappings == nil {
+			dst.Mappings = &v1beta1.OvirtMappings{}
+		}
+		convertOvirtMappingsTo(src.Mappings, dst.Mappings)
+	} else {
+		dst.Mappings = nil
+	}
+}
+
+func convertOvirtSourceFrom(src *v1beta1.VirtualMachineImportOvirtSourceSpec) *VirtualMachineImportOvirtSourceSpec {
+	dst := &VirtualMachineImportOvirtSourceSpec{
+		VM: VirtualMachineImportOvirtSourceVMSpec{
+			ID:   copyString(src.VM.ID),
+			Name: copyString(src.VM.Name),
+		},
+	}
+	if src.VM.Cluster != nil {
+		dst.VM.Cluster = &VirtualMachineImportOvirtSourceVMClusterSpec{
+			ID:   copyString(src.VM.Cluster.ID),
+			Name: copyString(src.VM.Cluster.Name),
+		}
+	}
+	if src.Mappings != nil {
+		dst.Mappings = convertOvirtMappingsFrom(src.Mappings)
+	}
+	return dst
+}
+
+// convertOvirtMappingsTo converts the mappings into dst, keeping the volume and access modes of the storage mappings
+// dst already holds for the same sources
+func convertOvirtMappingsTo(src *OvirtMappings, dst *v1beta1.OvirtMappings) {
+	dst.NetworkMappings = nil
+	if src.NetworkMappings != nil {
+		items := make([]v1beta1.NetworkResourceMappingItem, 0, len(*src.NetworkMappings))
+		for _, item := range *src.NetworkMappings {
+			items = append(items, v1beta1.NetworkResourceMappingItem{
+				Source: convertSourceTo(item.Source),
+				Target: *convertObjectIdentifierTo(&item.Target),
+				Type:   copyString(item.Type),
+			})
+		}
+		dst.NetworkMappings = &items
+	}
+	dst.StorageMappings = convertStorageMappingsTo(src.StorageMappings, dst.StorageMappings)
+	dst.DiskMappings = convertStorageMappingsTo(src.DiskMappings, dst.DiskMappings)
+}
+
+func convertOvirtMappingsFrom(src *v1beta1.OvirtMappings) *OvirtMappings {
+	dst := &OvirtMappings{}
+	if src.NetworkMappings != nil {
+		items := make([]ResourceMappingItem, 0, len(*src.NetworkMappings))
+		for _, item := range *src.NetworkMappings {
+			items = append(items, ResourceMappingItem{
+				Source: convertSourceFrom(item.Source),
+				Target: *convertObjectIdentifierFrom(&item.Target),
+				Type:   copyString(item.Type),
+			})
+		}
+		dst.NetworkMappings = &items
+	}
+	dst.StorageMappings = convertStorageMappingsFrom(src.StorageMappings)
+	dst.DiskMappings = convertStorageMappingsFrom(src.DiskMappings)
+	return dst
+}
+
+// convertStorageMappingsTo converts the storage mappings, taking the v1beta1 only fields from the restored mapping at
+// the same position when it has the same source. The type of a storage mapping doesn't exist in v1beta1, it is
+// ignored.
+func convertStorageMappingsTo(src *[]ResourceMappingItem, restored *[]v1beta1.StorageResourceMappingItem) *[]v1beta1.StorageResourceMappingItem {
+	if src == nil {
+		return nil
+	}
+	items := make([]v1beta1.StorageResourceMappingItem, 0, len(*src))
+	for i, item := range *src {
+		converted := v1beta1.StorageResourceMappingItem{}
+		source := convertSourceTo(item.Source)
+		if restored != nil && i < len(*restored) && equality.Semantic.DeepEqual((*restored)[i].Source, source) {
+			(*restored)[i].DeepCopyInto(&converted)
+		}
+		converted.Source = source
+		converted.Target = *convertObjectIdentifierTo(&item.Target)
+		items = append(items, converted)
+	}
+	return &items
+}
+
+func convertStorageMappingsFrom(src *[]v1beta1.StorageResourceMappingItem) *[]ResourceMappingItem {
+	if src == nil {
+		return nil
+	}
+	items := make([]ResourceMappingItem, 0, len(*src))
+	for _, item := range *src {
+		items = append(items, ResourceMappingItem{
+			Source: convertSourceFrom(item.Source),
+			Target: *convertObjectIdentifierFrom(&item.Target),
+		})
+	}
+	return &items
+}
+
+// convertDataVolumeItemTo converts the data volume item, taking the v1beta1 only fields from the restored item of the
+// same name
+func convertDataVolumeItemTo(src DataVolumeItem, restored []v1beta1.DataVolumeItem) v1beta1.DataVolumeItem {
+	for _, dv := range restored {
+		if dv.Name == src.Name {
+			return *dv.DeepCopy()
+		}
+	}
+	return v1beta1.DataVolumeItem{Name: src.Name}
+}
+
+func convertSourceTo(src Source) v1beta1.Source {
+	return v1beta1.Source{Name: copyString(src.Name), ID: copyString(src.ID)}
+}
+
+func convertSourceFrom(src v1beta1.Source) Source {
+	return Source{Name: copyString(src.Name), ID: copyString(src.ID)}
+}
+
+func convertObjectIdentifierTo(src *ObjectIdentifier) *v1beta1.ObjectIdentifier {
+	return &v1beta1.ObjectIdentifier{Name: src.Name, Namespace: copyString(src.Namespace)}
+}
+
+func convertObjectIdentifierFrom(src *v1beta1.ObjectIdentifier) *ObjectIdentifier {
+	return &ObjectIdentifier{Name: src.Name, Namespace: copyString(src.Namespace)}
+}
+
+func convertObjectMetaTo(src *metav1.ObjectMeta, dst *metav1.ObjectMeta) {
+	src.DeepCopyInto(dst)
+	removeConversionData(dst)
+}
+
+func convertObjectMetaFrom(src *metav1.ObjectMeta, dst *metav1.ObjectMeta) {
+	src.DeepCopyInto(dst)
+	removeConversionData(dst)
+}
+
+func removeConversionData(meta *metav1.ObjectMeta) {
+	delete(meta.Annotations, ConversionDataAnnotation)
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+}
+
+func marshalConversionData(meta *metav1.ObjectMeta, obj interface{}) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = make(map[string]string)
+	}
+	meta.Annotations[ConversionDataAnnotation] = string(data)
+	return nil
+}
+
+func unmarshalConversionData(meta *metav1.ObjectMeta, obj interface{}) (bool, error) {
+	data, found := meta.Annotations[ConversionDataAnnotation]
+	if !found {
+		return false, nil
+	}
+	return true, json.Unmarshal([]byte(data), obj)
+}
+
+func copyString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+func copyTime(t *metav1.Time) *metav1.Time {
+	if t == nil {
+		return nil
+	}
+	return t.DeepCopy()
+}
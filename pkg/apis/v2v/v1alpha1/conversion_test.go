@@ -0,0 +1,214 @@
+package v1alpha1
+
+import (
+	"time"
+
+	"github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("VirtualMachineImport conversion", func() {
+	var (
+		vmName    = "myvm"
+		profileID = "123"
+		reason    = "CopyingDisks"
+		now       = metav1.NewTime(time.Now().Truncate(time.Second))
+	)
+
+	newOvirtImport := func() *VirtualMachineImport {
+		startVM := true
+		return &VirtualMachineImport{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", Labels: map[string]string{"wave": "3"}},
+			Spec: VirtualMachineImportSpec{
+				ProviderCredentialsSecret: ObjectIdentifier{Name: "credentials"},
+				ResourceMapping:           &ObjectIdentifier{Name: "mapping"},
+				Source: VirtualMachineImportSourceSpec{
+					Ovirt: &VirtualMachineImportOvirtSourceSpec{
+						VM: VirtualMachineImportOvirtSourceVMSpec{Name: &vmName},
+						Mappings: &OvirtMappings{
+							NetworkMappings: &[]ResourceMappingItem{
+								{Source: Source{ID: &profileID}, Target: ObjectIdentifier{Name: "pod"}},
+							},
+						},
+					},
+				},
+				StartVM: &startVM,
+			},
+			Status: VirtualMachineImportStatus{
+				TargetVMName: vmName,
+				Conditions: []VirtualMachineImportCondition{
+					{Type: Processing, Status: corev1.ConditionTrue, Reason: &reason, LastTransitionTime: &now},
+				},
+				DataVolumes: []DataVolumeItem{{Name: "disk-1"}},
+			},
+		}
+	}
+
+	It("should convert a v1alpha1 import to v1beta1 and back: ", func() {
+		original := newOvirtImport()
+
+		hub := &v1beta1.VirtualMachineImport{}
+		Expect(original.ConvertTo(hub)).To(Succeed())
+		converted := &VirtualMachineImport{}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+
+		Expect(hub.Spec.ProviderCredentialsSecret.Name).To(Equal("credentials"))
+		Expect(*hub.Spec.Source.Ovirt.Mappings.NetworkMappings).To(HaveLen(1))
+		Expect(hub.Status.Conditions[0].Type).To(Equal(v1beta1.Processing))
+		Expect(converted).To(Equal(original))
+		Expect(converted.Annotations).ToNot(HaveKey(ConversionDataAnnotation))
+	})
+
+	It("should keep the v1beta1 only fields of a VMware import read as v1alpha1: ", func() {
+		vmID := "42"
+		original := &v1beta1.VirtualMachineImport{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+			Spec: v1beta1.VirtualMachineImportSpec{
+				Provider: &v1beta1.ObjectIdentifier{Name: "vcenter"},
+				Source: v1beta1.VirtualMachineImportSourceSpec{
+					Vmware: &v1beta1.VirtualMachineImportVmwareSourceSpec{
+						VM: v1beta1.VirtualMachineImportVmwareSourceVMSpec{ID: &vmID},
+					},
+				},
+				Warm: true,
+			},
+			Status: v1beta1.VirtualMachineImportStatus{
+				WarmImport: v1beta1.VirtualMachineWarmImportStatus{Successes: 2},
+			},
+		}
+
+		spoke := &VirtualMachineImport{}
+		Expect(spoke.ConvertFrom(original)).To(Succeed())
+		converted := &v1beta1.VirtualMachineImport{}
+		Expect(spoke.ConvertTo(converted)).To(Succeed())
+
+		Expect(spoke.Annotations).To(HaveKey(ConversionDataAnnotation))
+		Expect(converted).To(Equal(original))
+	})
+
+	It("should only keep the fields v1alpha1 can't represent in the conversion data: ", func() {
+		domain := "nfs"
+		sparse := v1beta1.SparsePreallocation
+		original := &v1beta1.VirtualMachineImport{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+			Spec: v1beta1.VirtualMachineImportSpec{
+				ProviderCredentialsSecret: &v1beta1.ObjectIdentifier{Name: "credentials"},
+				Source: v1beta1.VirtualMachineImportSourceSpec{
+					Ovirt: &v1beta1.VirtualMachineImportOvirtSourceSpec{
+						VM: v1beta1.VirtualMachineImportOvirtSourceVMSpec{Name: &vmName},
+						Mappings: &v1beta1.OvirtMappings{
+							NetworkMappings: &[]v1beta1.NetworkResourceMappingItem{
+								{Source: v1beta1.Source{ID: &profileID}, Target: v1beta1.ObjectIdentifier{Name: "pod"}},
+							},
+							StorageMappings: &[]v1beta1.StorageResourceMappingItem{
+								{Source: v1beta1.Source{Name: &domain}, Target: v1beta1.ObjectIdentifier{Name: "fast"}, Preallocation: &sparse},
+							},
+						},
+					},
+				},
+				Snapshot: true,
+			},
+			Status: v1beta1.VirtualMachineImportStatus{
+				TargetVMName: vmName,
+				Conditions: []v1beta1.VirtualMachineImportCondition{
+					{Type: v1beta1.Processing, Status: corev1.ConditionTrue, Reason: &reason, LastTransitionTime: &now},
+				},
+				DataVolumes: []v1beta1.DataVolumeItem{{Name: "disk-1", SizeBytes: 1024}, {Name: "disk-2"}},
+			},
+		}
+
+		spoke := &VirtualMachineImport{}
+		Expect(spoke.ConvertFrom(original)).To(Succeed())
+		converted := &v1beta1.VirtualMachineImport{}
+		Expect(spoke.ConvertTo(converted)).To(Succeed())
+
+		data := spoke.Annotations[ConversionDataAnnotation]
+		Expect(data).To(ContainSubstring(`"snapshot":true`))
+		Expect(data).To(ContainSubstring(`"preallocation":"sparse"`))
+		Expect(data).To(ContainSubstring(`"sizeBytes":1024`))
+		for _, represented := range []string{"credentials", vmName, profileID, "fast", reason, "disk-2"} {
+			Expect(data).ToNot(ContainSubstring(represented))
+		}
+		Expect(converted).To(Equal(original))
+	})
+
+	It("should apply the changes made in v1alpha1 on top of the v1beta1 only fields: ", func() {
+		original := &v1beta1.VirtualMachineImport{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+			Spec: v1beta1.VirtualMachineImportSpec{
+				ProviderCredentialsSecret: &v1beta1.ObjectIdentifier{Name: "credentials"},
+				Source: v1beta1.VirtualMachineImportSourceSpec{
+					Ovirt: &v1beta1.VirtualMachineImportOvirtSourceSpec{
+						VM: v1beta1.VirtualMachineImportOvirtSourceVMSpec{Name: &vmName},
+					},
+				},
+				Snapshot: true,
+			},
+		}
+
+		spoke := &VirtualMachineImport{}
+		Expect(spoke.ConvertFrom(original)).To(Succeed())
+		targetName := "renamed"
+		spoke.Spec.TargetVMName = &targetName
+		converted := &v1beta1.VirtualMachineImport{}
+		Expect(spoke.ConvertTo(converted)).To(Succeed())
+
+		Expect(*converted.Spec.TargetVMName).To(Equal("renamed"))
+		Expect(converted.Spec.Snapshot).To(BeTrue())
+		Expect(converted.Annotations).ToNot(HaveKey(ConversionDataAnnotation))
+	})
+})
+
+var _ = Describe("ResourceMapping conversion", func() {
+	It("should keep the VMware mappings and the storage modes of a mapping read as v1alpha1: ", func() {
+		domain, datastore := "nfs", "datastore1"
+		block := corev1.PersistentVolumeBlock
+		original := &v1beta1.ResourceMapping{
+			ObjectMeta: metav1.ObjectMeta{Name: "mapping", Namespace: "test"},
+			Spec: v1beta1.ResourceMappingSpec{
+				OvirtMappings: &v1beta1.OvirtMappings{
+					StorageMappings: &[]v1beta1.StorageResourceMappingItem{
+						{Source: v1beta1.Source{Name: &domain}, Target: v1beta1.ObjectIdentifier{Name: "fast"}, VolumeMode: &block},
+					},
+				},
+				VmwareMappings: &v1beta1.VmwareMappings{
+					StorageMappings: &[]v1beta1.StorageResourceMappingItem{
+						{Source: v1beta1.Source{Name: &datastore}, Target: v1beta1.ObjectIdentifier{Name: "fast"}},
+					},
+				},
+			},
+		}
+
+		spoke := &ResourceMapping{}
+		Expect(spoke.ConvertFrom(original)).To(Succeed())
+		converted := &v1beta1.ResourceMapping{}
+		Expect(spoke.ConvertTo(converted)).To(Succeed())
+
+		Expect(*spoke.Spec.OvirtMappings.StorageMappings).To(HaveLen(1))
+		Expect(converted).To(Equal(original))
+	})
+
+	It("should convert a v1alpha1 mapping to v1beta1 and back: ", func() {
+		profile := "ovirtmgmt/ovirtmgmt"
+		original := &ResourceMapping{
+			ObjectMeta: metav1.ObjectMeta{Name: "mapping", Namespace: "test"},
+			Spec: ResourceMappingSpec{
+				OvirtMappings: &OvirtMappings{
+					NetworkMappings: &[]ResourceMappingItem{
+						{Source: Source{Name: &profile}, Target: ObjectIdentifier{Name: "pod"}},
+					},
+				},
+			},
+		}
+
+		hub := &v1beta1.ResourceMapping{}
+		Expect(original.ConvertTo(hub)).To(Succeed())
+		converted := &ResourceMapping{}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+
+		Expect(converted).To(Equal(original))
+	})
+})
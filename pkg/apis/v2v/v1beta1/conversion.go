@@ -0,0 +1,7 @@
+package v1beta1
+
+// Hub marks VirtualMachineImport as the version the other versions are converted to and from
+func (*VirtualMachineImport) Hub() {}
+
+// Hub marks ResourceMapping as the version the other versions are converted to and from
+func (*ResourceMapping) Hub() {}
@@ -0,0 +1,217 @@
+package v1beta1
+
+import (
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProviderSpec defines the desired state of Provider
+// +k8s:openapi-gen=true
+type ProviderSpec struct {
+	// Type of the source provider
+	Type ProviderType `json:"type"`
+
+	// URL of the source provider API, e.g. https://engine.example.com/ovirt-engine/api or https://vcenter.example.com/sdk
+	URL string `json:"url"`
+
+	// SecretRef identifies the secret holding the username and password of the source provider
+	SecretRef ObjectIdentifier `json:"secretRef"`
+
+	// TLS defines how the certificate of the source provider is verified
+	// +optional
+	TLS *ProviderTLSSpec `json:"tls,omitempty"`
+
+	// TransferNetwork identifies the network attachment definition the disks are transferred over
+	// +optional
+	TransferNetwork *ObjectIdentifier `json:"transferNetwork,omitempty"`
+}
+
+// ProviderType defines the type of a source provider
+// +k8s:openapi-gen=true
+type ProviderType string
+
+// These are valid source provider types.
+const (
+	// OvirtProviderType is an oVirt engine
+	OvirtProviderType ProviderType = "ovirt"
+
+	// VmwareProviderType is a vCenter or a standalone ESXi host
+	VmwareProviderType ProviderType = "vmware"
+)
+
+// ProviderTLSSpec defines how the certificate of the source provider is verified. The system CA certificates are
+// used when none of the fields is set.
+// +k8s:openapi-gen=true
+type ProviderTLSSpec struct {
+	// CACert holds the PEM encoded CA certificates the certificate of the source provider is verified with
+	// +optional
+	CACert *string `json:"caCert,omitempty"`
+
+	// CABundleRef identifies the config map or the secret holding the PEM encoded CA certificates the certificate
+	// of the source provider is verified with
+	// +optional
+	CABundleRef *CABundleReference `json:"caBundleRef,omitempty"`
+
+	// Thumbprint pins the vCenter or ESXi host certificate to its SHA-1 or SHA-256 fingerprint, in colon-separated
+	// hexadecimal octets
+	// +optional
+	Thumbprint *string `json:"thumbprint,omitempty"`
+
+	// InsecureSkipVerify disables the verification of the certificate of the source provider, for lab environments.
+	// An event is recorded every time the source provider is connected to without verification.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// CABundleReference identifies the config map or the secret holding a CA bundle
+// +k8s:openapi-gen=true
+type CABundleReference struct {
+	// Kind of the resource holding the CA bundle, ConfigMap or Secret
+	Kind CABundleKind `json:"kind"`
+
+	// Name of the resource holding the CA bundle
+	Name string `json:"name"`
+
+	// Namespace of the resource holding the CA bundle, the namespace of the Provider by default
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+
+	// Key of the CA bundle in the resource, ca.crt by default
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// CABundleKind defines the kind of the resource holding a CA bundle
+// +k8s:openapi-gen=true
+type CABundleKind string
+
+// These are valid kinds of the resource holding a CA bundle.
+const (
+	// ConfigMapCABundle is a CA bundle held by a config map
+	ConfigMapCABundle CABundleKind = "ConfigMap"
+
+	// SecretCABundle is a CA bundle held by a secret
+	SecretCABundle CABundleKind = "Secret"
+
+	// DefaultCABundleKey is the key of the CA bundle when the reference doesn't set one
+	DefaultCABundleKey = "ca.crt"
+)
+
+// ProviderStatus defines the observed state of Provider
+// +k8s:openapi-gen=true
+type ProviderStatus struct {
+	// +optional
+	Conditions []ProviderCondition `json:"conditions,omitempty"`
+
+	// Version of the source provider
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Inventory counts the resources of the source provider
+	// +optional
+	Inventory *ProviderInventory `json:"inventory,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the status was computed for
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// ProviderInventory counts the resources of the source provider
+// +k8s:openapi-gen=true
+type ProviderInventory struct {
+	Datacenters int `json:"datacenters"`
+	Clusters    int `json:"clusters"`
+	Hosts       int `json:"hosts"`
+	VMs         int `json:"vms"`
+}
+
+// ProviderConditionType defines the condition of a source provider
+// +k8s:openapi-gen=true
+type ProviderConditionType string
+
+// These are valid conditions of a source provider.
+const (
+	// ProviderReady represents the connectivity of the source provider
+	ProviderReady ProviderConditionType = "Ready"
+)
+
+// ProviderConditionReason defines the reasons for the Ready condition of a source provider
+// +k8s:openapi-gen=true
+type ProviderConditionReason string
+
+// These are valid reasons for the Ready condition of a source provider.
+const (
+	// ProviderConnected represents a source provider that could be connected to
+	ProviderConnected ProviderConditionReason = "ConnectionSucceeded"
+
+	// ProviderSecretNotFound represents the nonexistence of the secret of the source provider
+	ProviderSecretNotFound ProviderConditionReason = "SecretNotFound"
+
+	// ProviderInvalidSecret represents a secret that lacks the username or the password
+	ProviderInvalidSecret ProviderConditionReason = "InvalidSecret"
+
+	// ProviderConnectionFailed represents a source provider that couldn't be connected to
+	ProviderConnectionFailed ProviderConditionReason = "ConnectionFailed"
+
+	// ProviderAuthenticationFailed represents a source provider that rejected the username or the password
+	ProviderAuthenticationFailed ProviderConditionReason = "AuthenticationFailed"
+
+	// ProviderCertificateVerificationFailed represents a source provider whose certificate couldn't be verified
+	ProviderCertificateVerificationFailed ProviderConditionReason = "CertificateVerificationFailed"
+
+	// ProviderInvalidTLSConfiguration represents TLS settings that are contradictory or reference a missing CA bundle
+	ProviderInvalidTLSConfiguration ProviderConditionReason = "InvalidTLSConfiguration"
+)
+
+// ProviderCondition defines the observed state of a source provider
+// +k8s:openapi-gen=true
+type ProviderCondition struct {
+	// Type of source provider condition
+	Type ProviderConditionType `json:"type"`
+
+	// Status of the condition, one of True, False, Unknown
+	Status k8sv1.ConditionStatus `json:"status"`
+
+	// A brief CamelCase string that describes why the source provider is in current condition status
+	// +optional
+	Reason *string `json:"reason,omitempty"`
+
+	// A human-readable message indicating details about last transition
+	// +optional
+	Message *string `json:"message,omitempty"`
+
+	// The last time we got an update on a given condition
+	// +optional
+	LastHeartbeatTime *metav1.Time `json:"lastHeartbeatTime,omitempty"`
+
+	// The last time the condition transit from one status to another
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Provider is the Schema for the Providers API
+// +k8s:openapi-gen=true
+// +genclient
+// +kubebuilder:subresource:status
+type Provider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProviderSpec   `json:"spec,omitempty"`
+	Status ProviderStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ProviderList contains a list of Provider
+type ProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Provider `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Provider{}, &ProviderList{})
+}
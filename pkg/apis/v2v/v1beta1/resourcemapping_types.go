@@ -42,6 +42,13 @@ type OvirtMappings struct {
 	// DiskMappings is respected only when provided in context of a single VM import within VirtualMachineImport
 	// +optional
 	DiskMappings *[]StorageResourceMappingItem `json:"diskMappings,omitempty"`
+
+	// CdromMappings defines the mapping of the ISO images inserted in the CD-ROMs of the VM
+	// CdromMappings.Source.ID represents the ID of the ISO image on ovirt, i.e. the ID of its disk in a data domain
+	// or its file name in an ISO domain
+	// CdromMappings.Source.Name represents the name of the ISO image on ovirt
+	// +optional
+	CdromMappings *[]CdromResourceMappingItem `json:"cdromMappings,omitempty"`
 }
 
 // VmwareMappings defines the mappings of vmware resources to kubevirt
@@ -63,6 +70,12 @@ type VmwareMappings struct {
 	// DiskMappings.Source.Name represents the disk name in vCenter
 	// DiskMappings.Source.ID represents the `DiskObjectId` or `vDiskID` of the VirtualDisk in vCenter
 	DiskMappings *[]StorageResourceMappingItem `json:"diskMappings,omitempty"`
+
+	// CdromMappings defines the mapping of the ISO images inserted in the CD-ROMs of the VM
+	// CdromMappings.Source.Name represents the datastore path of the ISO image, e.g. "[datastore1] iso/image.iso",
+	// or the name of the CD-ROM in vCenter
+	// +optional
+	CdromMappings *[]CdromResourceMappingItem `json:"cdromMappings,omitempty"`
 }
 
 // Source defines how to identify a resource on the provider, either by ID or by name
@@ -95,8 +108,51 @@ type StorageResourceMappingItem struct {
 	VolumeMode *corev1.PersistentVolumeMode `json:"volumeMode,omitempty"`
 	// +optional
 	AccessMode *corev1.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
+	// Preallocation defines whether the space of the imported disks is allocated up front or as they are written.
+	// CDI decides when it isn't set.
+	// +optional
+	Preallocation *PreallocationMode `json:"preallocation,omitempty"`
 }
 
+// PreallocationMode defines how the space of an imported disk is allocated
+// +k8s:openapi-gen=true
+type PreallocationMode string
+
+const (
+	// SparsePreallocation allocates the space of the disk as it is written
+	SparsePreallocation PreallocationMode = "sparse"
+	// FullPreallocation allocates the whole space of the disk up front
+	FullPreallocation PreallocationMode = "preallocated"
+	// InheritPreallocation preallocates the disk when the source disk is thick provisioned
+	InheritPreallocation PreallocationMode = "inheritFromSource"
+)
+
+// CdromResourceMappingItem defines the mapping of the ISO image of a CD-ROM from the provider to kubevirt
+// +k8s:openapi-gen=true
+type CdromResourceMappingItem struct {
+	Source Source `json:"source"`
+	// Target is the storage class the ISO image is imported to, or the PVC or DataSource holding the ISO image
+	Target ObjectIdentifier `json:"target"`
+
+	// Type defines what the target is, the ISO image is imported to a data volume of the target storage class
+	// when it isn't set
+	// +optional
+	Type *CdromMappingType `json:"type,omitempty"`
+}
+
+// CdromMappingType defines what the target of a CD-ROM mapping is
+// +k8s:openapi-gen=true
+type CdromMappingType string
+
+const (
+	// ImportCdromMapping imports the ISO image to a data volume of the target storage class
+	ImportCdromMapping CdromMappingType = "import"
+	// PersistentVolumeClaimCdromMapping backs the CD-ROM with the target PVC, which holds the ISO image already
+	PersistentVolumeClaimCdromMapping CdromMappingType = "persistentVolumeClaim"
+	// DataSourceCdromMapping clones the PVC of the target CDI DataSource to a data volume backing the CD-ROM
+	DataSourceCdromMapping CdromMappingType = "dataSource"
+)
+
 // ResourceMappingStatus defines the observed state of ResourceMapping
 // +k8s:openapi-gen=true
 type ResourceMappingStatus struct {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -16,7 +17,16 @@ type VirtualMachineImportSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
 	// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
-	ProviderCredentialsSecret ObjectIdentifier `json:"providerCredentialsSecret"`
+
+	// ProviderCredentialsSecret identifies the secret holding the connection details of the source provider.
+	// Either ProviderCredentialsSecret or Provider must be set.
+	// +optional
+	ProviderCredentialsSecret *ObjectIdentifier `json:"providerCredentialsSecret,omitempty"`
+
+	// Provider identifies the Provider resource of the source provider
+	// +optional
+	Provider *ObjectIdentifier `json:"provider,omitempty"`
+
 	// +optional
 	ResourceMapping *ObjectIdentifier              `json:"resourceMapping,omitempty"`
 	Source          VirtualMachineImportSourceSpec `json:"source"`
@@ -27,13 +37,137 @@ type VirtualMachineImportSpec struct {
 	// +optional
 	FinalizeDate *metav1.Time `json:"finalizeDate,omitempty"`
 
+	// Snapshot imports the disks from a snapshot of the source VM instead of stopping it, the snapshot is removed once the disks are copied
+	// +optional
+	Snapshot bool `json:"snapshot,omitempty"`
+
 	// +optional
 	TargetVMName *string `json:"targetVmName,omitempty"`
 
 	// +optional
 	StartVM *bool `json:"startVm,omitempty"`
+
+	// SourceShutdown defines how the source VM is stopped before its disks are imported
+	// +optional
+	SourceShutdown *SourceShutdownSpec `json:"sourceShutdown,omitempty"`
+
+	// ConversionPod overrides the settings of the virt-v2v guest conversion pod of the controller configuration
+	// +optional
+	ConversionPod *ConversionPodSpec `json:"conversionPod,omitempty"`
+
+	// GuestCustomization references the customization applied to the guest when it is converted
+	// +optional
+	GuestCustomization *GuestCustomizationSpec `json:"guestCustomization,omitempty"`
+
+	// Disks selects the disks of the source VM that are imported, all of them by default
+	// +optional
+	Disks *DiskSelectionSpec `json:"disks,omitempty"`
+}
+
+// DiskSelectionSpec selects the disks of the source VM that are imported
+// +k8s:openapi-gen=true
+type DiskSelectionSpec struct {
+	// Include imports only the disks matching one of the rules, all the disks when it is empty
+	// +optional
+	Include []DiskSelectorRule `json:"include,omitempty"`
+
+	// Exclude doesn't import the disks matching one of the rules, even when they are included
+	// +optional
+	Exclude []DiskSelectorRule `json:"exclude,omitempty"`
+}
+
+// DiskSelectorRule matches the disks of the source VM matching all its fields that are set
+// +k8s:openapi-gen=true
+type DiskSelectorRule struct {
+	// ID of the disk, the disk ID on oVirt or the vDiskID or DiskObjectId of the VirtualDisk on vSphere
+	// +optional
+	ID *string `json:"id,omitempty"`
+
+	// Name of the disk, the name or alias of the disk on oVirt or the label of the VirtualDisk on vSphere
+	// +optional
+	Name *string `json:"name,omitempty"`
+
+	// Bootable matches the disks the VM boots from when true, and the others when false
+	// +optional
+	Bootable *bool `json:"bootable,omitempty"`
+
+	// MinSize matches the disks whose provisioned size is at least the quantity
+	// +optional
+	MinSize *resource.Quantity `json:"minSize,omitempty"`
+
+	// MaxSize matches the disks whose provisioned size is at most the quantity
+	// +optional
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+}
+
+// GuestCustomizationSpec references the config map holding the firstboot scripts, the files and the packages the guest
+// is customized with when it is converted
+// +k8s:openapi-gen=true
+type GuestCustomizationSpec struct {
+	// ConfigMap in the namespace of the import holding the customization
+	ConfigMap k8sv1.LocalObjectReference `json:"configMap"`
 }
 
+// ConversionPodSpec defines the placement, the resources and the image of the virt-v2v guest conversion pod
+// +k8s:openapi-gen=true
+type ConversionPodSpec struct {
+	// Image of virt-v2v
+	// +optional
+	Image *string `json:"image,omitempty"`
+
+	// NodeSelector is added to the kubevirt.io/schedulable node selector of the pod
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations of the pod
+	// +optional
+	Tolerations []k8sv1.Toleration `json:"tolerations,omitempty"`
+
+	// Resources of the virt-v2v container, the kvm device is always requested
+	// +optional
+	Resources *k8sv1.ResourceRequirements `json:"resources,omitempty"`
+
+	// PriorityClassName of the pod
+	// +optional
+	PriorityClassName *string `json:"priorityClassName,omitempty"`
+
+	// ServiceAccountName of the pod
+	// +optional
+	ServiceAccountName *string `json:"serviceAccountName,omitempty"`
+}
+
+// SourceShutdownSpec defines how the source VM is stopped
+// +k8s:openapi-gen=true
+type SourceShutdownSpec struct {
+	// Timeout of the shut down, 5 minutes by default
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Force powers the source VM off when it isn't shut down before the timeout
+	// +optional
+	Force bool `json:"force,omitempty"`
+
+	// Method used to shut the source VM down, guest by default
+	// +optional
+	Method *SourceShutdownMethod `json:"method,omitempty"`
+}
+
+// SourceShutdownMethod defines how the source VM is shut down
+// +k8s:openapi-gen=true
+type SourceShutdownMethod string
+
+// These are valid source VM shut down methods.
+const (
+	// GuestShutdown shuts the guest OS down through the guest tools or agent
+	GuestShutdown SourceShutdownMethod = "guest"
+
+	// ACPIShutdown sends an ACPI shutdown event to the guest, which doesn't require the guest tools or agent
+	ACPIShutdown SourceShutdownMethod = "acpi"
+
+	// HardShutdown powers the source VM off immediately
+	HardShutdown SourceShutdownMethod = "hard"
+)
+
 // VirtualMachineImportSourceSpec defines the source provider and the internal mapping resources
 // +k8s:openapi-gen=true
 type VirtualMachineImportSourceSpec struct {
@@ -41,15 +175,55 @@ type VirtualMachineImportSourceSpec struct {
 	Ovirt *VirtualMachineImportOvirtSourceSpec `json:"ovirt,omitempty"`
 	// +optional
 	Vmware *VirtualMachineImportVmwareSourceSpec `json:"vmware,omitempty"`
+
+	// PVC creates the VM from disks already in persistent volume claims, e.g. replicated by the storage array or
+	// uploaded, instead of importing them
+	// +optional
+	PVC *VirtualMachineImportPVCSourceSpec `json:"pvc,omitempty"`
 }
 
 // VirtualMachineImportOvirtSourceSpec defines the mapping resources and the VM identity for oVirt source provider
 // +k8s:openapi-gen=true
 type VirtualMachineImportOvirtSourceSpec struct {
-	VM VirtualMachineImportOvirtSourceVMSpec `json:"vm"`
+	// +optional
+	VM VirtualMachineImportOvirtSourceVMSpec `json:"vm,omitempty"`
+
+	// VMSelector selects several source VMs at once, instead of a single VM
+	// +optional
+	VMSelector *VirtualMachineImportOvirtSourceVMSelectorSpec `json:"vmSelector,omitempty"`
 
 	// +optional
 	Mappings *OvirtMappings `json:"mappings,omitempty"`
+
+	// GuestConversion defines whether the guest is converted with virt-v2v after its disks are imported, Auto by default
+	// +optional
+	GuestConversion *GuestConversionPolicy `json:"guestConversion,omitempty"`
+}
+
+// GuestConversionPolicy defines whether the guest of an oVirt VM is converted with virt-v2v
+// +k8s:openapi-gen=true
+type GuestConversionPolicy string
+
+const (
+	// GuestConversionAuto converts the guest when it can't boot as it is, i.e. when it was imported to oVirt from
+	// VMware or Xen, or when it's a Windows guest without virtio drivers
+	GuestConversionAuto GuestConversionPolicy = "Auto"
+	// GuestConversionAlways always converts the guest
+	GuestConversionAlways GuestConversionPolicy = "Always"
+	// GuestConversionNever never converts the guest
+	GuestConversionNever GuestConversionPolicy = "Never"
+)
+
+// VirtualMachineImportOvirtSourceVMSelectorSpec defines how to select several VMs in oVirt. One VirtualMachineImport
+// is created for each of the selected VMs, sharing the credentials and the mappings of the selecting import.
+// +k8s:openapi-gen=true
+type VirtualMachineImportOvirtSourceVMSelectorSpec struct {
+	// oVirt search query selecting the VMs, e.g. "tag=wave3 and cluster=prod"
+	Search string `json:"search"`
+
+	// Pattern of the target VM names, in which {name}, {id} and {cluster} are replaced with the source VM's values
+	// +optional
+	TargetVMNamePattern *string `json:"targetVmNamePattern,omitempty"`
 }
 
 // VirtualMachineImportVmwareSourceSpec defines the mapping resources and the VM identity for vmware source provider
@@ -61,6 +235,84 @@ type VirtualMachineImportVmwareSourceSpec struct {
 	Mappings *VmwareMappings `json:"mappings,omitempty"`
 }
 
+// VirtualMachineImportPVCSourceSpec defines the persistent volume claims holding the disks of the VM and how the rest
+// of the VM is described, either by its hardware or by the source VM the disks come from. The disks aren't imported:
+// the guest is converted on the claims and the VM is created with them.
+// +k8s:openapi-gen=true
+type VirtualMachineImportPVCSourceSpec struct {
+	// Disks are the persistent volume claims holding the disks of the VM, in the namespace of the import. The VM
+	// boots from the first one.
+	Disks []PVCDisk `json:"disks"`
+
+	// Hardware describes the VM when its source VM isn't read from a source provider
+	// +optional
+	Hardware *VirtualMachineHardwareSpec `json:"hardware,omitempty"`
+
+	// SourceVM identifies the VM the disks come from, the VM is created from its configuration read from the provider
+	// of the import
+	// +optional
+	SourceVM *PVCSourceVMSpec `json:"sourceVM,omitempty"`
+}
+
+// PVCDisk defines a persistent volume claim holding a disk of the VM
+// +k8s:openapi-gen=true
+type PVCDisk struct {
+	// ClaimName is the name of the persistent volume claim
+	ClaimName string `json:"claimName"`
+
+	// Bus of the disk in the VM, virtio by default
+	// +optional
+	Bus *string `json:"bus,omitempty"`
+}
+
+// PVCSourceVMSpec identifies the source VM of a PVC source in one of the source providers
+// +k8s:openapi-gen=true
+type PVCSourceVMSpec struct {
+	// +optional
+	Ovirt *VirtualMachineImportOvirtSourceSpec `json:"ovirt,omitempty"`
+	// +optional
+	Vmware *VirtualMachineImportVmwareSourceSpec `json:"vmware,omitempty"`
+}
+
+// VirtualMachineHardwareSpec describes the hardware of a VM
+// +k8s:openapi-gen=true
+type VirtualMachineHardwareSpec struct {
+	// Number of CPU sockets, 1 by default
+	// +optional
+	Sockets *int32 `json:"sockets,omitempty"`
+
+	// Number of cores per CPU socket, 1 by default
+	// +optional
+	Cores *int32 `json:"cores,omitempty"`
+
+	// Memory of the VM
+	Memory resource.Quantity `json:"memory"`
+
+	// Firmware the guest boots with, bios by default
+	// +optional
+	Firmware *FirmwareType `json:"firmware,omitempty"`
+
+	// SecureBoot enables secure boot for an efi firmware
+	// +optional
+	SecureBoot bool `json:"secureBoot,omitempty"`
+
+	// OperatingSystem of the guest as named by the common templates, e.g. rhel8 or win2k19. The VM is created from
+	// the template of the operating system.
+	// +optional
+	OperatingSystem *string `json:"operatingSystem,omitempty"`
+}
+
+// FirmwareType defines the firmware a guest boots with
+// +k8s:openapi-gen=true
+type FirmwareType string
+
+const (
+	// BIOSFirmware boots the guest with a BIOS
+	BIOSFirmware FirmwareType = "bios"
+	// EFIFirmware boots the guest with UEFI
+	EFIFirmware FirmwareType = "efi"
+)
+
 // ObjectIdentifier defines how a resource should be identified on kubevirt
 // +k8s:openapi-gen=true
 type ObjectIdentifier struct {
@@ -90,10 +342,47 @@ type VirtualMachineImportVmwareSourceVMSpec struct {
 	// +optional
 	ID *string `json:"id,omitempty"`
 
+	// Type of the UUID used to identify the virtual machine, either bios (default) or instance
+	// +optional
+	UUIDType *VmwareUUIDType `json:"uuidType,omitempty"`
+
+	// Managed object reference of the virtual machine, e.g. vm-42
+	// +optional
+	MoRef *string `json:"moRef,omitempty"`
+
 	// +optional
 	Name *string `json:"name,omitempty"`
+
+	// Name of the datacenter the VM lookup is restricted to
+	// +optional
+	Datacenter *string `json:"datacenter,omitempty"`
+
+	// Name or inventory path of the cluster the VM lookup is restricted to
+	// +optional
+	Cluster *string `json:"cluster,omitempty"`
+
+	// Name or inventory path of the VM folder the VM lookup is restricted to
+	// +optional
+	Folder *string `json:"folder,omitempty"`
+
+	// Name or inventory path of the resource pool the VM lookup is restricted to
+	// +optional
+	ResourcePool *string `json:"resourcePool,omitempty"`
 }
 
+// VmwareUUIDType defines which of the vSphere UUIDs identifies a VM
+// +k8s:openapi-gen=true
+type VmwareUUIDType string
+
+// These are valid types of vSphere VM UUIDs.
+const (
+	// BiosUUID is the SMBIOS UUID of the VM, reported as config.uuid
+	BiosUUID VmwareUUIDType = "bios"
+
+	// InstanceUUID is the vCenter specific instance UUID of the VM, reported as config.instanceUuid
+	InstanceUUID VmwareUUIDType = "instance"
+)
+
 // VirtualMachineImportOvirtSourceVMClusterSpec defines the source cluster's identity of the VM in oVirt
 // +k8s:openapi-gen=true
 // +optional
@@ -122,12 +411,200 @@ type VirtualMachineImportStatus struct {
 
 	// +optional
 	WarmImport VirtualMachineWarmImportStatus `json:"warmImport"`
+
+	// SourceSnapshot is the snapshot of the source VM the disks are imported from in a snapshot import
+	// +optional
+	SourceSnapshot *string `json:"sourceSnapshot,omitempty"`
+
+	// SourceShutdown records the shut down of the source VM
+	// +optional
+	SourceShutdown *SourceShutdownStatus `json:"sourceShutdown,omitempty"`
+
+	// VirtualMachineImports created for the VMs matched by the VM selector
+	// +optional
+	VirtualMachineImports []ObjectIdentifier `json:"virtualMachineImports,omitempty"`
+
+	// Phase is the stage of the import process the import is in
+	// +optional
+	Phase VirtualMachineImportPhase `json:"phase,omitempty"`
+
+	// Progress is the percentage of the import process that is completed, from 0 to 100
+	// +optional
+	Progress int `json:"progress,omitempty"`
+
+	// PhaseTransitions records when the import entered and left each of the phases it went through
+	// +optional
+	PhaseTransitions []PhaseTransition `json:"phaseTransitions,omitempty"`
+
+	// EstimatedCompletionTime is when the copy of the disks is expected to complete at their current throughput
+	// +optional
+	EstimatedCompletionTime *metav1.Time `json:"estimatedCompletionTime,omitempty"`
+
+	// GuestInspection is the inspection of the guest reported by virt-v2v once it converted it
+	// +optional
+	GuestInspection *GuestInspection `json:"guestInspection,omitempty"`
+
+	// ConversionLog references the config map holding the tail of the log of the guest conversion
+	// +optional
+	ConversionLog *k8sv1.LocalObjectReference `json:"conversionLog,omitempty"`
+
+	// GuestCustomization reports the customization of the guest
+	// +optional
+	GuestCustomization *GuestCustomizationStatus `json:"guestCustomization,omitempty"`
+
+	// GuestConversion records whether the guest is converted, decided once before the target VM is created
+	// +optional
+	GuestConversion *bool `json:"guestConversion,omitempty"`
+}
+
+// GuestCustomizationPhase defines the result of the customization of the guest
+// +k8s:openapi-gen=true
+type GuestCustomizationPhase string
+
+const (
+	// GuestCustomizationApplied represents a guest customized successfully, the firstboot scripts run when it boots
+	GuestCustomizationApplied GuestCustomizationPhase = "Applied"
+
+	// GuestCustomizationFailed represents a failure to customize the guest
+	GuestCustomizationFailed GuestCustomizationPhase = "Failed"
+)
+
+// GuestCustomizationStatus reports the customization applied to the guest
+// +k8s:openapi-gen=true
+type GuestCustomizationStatus struct {
+	// Phase is the result of the customization
+	Phase GuestCustomizationPhase `json:"phase"`
+
+	// Scripts are the firstboot scripts installed in the guest
+	// +optional
+	Scripts []string `json:"scripts,omitempty"`
+
+	// Files are the paths of the files injected in the guest
+	// +optional
+	Files []string `json:"files,omitempty"`
+
+	// Packages are the packages installed at first boot
+	// +optional
+	Packages []string `json:"packages,omitempty"`
+
+	// Message describes the failure of the customization
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// GuestInspection defines the operating system, the drivers and the applications virt-v2v found in the guest
+// +k8s:openapi-gen=true
+type GuestInspection struct {
+	// Type of the operating system, e.g. linux or windows
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Distro of the operating system, e.g. rhel or windows
+	// +optional
+	Distro string `json:"distro,omitempty"`
+
+	// ProductName of the operating system, e.g. Windows Server 2019 Standard
+	// +optional
+	ProductName string `json:"productName,omitempty"`
+
+	// MajorVersion of the operating system
+	// +optional
+	MajorVersion int `json:"majorVersion,omitempty"`
+
+	// MinorVersion of the operating system
+	// +optional
+	MinorVersion int `json:"minorVersion,omitempty"`
+
+	// OSInfo is the libosinfo short ID of the operating system, e.g. rhel8.2 or win2k19
+	// +optional
+	OSInfo string `json:"osinfo,omitempty"`
+
+	// Firmware the guest boots with, bios or uefi
+	// +optional
+	Firmware string `json:"firmware,omitempty"`
+
+	// Drivers are the virtio drivers installed in the guest
+	// +optional
+	Drivers []string `json:"drivers,omitempty"`
+
+	// Applications are the names of the applications installed in the guest
+	// +optional
+	Applications []string `json:"applications,omitempty"`
+}
+
+// VirtualMachineImportPhase defines the stage of the import process
+// +k8s:openapi-gen=true
+type VirtualMachineImportPhase string
+
+// These are valid phases of VM import.
+const (
+	// PhasePending represents an import that wasn't validated yet, e.g. because the source provider can't be reached
+	PhasePending VirtualMachineImportPhase = "Pending"
+
+	// PhaseValidating represents the validation of the source VM and of the mapping rules
+	PhaseValidating VirtualMachineImportPhase = "Validating"
+
+	// PhaseStoppingSource represents shutting the source VM down
+	PhaseStoppingSource VirtualMachineImportPhase = "StoppingSource"
+
+	// PhaseCreating represents the creation of the target VM
+	PhaseCreating VirtualMachineImportPhase = "Creating"
+
+	// PhaseWarmCopying represents the copy of the disks of a running source VM in the stages of a warm import
+	PhaseWarmCopying VirtualMachineImportPhase = "WarmCopying"
+
+	// PhaseCopyingDisks represents the copy of the disks of the source VM
+	PhaseCopyingDisks VirtualMachineImportPhase = "CopyingDisks"
+
+	// PhaseConverting represents the conversion of the guest with virt-v2v
+	PhaseConverting VirtualMachineImportPhase = "Converting"
+
+	// PhaseStarting represents starting the target VM
+	PhaseStarting VirtualMachineImportPhase = "Starting"
+
+	// PhaseSucceeded represents an import that completed successfully
+	PhaseSucceeded VirtualMachineImportPhase = "Succeeded"
+
+	// PhaseFailed represents an import that failed
+	PhaseFailed VirtualMachineImportPhase = "Failed"
+)
+
+// PhaseTransition records when an import entered and left a phase
+// +k8s:openapi-gen=true
+type PhaseTransition struct {
+	Phase VirtualMachineImportPhase `json:"phase"`
+
+	// StartedAt is when the import entered the phase
+	StartedAt metav1.Time `json:"startedAt"`
+
+	// CompletedAt is when the import left the phase
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+}
+
+// SourceShutdownStatus records when the shut down of the source VM was requested
+type SourceShutdownStatus struct {
+	// RequestedAt is when the shut down was requested
+	// +optional
+	RequestedAt *metav1.Time `json:"requestedAt,omitempty"`
+
+	// ForcedAt is when the source VM was powered off after the shut down timed out
+	// +optional
+	ForcedAt *metav1.Time `json:"forcedAt,omitempty"`
+
+	// Method is the method of the last shut down request
+	// +optional
+	Method SourceShutdownMethod `json:"method,omitempty"`
 }
 
 type VirtualMachineWarmImportStatus struct {
 	// +optional
 	NextStageTime *metav1.Time `json:"nextStageTime,omitempty"`
 
+	// StageStartTime is when the stage being copied started
+	// +optional
+	StageStartTime *metav1.Time `json:"stageStartTime,omitempty"`
+
 	Successes           int `json:"successes"`
 	Failures            int `json:"failures"`
 	ConsecutiveFailures int `json:"consecutiveFailures"`
@@ -179,6 +656,9 @@ const (
 	// WarmImportFailed represents a failure to complete a warm import on the target VM.
 	WarmImportFailed SucceededConditionReason = "WarmImportFailed"
 
+	// SourceVMShutdownFailed represents a failure to shut the source VM down before the timeout
+	SourceVMShutdownFailed SucceededConditionReason = "SourceVMShutdownFailed"
+
 	// VMNotFound represents a failure to complete an import because
 	// the target VM cannot be found, perhaps due to being deleted during an import.
 	VMNotFound SucceededConditionReason = "VMNotFound"
@@ -188,6 +668,9 @@ const (
 
 	// VirtualMachineRunning represents the completion of the vm import and vm in running state
 	VirtualMachineRunning SucceededConditionReason = "VirtualMachineRunning"
+
+	// VirtualMachineImportsCreated represents the creation of a VirtualMachineImport for each of the VMs matched by a VM selector
+	VirtualMachineImportsCreated SucceededConditionReason = "VirtualMachineImportsCreated"
 )
 
 // ValidConditionReason defines the reasons for the Valid condition of VM import
@@ -211,9 +694,18 @@ const (
 	// UnreachableProvider represents a failure to connect to the provider
 	UnreachableProvider ValidConditionReason = "UnreachableProvider"
 
+	// AuthenticationFailed represents the provider rejecting the credentials, e.g. after they were rotated
+	AuthenticationFailed ValidConditionReason = "AuthenticationFailed"
+
+	// CertificateVerificationFailed represents a failure to verify the certificate of the provider
+	CertificateVerificationFailed ValidConditionReason = "CertificateVerificationFailed"
+
 	// SourceVmNotFound represents the nonexistence of the source VM
 	SourceVMNotFound ValidConditionReason = "SourceVMNotFound"
 
+	// AmbiguousSourceVM represents the source VM identity matching more than one VM
+	AmbiguousSourceVM ValidConditionReason = "AmbiguousSourceVM"
+
 	// IncompleteMappingRules represents the inability to prepare the mapping rules
 	IncompleteMappingRules ValidConditionReason = "IncompleteMappingRules"
 
@@ -225,6 +717,21 @@ const (
 
 	// DuplicateTargetVMName
 	DuplicateTargetVMName ValidConditionReason = "DuplicateTargetVMName"
+
+	// SnapshotImportNotSupported represents a snapshot import that can't be performed with the source provider
+	SnapshotImportNotSupported ValidConditionReason = "SnapshotImportNotSupported"
+
+	// ConversionPodNotAllowed represents conversion pod settings of the import that the controller configuration doesn't allow
+	ConversionPodNotAllowed ValidConditionReason = "ConversionPodNotAllowed"
+
+	// ProviderNotFound represents the nonexistence of the Provider resource
+	ProviderNotFound ValidConditionReason = "ProviderNotFound"
+
+	// IncompatibleProvider represents a Provider resource whose type doesn't match the source of the import
+	IncompatibleProvider ValidConditionReason = "IncompatibleProvider"
+
+	// PersistentVolumeClaimNotFound represents the nonexistence of a persistent volume claim holding a disk of the VM
+	PersistentVolumeClaimNotFound ValidConditionReason = "PersistentVolumeClaimNotFound"
 )
 
 // MappingRulesVerifiedReason defines the reasons for the MappingRulesVerified condition of VM import
@@ -252,6 +759,9 @@ const (
 	// VMTemplateMatching represents the VM template matching process
 	VMTemplateMatching ProcessingConditionReason = "VMTemplateMatching"
 
+	// StoppingSourceVM represents shutting the source VM down
+	StoppingSourceVM ProcessingConditionReason = "StoppingSourceVM"
+
 	// CreatingTargetVM represents the creation of the VM spec
 	CreatingTargetVM ProcessingConditionReason = "CreatingTargetVM"
 
@@ -314,6 +824,61 @@ func (cond VirtualMachineImportCondition) String() string {
 // +k8s:openapi-gen=true
 type DataVolumeItem struct {
 	Name string `json:"name"`
+
+	// SourceDiskID identifies the disk of the source VM the data volume is imported from
+	// +optional
+	SourceDiskID string `json:"sourceDiskID,omitempty"`
+
+	// SizeBytes is the size requested for the data volume
+	// +optional
+	SizeBytes int64 `json:"sizeBytes,omitempty"`
+
+	// SourceBytes is the size of the source disk, which the copy progress is measured against
+	// +optional
+	SourceBytes int64 `json:"sourceBytes,omitempty"`
+
+	// AccessMode is the access mode of the PVC of the data volume, set in the mapping or recommended by the
+	// StorageProfile of its storage class
+	// +optional
+	AccessMode k8sv1.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
+
+	// VolumeMode is the volume mode of the PVC of the data volume, set in the mapping or recommended by the
+	// StorageProfile of its storage class
+	// +optional
+	VolumeMode k8sv1.PersistentVolumeMode `json:"volumeMode,omitempty"`
+
+	// Preallocated tells whether the whole space of the data volume is allocated up front
+	// +optional
+	Preallocated bool `json:"preallocated,omitempty"`
+
+	// EstimatedBytes is the estimate, made before the copy, of the bytes of storage the data volume consumes: its
+	// whole size when it is preallocated, else the bytes allocated to the source disk
+	// +optional
+	EstimatedBytes int64 `json:"estimatedBytes,omitempty"`
+
+	// Phase is the phase of the data volume
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// Progress is the percentage of the disk that is copied, from 0 to 100
+	// +optional
+	Progress int `json:"progress,omitempty"`
+
+	// BytesTransferred is the number of bytes of the source disk that are copied
+	// +optional
+	BytesTransferred int64 `json:"bytesTransferred,omitempty"`
+
+	// Throughput is the average number of bytes copied per second since the copy started
+	// +optional
+	Throughput int64 `json:"throughput,omitempty"`
+
+	// StartedAt is when the copy of the disk started
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// CompletedAt is when the copy of the disk completed
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -21,114 +21,638 @@ package v1beta1
 
 import (
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CABundleReference) DeepCopyInto(out *CABundleReference) {
+	*out = *in
+	if in.Namespace != nil {
+		in, out := &in.Namespace, &out.Namespace
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CABundleReference.
+func (in *CABundleReference) DeepCopy() *CABundleReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CABundleReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CdromResourceMappingItem) DeepCopyInto(out *CdromResourceMappingItem) {
+	*out = *in
+	in.Source.DeepCopyInto(&out.Source)
+	in.Target.DeepCopyInto(&out.Target)
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(CdromMappingType)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CdromResourceMappingItem.
+func (in *CdromResourceMappingItem) DeepCopy() *CdromResourceMappingItem {
+	if in == nil {
+		return nil
+	}
+	out := new(CdromResourceMappingItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConversionPodSpec) DeepCopyInto(out *ConversionPodSpec) {
+	*out = *in
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PriorityClassName != nil {
+		in, out := &in.PriorityClassName, &out.PriorityClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.ServiceAccountName != nil {
+		in, out := &in.ServiceAccountName, &out.ServiceAccountName
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConversionPodSpec.
+func (in *ConversionPodSpec) DeepCopy() *ConversionPodSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConversionPodSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataVolumeItem) DeepCopyInto(out *DataVolumeItem) {
 	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeItem.
+func (in *DataVolumeItem) DeepCopy() *DataVolumeItem {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVolumeItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskSelectionSpec) DeepCopyInto(out *DiskSelectionSpec) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]DiskSelectorRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]DiskSelectorRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskSelectionSpec.
+func (in *DiskSelectionSpec) DeepCopy() *DiskSelectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskSelectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskSelectorRule) DeepCopyInto(out *DiskSelectorRule) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Bootable != nil {
+		in, out := &in.Bootable, &out.Bootable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MinSize != nil {
+		in, out := &in.MinSize, &out.MinSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskSelectorRule.
+func (in *DiskSelectorRule) DeepCopy() *DiskSelectorRule {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskSelectorRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkResourceMappingItem) DeepCopyInto(out *NetworkResourceMappingItem) {
+	*out = *in
+	in.Source.DeepCopyInto(&out.Source)
+	in.Target.DeepCopyInto(&out.Target)
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkResourceMappingItem.
+func (in *NetworkResourceMappingItem) DeepCopy() *NetworkResourceMappingItem {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkResourceMappingItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuestCustomizationSpec) DeepCopyInto(out *GuestCustomizationSpec) {
+	*out = *in
+	out.ConfigMap = in.ConfigMap
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestCustomizationSpec.
+func (in *GuestCustomizationSpec) DeepCopy() *GuestCustomizationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GuestCustomizationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuestCustomizationStatus) DeepCopyInto(out *GuestCustomizationStatus) {
+	*out = *in
+	if in.Scripts != nil {
+		in, out := &in.Scripts, &out.Scripts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Packages != nil {
+		in, out := &in.Packages, &out.Packages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestCustomizationStatus.
+func (in *GuestCustomizationStatus) DeepCopy() *GuestCustomizationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GuestCustomizationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuestInspection) DeepCopyInto(out *GuestInspection) {
+	*out = *in
+	if in.Drivers != nil {
+		in, out := &in.Drivers, &out.Drivers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestInspection.
+func (in *GuestInspection) DeepCopy() *GuestInspection {
+	if in == nil {
+		return nil
+	}
+	out := new(GuestInspection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectIdentifier) DeepCopyInto(out *ObjectIdentifier) {
+	*out = *in
+	if in.Namespace != nil {
+		in, out := &in.Namespace, &out.Namespace
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectIdentifier.
+func (in *ObjectIdentifier) DeepCopy() *ObjectIdentifier {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectIdentifier)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OvirtMappings) DeepCopyInto(out *OvirtMappings) {
+	*out = *in
+	if in.NetworkMappings != nil {
+		in, out := &in.NetworkMappings, &out.NetworkMappings
+		*out = new([]NetworkResourceMappingItem)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]NetworkResourceMappingItem, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+	if in.StorageMappings != nil {
+		in, out := &in.StorageMappings, &out.StorageMappings
+		*out = new([]StorageResourceMappingItem)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]StorageResourceMappingItem, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+	if in.DiskMappings != nil {
+		in, out := &in.DiskMappings, &out.DiskMappings
+		*out = new([]StorageResourceMappingItem)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]StorageResourceMappingItem, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+	if in.CdromMappings != nil {
+		in, out := &in.CdromMappings, &out.CdromMappings
+		*out = new([]CdromResourceMappingItem)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]CdromResourceMappingItem, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvirtMappings.
+func (in *OvirtMappings) DeepCopy() *OvirtMappings {
+	if in == nil {
+		return nil
+	}
+	out := new(OvirtMappings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCDisk) DeepCopyInto(out *PVCDisk) {
+	*out = *in
+	if in.Bus != nil {
+		in, out := &in.Bus, &out.Bus
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCDisk.
+func (in *PVCDisk) DeepCopy() *PVCDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCSourceVMSpec) DeepCopyInto(out *PVCSourceVMSpec) {
+	*out = *in
+	if in.Ovirt != nil {
+		in, out := &in.Ovirt, &out.Ovirt
+		*out = new(VirtualMachineImportOvirtSourceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Vmware != nil {
+		in, out := &in.Vmware, &out.Vmware
+		*out = new(VirtualMachineImportVmwareSourceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCSourceVMSpec.
+func (in *PVCSourceVMSpec) DeepCopy() *PVCSourceVMSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCSourceVMSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PhaseTransition) DeepCopyInto(out *PhaseTransition) {
+	*out = *in
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhaseTransition.
+func (in *PhaseTransition) DeepCopy() *PhaseTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(PhaseTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Provider) DeepCopyInto(out *Provider) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Provider.
+func (in *Provider) DeepCopy() *Provider {
+	if in == nil {
+		return nil
+	}
+	out := new(Provider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Provider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderCondition) DeepCopyInto(out *ProviderCondition) {
+	*out = *in
+	if in.Reason != nil {
+		in, out := &in.Reason, &out.Reason
+		*out = new(string)
+		**out = **in
+	}
+	if in.Message != nil {
+		in, out := &in.Message, &out.Message
+		*out = new(string)
+		**out = **in
+	}
+	if in.LastHeartbeatTime != nil {
+		in, out := &in.LastHeartbeatTime, &out.LastHeartbeatTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderCondition.
+func (in *ProviderCondition) DeepCopy() *ProviderCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderInventory) DeepCopyInto(out *ProviderInventory) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderInventory.
+func (in *ProviderInventory) DeepCopy() *ProviderInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderList) DeepCopyInto(out *ProviderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Provider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVolumeItem.
-func (in *DataVolumeItem) DeepCopy() *DataVolumeItem {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderList.
+func (in *ProviderList) DeepCopy() *ProviderList {
 	if in == nil {
 		return nil
 	}
-	out := new(DataVolumeItem)
+	out := new(ProviderList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkResourceMappingItem) DeepCopyInto(out *NetworkResourceMappingItem) {
+func (in *ProviderSpec) DeepCopyInto(out *ProviderSpec) {
 	*out = *in
-	in.Source.DeepCopyInto(&out.Source)
-	in.Target.DeepCopyInto(&out.Target)
-	if in.Type != nil {
-		in, out := &in.Type, &out.Type
-		*out = new(string)
-		**out = **in
+	in.SecretRef.DeepCopyInto(&out.SecretRef)
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ProviderTLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TransferNetwork != nil {
+		in, out := &in.TransferNetwork, &out.TransferNetwork
+		*out = new(ObjectIdentifier)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkResourceMappingItem.
-func (in *NetworkResourceMappingItem) DeepCopy() *NetworkResourceMappingItem {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderSpec.
+func (in *ProviderSpec) DeepCopy() *ProviderSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkResourceMappingItem)
+	out := new(ProviderSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectIdentifier) DeepCopyInto(out *ObjectIdentifier) {
+func (in *ProviderStatus) DeepCopyInto(out *ProviderStatus) {
 	*out = *in
-	if in.Namespace != nil {
-		in, out := &in.Namespace, &out.Namespace
-		*out = new(string)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ProviderCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Inventory != nil {
+		in, out := &in.Inventory, &out.Inventory
+		*out = new(ProviderInventory)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectIdentifier.
-func (in *ObjectIdentifier) DeepCopy() *ObjectIdentifier {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderStatus.
+func (in *ProviderStatus) DeepCopy() *ProviderStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectIdentifier)
+	out := new(ProviderStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OvirtMappings) DeepCopyInto(out *OvirtMappings) {
+func (in *ProviderTLSSpec) DeepCopyInto(out *ProviderTLSSpec) {
 	*out = *in
-	if in.NetworkMappings != nil {
-		in, out := &in.NetworkMappings, &out.NetworkMappings
-		*out = new([]NetworkResourceMappingItem)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]NetworkResourceMappingItem, len(*in))
-			for i := range *in {
-				(*in)[i].DeepCopyInto(&(*out)[i])
-			}
-		}
+	if in.CACert != nil {
+		in, out := &in.CACert, &out.CACert
+		*out = new(string)
+		**out = **in
 	}
-	if in.StorageMappings != nil {
-		in, out := &in.StorageMappings, &out.StorageMappings
-		*out = new([]StorageResourceMappingItem)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]StorageResourceMappingItem, len(*in))
-			for i := range *in {
-				(*in)[i].DeepCopyInto(&(*out)[i])
-			}
-		}
+	if in.CABundleRef != nil {
+		in, out := &in.CABundleRef, &out.CABundleRef
+		*out = new(CABundleReference)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.DiskMappings != nil {
-		in, out := &in.DiskMappings, &out.DiskMappings
-		*out = new([]StorageResourceMappingItem)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]StorageResourceMappingItem, len(*in))
-			for i := range *in {
-				(*in)[i].DeepCopyInto(&(*out)[i])
-			}
-		}
+	if in.Thumbprint != nil {
+		in, out := &in.Thumbprint, &out.Thumbprint
+		*out = new(string)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OvirtMappings.
-func (in *OvirtMappings) DeepCopy() *OvirtMappings {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderTLSSpec.
+func (in *ProviderTLSSpec) DeepCopy() *ProviderTLSSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OvirtMappings)
+	out := new(ProviderTLSSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -262,6 +786,56 @@ func (in *Source) DeepCopy() *Source {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceShutdownSpec) DeepCopyInto(out *SourceShutdownSpec) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Method != nil {
+		in, out := &in.Method, &out.Method
+		*out = new(SourceShutdownMethod)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceShutdownSpec.
+func (in *SourceShutdownSpec) DeepCopy() *SourceShutdownSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceShutdownSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceShutdownStatus) DeepCopyInto(out *SourceShutdownStatus) {
+	*out = *in
+	if in.RequestedAt != nil {
+		in, out := &in.RequestedAt, &out.RequestedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ForcedAt != nil {
+		in, out := &in.ForcedAt, &out.ForcedAt
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceShutdownStatus.
+func (in *SourceShutdownStatus) DeepCopy() *SourceShutdownStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceShutdownStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageResourceMappingItem) DeepCopyInto(out *StorageResourceMappingItem) {
 	*out = *in
@@ -277,6 +851,11 @@ func (in *StorageResourceMappingItem) DeepCopyInto(out *StorageResourceMappingIt
 		*out = new(v1.PersistentVolumeAccessMode)
 		**out = **in
 	}
+	if in.Preallocation != nil {
+		in, out := &in.Preallocation, &out.Preallocation
+		*out = new(PreallocationMode)
+		**out = **in
+	}
 	return
 }
 
@@ -385,6 +964,43 @@ func (in *VMImportConfigStatus) DeepCopy() *VMImportConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineHardwareSpec) DeepCopyInto(out *VirtualMachineHardwareSpec) {
+	*out = *in
+	if in.Sockets != nil {
+		in, out := &in.Sockets, &out.Sockets
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Cores != nil {
+		in, out := &in.Cores, &out.Cores
+		*out = new(int32)
+		**out = **in
+	}
+	out.Memory = in.Memory.DeepCopy()
+	if in.Firmware != nil {
+		in, out := &in.Firmware, &out.Firmware
+		*out = new(FirmwareType)
+		**out = **in
+	}
+	if in.OperatingSystem != nil {
+		in, out := &in.OperatingSystem, &out.OperatingSystem
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineHardwareSpec.
+func (in *VirtualMachineHardwareSpec) DeepCopy() *VirtualMachineHardwareSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineHardwareSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineImport) DeepCopyInto(out *VirtualMachineImport) {
 	*out = *in
@@ -484,11 +1100,21 @@ func (in *VirtualMachineImportList) DeepCopyObject() runtime.Object {
 func (in *VirtualMachineImportOvirtSourceSpec) DeepCopyInto(out *VirtualMachineImportOvirtSourceSpec) {
 	*out = *in
 	in.VM.DeepCopyInto(&out.VM)
+	if in.VMSelector != nil {
+		in, out := &in.VMSelector, &out.VMSelector
+		*out = new(VirtualMachineImportOvirtSourceVMSelectorSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Mappings != nil {
 		in, out := &in.Mappings, &out.Mappings
 		*out = new(OvirtMappings)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GuestConversion != nil {
+		in, out := &in.GuestConversion, &out.GuestConversion
+		*out = new(GuestConversionPolicy)
+		**out = **in
+	}
 	return
 }
 
@@ -528,6 +1154,27 @@ func (in *VirtualMachineImportOvirtSourceVMClusterSpec) DeepCopy() *VirtualMachi
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineImportOvirtSourceVMSelectorSpec) DeepCopyInto(out *VirtualMachineImportOvirtSourceVMSelectorSpec) {
+	*out = *in
+	if in.TargetVMNamePattern != nil {
+		in, out := &in.TargetVMNamePattern, &out.TargetVMNamePattern
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineImportOvirtSourceVMSelectorSpec.
+func (in *VirtualMachineImportOvirtSourceVMSelectorSpec) DeepCopy() *VirtualMachineImportOvirtSourceVMSelectorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineImportOvirtSourceVMSelectorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineImportOvirtSourceVMSpec) DeepCopyInto(out *VirtualMachineImportOvirtSourceVMSpec) {
 	*out = *in
@@ -559,6 +1206,39 @@ func (in *VirtualMachineImportOvirtSourceVMSpec) DeepCopy() *VirtualMachineImpor
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineImportPVCSourceSpec) DeepCopyInto(out *VirtualMachineImportPVCSourceSpec) {
+	*out = *in
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]PVCDisk, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Hardware != nil {
+		in, out := &in.Hardware, &out.Hardware
+		*out = new(VirtualMachineHardwareSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SourceVM != nil {
+		in, out := &in.SourceVM, &out.SourceVM
+		*out = new(PVCSourceVMSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineImportPVCSourceSpec.
+func (in *VirtualMachineImportPVCSourceSpec) DeepCopy() *VirtualMachineImportPVCSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineImportPVCSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineImportSourceSpec) DeepCopyInto(out *VirtualMachineImportSourceSpec) {
 	*out = *in
@@ -572,6 +1252,11 @@ func (in *VirtualMachineImportSourceSpec) DeepCopyInto(out *VirtualMachineImport
 		*out = new(VirtualMachineImportVmwareSourceSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(VirtualMachineImportPVCSourceSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -588,7 +1273,16 @@ func (in *VirtualMachineImportSourceSpec) DeepCopy() *VirtualMachineImportSource
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineImportSpec) DeepCopyInto(out *VirtualMachineImportSpec) {
 	*out = *in
-	in.ProviderCredentialsSecret.DeepCopyInto(&out.ProviderCredentialsSecret)
+	if in.ProviderCredentialsSecret != nil {
+		in, out := &in.ProviderCredentialsSecret, &out.ProviderCredentialsSecret
+		*out = new(ObjectIdentifier)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Provider != nil {
+		in, out := &in.Provider, &out.Provider
+		*out = new(ObjectIdentifier)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ResourceMapping != nil {
 		in, out := &in.ResourceMapping, &out.ResourceMapping
 		*out = new(ObjectIdentifier)
@@ -609,6 +1303,26 @@ func (in *VirtualMachineImportSpec) DeepCopyInto(out *VirtualMachineImportSpec)
 		*out = new(bool)
 		**out = **in
 	}
+	if in.SourceShutdown != nil {
+		in, out := &in.SourceShutdown, &out.SourceShutdown
+		*out = new(SourceShutdownSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConversionPod != nil {
+		in, out := &in.ConversionPod, &out.ConversionPod
+		*out = new(ConversionPodSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GuestCustomization != nil {
+		in, out := &in.GuestCustomization, &out.GuestCustomization
+		*out = new(GuestCustomizationSpec)
+		**out = **in
+	}
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = new(DiskSelectionSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -635,9 +1349,59 @@ func (in *VirtualMachineImportStatus) DeepCopyInto(out *VirtualMachineImportStat
 	if in.DataVolumes != nil {
 		in, out := &in.DataVolumes, &out.DataVolumes
 		*out = make([]DataVolumeItem, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	in.WarmImport.DeepCopyInto(&out.WarmImport)
+	if in.SourceSnapshot != nil {
+		in, out := &in.SourceSnapshot, &out.SourceSnapshot
+		*out = new(string)
+		**out = **in
+	}
+	if in.SourceShutdown != nil {
+		in, out := &in.SourceShutdown, &out.SourceShutdown
+		*out = new(SourceShutdownStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VirtualMachineImports != nil {
+		in, out := &in.VirtualMachineImports, &out.VirtualMachineImports
+		*out = make([]ObjectIdentifier, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PhaseTransitions != nil {
+		in, out := &in.PhaseTransitions, &out.PhaseTransitions
+		*out = make([]PhaseTransition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EstimatedCompletionTime != nil {
+		in, out := &in.EstimatedCompletionTime, &out.EstimatedCompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.GuestInspection != nil {
+		in, out := &in.GuestInspection, &out.GuestInspection
+		*out = new(GuestInspection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConversionLog != nil {
+		in, out := &in.ConversionLog, &out.ConversionLog
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.GuestCustomization != nil {
+		in, out := &in.GuestCustomization, &out.GuestCustomization
+		*out = new(GuestCustomizationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GuestConversion != nil {
+		in, out := &in.GuestConversion, &out.GuestConversion
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -681,11 +1445,41 @@ func (in *VirtualMachineImportVmwareSourceVMSpec) DeepCopyInto(out *VirtualMachi
 		*out = new(string)
 		**out = **in
 	}
+	if in.UUIDType != nil {
+		in, out := &in.UUIDType, &out.UUIDType
+		*out = new(VmwareUUIDType)
+		**out = **in
+	}
+	if in.MoRef != nil {
+		in, out := &in.MoRef, &out.MoRef
+		*out = new(string)
+		**out = **in
+	}
 	if in.Name != nil {
 		in, out := &in.Name, &out.Name
 		*out = new(string)
 		**out = **in
 	}
+	if in.Datacenter != nil {
+		in, out := &in.Datacenter, &out.Datacenter
+		*out = new(string)
+		**out = **in
+	}
+	if in.Cluster != nil {
+		in, out := &in.Cluster, &out.Cluster
+		*out = new(string)
+		**out = **in
+	}
+	if in.Folder != nil {
+		in, out := &in.Folder, &out.Folder
+		*out = new(string)
+		**out = **in
+	}
+	if in.ResourcePool != nil {
+		in, out := &in.ResourcePool, &out.ResourcePool
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -706,6 +1500,10 @@ func (in *VirtualMachineWarmImportStatus) DeepCopyInto(out *VirtualMachineWarmIm
 		in, out := &in.NextStageTime, &out.NextStageTime
 		*out = (*in).DeepCopy()
 	}
+	if in.StageStartTime != nil {
+		in, out := &in.StageStartTime, &out.StageStartTime
+		*out = (*in).DeepCopy()
+	}
 	if in.RootSnapshot != nil {
 		in, out := &in.RootSnapshot, &out.RootSnapshot
 		*out = new(string)
@@ -760,6 +1558,17 @@ func (in *VmwareMappings) DeepCopyInto(out *VmwareMappings) {
 			}
 		}
 	}
+	if in.CdromMappings != nil {
+		in, out := &in.CdromMappings, &out.CdromMappings
+		*out = new([]CdromResourceMappingItem)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]CdromResourceMappingItem, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
 	return
 }
 
@@ -0,0 +1,287 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// KeepAliveInterval is how often the cached clients are checked and their sessions kept alive
+	KeepAliveInterval = 1 * time.Minute
+	// IdleTimeout is how long an unused client is kept in the cache before it is closed
+	IdleTimeout = 10 * time.Minute
+
+	ovirtClientType  = "ovirt"
+	vmwareClientType = "vmware"
+)
+
+var log = logf.Log.WithName("client-cache")
+
+// keepAliver is implemented by the clients that can refresh their session, logging in again if it has expired
+type keepAliver interface {
+	KeepAlive() error
+}
+
+// CachingClientFactory shares the source clients across reconciles instead of logging in to the source provider
+// every time. Clients are keyed by the provider type, the API URL and the username, so a change to the credentials
+// Secret replaces the cached client with a new one. The cached clients are kept alive and closed once idle by Start.
+// The lock only guards the maps: connecting, probing and closing the clients happen outside of it, so a slow or
+// unreachable source provider doesn't block the reconciles of the other ones. Only the clients that aren't leased are
+// probed, and the leases of a client wait until its probe is done.
+type CachingClientFactory struct {
+	factory           Factory
+	keepAliveInterval time.Duration
+	idleTimeout       time.Duration
+
+	lock sync.Mutex
+	// clients holds the current client of each source provider endpoint and user
+	clients map[string]*cachedClient
+	// retired holds the clients that were replaced or failed while still in use
+	retired map[VMClient]*cachedClient
+	// connecting holds the connections in progress, which the concurrent leases of the same key wait for
+	connecting map[string]*connection
+}
+
+type cachedClient struct {
+	client      VMClient
+	credentials string
+	leases      int
+	lastUsed    time.Time
+	// probing is closed once the keep alive check of the client is done, it is nil when the client isn't checked
+	probing chan struct{}
+}
+
+type connection struct {
+	credentials string
+	done        chan struct{}
+	err         error
+}
+
+// NewCachingClientFactory creates a client cache backed by the given factory
+func NewCachingClientFactory(factory Factory) *CachingClientFactory {
+	return &CachingClientFactory{
+		factory:           factory,
+		keepAliveInterval: KeepAliveInterval,
+		idleTimeout:       IdleTimeout,
+		clients:           make(map[string]*cachedClient),
+		retired:           make(map[VMClient]*cachedClient),
+		connecting:        make(map[string]*connection),
+	}
+}
+
+// NewOvirtClient returns the cached oVirt client for the given connection details, connecting if there is none
+func (f *CachingClientFactory) NewOvirtClient(dataMap map[string]string) (OvirtClient, error) {
+	client, err := f.lease(ovirtClientType, dataMap, func(dataMap map[string]string) (VMClient, error) {
+		return f.factory.NewOvirtClient(dataMap)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// only oVirt clients are cached under the oVirt client type
+	return client.(OvirtClient), nil
+}
+
+// NewVmwareClient returns the cached VMware client for the given connection details, connecting if there is none
+func (f *CachingClientFactory) NewVmwareClient(dataMap map[string]string) (VMClient, error) {
+	return f.lease(vmwareClientType, dataMap, f.factory.NewVmwareClient)
+}
+
+// Release returns the client to the cache. Clients that were replaced while in use are closed.
+func (f *CachingClientFactory) Release(client VMClient) error {
+	f.lock.Lock()
+	if entry, found := f.retired[client]; found {
+		entry.leases--
+		if entry.leases > 0 {
+			f.lock.Unlock()
+			return nil
+		}
+		delete(f.retired, client)
+		f.lock.Unlock()
+		return client.Close()
+	}
+	for _, entry := range f.clients {
+		if entry.client == client {
+			entry.leases--
+			entry.lastUsed = time.Now()
+			f.lock.Unlock()
+			return nil
+		}
+	}
+	f.lock.Unlock()
+	// the client doesn't come from this cache
+	return client.Close()
+}
+
+// Start keeps the cached clients alive until the stop channel is closed, then closes them.
+// It implements the controller-runtime manager.Runnable interface.
+func (f *CachingClientFactory) Start(stop <-chan struct{}) error {
+	ticker := time.NewTicker(f.keepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			f.keepAlive()
+		case <-stop:
+			f.closeAll()
+			return nil
+		}
+	}
+}
+
+func (f *CachingClientFactory) lease(clientType string, dataMap map[string]string, connect func(map[string]string) (VMClient, error)) (VMClient, error) {
+	key := clientType + "|" + dataMap["apiUrl"] + "|" + dataMap["username"]
+	credentials, err := hash(dataMap)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		f.lock.Lock()
+		var retired VMClient
+		if entry, found := f.clients[key]; found {
+			if probing := entry.probing; probing != nil {
+				f.lock.Unlock()
+				// lease the client once it is checked, or connect again if it was dropped
+				<-probing
+				continue
+			}
+			if entry.credentials == credentials {
+				entry.leases++
+				entry.lastUsed = time.Now()
+				f.lock.Unlock()
+				return entry.client, nil
+			}
+			// the credentials Secret has changed
+			retired = f.retire(key, entry)
+		}
+		if pending, found := f.connecting[key]; found {
+			f.lock.Unlock()
+			f.close(key, retired)
+			<-pending.done
+			if pending.credentials == credentials && pending.err != nil {
+				return nil, pending.err
+			}
+			// lease the client the other connection cached, or connect with other credentials
+			continue
+		}
+		pending := &connection{credentials: credentials, done: make(chan struct{})}
+		f.connecting[key] = pending
+		f.lock.Unlock()
+		f.close(key, retired)
+
+		client, err := connect(dataMap)
+
+		f.lock.Lock()
+		delete(f.connecting, key)
+		if err == nil {
+			f.clients[key] = &cachedClient{
+				client:      client,
+				credentials: credentials,
+				leases:      1,
+				lastUsed:    time.Now(),
+			}
+		}
+		pending.err = err
+		close(pending.done)
+		f.lock.Unlock()
+		return client, err
+	}
+}
+
+// keepAlive closes the idle clients and checks the ones that aren't leased, dropping the ones that can't reach the
+// source provider anymore so that the next reconcile logs in again. The leased clients are in use by the reconciles,
+// which keeps their sessions alive.
+func (f *CachingClientFactory) keepAlive() {
+	f.lock.Lock()
+	idle := make(map[string]VMClient)
+	unused := make(map[string]*cachedClient)
+	for key, entry := range f.clients {
+		if entry.leases > 0 {
+			continue
+		}
+		if time.Since(entry.lastUsed) > f.idleTimeout {
+			idle[key] = f.retire(key, entry)
+			continue
+		}
+		entry.probing = make(chan struct{})
+		unused[key] = entry
+	}
+	f.lock.Unlock()
+
+	for key, client := range idle {
+		f.close(key, client)
+	}
+	for key, entry := range unused {
+		var err error
+		if client, ok := entry.client.(keepAliver); ok {
+			err = client.KeepAlive()
+		} else {
+			err = entry.client.TestConnection()
+		}
+
+		f.lock.Lock()
+		var retired VMClient
+		if err != nil {
+			log.Info("Dropping the source provider client", "Key", key, "Error", err.Error())
+			retired = f.retire(key, entry)
+		}
+		close(entry.probing)
+		entry.probing = nil
+		f.lock.Unlock()
+		f.close(key, retired)
+	}
+}
+
+// retire removes the client from the cache and returns it to be closed, or defers closing it until it is released.
+// It must be called with the lock held.
+func (f *CachingClientFactory) retire(key string, entry *cachedClient) VMClient {
+	delete(f.clients, key)
+	if entry.leases > 0 {
+		f.retired[entry.client] = entry
+		return nil
+	}
+	return entry.client
+}
+
+// close closes the retired client, if any
+func (f *CachingClientFactory) close(key string, client VMClient) {
+	if client == nil {
+		return
+	}
+	if err := client.Close(); err != nil {
+		log.Error(err, "Failed to close the source provider client", "Key", key)
+	}
+}
+
+func (f *CachingClientFactory) closeAll() {
+	f.lock.Lock()
+	clients := make([]VMClient, 0, len(f.clients)+len(f.retired))
+	for key, entry := range f.clients {
+		delete(f.clients, key)
+		clients = append(clients, entry.client)
+	}
+	for client := range f.retired {
+		delete(f.retired, client)
+		clients = append(clients, client)
+	}
+	f.lock.Unlock()
+
+	for _, client := range clients {
+		_ = client.Close()
+	}
+}
+
+func hash(dataMap map[string]string) (string, error) {
+	// map keys are sorted when marshalled, which makes the hash stable
+	data, err := json.Marshal(dataMap)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,293 @@
+package client_test
+
+import (
+	"fmt"
+	"sync"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	pclient "github.com/kubevirt/vm-import-operator/pkg/client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	ovirtsdk "github.com/ovirt/go-ovirt"
+)
+
+var _ = Describe("Caching client factory", func() {
+	var (
+		factory *fakeFactory
+		cache   *pclient.CachingClientFactory
+	)
+	dataMap := map[string]string{"apiUrl": "https://engine/api", "username": "admin", "password": "secret"}
+
+	BeforeEach(func() {
+		factory = &fakeFactory{}
+		cache = pclient.NewCachingClientFactory(factory)
+	})
+
+	It("should share a client across reconciles", func() {
+		first, err := cache.NewOvirtClient(dataMap)
+		Expect(err).To(BeNil())
+		Expect(cache.Release(first)).To(Succeed())
+
+		second, err := cache.NewOvirtClient(dataMap)
+		Expect(err).To(BeNil())
+
+		Expect(second).To(BeIdenticalTo(first))
+		Expect(factory.created).To(HaveLen(1))
+		Expect(first.(*fakeClient).closed).To(BeFalse())
+	})
+
+	It("should not share a client between provider types", func() {
+		ovirtClient, err := cache.NewOvirtClient(dataMap)
+		Expect(err).To(BeNil())
+		vmwareClient, err := cache.NewVmwareClient(dataMap)
+		Expect(err).To(BeNil())
+
+		Expect(vmwareClient).ToNot(BeIdenticalTo(ovirtClient))
+	})
+
+	It("should replace the client when the credentials change", func() {
+		first, err := cache.NewOvirtClient(dataMap)
+		Expect(err).To(BeNil())
+		Expect(cache.Release(first)).To(Succeed())
+
+		rotated := map[string]string{"apiUrl": "https://engine/api", "username": "admin", "password": "rotated"}
+		second, err := cache.NewOvirtClient(rotated)
+		Expect(err).To(BeNil())
+
+		Expect(second).ToNot(BeIdenticalTo(first))
+		Expect(first.(*fakeClient).closed).To(BeTrue())
+	})
+
+	It("should close a replaced client once it is released", func() {
+		first, err := cache.NewOvirtClient(dataMap)
+		Expect(err).To(BeNil())
+
+		rotated := map[string]string{"apiUrl": "https://engine/api", "username": "admin", "password": "rotated"}
+		_, err = cache.NewOvirtClient(rotated)
+		Expect(err).To(BeNil())
+		Expect(first.(*fakeClient).closed).To(BeFalse())
+
+		Expect(cache.Release(first)).To(Succeed())
+		Expect(first.(*fakeClient).closed).To(BeTrue())
+	})
+
+	It("should not cache a client that failed to connect", func() {
+		factory.err = fmt.Errorf("connection refused")
+		_, err := cache.NewOvirtClient(dataMap)
+		Expect(err).To(HaveOccurred())
+
+		factory.err = nil
+		_, err = cache.NewOvirtClient(dataMap)
+		Expect(err).To(BeNil())
+		Expect(factory.created).To(HaveLen(1))
+	})
+
+	It("should connect once for concurrent leases of the same client", func() {
+		blocked := make(chan struct{})
+		factory.blocked = map[string]chan struct{}{dataMap["apiUrl"]: blocked}
+
+		cache := cache
+		clients := make(chan pclient.VMClient, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				defer GinkgoRecover()
+				client, err := cache.NewOvirtClient(dataMap)
+				Expect(err).To(BeNil())
+				clients <- client
+			}()
+		}
+		close(blocked)
+
+		first, second := <-clients, <-clients
+		Expect(second).To(BeIdenticalTo(first))
+		Expect(factory.created).To(HaveLen(1))
+	})
+
+	It("should not wait for the connection to another source provider", func() {
+		blocked := make(chan struct{})
+		factory.blocked = map[string]chan struct{}{"https://unreachable/api": blocked}
+		cache := cache
+		unblocked := make(chan struct{})
+		go func() {
+			_, _ = cache.NewOvirtClient(map[string]string{"apiUrl": "https://unreachable/api", "username": "admin"})
+			close(unblocked)
+		}()
+
+		connected := make(chan error)
+		go func() {
+			_, err := cache.NewOvirtClient(dataMap)
+			connected <- err
+		}()
+
+		Eventually(connected).Should(Receive(BeNil()))
+		close(blocked)
+		<-unblocked
+	})
+
+	It("should wait for the keep alive check of the client before leasing it", func() {
+		first, err := cache.NewOvirtClient(dataMap)
+		Expect(err).To(BeNil())
+		Expect(cache.Release(first)).To(Succeed())
+		probing, probed := make(chan struct{}), make(chan struct{})
+		first.(*fakeClient).probing = probing
+		first.(*fakeClient).probed = probed
+
+		cache := cache
+		checked := make(chan struct{})
+		go func() {
+			cache.KeepAlive()
+			close(checked)
+		}()
+		<-probing
+		leased := make(chan pclient.VMClient)
+		go func() {
+			defer GinkgoRecover()
+			client, err := cache.NewOvirtClient(dataMap)
+			Expect(err).To(BeNil())
+			// the lease must not overlap with the session refresh
+			_ = client.(*fakeClient).sessions
+			leased <- client
+		}()
+
+		Consistently(leased).ShouldNot(Receive())
+		close(probed)
+		Eventually(leased).Should(Receive(BeIdenticalTo(first)))
+		<-checked
+		Expect(factory.created).To(HaveLen(1))
+	})
+
+	It("should connect again when the keep alive check of the client fails", func() {
+		first, err := cache.NewOvirtClient(dataMap)
+		Expect(err).To(BeNil())
+		Expect(cache.Release(first)).To(Succeed())
+		first.(*fakeClient).keepAliveErr = fmt.Errorf("session expired")
+
+		cache.KeepAlive()
+
+		second, err := cache.NewOvirtClient(dataMap)
+		Expect(err).To(BeNil())
+		Expect(second).ToNot(BeIdenticalTo(first))
+		Expect(first.(*fakeClient).closed).To(BeTrue())
+	})
+
+	It("should not check the leased clients", func() {
+		client, err := cache.NewOvirtClient(dataMap)
+		Expect(err).To(BeNil())
+
+		cache.KeepAlive()
+
+		Expect(client.(*fakeClient).sessions).To(BeZero())
+	})
+
+	It("should close the cached clients when stopped", func() {
+		client, err := cache.NewOvirtClient(dataMap)
+		Expect(err).To(BeNil())
+
+		stop := make(chan struct{})
+		close(stop)
+		Expect(cache.Start(stop)).To(Succeed())
+
+		Expect(client.(*fakeClient).closed).To(BeTrue())
+	})
+})
+
+type fakeFactory struct {
+	lock    sync.Mutex
+	created []*fakeClient
+	err     error
+	// blocked makes the connections to the API URL wait until it is closed
+	blocked map[string]chan struct{}
+}
+
+func (f *fakeFactory) NewOvirtClient(dataMap map[string]string) (pclient.OvirtClient, error) {
+	if blocked, found := f.blocked[dataMap["apiUrl"]]; found {
+		<-blocked
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.newClient(), nil
+}
+
+func (f *fakeFactory) NewVmwareClient(dataMap map[string]string) (pclient.VMClient, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.newClient(), nil
+}
+
+func (f *fakeFactory) Release(client pclient.VMClient) error {
+	return client.Close()
+}
+
+func (f *fakeFactory) newClient() *fakeClient {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	client := &fakeClient{}
+	f.created = append(f.created, client)
+	return client
+}
+
+type fakeClient struct {
+	closed bool
+	// sessions counts the keep alive checks of the client
+	sessions     int
+	keepAliveErr error
+	// probing is closed when a keep alive check starts, which then waits until probed is closed
+	probing chan struct{}
+	probed  chan struct{}
+}
+
+func (c *fakeClient) KeepAlive() error {
+	if c.probing != nil {
+		close(c.probing)
+		<-c.probed
+	}
+	c.sessions++
+	return c.keepAliveErr
+}
+
+func (c *fakeClient) TestConnection() error {
+	return nil
+}
+
+func (c *fakeClient) GetVM(id *string, name *string, cluster *string, clusterID *string) (interface{}, error) {
+	return nil, nil
+}
+
+func (c *fakeClient) StopVM(id string, method v2vv1.SourceShutdownMethod) error {
+	return nil
+}
+
+func (c *fakeClient) StartVM(id string) error {
+	return nil
+}
+
+func (c *fakeClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+func (c *fakeClient) ListVMs(search string) ([]*ovirtsdk.Vm, error) {
+	return nil, nil
+}
+
+func (c *fakeClient) GetDisk(id string) (*ovirtsdk.Disk, error) {
+	return nil, nil
+}
+
+func (c *fakeClient) GetEngineCA() (string, error) {
+	return "", nil
+}
+
+func (c *fakeClient) CreateVMSnapshot(vmID string, description string) (string, error) {
+	return "", nil
+}
+
+func (c *fakeClient) GetVMSnapshot(vmID string, snapshotID string) (*ovirtsdk.Snapshot, error) {
+	return nil, nil
+}
+
+func (c *fakeClient) RemoveVMSnapshot(vmID string, snapshotID string) error {
+	return nil
+}
@@ -1,25 +1,45 @@
 package client
 
 import (
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
 	ovirtclient "github.com/kubevirt/vm-import-operator/pkg/providers/ovirt/client"
 	vmwareclient "github.com/kubevirt/vm-import-operator/pkg/providers/vmware/client"
+	ovirtsdk "github.com/ovirt/go-ovirt"
 )
 
-// Factory creates new clients
+// Factory creates new clients. The clients are released with Release instead of being closed, because they may be shared.
 type Factory interface {
-	NewOvirtClient(dataMap map[string]string) (VMClient, error)
+	NewOvirtClient(dataMap map[string]string) (OvirtClient, error)
 	NewVmwareClient(dataMap map[string]string) (VMClient, error)
+	Release(client VMClient) error
 }
 
 // VMClient provides interface how source virtual machines should be fetched
 type VMClient interface {
 	TestConnection() error
 	GetVM(id *string, name *string, cluster *string, clusterID *string) (interface{}, error)
-	StopVM(id string) error
+	StopVM(id string, method v2vv1.SourceShutdownMethod) error
 	StartVM(id string) error
 	Close() error
 }
 
+// OvirtClient provides the oVirt specific operations on top of VMClient
+type OvirtClient interface {
+	VMClient
+	ListVMs(search string) ([]*ovirtsdk.Vm, error)
+	GetDisk(id string) (*ovirtsdk.Disk, error)
+	GetEngineCA() (string, error)
+	CreateVMSnapshot(vmID string, description string) (string, error)
+	GetVMSnapshot(vmID string, snapshotID string) (*ovirtsdk.Snapshot, error)
+	RemoveVMSnapshot(vmID string, snapshotID string) error
+}
+
+// InventoryClient provides interface how the source provider is described
+type InventoryClient interface {
+	GetVersion() (string, error)
+	GetInventory() (*v2vv1.ProviderInventory, error)
+}
+
 // SourceClientFactory provides default client factory implementation
 type SourceClientFactory struct{}
 
@@ -29,12 +49,13 @@ func NewSourceClientFactory() *SourceClientFactory {
 }
 
 // NewOvirtClient creates new Ovirt clients
-func (f *SourceClientFactory) NewOvirtClient(dataMap map[string]string) (VMClient, error) {
+func (f *SourceClientFactory) NewOvirtClient(dataMap map[string]string) (OvirtClient, error) {
 	return ovirtclient.NewRichOvirtClient(&ovirtclient.ConnectionSettings{
-		URL:      dataMap["apiUrl"],
-		Username: dataMap["username"],
-		Password: dataMap["password"],
-		CACert:   []byte(dataMap["caCert"]),
+		URL:                dataMap["apiUrl"],
+		Username:           dataMap["username"],
+		Password:           dataMap["password"],
+		CACert:             []byte(dataMap["caCert"]),
+		InsecureSkipVerify: dataMap["insecureSkipVerify"] == "true",
 	})
 }
 
@@ -44,5 +65,14 @@ func (f *SourceClientFactory) NewVmwareClient(dataMap map[string]string) (VMClie
 		dataMap["apiUrl"],
 		dataMap["username"],
 		dataMap["password"],
-		dataMap["thumbprint"])
+		vmwareclient.TLSOptions{
+			Thumbprint:         dataMap["thumbprint"],
+			CACert:             dataMap["caCert"],
+			InsecureSkipVerify: dataMap["insecureSkipVerify"] == "true",
+		})
+}
+
+// Release closes the client
+func (f *SourceClientFactory) Release(client VMClient) error {
+	return client.Close()
 }
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"crypto/x509"
+	"errors"
+
+	vmwareclient "github.com/kubevirt/vm-import-operator/pkg/providers/vmware/client"
+	ovirtsdk "github.com/ovirt/go-ovirt"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// IsAuthenticationError returns whether the source provider rejected the credentials,
+// as opposed to not being reachable at all
+func IsAuthenticationError(err error) bool {
+	var ovirtAuthError *ovirtsdk.AuthError
+	if errors.As(err, &ovirtAuthError) {
+		return true
+	}
+	for ; err != nil; err = errors.Unwrap(err) {
+		if soap.IsSoapFault(err) {
+			if _, ok := soap.ToSoapFault(err).VimFault().(types.InvalidLogin); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsCertificateError returns whether the certificate of the source provider couldn't be verified, either because
+// it isn't signed by a trusted CA, it doesn't match the host name or it doesn't have the pinned thumbprint
+func IsCertificateError(err error) bool {
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	var mismatch vmwareclient.ThumbprintMismatchError
+	return errors.As(err, &unknownAuthority) ||
+		errors.As(err, &hostname) ||
+		errors.As(err, &invalid) ||
+		errors.As(err, &mismatch)
+}
@@ -0,0 +1,90 @@
+package client_test
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/url"
+
+	pclient "github.com/kubevirt/vm-import-operator/pkg/client"
+	vclient "github.com/kubevirt/vm-import-operator/pkg/providers/vmware/client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	ovirtsdk "github.com/ovirt/go-ovirt"
+	"github.com/vmware/govmomi/simulator"
+)
+
+var _ = Describe("Authentication errors", func() {
+	It("should recognize rejected oVirt credentials", func() {
+		err := fmt.Errorf("failed to connect: %w", &ovirtsdk.AuthError{})
+
+		Expect(pclient.IsAuthenticationError(err)).To(BeTrue())
+	})
+
+	It("should recognize rejected VMware credentials", func() {
+		model := simulator.VPX()
+		defer model.Remove()
+		Expect(model.Create()).To(Succeed())
+		model.Service.Listen = &url.URL{User: url.UserPassword("administrator@vsphere.local", "secret")}
+		server := model.Service.NewServer()
+		defer server.Close()
+		apiURL := *server.URL
+		apiURL.User = nil
+
+		_, err := vclient.NewRichVMWareClient(apiURL.String(), "administrator@vsphere.local", "wrong", vclient.TLSOptions{})
+
+		Expect(err).ToNot(BeNil())
+		Expect(pclient.IsAuthenticationError(err)).To(BeTrue())
+	})
+
+	It("should not mistake a connection failure for an authentication failure", func() {
+		_, err := vclient.NewRichVMWareClient((&url.URL{Scheme: "https", Host: "127.0.0.1:1", Path: "/sdk"}).String(), "user", "password", vclient.TLSOptions{})
+
+		Expect(err).ToNot(BeNil())
+		Expect(pclient.IsAuthenticationError(err)).To(BeFalse())
+	})
+
+	Describe("certificate errors", func() {
+		var (
+			model  *simulator.Model
+			server *simulator.Server
+		)
+
+		BeforeEach(func() {
+			model = simulator.VPX()
+			Expect(model.Create()).To(Succeed())
+			model.Service.TLS = new(tls.Config)
+			server = model.Service.NewServer()
+		})
+
+		AfterEach(func() {
+			server.Close()
+			model.Remove()
+		})
+
+		connect := func(options vclient.TLSOptions) error {
+			username := server.URL.User.Username()
+			password, _ := server.URL.User.Password()
+			_, err := vclient.NewRichVMWareClient(server.URL.String(), username, password, options)
+			return err
+		}
+
+		It("should recognize a certificate that isn't trusted", func() {
+			err := connect(vclient.TLSOptions{})
+
+			Expect(pclient.IsCertificateError(err)).To(BeTrue())
+			Expect(pclient.IsAuthenticationError(err)).To(BeFalse())
+		})
+
+		It("should recognize a certificate with another thumbprint", func() {
+			err := connect(vclient.TLSOptions{Thumbprint: "21:EA:74:11:59:89:5E:20:D5:D9:A2:39:5C:6A:2D:36:38:B2:52:2B"})
+
+			Expect(pclient.IsCertificateError(err)).To(BeTrue())
+		})
+
+		It("should not mistake rejected credentials for a certificate error", func() {
+			err := fmt.Errorf("failed to connect: %w", &ovirtsdk.AuthError{})
+
+			Expect(pclient.IsCertificateError(err)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,6 @@
+package client
+
+// KeepAlive runs a keep alive check of the cached clients
+func (f *CachingClientFactory) KeepAlive() {
+	f.keepAlive()
+}
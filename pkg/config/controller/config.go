@@ -3,7 +3,11 @@ package controller
 import (
 	"strconv"
 
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
 	"github.com/kubevirt/vm-import-operator/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -25,8 +29,46 @@ const (
 	// ImportWithoutTemplateKey defines whether imports are permitted to run if an Openshift VM template can't be found.
 	ImportWithoutTemplateKey         = "importWithoutTemplate"
 	importWithoutTemplateDefault     = false
+
+	// ConversionPodImageKey defines the image of the virt-v2v guest conversion pod
+	ConversionPodImageKey = "conversionPod.image"
+	// ConversionPodNodeSelectorKey defines the node selector, in YAML, added to the one of the guest conversion pod
+	ConversionPodNodeSelectorKey = "conversionPod.nodeSelector"
+	// ConversionPodTolerationsKey defines the tolerations, in YAML, of the guest conversion pod
+	ConversionPodTolerationsKey = "conversionPod.tolerations"
+	// ConversionPodResourcesKey defines the resource requirements, in YAML, of the guest conversion pod
+	ConversionPodResourcesKey = "conversionPod.resources"
+	// ConversionPodPriorityClassNameKey defines the priority class of the guest conversion pod
+	ConversionPodPriorityClassNameKey = "conversionPod.priorityClassName"
+	// ConversionPodServiceAccountNameKey defines the service account of the guest conversion pod
+	ConversionPodServiceAccountNameKey = "conversionPod.serviceAccountName"
+	// ConversionPodAllowedImagesKey defines the images, in YAML, an import may run its guest conversion pod with
+	ConversionPodAllowedImagesKey = "conversionPod.allowedImages"
+	// ConversionPodAllowedServiceAccountNamesKey defines the service accounts, in YAML, an import may run its guest
+	// conversion pod with
+	ConversionPodAllowedServiceAccountNamesKey = "conversionPod.allowedServiceAccountNames"
+	// ConversionPodAllowedNodeSelectorLabelsKey defines the values of each node label, in YAML, an import may select
+	// the nodes of its guest conversion pod with
+	ConversionPodAllowedNodeSelectorLabelsKey = "conversionPod.allowedNodeSelectorLabels"
+	// ConversionPodAllowedTolerationsKey defines the tolerations, in YAML, an import may give its guest conversion pod
+	ConversionPodAllowedTolerationsKey = "conversionPod.allowedTolerations"
+	// ConversionPodAllowedPriorityClassNamesKey defines the priority classes, in YAML, an import may run its guest
+	// conversion pod with
+	ConversionPodAllowedPriorityClassNamesKey = "conversionPod.allowedPriorityClassNames"
 )
 
+var log = logf.Log.WithName("controller-config")
+
+// ConversionPodAllowList holds the images, the service accounts and the placement an import may run its guest
+// conversion pod with, besides the ones of the controller config
+type ConversionPodAllowList struct {
+	Images              []string
+	ServiceAccountNames []string
+	NodeSelectorLabels  map[string][]string
+	Tolerations         []corev1.Toleration
+	PriorityClassNames  []string
+}
+
 // ControllerConfig stores controller runtime configuration
 type ControllerConfig struct {
 	config.Config
@@ -65,6 +107,77 @@ func (c ControllerConfig) ImportWithoutTemplateEnabled() bool {
 	return c.getKeyAsBool(ImportWithoutTemplateKey, importWithoutTemplateDefault)
 }
 
+// ConversionPod provides the settings of the virt-v2v guest conversion pod. Settings that can't be parsed are ignored
+// and logged.
+func (c ControllerConfig) ConversionPod() v2vv1.ConversionPodSpec {
+	spec := v2vv1.ConversionPodSpec{
+		Image:              c.getKeyAsStringPtr(ConversionPodImageKey),
+		PriorityClassName:  c.getKeyAsStringPtr(ConversionPodPriorityClassNameKey),
+		ServiceAccountName: c.getKeyAsStringPtr(ConversionPodServiceAccountNameKey),
+	}
+	var nodeSelector map[string]string
+	if c.getKeyAsYAML(ConversionPodNodeSelectorKey, &nodeSelector) {
+		spec.NodeSelector = nodeSelector
+	}
+	var tolerations []corev1.Toleration
+	if c.getKeyAsYAML(ConversionPodTolerationsKey, &tolerations) {
+		spec.Tolerations = tolerations
+	}
+	var resources corev1.ResourceRequirements
+	if c.getKeyAsYAML(ConversionPodResourcesKey, &resources) {
+		spec.Resources = &resources
+	}
+	return spec
+}
+
+// ConversionPodAllowList provides the images, the service accounts and the placement an import may run its guest
+// conversion pod with. Lists that can't be parsed are ignored and logged.
+func (c ControllerConfig) ConversionPodAllowList() ConversionPodAllowList {
+	var allowList ConversionPodAllowList
+	var images []string
+	if c.getKeyAsYAML(ConversionPodAllowedImagesKey, &images) {
+		allowList.Images = images
+	}
+	var serviceAccountNames []string
+	if c.getKeyAsYAML(ConversionPodAllowedServiceAccountNamesKey, &serviceAccountNames) {
+		allowList.ServiceAccountNames = serviceAccountNames
+	}
+	var nodeSelectorLabels map[string][]string
+	if c.getKeyAsYAML(ConversionPodAllowedNodeSelectorLabelsKey, &nodeSelectorLabels) {
+		allowList.NodeSelectorLabels = nodeSelectorLabels
+	}
+	var tolerations []corev1.Toleration
+	if c.getKeyAsYAML(ConversionPodAllowedTolerationsKey, &tolerations) {
+		allowList.Tolerations = tolerations
+	}
+	var priorityClassNames []string
+	if c.getKeyAsYAML(ConversionPodAllowedPriorityClassNamesKey, &priorityClassNames) {
+		allowList.PriorityClassNames = priorityClassNames
+	}
+	return allowList
+}
+
+func (c ControllerConfig) getKeyAsStringPtr(key string) *string {
+	raw, found := c.ConfigMap.Data[key]
+	if !found || raw == "" {
+		return nil
+	}
+	return &raw
+}
+
+func (c ControllerConfig) getKeyAsYAML(key string, target interface{}) bool {
+	raw := c.ConfigMap.Data[key]
+	if raw == "" {
+		return false
+	}
+	err := yaml.Unmarshal([]byte(raw), target)
+	if err != nil {
+		log.Error(err, "Ignoring a setting of the controller config that can't be parsed", "Key", key)
+		return false
+	}
+	return true
+}
+
 func (c ControllerConfig) getKeyAsBool(key string, default_ bool) bool {
 	raw := c.ConfigMap.Data[key]
 	parsed, err := strconv.ParseBool(raw)
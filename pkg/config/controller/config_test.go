@@ -1,6 +1,7 @@
 package controller_test
 
 import (
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
 	"github.com/kubevirt/vm-import-operator/pkg/config"
 	"github.com/kubevirt/vm-import-operator/pkg/config/controller"
 	. "github.com/onsi/ginkgo"
@@ -33,3 +34,84 @@ var _ = Describe("Controller config creator", func() {
 		Expect(cfg.OsConfigMapNamespace()).To(BeEquivalentTo(configMapNamespace))
 	})
 })
+
+var _ = Describe("Controller config conversion pod", func() {
+	It("should be empty when not configured", func() {
+		cfg := controller.NewControllerConfigFrom(config.Config{ConfigMap: corev1.ConfigMap{}})
+
+		Expect(cfg.ConversionPod()).To(Equal(v2vv1.ConversionPodSpec{}))
+	})
+
+	It("should parse the conversion pod settings", func() {
+		configMap := corev1.ConfigMap{
+			Data: map[string]string{
+				controller.ConversionPodImageKey:              "virt-v2v:latest",
+				controller.ConversionPodNodeSelectorKey:       "conversion: \"true\"",
+				controller.ConversionPodTolerationsKey:        "- key: dedicated\n  operator: Exists\n  effect: NoSchedule",
+				controller.ConversionPodResourcesKey:          "requests:\n  cpu: 500m\n  memory: 1Gi",
+				controller.ConversionPodPriorityClassNameKey:  "low",
+				controller.ConversionPodServiceAccountNameKey: "virt-v2v",
+			},
+		}
+		cfg := controller.NewControllerConfigFrom(config.Config{ConfigMap: configMap})
+
+		spec := cfg.ConversionPod()
+
+		Expect(*spec.Image).To(Equal("virt-v2v:latest"))
+		Expect(spec.NodeSelector).To(Equal(map[string]string{"conversion": "true"}))
+		Expect(spec.Tolerations).To(ConsistOf(corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}))
+		Expect(spec.Resources.Requests.Cpu().String()).To(Equal("500m"))
+		Expect(spec.Resources.Requests.Memory().String()).To(Equal("1Gi"))
+		Expect(*spec.PriorityClassName).To(Equal("low"))
+		Expect(*spec.ServiceAccountName).To(Equal("virt-v2v"))
+	})
+
+	It("should ignore settings that can't be parsed", func() {
+		configMap := corev1.ConfigMap{
+			Data: map[string]string{
+				controller.ConversionPodNodeSelectorKey: "- not a map",
+				controller.ConversionPodResourcesKey:    "requests: 12",
+			},
+		}
+		cfg := controller.NewControllerConfigFrom(config.Config{ConfigMap: configMap})
+
+		spec := cfg.ConversionPod()
+
+		Expect(spec.NodeSelector).To(BeNil())
+		Expect(spec.Resources).To(BeNil())
+	})
+
+	It("should parse the images and the service accounts the imports may choose", func() {
+		configMap := corev1.ConfigMap{
+			Data: map[string]string{
+				controller.ConversionPodAllowedImagesKey:              "- virt-v2v:debug\n- virt-v2v:next",
+				controller.ConversionPodAllowedServiceAccountNamesKey: "not a list",
+			},
+		}
+		cfg := controller.NewControllerConfigFrom(config.Config{ConfigMap: configMap})
+
+		allowList := cfg.ConversionPodAllowList()
+
+		Expect(allowList.Images).To(Equal([]string{"virt-v2v:debug", "virt-v2v:next"}))
+		Expect(allowList.ServiceAccountNames).To(BeEmpty())
+	})
+
+	It("should parse the placement the imports may choose", func() {
+		configMap := corev1.ConfigMap{
+			Data: map[string]string{
+				controller.ConversionPodAllowedNodeSelectorLabelsKey: "zone:\n- a\n- b",
+				controller.ConversionPodAllowedTolerationsKey:        "- key: conversion\n  operator: Exists\n  effect: NoSchedule",
+				controller.ConversionPodAllowedPriorityClassNamesKey: "- low-priority",
+			},
+		}
+		cfg := controller.NewControllerConfigFrom(config.Config{ConfigMap: configMap})
+
+		allowList := cfg.ConversionPodAllowList()
+
+		Expect(allowList.NodeSelectorLabels).To(Equal(map[string][]string{"zone": {"a", "b"}}))
+		Expect(allowList.Tolerations).To(Equal([]corev1.Toleration{
+			{Key: "conversion", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+		}))
+		Expect(allowList.PriorityClassNames).To(Equal([]string{"low-priority"}))
+	})
+})
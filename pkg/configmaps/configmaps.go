@@ -63,6 +63,11 @@ func (m *Manager) CreateFor(configMap *corev1.ConfigMap, vmiCrName types.Namespa
 	return m.client.Create(context.TODO(), configMap)
 }
 
+// Update updates given config map, e.g. after the credentials it was derived from have changed.
+func (m *Manager) Update(configMap *corev1.ConfigMap) error {
+	return m.client.Update(context.TODO(), configMap)
+}
+
 // DeleteFor removes config map created for vmiCrName
 func (m *Manager) DeleteFor(vmiCrName types.NamespacedName) error {
 	configMap, err := m.FindFor(vmiCrName)
@@ -0,0 +1,10 @@
+package controller
+
+import (
+	"github.com/kubevirt/vm-import-operator/pkg/controller/provider"
+)
+
+func init() {
+	// AddToManagerFuncs is a list of functions to create controllers and add them to a manager.
+	AddToManagerFuncs = append(AddToManagerFuncs, provider.Add)
+}
@@ -1,18 +1,29 @@
 package controller
 
 import (
+	pclient "github.com/kubevirt/vm-import-operator/pkg/client"
 	ctrlConfig "github.com/kubevirt/vm-import-operator/pkg/config/controller"
 	kvConfig "github.com/kubevirt/vm-import-operator/pkg/config/kubevirt"
+	"github.com/kubevirt/vm-import-operator/pkg/controller/index"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 // AddToManagerFuncs is a list of functions to add all Controllers to the Manager
-var AddToManagerFuncs []func(manager.Manager, kvConfig.KubeVirtConfigProvider, ctrlConfig.ControllerConfigProvider) error
+var AddToManagerFuncs []func(manager.Manager, kvConfig.KubeVirtConfigProvider, ctrlConfig.ControllerConfigProvider, pclient.Factory) error
 
 // AddToManager adds all Controllers to the Manager
 func AddToManager(m manager.Manager, kvConfigProvider kvConfig.KubeVirtConfigProvider, ctrlConfigProvider ctrlConfig.ControllerConfigProvider) error {
+	// the controllers look the referencing objects up through the indexes
+	if err := index.AddToManager(m); err != nil {
+		return err
+	}
+	// the controllers share the source provider clients, kept alive and closed once idle by the manager
+	factory := pclient.NewCachingClientFactory(pclient.NewSourceClientFactory())
+	if err := m.Add(factory); err != nil {
+		return err
+	}
 	for _, f := range AddToManagerFuncs {
-		if err := f(m, kvConfigProvider, ctrlConfigProvider); err != nil {
+		if err := f(m, kvConfigProvider, ctrlConfigProvider, factory); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,113 @@
+package index
+
+import (
+	"context"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// ProviderReferencesField indexes the providers by the secrets and config maps they reference
+	ProviderReferencesField = "providerReferences"
+	// ImportReferencesField indexes the imports by the credentials secret and the provider they reference
+	ImportReferencesField = "importReferences"
+
+	// SecretKind is the kind of the referenced secrets
+	SecretKind = "Secret"
+	// ConfigMapKind is the kind of the referenced config maps
+	ConfigMapKind = "ConfigMap"
+	// ProviderKind is the kind of the referenced providers
+	ProviderKind = "Provider"
+)
+
+var log = logf.Log.WithName("index")
+
+// Key returns the value the objects referencing the named object of the given kind are indexed with
+func Key(kind string, name types.NamespacedName) string {
+	return kind + "/" + name.Namespace + "/" + name.Name
+}
+
+// AddToManager registers the indexes of the cache of the Manager, so that the objects referencing a secret, a config
+// map or a provider are listed without listing all of them
+func AddToManager(mgr manager.Manager) error {
+	indexer := mgr.GetFieldIndexer()
+	err := indexer.IndexField(context.TODO(), &v2vv1.Provider{}, ProviderReferencesField, providerReferences)
+	if err != nil {
+		return err
+	}
+	return indexer.IndexField(context.TODO(), &v2vv1.VirtualMachineImport{}, ImportReferencesField, importReferences)
+}
+
+// ReferencedAsCABundle filters the events of the config maps to the ones holding the CA bundle of a provider, which
+// the reader looks up through the provider index
+func ReferencedAsCABundle(reader client.Reader) predicate.Funcs {
+	referenced := func(meta metav1.Object) bool {
+		name := types.NamespacedName{Name: meta.GetName(), Namespace: meta.GetNamespace()}
+		providers := &v2vv1.ProviderList{}
+		err := reader.List(context.TODO(), providers, client.MatchingFields{ProviderReferencesField: Key(ConfigMapKind, name)})
+		if err != nil {
+			log.Error(err, "Failed to list the providers referencing a config map", "ConfigMap", name)
+			return true
+		}
+		return len(providers.Items) > 0
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return referenced(e.Meta)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return referenced(e.MetaNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return referenced(e.Meta)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return referenced(e.Meta)
+		},
+	}
+}
+
+func providerReferences(object runtime.Object) []string {
+	provider, ok := object.(*v2vv1.Provider)
+	if !ok {
+		return nil
+	}
+	references := []string{Key(SecretKind, resolve(provider.Spec.SecretRef, provider.Namespace))}
+	if provider.Spec.TLS != nil && provider.Spec.TLS.CABundleRef != nil {
+		ref := provider.Spec.TLS.CABundleRef
+		id := v2vv1.ObjectIdentifier{Name: ref.Name, Namespace: ref.Namespace}
+		references = append(references, Key(string(ref.Kind), resolve(id, provider.Namespace)))
+	}
+	return references
+}
+
+func importReferences(object runtime.Object) []string {
+	vmImport, ok := object.(*v2vv1.VirtualMachineImport)
+	if !ok {
+		return nil
+	}
+	var references []string
+	if secret := vmImport.Spec.ProviderCredentialsSecret; secret != nil {
+		references = append(references, Key(SecretKind, resolve(*secret, vmImport.Namespace)))
+	}
+	if provider := vmImport.Spec.Provider; provider != nil {
+		references = append(references, Key(ProviderKind, resolve(*provider, vmImport.Namespace)))
+	}
+	return references
+}
+
+// resolve returns the name of the identified object, relative to the namespace of the resource holding the identifier
+func resolve(id v2vv1.ObjectIdentifier, namespace string) types.NamespacedName {
+	if id.Namespace != nil {
+		namespace = *id.Namespace
+	}
+	return types.NamespacedName{Name: id.Name, Namespace: namespace}
+}
@@ -0,0 +1,89 @@
+package index
+
+import (
+	"context"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+var _ = Describe("Indexes", func() {
+	other := "other"
+
+	It("should index a provider by its secret and its CA bundle", func() {
+		provider := &v2vv1.Provider{
+			ObjectMeta: metav1.ObjectMeta{Name: "engine", Namespace: "test"},
+			Spec: v2vv1.ProviderSpec{
+				SecretRef: v2vv1.ObjectIdentifier{Name: "credentials"},
+				TLS: &v2vv1.ProviderTLSSpec{
+					CABundleRef: &v2vv1.CABundleReference{Kind: v2vv1.ConfigMapCABundle, Name: "trusted-ca", Namespace: &other},
+				},
+			},
+		}
+
+		Expect(providerReferences(provider)).To(ConsistOf("Secret/test/credentials", "ConfigMap/other/trusted-ca"))
+	})
+
+	It("should index an import by its credentials secret and its provider", func() {
+		vmImport := &v2vv1.VirtualMachineImport{
+			ObjectMeta: metav1.ObjectMeta{Name: "import", Namespace: "test"},
+			Spec: v2vv1.VirtualMachineImportSpec{
+				ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "credentials"},
+				Provider:                  &v2vv1.ObjectIdentifier{Name: "engine", Namespace: &other},
+			},
+		}
+
+		Expect(importReferences(vmImport)).To(ConsistOf("Secret/test/credentials", "Provider/other/engine"))
+	})
+
+	It("should not index other objects", func() {
+		Expect(importReferences(&v2vv1.Provider{})).To(BeEmpty())
+	})
+
+	It("should only pass the events of the config maps holding the CA bundle of a provider", func() {
+		provider := v2vv1.Provider{
+			ObjectMeta: metav1.ObjectMeta{Name: "engine", Namespace: "test"},
+			Spec: v2vv1.ProviderSpec{
+				SecretRef: v2vv1.ObjectIdentifier{Name: "credentials"},
+				TLS: &v2vv1.ProviderTLSSpec{
+					CABundleRef: &v2vv1.CABundleReference{Kind: v2vv1.ConfigMapCABundle, Name: "trusted-ca"},
+				},
+			},
+		}
+		predicate := ReferencedAsCABundle(&indexedReader{providers: []v2vv1.Provider{provider}})
+		trustedCA := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "trusted-ca", Namespace: "test"}}
+		other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "test"}}
+
+		Expect(predicate.Create(event.CreateEvent{Meta: trustedCA, Object: trustedCA})).To(BeTrue())
+		Expect(predicate.Update(event.UpdateEvent{MetaOld: other, ObjectOld: other, MetaNew: other, ObjectNew: other})).To(BeFalse())
+	})
+})
+
+// indexedReader lists the providers matching the provider index
+type indexedReader struct {
+	providers []v2vv1.Provider
+}
+
+func (r *indexedReader) Get(context.Context, client.ObjectKey, runtime.Object) error {
+	return nil
+}
+
+func (r *indexedReader) List(_ context.Context, list runtime.Object, opts ...client.ListOption) error {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	providers := list.(*v2vv1.ProviderList)
+	for i := range r.providers {
+		for _, reference := range providerReferences(&r.providers[i]) {
+			if value, _ := listOpts.FieldSelector.RequiresExactMatch(ProviderReferencesField); value == reference {
+				providers.Items = append(providers.Items, r.providers[i])
+			}
+		}
+	}
+	return nil
+}
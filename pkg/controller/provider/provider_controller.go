@@ -0,0 +1,296 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	pclient "github.com/kubevirt/vm-import-operator/pkg/client"
+	ctrlConfig "github.com/kubevirt/vm-import-operator/pkg/config/controller"
+	kvConfig "github.com/kubevirt/vm-import-operator/pkg/config/kubevirt"
+	"github.com/kubevirt/vm-import-operator/pkg/controller/index"
+	provider "github.com/kubevirt/vm-import-operator/pkg/providers"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// HealthCheckInterval is how often the connectivity of the source providers is checked
+	HealthCheckInterval = 5 * time.Minute
+
+	// EventInsecureConnection is emitted every time a source provider is connected to without verifying its certificate
+	EventInsecureConnection = "InsecureConnection"
+)
+
+var log = logf.Log.WithName("controller_provider")
+
+// Add creates a new Provider Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, _ kvConfig.KubeVirtConfigProvider, _ ctrlConfig.ControllerConfigProvider, factory pclient.Factory) error {
+	return add(mgr, newReconciler(mgr, factory))
+}
+
+// newReconciler returns a new reconcile.Reconciler checking the source providers with the clients of the factory
+func newReconciler(mgr manager.Manager, factory pclient.Factory) *ReconcileProvider {
+	return &ReconcileProvider{
+		client:   mgr.GetClient(),
+		factory:  factory,
+		recorder: mgr.GetEventRecorderFor("provider-controller"),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r *ReconcileProvider) error {
+	// Create a new controller
+	c, err := controller.New("provider-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource Provider. The status is written at every check, so only the changes to
+	// the spec queue the provider, the next check being queued after HealthCheckInterval.
+	err = c.Watch(&source.Kind{Type: &v2vv1.Provider{}}, &handler.EnqueueRequestForObject{}, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the secrets of the providers and to the secrets and config maps holding their CA bundles
+	err = c.Watch(
+		&source.Kind{Type: &corev1.Secret{}},
+		&handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.providersOfSecret)},
+	)
+	if err != nil {
+		return err
+	}
+	err = c.Watch(
+		&source.Kind{Type: &corev1.ConfigMap{}},
+		&handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.providersOfConfigMap)},
+		index.ReferencedAsCABundle(r.client),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileProvider implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileProvider{}
+
+// ReconcileProvider reconciles a Provider object
+type ReconcileProvider struct {
+	client   client.Client
+	factory  pclient.Factory
+	recorder record.EventRecorder
+}
+
+// Reconcile checks the connectivity of the source provider and reports its version and inventory
+func (r *ReconcileProvider) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	reqLogger.Info("Reconciling Provider")
+
+	instance := &v2vv1.Provider{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	status := instance.Status.DeepCopy()
+	reason, err := r.check(instance, status)
+	if err != nil {
+		reqLogger.Info("Provider is not ready", "Reason", reason, "Error", err.Error())
+		upsertCondition(status, newReadyCondition(reason, err.Error(), corev1.ConditionFalse))
+	} else {
+		upsertCondition(status, newReadyCondition(reason, "Connected to the source provider", corev1.ConditionTrue))
+	}
+	status.ObservedGeneration = instance.Generation
+
+	instance.Status = *status
+	err = r.client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: HealthCheckInterval}, nil
+}
+
+// check connects to the source provider and updates its version and inventory in the status
+func (r *ReconcileProvider) check(instance *v2vv1.Provider, status *v2vv1.ProviderStatus) (v2vv1.ProviderConditionReason, error) {
+	secretNamespace := instance.Namespace
+	if instance.Spec.SecretRef.Namespace != nil {
+		secretNamespace = *instance.Spec.SecretRef.Namespace
+	}
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.SecretRef.Name, Namespace: secretNamespace}, secret)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return v2vv1.ProviderSecretNotFound, err
+		}
+		return v2vv1.ProviderConnectionFailed, err
+	}
+
+	caBundle, err := provider.CABundleOf(r.client, instance)
+	if err != nil {
+		var invalidTLS provider.InvalidTLSError
+		if k8serrors.IsNotFound(err) || errors.As(err, &invalidTLS) {
+			return v2vv1.ProviderInvalidTLSConfiguration, err
+		}
+		return v2vv1.ProviderConnectionFailed, err
+	}
+
+	details, err := provider.ConnectionDetailsOf(instance, secret, caBundle)
+	if err != nil {
+		var invalidTLS provider.InvalidTLSError
+		if errors.As(err, &invalidTLS) {
+			return v2vv1.ProviderInvalidTLSConfiguration, err
+		}
+		return v2vv1.ProviderInvalidSecret, err
+	}
+	if provider.InsecureSkipVerify(details) {
+		log.Info("Connecting to the source provider without verifying its certificate", "Provider.Namespace", instance.Namespace, "Provider.Name", instance.Name, "URL", instance.Spec.URL)
+		r.recorder.Eventf(instance, corev1.EventTypeWarning, EventInsecureConnection, "Connecting to %s without verifying its certificate", instance.Spec.URL)
+	}
+
+	var sourceClient pclient.VMClient
+	switch instance.Spec.Type {
+	case v2vv1.OvirtProviderType:
+		sourceClient, err = r.factory.NewOvirtClient(details)
+	case v2vv1.VmwareProviderType:
+		sourceClient, err = r.factory.NewVmwareClient(details)
+	default:
+		err = fmt.Errorf("unsupported provider type %s", instance.Spec.Type)
+	}
+	if err != nil {
+		return connectionFailureReason(err), err
+	}
+	defer func() {
+		_ = r.factory.Release(sourceClient)
+	}()
+
+	err = sourceClient.TestConnection()
+	if err != nil {
+		return connectionFailureReason(err), err
+	}
+
+	if inventoryClient, ok := sourceClient.(pclient.InventoryClient); ok {
+		version, err := inventoryClient.GetVersion()
+		if err != nil {
+			return v2vv1.ProviderConnectionFailed, err
+		}
+		inventory, err := inventoryClient.GetInventory()
+		if err != nil {
+			return v2vv1.ProviderConnectionFailed, err
+		}
+		status.Version = version
+		status.Inventory = inventory
+	}
+	return v2vv1.ProviderConnected, nil
+}
+
+// providersOfSecret maps a secret to the providers referencing it, as their credentials or their CA bundle
+func (r *ReconcileProvider) providersOfSecret(object handler.MapObject) []reconcile.Request {
+	return r.providersReferencing(object, index.SecretKind, func(p *v2vv1.Provider) bool {
+		return references(p.Namespace, p.Spec.SecretRef.Name, p.Spec.SecretRef.Namespace, object) ||
+			referencesCABundle(p, v2vv1.SecretCABundle, object)
+	})
+}
+
+// providersOfConfigMap maps a config map to the providers referencing it as their CA bundle
+func (r *ReconcileProvider) providersOfConfigMap(object handler.MapObject) []reconcile.Request {
+	return r.providersReferencing(object, index.ConfigMapKind, func(p *v2vv1.Provider) bool {
+		return referencesCABundle(p, v2vv1.ConfigMapCABundle, object)
+	})
+}
+
+// providersReferencing lists the providers referencing the object through the index of the cache, and checks the
+// references again since the index is only a first filter
+func (r *ReconcileProvider) providersReferencing(object handler.MapObject, kind string, referencing func(*v2vv1.Provider) bool) []reconcile.Request {
+	providers := &v2vv1.ProviderList{}
+	name := types.NamespacedName{Name: object.Meta.GetName(), Namespace: object.Meta.GetNamespace()}
+	err := r.client.List(context.TODO(), providers, client.MatchingFields{index.ProviderReferencesField: index.Key(kind, name)})
+	if err != nil {
+		log.Error(err, "Failed to list the providers referencing an object", "Object.Namespace", object.Meta.GetNamespace(), "Object.Name", object.Meta.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range providers.Items {
+		p := &providers.Items[i]
+		if referencing(p) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: p.Name, Namespace: p.Namespace}})
+		}
+	}
+	return requests
+}
+
+func referencesCABundle(p *v2vv1.Provider, kind v2vv1.CABundleKind, object handler.MapObject) bool {
+	if p.Spec.TLS == nil || p.Spec.TLS.CABundleRef == nil || p.Spec.TLS.CABundleRef.Kind != kind {
+		return false
+	}
+	return references(p.Namespace, p.Spec.TLS.CABundleRef.Name, p.Spec.TLS.CABundleRef.Namespace, object)
+}
+
+// references returns whether the name and the optional namespace, relative to the namespace of the provider,
+// refer to the object
+func references(providerNamespace string, name string, namespace *string, object handler.MapObject) bool {
+	if namespace != nil {
+		providerNamespace = *namespace
+	}
+	return name == object.Meta.GetName() && providerNamespace == object.Meta.GetNamespace()
+}
+
+func connectionFailureReason(err error) v2vv1.ProviderConditionReason {
+	if pclient.IsAuthenticationError(err) {
+		return v2vv1.ProviderAuthenticationFailed
+	}
+	if pclient.IsCertificateError(err) {
+		return v2vv1.ProviderCertificateVerificationFailed
+	}
+	return v2vv1.ProviderConnectionFailed
+}
+
+func newReadyCondition(reason v2vv1.ProviderConditionReason, message string, status corev1.ConditionStatus) v2vv1.ProviderCondition {
+	now := metav1.Now()
+	reasonStr := string(reason)
+	return v2vv1.ProviderCondition{
+		Type:               v2vv1.ProviderReady,
+		Status:             status,
+		Reason:             &reasonStr,
+		Message:            &message,
+		LastHeartbeatTime:  &now,
+		LastTransitionTime: &now,
+	}
+}
+
+func upsertCondition(status *v2vv1.ProviderStatus, condition v2vv1.ProviderCondition) {
+	for i := range status.Conditions {
+		existing := &status.Conditions[i]
+		if existing.Type != condition.Type {
+			continue
+		}
+		existing.Reason = condition.Reason
+		existing.Message = condition.Message
+		existing.LastHeartbeatTime = condition.LastHeartbeatTime
+		if existing.Status != condition.Status {
+			existing.Status = condition.Status
+			existing.LastTransitionTime = condition.LastTransitionTime
+		}
+		return
+	}
+	status.Conditions = append(status.Conditions, condition)
+}
@@ -0,0 +1,321 @@
+package provider
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	pclient "github.com/kubevirt/vm-import-operator/pkg/client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	ovirtsdk "github.com/ovirt/go-ovirt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Reconcile provider", func() {
+	var (
+		factory  *fakeFactory
+		recorder *record.FakeRecorder
+		request  reconcile.Request
+		instance *v2vv1.Provider
+		secret   *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		factory = &fakeFactory{client: &fakeClient{version: "4.4.1", inventory: &v2vv1.ProviderInventory{Datacenters: 1, Clusters: 2, Hosts: 3, VMs: 4}}}
+		request = reconcile.Request{NamespacedName: types.NamespacedName{Name: "engine", Namespace: "test"}}
+		instance = &v2vv1.Provider{
+			ObjectMeta: metav1.ObjectMeta{Name: "engine", Namespace: "test", Generation: 2},
+			Spec: v2vv1.ProviderSpec{
+				Type:      v2vv1.OvirtProviderType,
+				URL:       "https://engine.example.com/ovirt-engine/api",
+				SecretRef: v2vv1.ObjectIdentifier{Name: "engine-credentials"},
+			},
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "engine-credentials", Namespace: "test"},
+			Data:       map[string][]byte{"username": []byte("admin@internal"), "password": []byte("123456")},
+		}
+	})
+
+	reconcileWith := func(objs ...runtime.Object) *ReconcileProvider {
+		scheme := runtime.NewScheme()
+		Expect(v2vv1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		return &ReconcileProvider{
+			client:   fake.NewFakeClientWithScheme(scheme, objs...),
+			factory:  factory,
+			recorder: recorder,
+		}
+	}
+
+	readyCondition := func(r *ReconcileProvider) (*v2vv1.Provider, v2vv1.ProviderCondition) {
+		updated := &v2vv1.Provider{}
+		Expect(r.client.Get(context.TODO(), request.NamespacedName, updated)).To(Succeed())
+		Expect(updated.Status.Conditions).To(HaveLen(1))
+		return updated, updated.Status.Conditions[0]
+	}
+
+	It("should report a connected provider with its version and inventory", func() {
+		r := reconcileWith(instance, secret)
+
+		result, err := r.Reconcile(request)
+
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal(reconcile.Result{RequeueAfter: HealthCheckInterval}))
+		updated, condition := readyCondition(r)
+		Expect(condition.Type).To(Equal(v2vv1.ProviderReady))
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+		Expect(*condition.Reason).To(Equal(string(v2vv1.ProviderConnected)))
+		Expect(updated.Status.Version).To(Equal("4.4.1"))
+		Expect(updated.Status.Inventory.VMs).To(Equal(4))
+		Expect(updated.Status.ObservedGeneration).To(Equal(int64(2)))
+		Expect(factory.connectedWith["username"]).To(Equal("admin@internal"))
+		Expect(factory.connectedWith["apiUrl"]).To(Equal(instance.Spec.URL))
+		Expect(factory.released).To(Equal(1))
+	})
+
+	It("should report a missing secret", func() {
+		r := reconcileWith(instance)
+
+		_, err := r.Reconcile(request)
+
+		Expect(err).To(BeNil())
+		_, condition := readyCondition(r)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(*condition.Reason).To(Equal(string(v2vv1.ProviderSecretNotFound)))
+	})
+
+	It("should report a secret without password", func() {
+		delete(secret.Data, "password")
+		r := reconcileWith(instance, secret)
+
+		_, err := r.Reconcile(request)
+
+		Expect(err).To(BeNil())
+		_, condition := readyCondition(r)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(*condition.Reason).To(Equal(string(v2vv1.ProviderInvalidSecret)))
+	})
+
+	It("should report an unreachable provider", func() {
+		factory.client.connectionErr = fmt.Errorf("connection refused")
+		r := reconcileWith(instance, secret)
+
+		_, err := r.Reconcile(request)
+
+		Expect(err).To(BeNil())
+		_, condition := readyCondition(r)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(*condition.Reason).To(Equal(string(v2vv1.ProviderConnectionFailed)))
+		Expect(*condition.Message).To(Equal("connection refused"))
+		Expect(factory.released).To(Equal(1))
+	})
+
+	It("should report rejected credentials", func() {
+		factory.client.connectionErr = &ovirtsdk.AuthError{}
+		r := reconcileWith(instance, secret)
+
+		_, err := r.Reconcile(request)
+
+		Expect(err).To(BeNil())
+		_, condition := readyCondition(r)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(*condition.Reason).To(Equal(string(v2vv1.ProviderAuthenticationFailed)))
+	})
+
+	It("should report a certificate that couldn't be verified", func() {
+		factory.client.connectionErr = fmt.Errorf("failed to connect: %w", x509.UnknownAuthorityError{})
+		r := reconcileWith(instance, secret)
+
+		_, err := r.Reconcile(request)
+
+		Expect(err).To(BeNil())
+		_, condition := readyCondition(r)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(*condition.Reason).To(Equal(string(v2vv1.ProviderCertificateVerificationFailed)))
+	})
+
+	It("should verify the certificate with the referenced CA bundle", func() {
+		instance.Spec.TLS = &v2vv1.ProviderTLSSpec{
+			CABundleRef: &v2vv1.CABundleReference{Kind: v2vv1.ConfigMapCABundle, Name: "trusted-ca"},
+		}
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "trusted-ca", Namespace: "test"},
+			Data:       map[string]string{v2vv1.DefaultCABundleKey: "-----BEGIN CERTIFICATE-----"},
+		}
+		r := reconcileWith(instance, secret, configMap)
+
+		_, err := r.Reconcile(request)
+
+		Expect(err).To(BeNil())
+		_, condition := readyCondition(r)
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+		Expect(factory.connectedWith["caCert"]).To(Equal("-----BEGIN CERTIFICATE-----"))
+	})
+
+	It("should report a missing CA bundle", func() {
+		instance.Spec.TLS = &v2vv1.ProviderTLSSpec{
+			CABundleRef: &v2vv1.CABundleReference{Kind: v2vv1.SecretCABundle, Name: "trusted-ca"},
+		}
+		r := reconcileWith(instance, secret)
+
+		_, err := r.Reconcile(request)
+
+		Expect(err).To(BeNil())
+		_, condition := readyCondition(r)
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(*condition.Reason).To(Equal(string(v2vv1.ProviderInvalidTLSConfiguration)))
+	})
+
+	It("should report contradictory TLS settings", func() {
+		caCert := "-----BEGIN CERTIFICATE-----"
+		instance.Spec.TLS = &v2vv1.ProviderTLSSpec{CACert: &caCert, InsecureSkipVerify: true}
+		r := reconcileWith(instance, secret)
+
+		_, err := r.Reconcile(request)
+
+		Expect(err).To(BeNil())
+		_, condition := readyCondition(r)
+		Expect(*condition.Reason).To(Equal(string(v2vv1.ProviderInvalidTLSConfiguration)))
+		Expect(factory.connectedWith).To(BeNil())
+	})
+
+	It("should record connecting without verifying the certificate", func() {
+		instance.Spec.TLS = &v2vv1.ProviderTLSSpec{InsecureSkipVerify: true}
+		r := reconcileWith(instance, secret)
+
+		_, err := r.Reconcile(request)
+
+		Expect(err).To(BeNil())
+		Expect(factory.connectedWith["insecureSkipVerify"]).To(Equal("true"))
+		Expect(recorder.Events).To(Receive(ContainSubstring(EventInsecureConnection)))
+	})
+
+	It("should keep the transition time while the provider stays ready", func() {
+		r := reconcileWith(instance, secret)
+		_, err := r.Reconcile(request)
+		Expect(err).To(BeNil())
+		_, first := readyCondition(r)
+
+		_, err = r.Reconcile(request)
+		Expect(err).To(BeNil())
+		_, second := readyCondition(r)
+
+		Expect(second.LastTransitionTime.Equal(first.LastTransitionTime)).To(BeTrue())
+	})
+
+	It("should map a config map to the providers whose CA bundle it holds", func() {
+		instance.Spec.TLS = &v2vv1.ProviderTLSSpec{
+			CABundleRef: &v2vv1.CABundleReference{Kind: v2vv1.ConfigMapCABundle, Name: "trusted-ca"},
+		}
+		other := instance.DeepCopy()
+		other.Name = "other"
+		other.Spec.TLS.CABundleRef.Kind = v2vv1.SecretCABundle
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "trusted-ca", Namespace: "test"}}
+		r := reconcileWith(instance, other)
+
+		requests := r.providersOfConfigMap(handler.MapObject{Meta: configMap, Object: configMap})
+
+		Expect(requests).To(ConsistOf(request))
+	})
+
+	It("should map a secret to the providers referencing it", func() {
+		other := instance.DeepCopy()
+		other.Name = "other"
+		other.Spec.SecretRef.Name = "other-credentials"
+		r := reconcileWith(instance, other, secret)
+
+		requests := r.providersOfSecret(handler.MapObject{Meta: secret, Object: secret})
+
+		Expect(requests).To(ConsistOf(request))
+	})
+})
+
+type fakeFactory struct {
+	client        *fakeClient
+	connectedWith map[string]string
+	released      int
+}
+
+func (f *fakeFactory) NewOvirtClient(dataMap map[string]string) (pclient.OvirtClient, error) {
+	f.connectedWith = dataMap
+	return f.client, nil
+}
+
+func (f *fakeFactory) NewVmwareClient(dataMap map[string]string) (pclient.VMClient, error) {
+	f.connectedWith = dataMap
+	return f.client, nil
+}
+
+func (f *fakeFactory) Release(pclient.VMClient) error {
+	f.released++
+	return nil
+}
+
+type fakeClient struct {
+	connectionErr error
+	version       string
+	inventory     *v2vv1.ProviderInventory
+}
+
+func (c *fakeClient) TestConnection() error {
+	return c.connectionErr
+}
+
+func (c *fakeClient) GetVM(*string, *string, *string, *string) (interface{}, error) {
+	return nil, nil
+}
+
+func (c *fakeClient) StopVM(string, v2vv1.SourceShutdownMethod) error {
+	return nil
+}
+
+func (c *fakeClient) StartVM(string) error {
+	return nil
+}
+
+func (c *fakeClient) Close() error {
+	return nil
+}
+
+func (c *fakeClient) ListVMs(string) ([]*ovirtsdk.Vm, error) {
+	return nil, nil
+}
+
+func (c *fakeClient) GetDisk(string) (*ovirtsdk.Disk, error) {
+	return nil, nil
+}
+
+func (c *fakeClient) GetEngineCA() (string, error) {
+	return "", nil
+}
+
+func (c *fakeClient) CreateVMSnapshot(string, string) (string, error) {
+	return "", nil
+}
+
+func (c *fakeClient) GetVMSnapshot(string, string) (*ovirtsdk.Snapshot, error) {
+	return nil, nil
+}
+
+func (c *fakeClient) RemoveVMSnapshot(string, string) error {
+	return nil
+}
+
+func (c *fakeClient) GetVersion() (string, error) {
+	return c.version, nil
+}
+
+func (c *fakeClient) GetInventory() (*v2vv1.ProviderInventory, error) {
+	return c.inventory, nil
+}
@@ -0,0 +1,88 @@
+package virtualmachineimport
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kubevirt/vm-import-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+)
+
+// dataSourceKind is the kind of the CDI DataSources, which the CDI API the operator is built with lacks
+var dataSourceKind = schema.GroupVersionKind{Group: "cdi.kubevirt.io", Version: "v1beta1", Kind: "DataSource"}
+
+// resolveCloneSource makes the data volume cloning the ISO image of a CD-ROM clone the PVC of the DataSource it is
+// mapped to, and sizes it after the cloned PVC. The data volume is created by the operator, which reads the sources
+// with its cluster-wide reader, so only the DataSources and PVCs of the namespace of the import are cloned.
+func (r *ReconcileVirtualMachineImport) resolveCloneSource(dv *cdiv1.DataVolume) error {
+	if dataSource, found := dv.Annotations[utils.AnnDataSource]; found {
+		source, err := r.dataSourcePVC(dataSource, dv.Namespace)
+		if err != nil {
+			return err
+		}
+		dv.Spec.Source = cdiv1.DataVolumeSource{PVC: source}
+	}
+	if dv.Spec.Source.PVC == nil || dv.Spec.PVC == nil {
+		return nil
+	}
+	if dv.Spec.Source.PVC.Namespace != dv.Namespace {
+		return fmt.Errorf("PVC %s/%s can't be cloned, only the PVCs of namespace %s are", dv.Spec.Source.PVC.Namespace, dv.Spec.Source.PVC.Name, dv.Namespace)
+	}
+	if _, sized := dv.Spec.PVC.Resources.Requests[corev1.ResourceStorage]; sized {
+		return nil
+	}
+
+	sourcePVC := &corev1.PersistentVolumeClaim{}
+	sourceName := types.NamespacedName{Namespace: dv.Spec.Source.PVC.Namespace, Name: dv.Spec.Source.PVC.Name}
+	if err := r.apiReader.Get(context.TODO(), sourceName, sourcePVC); err != nil {
+		return err
+	}
+	size, found := sourcePVC.Status.Capacity[corev1.ResourceStorage]
+	if !found {
+		size, found = sourcePVC.Spec.Resources.Requests[corev1.ResourceStorage]
+	}
+	if !found {
+		return fmt.Errorf("size of PVC %s is unknown", sourceName)
+	}
+	if dv.Spec.PVC.Resources.Requests == nil {
+		dv.Spec.PVC.Resources.Requests = corev1.ResourceList{}
+	}
+	dv.Spec.PVC.Resources.Requests[corev1.ResourceStorage] = size
+	return nil
+}
+
+// dataSourcePVC returns the PVC of the DataSource, given as namespace/name, which must be in the namespace
+func (r *ReconcileVirtualMachineImport) dataSourcePVC(dataSource string, namespace string) (*cdiv1.DataVolumeSourcePVC, error) {
+	parts := strings.SplitN(dataSource, "/", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid DataSource %s", dataSource)
+	}
+	if parts[0] != namespace {
+		return nil, fmt.Errorf("DataSource %s can't be cloned, only the DataSources of namespace %s are", dataSource, namespace)
+	}
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(dataSourceKind)
+	if err := r.apiReader.Get(context.TODO(), types.NamespacedName{Namespace: parts[0], Name: parts[1]}, object); err != nil {
+		return nil, err
+	}
+	name, _, err := unstructured.NestedString(object.Object, "spec", "source", "pvc", "name")
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, fmt.Errorf("DataSource %s has no PVC source", dataSource)
+	}
+	pvcNamespace, _, err := unstructured.NestedString(object.Object, "spec", "source", "pvc", "namespace")
+	if err != nil {
+		return nil, err
+	}
+	if pvcNamespace == "" {
+		pvcNamespace = parts[0]
+	}
+	return &cdiv1.DataVolumeSourcePVC{Namespace: pvcNamespace, Name: name}, nil
+}
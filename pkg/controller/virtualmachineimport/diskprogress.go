@@ -0,0 +1,174 @@
+package virtualmachineimport
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+)
+
+// newDataVolumeItem creates the status item of a data volume created by the import
+func newDataVolumeItem(dv *cdiv1.DataVolume) v2vv1.DataVolumeItem {
+	item := v2vv1.DataVolumeItem{
+		Name:         dv.Name,
+		SourceDiskID: sourceDiskID(dv),
+	}
+	if dv.Spec.PVC != nil {
+		if size, ok := dv.Spec.PVC.Resources.Requests[corev1.ResourceStorage]; ok {
+			item.SizeBytes = size.Value()
+		}
+		if len(dv.Spec.PVC.AccessModes) > 0 {
+			item.AccessMode = dv.Spec.PVC.AccessModes[0]
+		}
+		if dv.Spec.PVC.VolumeMode != nil {
+			item.VolumeMode = *dv.Spec.PVC.VolumeMode
+		}
+	}
+	item.Preallocated = dv.Annotations[utils.AnnPreallocation] == "true"
+	if estimatedBytes, err := strconv.ParseInt(dv.Annotations[utils.AnnEstimatedBytes], 10, 64); err == nil {
+		item.EstimatedBytes = estimatedBytes
+	}
+	if sourceBytes, err := strconv.ParseInt(dv.Annotations[utils.AnnSourceBytes], 10, 64); err == nil {
+		item.SourceBytes = sourceBytes
+	}
+	return item
+}
+
+// copiedBytes returns the number of bytes the copy of the data volume transfers: the size of the source disk, or the
+// size of the data volume when the source disk size isn't recorded, e.g. for a clone or a data volume created before
+// the size was recorded. The size of the data volume also holds the file system overhead.
+func copiedBytes(item *v2vv1.DataVolumeItem) int64 {
+	if item.SourceBytes > 0 {
+		return item.SourceBytes
+	}
+	return item.SizeBytes
+}
+
+// sourceDiskID returns the identifier of the source disk the data volume is imported from
+func sourceDiskID(dv *cdiv1.DataVolume) string {
+	switch {
+	case dv.Spec.Source.Imageio != nil:
+		return dv.Spec.Source.Imageio.DiskID
+	case dv.Spec.Source.VDDK != nil:
+		return dv.Spec.Source.VDDK.BackingFile
+	case dv.Spec.Source.HTTP != nil:
+		return dv.Spec.Source.HTTP.URL
+	case dv.Spec.Source.PVC != nil:
+		return dv.Spec.Source.PVC.Namespace + "/" + dv.Spec.Source.PVC.Name
+	}
+	return ""
+}
+
+// dataVolumeProgress parses the progress reported by CDI, e.g. 45.20%, which is N/A until the copy starts
+func dataVolumeProgress(dv *cdiv1.DataVolume) float64 {
+	if dv.Status.Phase == cdiv1.Succeeded {
+		return 100
+	}
+	progress, err := strconv.ParseFloat(strings.TrimRight(string(dv.Status.Progress), "%"), 64)
+	if err != nil {
+		return 0
+	}
+	return progress
+}
+
+// updateDataVolumeItem updates the copy progress of the status item from its data volume. The throughput is averaged
+// since the controller first saw the copy in progress.
+func updateDataVolumeItem(item *v2vv1.DataVolumeItem, dv *cdiv1.DataVolume, now metav1.Time) {
+	if item.SourceDiskID == "" || item.SizeBytes == 0 {
+		created := newDataVolumeItem(dv)
+		item.SourceDiskID = created.SourceDiskID
+		item.SizeBytes = created.SizeBytes
+	}
+	if item.SourceBytes == 0 {
+		item.SourceBytes = newDataVolumeItem(dv).SourceBytes
+	}
+	item.Phase = string(dv.Status.Phase)
+
+	progress := dataVolumeProgress(dv)
+	item.Progress = int(progress)
+	item.BytesTransferred = int64(float64(copiedBytes(item)) * progress / 100)
+
+	if item.StartedAt == nil && (dv.Status.Phase == cdiv1.ImportInProgress || progress > 0) {
+		item.StartedAt = &now
+	}
+	if item.CompletedAt == nil && dv.Status.Phase == cdiv1.Succeeded {
+		item.CompletedAt = &now
+	}
+	if item.StartedAt != nil {
+		end := now.Time
+		if item.CompletedAt != nil {
+			end = item.CompletedAt.Time
+		}
+		if elapsed := end.Sub(item.StartedAt.Time).Seconds(); elapsed > 0 {
+			item.Throughput = int64(float64(item.BytesTransferred) / elapsed)
+		}
+	}
+}
+
+// estimateCompletionTime estimates when the disks that aren't copied yet will be, at the throughput of the disks being
+// copied. CDI copies the disks in parallel, so their throughputs add up. It returns nil when the disks are copied or
+// when none of them is being copied.
+func estimateCompletionTime(items []v2vv1.DataVolumeItem, now metav1.Time) *metav1.Time {
+	var remaining, throughput int64
+	for _, item := range items {
+		if item.CompletedAt != nil {
+			continue
+		}
+		remaining += copiedBytes(&item) - item.BytesTransferred
+		if item.Phase == string(cdiv1.ImportInProgress) {
+			throughput += item.Throughput
+		}
+	}
+	if remaining <= 0 || throughput <= 0 {
+		return nil
+	}
+	seconds := math.Ceil(float64(remaining) / float64(throughput))
+	eta := metav1.NewTime(now.Add(time.Duration(seconds) * time.Second))
+	return &eta
+}
+
+// updateDisksProgress stores the copy progress of the data volumes and the estimated completion time of the copy
+func (r *ReconcileVirtualMachineImport) updateDisksProgress(vmiName types.NamespacedName, dvs []*cdiv1.DataVolume) error {
+	var instance v2vv1.VirtualMachineImport
+	err := r.apiReader.Get(context.TODO(), vmiName, &instance)
+	if err != nil {
+		return err
+	}
+
+	copy := instance.DeepCopy()
+	now := metav1.Now()
+	for _, dv := range dvs {
+		index := -1
+		for i := range copy.Status.DataVolumes {
+			if copy.Status.DataVolumes[i].Name == dv.Name {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			copy.Status.DataVolumes = append(copy.Status.DataVolumes, newDataVolumeItem(dv))
+			index = len(copy.Status.DataVolumes) - 1
+		}
+		updateDataVolumeItem(&copy.Status.DataVolumes[index], dv, now)
+	}
+	copy.Status.EstimatedCompletionTime = estimateCompletionTime(copy.Status.DataVolumes, now)
+
+	if equality.Semantic.DeepEqual(instance.Status, copy.Status) {
+		return nil
+	}
+	err = r.client.Status().Update(context.TODO(), copy)
+	if err != nil {
+		return err
+	}
+	saveCopiedBytes(copy, instance.Status.DataVolumes, dvs)
+	return nil
+}
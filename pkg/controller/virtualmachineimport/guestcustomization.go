@@ -0,0 +1,77 @@
+package virtualmachineimport
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/guestconversion"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// customizationFailedExitCode is the exit code of the conversion pod when it converted the guest but failed to
+// customize it
+const customizationFailedExitCode = 2
+
+// loadGuestCustomization reads the guest customization config map of the import, if the import customizes the guest.
+// The returned message explains why the guest can't be customized, e.g. when the config map doesn't exist.
+func (r *ReconcileVirtualMachineImport) loadGuestCustomization(instance *v2vv1.VirtualMachineImport) (*guestconversion.GuestCustomization, string, error) {
+	if instance.Spec.GuestCustomization == nil {
+		return nil, "", nil
+	}
+	configMap := &corev1.ConfigMap{}
+	name := types.NamespacedName{Name: instance.Spec.GuestCustomization.ConfigMap.Name, Namespace: instance.Namespace}
+	err := r.client.Get(context.TODO(), name, configMap)
+	if k8serrors.IsNotFound(err) {
+		return nil, fmt.Sprintf("guest customization config map %s not found", name.Name), nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	customization, err := guestconversion.ParseGuestCustomization(configMap)
+	if err != nil {
+		return nil, err.Error(), nil
+	}
+	return customization, "", nil
+}
+
+// guestCustomizationStatus reports the customization of the guest by the finished conversion pod. It is nil when the
+// import doesn't customize the guest or when the pod failed before customizing it.
+func guestCustomizationStatus(customization *guestconversion.GuestCustomization, pod *corev1.Pod) *v2vv1.GuestCustomizationStatus {
+	if customization == nil {
+		return nil
+	}
+	if pod.Status.Phase == corev1.PodFailed {
+		if conversionExitCode(pod) != customizationFailedExitCode {
+			return nil
+		}
+		return &v2vv1.GuestCustomizationStatus{
+			Phase:   v2vv1.GuestCustomizationFailed,
+			Message: fmt.Sprintf("virt-customize failed, see the log of pod %s", pod.Name),
+		}
+	}
+	files := make([]string, 0, len(customization.Files))
+	for _, path := range customization.Files {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return &v2vv1.GuestCustomizationStatus{
+		Phase:    v2vv1.GuestCustomizationApplied,
+		Scripts:  customization.Scripts,
+		Files:    files,
+		Packages: customization.Packages,
+	}
+}
+
+// conversionExitCode returns the exit code of the virt-v2v container of the conversion pod, -1 when it didn't exit
+func conversionExitCode(pod *corev1.Pod) int32 {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == conversionContainerName && status.State.Terminated != nil {
+			return status.State.Terminated.ExitCode
+		}
+	}
+	return -1
+}
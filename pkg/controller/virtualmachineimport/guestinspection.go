@@ -0,0 +1,223 @@
+package virtualmachineimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/conditions"
+	"github.com/kubevirt/vm-import-operator/pkg/guestconversion"
+	"github.com/kubevirt/vm-import-operator/pkg/templates"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// ConversionLogKey is the key of the tail of the virt-v2v log in the conversion log config map
+	ConversionLogKey = "virt-v2v.log"
+
+	conversionContainerName = "virt-v2v"
+	conversionLogTailLines  = int64(1000)
+	// a config map holds at most 1MiB
+	conversionLogLimitBytes = int64(512 * 1024)
+
+	// defaultTemplateNamespace is where the templates are looked for when the VM doesn't tell the namespace of its template
+	defaultTemplateNamespace = "openshift"
+)
+
+// windowsStorageDrivers are the virtio drivers a Windows guest needs to boot from virtio disks
+var windowsStorageDrivers = []string{"viostor", "vioscsi"}
+
+// guestInspection returns the inspection virt-v2v wrote in the termination message of the conversion pod, if any
+func guestInspection(pod *corev1.Pod) *v2vv1.GuestInspection {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != conversionContainerName || status.State.Terminated == nil || status.State.Terminated.Message == "" {
+			continue
+		}
+		inspection := v2vv1.GuestInspection{}
+		err := json.Unmarshal([]byte(status.State.Terminated.Message), &inspection)
+		if err != nil {
+			log.Info("Cannot parse the guest inspection", "Pod.Name", pod.Name, "Error", err.Error())
+			return nil
+		}
+		return &inspection
+	}
+	return nil
+}
+
+// guestInspectionWarnings returns the issues the inspection found in the converted guest
+func guestInspectionWarnings(inspection *v2vv1.GuestInspection) []string {
+	var warnings []string
+	if inspection.Type == "windows" && !hasAnyDriver(inspection, windowsStorageDrivers) {
+		warnings = append(warnings, fmt.Sprintf("virt-v2v didn't install the virtio-win storage drivers (%s) in the guest, it may not boot", strings.Join(windowsStorageDrivers, ", ")))
+	}
+	return warnings
+}
+
+// templateMismatchWarning returns a warning when the VM was created from a template for another operating system than
+// the one virt-v2v found in the guest. The warning names the newest template for that operating system, of the same
+// workload and flavor, or tells that no template matches it. The VM keeps its template: its spec was created from it,
+// so only the user can tell whether the VM has to be created again from another template.
+func (r *ReconcileVirtualMachineImport) templateMismatchWarning(vm *kubevirtv1.VirtualMachine, inspection *v2vv1.GuestInspection) (string, error) {
+	templateOS := templateLabelValues(vm, templates.TemplateOsLabel)
+	if inspection.OSInfo == "" || len(templateOS) == 0 || contains(templateOS, inspection.OSInfo) {
+		return "", nil
+	}
+
+	namespace := vm.GetLabels()[templates.TemplateNamespaceLabel]
+	if namespace == "" {
+		namespace = defaultTemplateNamespace
+	}
+	var workload, flavor *string
+	if workloads := templateLabelValues(vm, templates.TemplateWorkloadLabel); len(workloads) > 0 {
+		workload = &workloads[0]
+	}
+	if flavors := templateLabelValues(vm, templates.TemplateFlavorLabel); len(flavors) > 0 {
+		flavor = &flavors[0]
+	}
+	tmpls, err := r.templateProvider.Find(&namespace, &inspection.OSInfo, workload, flavor)
+	if err != nil {
+		return "", err
+	}
+	if len(tmpls.Items) == 0 {
+		return fmt.Sprintf("virt-v2v found %s in the guest but the VM was created from a template for %s and no template matches %s", inspection.OSInfo, strings.Join(templateOS, ", "), inspection.OSInfo), nil
+	}
+	sort.Slice(tmpls.Items, func(i, j int) bool {
+		return tmpls.Items[j].CreationTimestamp.Before(&tmpls.Items[i].CreationTimestamp)
+	})
+	template := &tmpls.Items[0]
+	return fmt.Sprintf("virt-v2v found %s in the guest but the VM was created from a template for %s, template %s/%s matches %s", inspection.OSInfo, strings.Join(templateOS, ", "), template.Namespace, template.Name, inspection.OSInfo), nil
+}
+
+// templateLabelValues returns the values the label format of a template takes in the labels of the VM, for instance the
+// operating systems of the template the VM was created from
+func templateLabelValues(vm metav1.Object, labelFormat string) []string {
+	prefix := strings.TrimSuffix(labelFormat, "%s")
+	var values []string
+	for label := range vm.GetLabels() {
+		if strings.HasPrefix(label, prefix) {
+			values = append(values, strings.TrimPrefix(label, prefix))
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+func hasAnyDriver(inspection *v2vv1.GuestInspection, drivers []string) bool {
+	for _, driver := range drivers {
+		if contains(inspection.Drivers, driver) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// collectGuestConversionResults stores the guest inspection, the result of the guest customization and the tail of the
+// log of the finished conversion pod, and reports the issues found in the guest, including a template of the VM that
+// doesn't match the operating system found in the guest, as warnings of the Valid condition
+func (r *ReconcileVirtualMachineImport) collectGuestConversionResults(instance *v2vv1.VirtualMachineImport, pod *corev1.Pod, vm *kubevirtv1.VirtualMachine, customization *guestconversion.GuestCustomization) error {
+	if instance.Status.ConversionLog != nil {
+		return nil
+	}
+	configMap, err := r.createConversionLogConfigMap(instance, pod)
+	if err != nil {
+		return err
+	}
+
+	instanceCopy := instance.DeepCopy()
+	instance.Status.ConversionLog = &corev1.LocalObjectReference{Name: configMap.Name}
+	instance.Status.GuestInspection = guestInspection(pod)
+	instance.Status.GuestCustomization = guestCustomizationStatus(customization, pod)
+	if instance.Status.GuestInspection != nil {
+		warnings := guestInspectionWarnings(instance.Status.GuestInspection)
+		templateWarning, err := r.templateMismatchWarning(vm, instance.Status.GuestInspection)
+		if err != nil {
+			return err
+		}
+		if templateWarning != "" {
+			warnings = append(warnings, templateWarning)
+		}
+		if len(warnings) > 0 {
+			conditions.UpsertCondition(instance, validConditionWithWarnings(instance, warnings))
+		}
+	}
+	return r.client.Status().Patch(context.TODO(), instance, client.MergeFrom(instanceCopy))
+}
+
+// validConditionWithWarnings returns the Valid condition of the import with the warnings added to it
+func validConditionWithWarnings(instance *v2vv1.VirtualMachineImport, warnings []string) v2vv1.VirtualMachineImportCondition {
+	message := strings.Join(warnings, "; ")
+	if current := conditions.FindConditionOfType(instance.Status.Conditions, v2vv1.Valid); current != nil && current.Message != nil &&
+		current.Reason != nil && *current.Reason == string(v2vv1.ValidationReportedWarnings) {
+		message = *current.Message + "; " + message
+	}
+	return conditions.NewCondition(v2vv1.Valid, string(v2vv1.ValidationReportedWarnings), message, corev1.ConditionTrue)
+}
+
+// createConversionLogConfigMap stores the tail of the log of the conversion pod in a config map owned by the import
+func (r *ReconcileVirtualMachineImport) createConversionLogConfigMap(instance *v2vv1.VirtualMachineImport, pod *corev1.Pod) (*corev1.ConfigMap, error) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name + "-log",
+			Namespace: pod.Namespace,
+		},
+		Data: map[string]string{
+			ConversionLogKey: r.conversionLogTail(pod),
+		},
+	}
+	if err := controllerutil.SetControllerReference(instance, configMap, r.scheme); err != nil {
+		return nil, err
+	}
+	err := r.client.Create(context.TODO(), configMap)
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		return nil, err
+	}
+	return configMap, nil
+}
+
+// conversionLogTail returns the last lines of the log of the conversion pod. The log is informative, so a failure to
+// read it is only logged.
+func (r *ReconcileVirtualMachineImport) conversionLogTail(pod *corev1.Pod) string {
+	logs, err := r.podLogs.TailLogs(pod, conversionContainerName, conversionLogTailLines, conversionLogLimitBytes)
+	if err != nil {
+		log.Error(err, "Cannot read the log of the conversion pod", "Pod.Namespace", pod.Namespace, "Pod.Name", pod.Name)
+		return fmt.Sprintf("Cannot read the log of pod %s: %s", pod.Name, err.Error())
+	}
+	return string(logs)
+}
+
+// PodLogReader reads the logs of pods
+type PodLogReader interface {
+	TailLogs(pod *corev1.Pod, container string, lines int64, limitBytes int64) ([]byte, error)
+}
+
+// kubePodLogReader reads the logs of pods from the Kubernetes API
+type kubePodLogReader struct {
+	client kubernetes.Interface
+}
+
+// TailLogs returns at most the given number of last lines and bytes of the log of the container of the pod
+func (r *kubePodLogReader) TailLogs(pod *corev1.Pod, container string, lines int64, limitBytes int64) ([]byte, error) {
+	return r.client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container:  container,
+		TailLines:  &lines,
+		LimitBytes: &limitBytes,
+	}).DoRaw(context.TODO())
+}
@@ -0,0 +1,74 @@
+package virtualmachineimport
+
+import (
+	"context"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/metrics"
+	corev1 "k8s.io/api/core/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+)
+
+// pvcSourceType is the type reported for the VMs created from disks already in persistent volume claims
+const pvcSourceType = "pvc"
+
+// providerType returns the type of the source provider the VM is imported from
+func providerType(instance *v2vv1.VirtualMachineImport) string {
+	switch {
+	case instance.Spec.Source.Ovirt != nil:
+		return string(v2vv1.OvirtProviderType)
+	case instance.Spec.Source.Vmware != nil:
+		return string(v2vv1.VmwareProviderType)
+	case instance.Spec.Source.PVC != nil:
+		return pvcSourceType
+	}
+	return ""
+}
+
+// countImportsInProgress counts the imports that are neither done nor waiting to be processed by phase and provider
+func (r *ReconcileVirtualMachineImport) countImportsInProgress() (map[metrics.ImportsInProgress]int, error) {
+	list := v2vv1.VirtualMachineImportList{}
+	err := r.client.List(context.TODO(), &list)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[metrics.ImportsInProgress]int)
+	for i := range list.Items {
+		phase := list.Items[i].Status.Phase
+		if phase == "" || isFinalPhase(phase) {
+			continue
+		}
+		counts[metrics.ImportsInProgress{Phase: string(phase), Provider: providerType(&list.Items[i])}]++
+	}
+	return counts, nil
+}
+
+// saveCopiedBytes counts the bytes copied to the data volumes since their previous status
+func saveCopiedBytes(instance *v2vv1.VirtualMachineImport, previous []v2vv1.DataVolumeItem, dvs []*cdiv1.DataVolume) {
+	transferred := make(map[string]int64)
+	for _, item := range previous {
+		transferred[item.Name] = item.BytesTransferred
+	}
+	for _, item := range instance.Status.DataVolumes {
+		for _, dv := range dvs {
+			if dv.Name != item.Name {
+				continue
+			}
+			var storageClass string
+			if dv.Spec.PVC != nil && dv.Spec.PVC.StorageClassName != nil {
+				storageClass = *dv.Spec.PVC.StorageClassName
+			}
+			metrics.ImportMetrics.AddCopiedBytes(providerType(instance), storageClass, item.BytesTransferred-transferred[item.Name])
+		}
+	}
+}
+
+// saveValidationFailures counts the conditions that fail the validation of the import by reason, whatever the provider
+func saveValidationFailures(instance *v2vv1.VirtualMachineImport, conditions ...v2vv1.VirtualMachineImportCondition) {
+	for _, condition := range conditions {
+		if condition.Status == corev1.ConditionFalse && condition.Reason != nil {
+			metrics.ImportMetrics.IncValidationFailure(instance.UID, *condition.Reason)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package virtualmachineimport
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/metrics"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// phaseTransitions are the phases an import can move to from each of the phases, the states and transitions of the
+// state machine every import goes through. Succeeded and Failed are final. An import without a phase, i.e. one started
+// before the phase was recorded, can move to any phase. The VM of an import whose disks are already in persistent
+// volume claims and whose guest isn't converted is started, or done, right after it's created.
+var phaseTransitions = map[v2vv1.VirtualMachineImportPhase][]v2vv1.VirtualMachineImportPhase{
+	v2vv1.PhasePending:        {v2vv1.PhaseValidating, v2vv1.PhaseFailed},
+	v2vv1.PhaseValidating:     {v2vv1.PhaseStoppingSource, v2vv1.PhaseCreating, v2vv1.PhaseSucceeded, v2vv1.PhaseFailed},
+	v2vv1.PhaseStoppingSource: {v2vv1.PhaseCreating, v2vv1.PhaseCopyingDisks, v2vv1.PhaseFailed},
+	v2vv1.PhaseCreating:       {v2vv1.PhaseStoppingSource, v2vv1.PhaseWarmCopying, v2vv1.PhaseCopyingDisks, v2vv1.PhaseConverting, v2vv1.PhaseStarting, v2vv1.PhaseSucceeded, v2vv1.PhaseFailed},
+	v2vv1.PhaseWarmCopying:    {v2vv1.PhaseStoppingSource, v2vv1.PhaseCopyingDisks, v2vv1.PhaseFailed},
+	v2vv1.PhaseCopyingDisks:   {v2vv1.PhaseConverting, v2vv1.PhaseStarting, v2vv1.PhaseSucceeded, v2vv1.PhaseFailed},
+	v2vv1.PhaseConverting:     {v2vv1.PhaseStarting, v2vv1.PhaseSucceeded, v2vv1.PhaseFailed},
+	v2vv1.PhaseStarting:       {v2vv1.PhaseSucceeded, v2vv1.PhaseFailed},
+}
+
+// phaseProgress is the progress of an import when it enters each of the phases
+var phaseProgress = map[v2vv1.VirtualMachineImportPhase]int{
+	v2vv1.PhaseCopyingDisks: progressCopyingDisks,
+	v2vv1.PhaseConverting:   progressConvertingGuest,
+	v2vv1.PhaseStarting:     progressStartVM,
+	v2vv1.PhaseSucceeded:    progressDone,
+	v2vv1.PhaseFailed:       progressDone,
+}
+
+func canTransition(from v2vv1.VirtualMachineImportPhase, to v2vv1.VirtualMachineImportPhase) bool {
+	if from == "" {
+		return true
+	}
+	for _, phase := range phaseTransitions[from] {
+		if phase == to {
+			return true
+		}
+	}
+	return false
+}
+
+// isFinalPhase returns whether the import is done, successfully or not
+func isFinalPhase(phase v2vv1.VirtualMachineImportPhase) bool {
+	return phase == v2vv1.PhaseSucceeded || phase == v2vv1.PhaseFailed
+}
+
+// enterPhase moves the status to the phase, recording when the previous phase was left and the new one entered, and
+// raises the progress to the one of the phase. It fails if the phase can't be reached from the current one.
+func enterPhase(status *v2vv1.VirtualMachineImportStatus, phase v2vv1.VirtualMachineImportPhase, now metav1.Time) error {
+	if status.Phase == phase {
+		return nil
+	}
+	if !canTransition(status.Phase, phase) {
+		return fmt.Errorf("import can't move from phase %s to phase %s", status.Phase, phase)
+	}
+
+	if count := len(status.PhaseTransitions); count > 0 && status.PhaseTransitions[count-1].CompletedAt == nil {
+		previous := &status.PhaseTransitions[count-1]
+		previous.CompletedAt = &now
+	}
+	transition := v2vv1.PhaseTransition{Phase: phase, StartedAt: now}
+	if isFinalPhase(phase) {
+		transition.CompletedAt = &now
+	}
+	status.PhaseTransitions = append(status.PhaseTransitions, transition)
+	status.Phase = phase
+	if progress := phaseProgress[phase]; status.Progress < progress {
+		status.Progress = progress
+	}
+	return nil
+}
+
+// updatePhase is the only way an import moves to another phase: the transition is checked against phaseTransitions
+// and stored in the status together with the other changes of the status it comes with, so that both are seen at once.
+// The progress annotation is kept in sync for the clients that read the progress from it.
+func (r *ReconcileVirtualMachineImport) updatePhase(instance *v2vv1.VirtualMachineImport, phase v2vv1.VirtualMachineImportPhase, changes ...func(*v2vv1.VirtualMachineImport)) error {
+	if instance.Status.Phase == phase && len(changes) == 0 {
+		return nil
+	}
+	instanceCopy := instance.DeepCopy()
+	err := enterPhase(&instance.Status, phase, metav1.Now())
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		change(instance)
+	}
+	err = r.client.Status().Patch(context.TODO(), instance, client.MergeFrom(instanceCopy))
+	if err != nil {
+		return err
+	}
+	savePhaseDuration(&instanceCopy.Status, &instance.Status)
+	if instanceCopy.Status.Phase != phase {
+		log.Info("Import phase changed", "Request.Namespace", instance.Namespace, "Request.Name", instance.Name, "From", instanceCopy.Status.Phase, "To", phase)
+	}
+	return r.updateProgressAnnotation(instance)
+}
+
+// savePhaseDuration saves in the metrics the time spent in the phase that was in progress before the status was
+// updated and that the update completed. It is only called once the update is stored, so that an update retried after
+// a conflict doesn't save the same phase twice.
+func savePhaseDuration(before *v2vv1.VirtualMachineImportStatus, after *v2vv1.VirtualMachineImportStatus) {
+	count := len(before.PhaseTransitions)
+	if count == 0 || before.PhaseTransitions[count-1].CompletedAt != nil || len(after.PhaseTransitions) < count {
+		return
+	}
+	left := after.PhaseTransitions[count-1]
+	if left.CompletedAt == nil {
+		return
+	}
+	metrics.ImportMetrics.SavePhaseDuration(string(left.Phase), left.CompletedAt.Sub(left.StartedAt.Time).Seconds())
+}
+
+// updateProgress raises the progress of the import, the progress never goes back
+func (r *ReconcileVirtualMachineImport) updateProgress(instance *v2vv1.VirtualMachineImport, progress int) error {
+	if instance.Status.Progress >= progress {
+		return nil
+	}
+	instanceCopy := instance.DeepCopy()
+	instance.Status.Progress = progress
+	err := r.client.Status().Patch(context.TODO(), instance, client.MergeFrom(instanceCopy))
+	if err != nil {
+		return err
+	}
+	return r.updateProgressAnnotation(instance)
+}
+
+// updateProgressAnnotation copies the progress of the status to the annotation it was stored in before, once the
+// import is in progress since the annotation also tells that the import started
+func (r *ReconcileVirtualMachineImport) updateProgressAnnotation(instance *v2vv1.VirtualMachineImport) error {
+	switch instance.Status.Phase {
+	case "", v2vv1.PhasePending, v2vv1.PhaseValidating:
+		return nil
+	}
+	progress := strconv.Itoa(instance.Status.Progress)
+	if instance.Annotations[AnnCurrentProgress] == progress {
+		return nil
+	}
+	instanceCopy := instance.DeepCopy()
+	if instance.Annotations == nil {
+		instance.Annotations = make(map[string]string)
+	}
+	instance.Annotations[AnnCurrentProgress] = progress
+	return r.client.Patch(context.TODO(), instance, client.MergeFrom(instanceCopy))
+}
+
+// vmImportInProgress returns whether the import started processing the source VM, even if it's done already
+func (r *ReconcileVirtualMachineImport) vmImportInProgress(instance *v2vv1.VirtualMachineImport) bool {
+	switch instance.Status.Phase {
+	case "", v2vv1.PhasePending, v2vv1.PhaseValidating:
+		// imports started before the phase was recorded only have the progress annotation
+		_, found := instance.Annotations[AnnCurrentProgress]
+		return found
+	}
+	return true
+}
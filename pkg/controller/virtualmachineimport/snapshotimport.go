@@ -0,0 +1,97 @@
+package virtualmachineimport
+
+import (
+	"context"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	provider "github.com/kubevirt/vm-import-operator/pkg/providers"
+	"github.com/kubevirt/vm-import-operator/pkg/utils"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func shouldSnapshotImport(provider provider.Provider, instance *v2vv1.VirtualMachineImport) bool {
+	return instance.Spec.Snapshot && provider.SupportsSnapshotImport()
+}
+
+// validateSnapshotImport returns the reason why the requested snapshot import can't be performed, if any.
+func validateSnapshotImport(provider provider.Provider, instance *v2vv1.VirtualMachineImport) string {
+	if !instance.Spec.Snapshot {
+		return ""
+	}
+	if instance.Spec.Warm {
+		return "Snapshot import can't be combined with warm import"
+	}
+	if !provider.SupportsSnapshotImport() {
+		return "Source provider doesn't support importing from a snapshot of the running VM"
+	}
+	return ""
+}
+
+// copiedFromSnapshot tells whether the data volume copies a disk of the source VM, which can be copied from a snapshot.
+// The ISO images of the CD-ROMs are copied as they are.
+func copiedFromSnapshot(dv *cdiv1.DataVolume) bool {
+	return dv.Spec.Source.VDDK != nil || dv.Spec.Source.Imageio != nil
+}
+
+// useSourceSnapshot makes the data volume copy the disk from the snapshot of the source VM, the snapshot
+// is created if it doesn't exist yet. It returns false while the snapshot isn't ready, the data volume
+// must not be created until then.
+func (r *ReconcileVirtualMachineImport) useSourceSnapshot(provider provider.Provider, instance *v2vv1.VirtualMachineImport, dv *cdiv1.DataVolume) (bool, error) {
+	snapshotRef, err := r.ensureSourceSnapshot(provider, instance)
+	if err != nil {
+		return false, err
+	}
+	ready, err := provider.VMSnapshotReady(snapshotRef)
+	if err != nil || !ready {
+		return false, err
+	}
+	dv.Spec.Checkpoints = []cdiv1.DataVolumeCheckpoint{
+		{Previous: "", Current: snapshotRef},
+	}
+	dv.Spec.FinalCheckpoint = true
+	return true, nil
+}
+
+func (r *ReconcileVirtualMachineImport) ensureSourceSnapshot(provider provider.Provider, instance *v2vv1.VirtualMachineImport) (string, error) {
+	if instance.Status.SourceSnapshot != nil {
+		return *instance.Status.SourceSnapshot, nil
+	}
+
+	err := utils.AddFinalizer(instance, utils.CleanupSnapshotsFinalizer, r.client)
+	if err != nil {
+		return "", err
+	}
+	snapshotRef, err := provider.CreateVMSnapshot()
+	if err != nil {
+		return "", err
+	}
+
+	instanceCopy := instance.DeepCopy()
+	instance.Status.SourceSnapshot = &snapshotRef
+	err = r.client.Status().Patch(context.TODO(), instance, client.MergeFrom(instanceCopy))
+	if err != nil {
+		return "", err
+	}
+	return snapshotRef, nil
+}
+
+// removeSourceSnapshot removes the snapshot of the source VM once all the disks have been copied from it.
+func (r *ReconcileVirtualMachineImport) removeSourceSnapshot(provider provider.Provider, instance *v2vv1.VirtualMachineImport) error {
+	if instance.Status.SourceSnapshot == nil {
+		return nil
+	}
+
+	err := provider.RemoveVMSnapshot(*instance.Status.SourceSnapshot, false)
+	if err != nil {
+		return err
+	}
+
+	instanceCopy := instance.DeepCopy()
+	instance.Status.SourceSnapshot = nil
+	err = r.client.Status().Patch(context.TODO(), instance, client.MergeFrom(instanceCopy))
+	if err != nil {
+		return err
+	}
+	return utils.RemoveFinalizer(instance, utils.CleanupSnapshotsFinalizer, r.client)
+}
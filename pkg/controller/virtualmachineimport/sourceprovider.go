@@ -0,0 +1,220 @@
+package virtualmachineimport
+
+import (
+	"context"
+	"fmt"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/controller/index"
+	provider "github.com/kubevirt/vm-import-operator/pkg/providers"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// fetchSourceProvider fetches the Provider resource referenced by the import
+func (r *ReconcileVirtualMachineImport) fetchSourceProvider(instance *v2vv1.VirtualMachineImport) (*v2vv1.Provider, error) {
+	namespace := instance.Namespace
+	if instance.Spec.Provider.Namespace != nil {
+		namespace = *instance.Spec.Provider.Namespace
+	}
+	sourceProvider := &v2vv1.Provider{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.Provider.Name, Namespace: namespace}, sourceProvider)
+	return sourceProvider, err
+}
+
+// fetchProviderCredentials builds the credentials secret of the import from the Provider resource it references
+// and the secret of the Provider
+func (r *ReconcileVirtualMachineImport) fetchProviderCredentials(instance *v2vv1.VirtualMachineImport) (*corev1.Secret, string, error) {
+	sourceProvider, err := r.fetchSourceProvider(instance)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			message := "Provider not found"
+			cerr := r.upsertValidationCondition(instance, v2vv1.ProviderNotFound, message, err)
+			if cerr != nil {
+				return nil, message, cerr
+			}
+		}
+		return nil, "Failed to read the provider", err
+	}
+
+	sourceType, _ := provider.SourceTypeOf(instance)
+	if sourceProvider.Spec.Type != sourceType {
+		message := "Provider doesn't match the source"
+		err = fmt.Errorf("provider %s/%s is of type %s", sourceProvider.Namespace, sourceProvider.Name, sourceProvider.Spec.Type)
+		cerr := r.upsertValidationCondition(instance, v2vv1.IncompatibleProvider, message, err)
+		if cerr != nil {
+			return nil, message, cerr
+		}
+		return nil, message, err
+	}
+
+	secretNamespace := sourceProvider.Namespace
+	if sourceProvider.Spec.SecretRef.Namespace != nil {
+		secretNamespace = *sourceProvider.Spec.SecretRef.Namespace
+	}
+	secret := &corev1.Secret{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: sourceProvider.Spec.SecretRef.Name, Namespace: secretNamespace}, secret)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			message := "Secret not found"
+			cerr := r.upsertValidationCondition(instance, v2vv1.SecretNotFound, message, err)
+			if cerr != nil {
+				return nil, message, cerr
+			}
+		}
+		return nil, "Failed to read the secret", err
+	}
+
+	caBundle, err := provider.CABundleOf(r.client, sourceProvider)
+	if err != nil {
+		message := "Failed to read the CA bundle of the provider"
+		cerr := r.upsertValidationCondition(instance, v2vv1.UninitializedProvider, message, err)
+		if cerr != nil {
+			return nil, message, cerr
+		}
+		return nil, message, err
+	}
+
+	credentials, err := provider.CredentialsSecretOf(sourceProvider, secret, caBundle)
+	if err != nil {
+		message := "Source provider initialization failed"
+		cerr := r.upsertValidationCondition(instance, v2vv1.UninitializedProvider, message, err)
+		if cerr != nil {
+			return nil, message, cerr
+		}
+		return nil, message, err
+	}
+	return credentials, "", nil
+}
+
+// auditInsecureConnection records the import connecting to the source provider without verifying its certificate
+func (r *ReconcileVirtualMachineImport) auditInsecureConnection(instance *v2vv1.VirtualMachineImport, credentials *corev1.Secret) {
+	sourceType, ok := provider.SourceTypeOf(instance)
+	if !ok || !provider.SkipsVerification(credentials, sourceType) {
+		return
+	}
+	log.Info("Connecting to the source provider without verifying its certificate", "Request.Namespace", instance.Namespace, "Request.Name", instance.Name)
+	r.recorder.Event(instance, corev1.EventTypeWarning, EventInsecureConnection, "Connecting to the source provider without verifying its certificate")
+}
+
+// setProviderTransferNetwork makes the importer pod of the data volume use the transfer network of the Provider
+// resource, unless the import sets the network of the importer pods itself
+func (r *ReconcileVirtualMachineImport) setProviderTransferNetwork(instance *v2vv1.VirtualMachineImport, dv *cdiv1.DataVolume) error {
+	if instance.Spec.Provider == nil {
+		return nil
+	}
+	if _, found := dv.Annotations[AnnDVNetwork]; found {
+		return nil
+	}
+	sourceProvider, err := r.fetchSourceProvider(instance)
+	if err != nil {
+		return err
+	}
+	network := sourceProvider.Spec.TransferNetwork
+	if network == nil {
+		return nil
+	}
+	if dv.Annotations == nil {
+		dv.Annotations = map[string]string{}
+	}
+	if network.Namespace != nil {
+		dv.Annotations[AnnDVNetwork] = *network.Namespace + "/" + network.Name
+	} else {
+		dv.Annotations[AnnDVNetwork] = network.Name
+	}
+	return nil
+}
+
+// importsOfSecret maps a secret to the imports whose credentials come from it, either directly or through their
+// Provider resource, and to the imports of the providers whose CA bundle it holds
+func (r *ReconcileVirtualMachineImport) importsOfSecret(object handler.MapObject) []reconcile.Request {
+	secret := types.NamespacedName{Name: object.Meta.GetName(), Namespace: object.Meta.GetNamespace()}
+	return r.importsReferencing(secret, index.SecretKind, true, func(p *v2vv1.Provider) bool {
+		return identifies(p.Spec.SecretRef, p.Namespace, secret) || holdsCABundle(p, v2vv1.SecretCABundle, secret)
+	})
+}
+
+// importsOfConfigMap maps a config map to the imports of the providers whose CA bundle it holds
+func (r *ReconcileVirtualMachineImport) importsOfConfigMap(object handler.MapObject) []reconcile.Request {
+	configMap := types.NamespacedName{Name: object.Meta.GetName(), Namespace: object.Meta.GetNamespace()}
+	return r.importsReferencing(configMap, index.ConfigMapKind, false, func(p *v2vv1.Provider) bool {
+		return holdsCABundle(p, v2vv1.ConfigMapCABundle, configMap)
+	})
+}
+
+// importsReferencing maps an object to the imports of the providers referencing it and, if it is a credentials
+// secret, to the imports referencing it directly. The providers and the imports are looked up through the
+// indexes of the cache, the references are checked again since the indexes are only a first filter.
+func (r *ReconcileVirtualMachineImport) importsReferencing(object types.NamespacedName, kind string, credentials bool, referencing func(*v2vv1.Provider) bool) []reconcile.Request {
+	providers := &v2vv1.ProviderList{}
+	err := r.client.List(context.TODO(), providers, client.MatchingFields{index.ProviderReferencesField: index.Key(kind, object)})
+	if err != nil {
+		log.Error(err, "Failed to list the providers referencing an object", "Object", object)
+		return nil
+	}
+	var keys []string
+	if credentials {
+		keys = append(keys, index.Key(kind, object))
+	}
+	providersReferencing := make(map[types.NamespacedName]bool)
+	for i := range providers.Items {
+		p := &providers.Items[i]
+		if referencing(p) {
+			name := types.NamespacedName{Name: p.Name, Namespace: p.Namespace}
+			providersReferencing[name] = true
+			keys = append(keys, index.Key(index.ProviderKind, name))
+		}
+	}
+
+	found := make(map[types.NamespacedName]bool)
+	var requests []reconcile.Request
+	for _, key := range keys {
+		imports := &v2vv1.VirtualMachineImportList{}
+		err = r.client.List(context.TODO(), imports, client.MatchingFields{index.ImportReferencesField: key})
+		if err != nil {
+			log.Error(err, "Failed to list the imports referencing an object", "Object", object)
+			return nil
+		}
+		for _, vmImport := range imports.Items {
+			referenced := false
+			if secretID := vmImport.Spec.ProviderCredentialsSecret; secretID != nil && credentials {
+				referenced = identifies(*secretID, vmImport.Namespace, object)
+			}
+			if providerID := vmImport.Spec.Provider; providerID != nil {
+				namespace := vmImport.Namespace
+				if providerID.Namespace != nil {
+					namespace = *providerID.Namespace
+				}
+				referenced = referenced || providersReferencing[types.NamespacedName{Name: providerID.Name, Namespace: namespace}]
+			}
+			name := types.NamespacedName{Name: vmImport.Name, Namespace: vmImport.Namespace}
+			if referenced && !found[name] {
+				found[name] = true
+				requests = append(requests, reconcile.Request{NamespacedName: name})
+			}
+		}
+	}
+	return requests
+}
+
+// holdsCABundle returns whether the object of the given kind holds the CA bundle of the provider
+func holdsCABundle(p *v2vv1.Provider, kind v2vv1.CABundleKind, name types.NamespacedName) bool {
+	if p.Spec.TLS == nil || p.Spec.TLS.CABundleRef == nil || p.Spec.TLS.CABundleRef.Kind != kind {
+		return false
+	}
+	ref := p.Spec.TLS.CABundleRef
+	return identifies(v2vv1.ObjectIdentifier{Name: ref.Name, Namespace: ref.Namespace}, p.Namespace, name)
+}
+
+// identifies returns whether the identifier, relative to the namespace of the resource holding it, refers to name
+func identifies(id v2vv1.ObjectIdentifier, namespace string, name types.NamespacedName) bool {
+	if id.Namespace != nil {
+		namespace = *id.Namespace
+	}
+	return id.Name == name.Name && namespace == name.Namespace
+}
@@ -0,0 +1,86 @@
+package virtualmachineimport
+
+import (
+	"fmt"
+	"time"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/conditions"
+	provider "github.com/kubevirt/vm-import-operator/pkg/providers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// stopSourceVM requests the shut down of the source VM and checks on the following reconciles whether it is down,
+// so that the reconcile doesn't block while the guest shuts down. Once the shut down times out, the source VM is
+// powered off if forcing is allowed, otherwise the import fails. It returns true when the source VM is down.
+func (r *ReconcileVirtualMachineImport) stopSourceVM(vmProvider provider.Provider, instance *v2vv1.VirtualMachineImport) (bool, error) {
+	// the status is unknown while the source VM is in a transitional state, e.g. powering down
+	vmStatus, err := vmProvider.GetVMStatus()
+	if err == nil && vmStatus == provider.VMStatusDown {
+		return true, nil
+	}
+
+	method, timeout, force := provider.SourceShutdownOf(instance)
+	shutdown := instance.Status.SourceShutdown
+	if shutdown == nil || shutdown.RequestedAt == nil {
+		message := fmt.Sprintf("Stopping the source virtual machine with a %s shutdown, timeout %s", method, timeout)
+		if force {
+			message += ", then forcing power off"
+		}
+		err = r.requestSourceShutdown(vmProvider, instance, method, message, func(status *v2vv1.SourceShutdownStatus, now *metav1.Time) {
+			status.RequestedAt = now
+		})
+		return false, err
+	}
+	if time.Since(shutdown.RequestedAt.Time) < timeout {
+		return false, nil
+	}
+
+	if force && method != v2vv1.HardShutdown && shutdown.ForcedAt == nil {
+		message := fmt.Sprintf("Powering the source virtual machine off, it wasn't shut down within %s", timeout)
+		err = r.requestSourceShutdown(vmProvider, instance, v2vv1.HardShutdown, message, func(status *v2vv1.SourceShutdownStatus, now *metav1.Time) {
+			status.ForcedAt = now
+		})
+		return false, err
+	}
+	if shutdown.ForcedAt != nil && time.Since(shutdown.ForcedAt.Time) < timeout {
+		return false, nil
+	}
+
+	message := fmt.Sprintf("source virtual machine wasn't shut down within %s", timeout)
+	r.recorder.Event(instance, corev1.EventTypeWarning, EventSourceVMShutdownFailed, message)
+	return false, r.fail(vmProvider, instance, v2vv1.SourceVMShutdownFailed, message)
+}
+
+// requestSourceShutdown records the request in the status before sending it, so that the timeout applies even if the
+// request fails. A failed guest or ACPI shut down is then escalated once the timeout expires, like a guest that
+// ignores the request.
+func (r *ReconcileVirtualMachineImport) requestSourceShutdown(vmProvider provider.Provider, instance *v2vv1.VirtualMachineImport, method v2vv1.SourceShutdownMethod, message string, record func(*v2vv1.SourceShutdownStatus, *metav1.Time)) error {
+	processingCond := conditions.NewProcessingCondition(string(v2vv1.StoppingSourceVM), message, corev1.ConditionTrue)
+	err := r.upsertStatusConditions(types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, processingCond)
+	if err != nil {
+		return err
+	}
+
+	err = r.updatePhase(instance, v2vv1.PhaseStoppingSource, func(instance *v2vv1.VirtualMachineImport) {
+		if instance.Status.SourceShutdown == nil {
+			instance.Status.SourceShutdown = &v2vv1.SourceShutdownStatus{}
+		}
+		now := metav1.Now()
+		record(instance.Status.SourceShutdown, &now)
+		instance.Status.SourceShutdown.Method = method
+	})
+	if err != nil {
+		return err
+	}
+
+	err = vmProvider.StopVM(instance, r.client, method)
+	if err != nil && method != v2vv1.HardShutdown {
+		log.Error(err, "Failed to request the shut down of the source VM, waiting for the timeout", "Request.Namespace", instance.Namespace, "Request.Name", instance.Name, "Method", method)
+		r.recorder.Event(instance, corev1.EventTypeWarning, EventSourceVMShutdownFailed, fmt.Sprintf("Failed to request a %s shutdown of the source virtual machine: %v", method, err))
+		return nil
+	}
+	return err
+}
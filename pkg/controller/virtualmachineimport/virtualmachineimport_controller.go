@@ -25,19 +25,25 @@ import (
 	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
 	pclient "github.com/kubevirt/vm-import-operator/pkg/client"
 	"github.com/kubevirt/vm-import-operator/pkg/conditions"
+	"github.com/kubevirt/vm-import-operator/pkg/controller/index"
+	"github.com/kubevirt/vm-import-operator/pkg/guestconversion"
 	"github.com/kubevirt/vm-import-operator/pkg/mappings"
 	"github.com/kubevirt/vm-import-operator/pkg/metrics"
 	"github.com/kubevirt/vm-import-operator/pkg/ownerreferences"
 	provider "github.com/kubevirt/vm-import-operator/pkg/providers"
 	ovirtprovider "github.com/kubevirt/vm-import-operator/pkg/providers/ovirt"
+	pvcprovider "github.com/kubevirt/vm-import-operator/pkg/providers/pvc"
+	"github.com/kubevirt/vm-import-operator/pkg/templates"
 	"github.com/kubevirt/vm-import-operator/pkg/utils"
 	templatev1 "github.com/openshift/client-go/template/clientset/versioned/typed/template/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 	kubevirtv1 "kubevirt.io/client-go/api/v1"
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
@@ -54,7 +60,8 @@ import (
 const (
 	annAPIGroup          = "vmimport.v2v.kubevirt.io"
 	sourceVMInitialState = annAPIGroup + "/source-vm-initial-state"
-	// AnnCurrentProgress is annotations storing current progress of the vm import
+	// AnnCurrentProgress is the annotation the progress of the vm import was stored in before status.progress. It is
+	// still kept in sync with status.progress once the import is in progress, for the clients reading it.
 	AnnCurrentProgress = annAPIGroup + "/progress"
 	// AnnPropagate is annotation defining which values to propagate
 	AnnPropagate = annAPIGroup + "/propagate-annotations"
@@ -65,12 +72,11 @@ const (
 	// TrackingLabel is a label used to track related entities.
 	TrackingLabel = annAPIGroup + "/tracker"
 	// constants
-	progressStart           = "0"
-	progressCreatingVM      = "5"
-	progressCopyingDisks    = "10"
-	progressConvertingGuest = "70"
-	progressStartVM         = "90"
-	progressDone            = "100"
+	progressCreatingVM      = 5
+	progressCopyingDisks    = 10
+	progressConvertingGuest = 70
+	progressStartVM         = 90
+	progressDone            = 100
 	progressForCopyDisk     = 65
 	progressCopyDiskRange   = float64(progressForCopyDisk / 100.0)
 
@@ -98,6 +104,10 @@ const (
 	EventGuestConversionFailed = "GuestConversionFailed"
 	// EventWarmImportFailed is emmitted when a warm import attempt fails.
 	EventWarmImportFailed = "WarmImportFailed"
+	// EventSourceVMShutdownFailed is emitted when the source VM isn't shut down before the timeout.
+	EventSourceVMShutdownFailed = "SourceVMShutdownFailed"
+	// EventInsecureConnection is emitted when the import connects to the source provider without verifying its certificate.
+	EventInsecureConnection = "InsecureConnection"
 	// EventVMNotFound is emitted when the target VM cannot be found, perhaps due to being deleted during an import.
 	EventVMNotFound = "VMNotFound"
 
@@ -120,22 +130,26 @@ var (
 
 // Add creates a new VirtualMachineImport Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
-func Add(mgr manager.Manager, kvConfigProvider kvConfig.KubeVirtConfigProvider, ctrlConfigProvider ctrlConfig.ControllerConfigProvider) error {
-	return add(mgr, newReconciler(mgr, kvConfigProvider, ctrlConfigProvider))
+func Add(mgr manager.Manager, kvConfigProvider kvConfig.KubeVirtConfigProvider, ctrlConfigProvider ctrlConfig.ControllerConfigProvider, factory pclient.Factory) error {
+	return add(mgr, newReconciler(mgr, kvConfigProvider, ctrlConfigProvider, factory))
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager, kvConfigProvider kvConfig.KubeVirtConfigProvider, ctrlConfigProvider ctrlConfig.ControllerConfigProvider) *ReconcileVirtualMachineImport {
+func newReconciler(mgr manager.Manager, kvConfigProvider kvConfig.KubeVirtConfigProvider, ctrlConfigProvider ctrlConfig.ControllerConfigProvider, factory pclient.Factory) *ReconcileVirtualMachineImport {
 	tempClient, err := templatev1.NewForConfig(mgr.GetConfig())
 	if err != nil {
 		log.Error(err, "Unable to get OC client")
 		panic("Controller cannot operate without OC client")
 	}
+	kubeClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		log.Error(err, "Unable to get Kubernetes client")
+		panic("Controller cannot operate without Kubernetes client")
+	}
 	reader := mgr.GetAPIReader()
 	client := mgr.GetClient()
 	finder := mappings.NewResourceMappingsFinder(client)
 	ownerreferencesmgr := ownerreferences.NewOwnerReferenceManager(client)
-	factory := pclient.NewSourceClientFactory()
 
 	controllerConfig, err := ctrlConfigProvider.GetConfig()
 	if err != nil {
@@ -146,6 +160,8 @@ func newReconciler(mgr manager.Manager, kvConfigProvider kvConfig.KubeVirtConfig
 		scheme:                 mgr.GetScheme(),
 		resourceMappingsFinder: finder,
 		ocClient:               tempClient,
+		templateProvider:       templates.NewTemplateProvider(tempClient),
+		podLogs:                &kubePodLogReader{client: kubeClient},
 		ownerreferencesmgr:     ownerreferencesmgr,
 		factory:                factory,
 		kvConfigProvider:       kvConfigProvider,
@@ -163,6 +179,7 @@ func add(mgr manager.Manager, r *ReconcileVirtualMachineImport) error {
 		return err
 	}
 	r.controller = c
+	metrics.ImportMetrics.SetInProgressCounter(r.countImportsInProgress)
 
 	// Watch for changes to primary resource VirtualMachineImport
 	err = c.Watch(
@@ -207,6 +224,25 @@ func add(mgr manager.Manager, r *ReconcileVirtualMachineImport) error {
 		return err
 	}
 
+	// Watch for changes to the provider secrets, so that rotated credentials are picked up
+	err = c.Watch(
+		&source.Kind{Type: &corev1.Secret{}},
+		&handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.importsOfSecret)},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the config maps holding the CA bundles of the providers
+	err = c.Watch(
+		&source.Kind{Type: &corev1.ConfigMap{}},
+		&handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.importsOfConfigMap)},
+		index.ReferencedAsCABundle(r.client),
+	)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -221,6 +257,8 @@ type ReconcileVirtualMachineImport struct {
 	scheme                 *runtime.Scheme
 	resourceMappingsFinder mappings.ResourceFinder
 	ocClient               *templatev1.TemplateV1Client
+	templateProvider       templates.TemplateProvider
+	podLogs                PodLogReader
 	ownerreferencesmgr     ownerreferences.OwnerReferenceManager
 	factory                pclient.Factory
 	kvConfigProvider       kvConfig.KubeVirtConfigProvider
@@ -272,6 +310,7 @@ func (r *ReconcileVirtualMachineImport) Reconcile(request reconcile.Request) (re
 
 	// Handle deleted import
 	if instance.DeletionTimestamp != nil {
+		metrics.ImportMetrics.ForgetValidationFailures(instance.UID)
 
 		// We know that additional finalizers after this point are created when VM import is in progress
 		// Therefore, if one of them fails and we return to Reconcile again ==> the code above will not add cancel finalizer again
@@ -294,6 +333,13 @@ func (r *ReconcileVirtualMachineImport) Reconcile(request reconcile.Request) (re
 		}
 	}
 
+	if instance.DeletionTimestamp == nil && instance.Status.Phase == "" && !r.vmImportInProgress(instance) {
+		err = r.updatePhase(instance, v2vv1.PhasePending)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
 	r.filesystemOverhead, err = r.getCDIFilesystemOverhead()
 	if err != nil {
 		return reconcile.Result{}, err
@@ -327,8 +373,13 @@ func (r *ReconcileVirtualMachineImport) Reconcile(request reconcile.Request) (re
 				return reconcile.Result{RequeueAfter: 1 * time.Second}, nil
 			}
 		}
-		if utils.HasFinalizer(instance, utils.CleanupSnapshotsFinalizer) && instance.Status.WarmImport.RootSnapshot != nil {
-			_ = provider.RemoveVMSnapshot(*instance.Status.WarmImport.RootSnapshot, true)
+		if utils.HasFinalizer(instance, utils.CleanupSnapshotsFinalizer) && (instance.Status.WarmImport.RootSnapshot != nil || instance.Status.SourceSnapshot != nil) {
+			if instance.Status.WarmImport.RootSnapshot != nil {
+				_ = provider.RemoveVMSnapshot(*instance.Status.WarmImport.RootSnapshot, true)
+			}
+			if instance.Status.SourceSnapshot != nil {
+				_ = provider.RemoveVMSnapshot(*instance.Status.SourceSnapshot, false)
+			}
 			err = utils.RemoveFinalizer(instance, utils.CleanupSnapshotsFinalizer, r.client)
 			if err != nil {
 				reqLogger.Error(err, "Finalizing - failed to remove snapshot finalizer")
@@ -343,6 +394,25 @@ func (r *ReconcileVirtualMachineImport) Reconcile(request reconcile.Request) (re
 		return reconcile.Result{}, nil
 	}
 
+	if instance.Status.Phase == v2vv1.PhasePending {
+		err = r.updatePhase(instance, v2vv1.PhaseValidating)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Create an import for each of the VMs matched by the VM selector
+	if isSelectorImport(instance) {
+		selected, err := r.expandVMSelector(instance, provider)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if !selected {
+			return reconcile.Result{RequeueAfter: requeueAfterValidationFailureTime}, nil
+		}
+		return reconcile.Result{}, nil
+	}
+
 	// fetch source vm
 	err = r.fetchVM(instance, provider)
 	if err != nil {
@@ -358,8 +428,8 @@ func (r *ReconcileVirtualMachineImport) Reconcile(request reconcile.Request) (re
 		return reconcile.Result{RequeueAfter: requeueAfterValidationFailureTime}, nil
 	}
 
-	// don't stop the VM during a warm import unless it's time to finalize
-	if !shouldWarmImport(provider, instance) || shouldFinalizeWarmImport(instance) {
+	// don't stop the VM during a warm import unless it's time to finalize, nor during a snapshot import
+	if !shouldSnapshotImport(provider, instance) && (!shouldWarmImport(provider, instance) || shouldFinalizeWarmImport(instance)) {
 		if _, ok := instance.Annotations[sourceVMInitialState]; !ok {
 			vmStatus, err := provider.GetVMStatus()
 			if err != nil {
@@ -373,9 +443,19 @@ func (r *ReconcileVirtualMachineImport) Reconcile(request reconcile.Request) (re
 			}
 		}
 		// Stop the VM
-		if err = provider.StopVM(instance, r.client); err != nil {
+		stopped, err := r.stopSourceVM(provider, instance)
+		if err != nil {
 			return reconcile.Result{}, err
 		}
+		if !stopped {
+			reqLogger.Info("Waiting for the source VM to shut down")
+			return reconcile.Result{RequeueAfter: SlowReQ}, nil
+		}
+	}
+
+	err = r.storeGuestConversion(instance, provider)
+	if err != nil {
+		return reconcile.Result{}, err
 	}
 
 	// Create mapper:
@@ -449,9 +529,15 @@ func (r *ReconcileVirtualMachineImport) Reconcile(request reconcile.Request) (re
 			reqLogger.Info("Waiting for disks to be imported")
 			return reconcile.Result{RequeueAfter: SlowReQ}, nil
 		}
+
+		// the disks have been copied, so the snapshot isn't needed anymore
+		err = r.removeSourceSnapshot(provider, instance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
 	}
 
-	if shouldConvertGuest(provider, instance) {
+	if shouldConvertGuest(instance) {
 		done, err := r.convertGuest(provider, instance, mapper, vmName)
 		if err != nil {
 			return reconcile.Result{}, err
@@ -479,8 +565,8 @@ func (r *ReconcileVirtualMachineImport) Reconcile(request reconcile.Request) (re
 			return reconcile.Result{RequeueAfter: time.Second * 15}, err
 		}
 	} else {
-		// Update progress if all disks import done:
-		if err := r.updateProgress(instance, progressDone); err != nil {
+		// All disks are imported and the VM doesn't need to be started:
+		if err := r.updatePhase(instance, v2vv1.PhaseSucceeded); err != nil {
 			return reconcile.Result{}, err
 		}
 		if err := r.afterSuccess(vmName, provider, instance); err != nil {
@@ -566,9 +652,16 @@ func (r *ReconcileVirtualMachineImport) convertGuest(provider provider.Provider,
 	if err != nil {
 		return false, err
 	}
+	customization, failure, err := r.loadGuestCustomization(instance)
+	if err != nil {
+		return false, err
+	}
 	if pod == nil {
+		if failure != "" {
+			return false, r.endGuestConversionFailed(provider, instance, failure)
+		}
 		log.Info("Creating conversion pod")
-		pod, err = provider.LaunchGuestConversionPod(vmSpec, dataVolumes)
+		pod, err = provider.LaunchGuestConversionPod(vmSpec, dataVolumes, customization)
 		if err != nil {
 			return false, err
 		}
@@ -577,9 +670,14 @@ func (r *ReconcileVirtualMachineImport) convertGuest(provider provider.Provider,
 		if err != nil {
 			return false, err
 		}
+	}
+
+	if err = r.updatePhase(instance, v2vv1.PhaseConverting); err != nil {
+		return false, err
+	}
 
-		// Update progress to converting guest:
-		if err = r.updateProgress(instance, progressConvertingGuest); err != nil {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		if err = r.collectGuestConversionResults(instance, pod, vmSpec, customization); err != nil {
 			return false, err
 		}
 	}
@@ -588,7 +686,11 @@ func (r *ReconcileVirtualMachineImport) convertGuest(provider provider.Provider,
 		return true, nil
 	} else if pod.Status.Phase == corev1.PodFailed {
 		log.Info("Conversion pod failed.", "Pod.Name", pod.Name)
-		err := r.endGuestConversionFailed(provider, instance, fmt.Sprintf("virt-v2v pod %s failed", pod.Name))
+		message := fmt.Sprintf("virt-v2v pod %s failed", pod.Name)
+		if conversionExitCode(pod) == customizationFailedExitCode {
+			message = fmt.Sprintf("virt-v2v pod %s failed to customize the guest", pod.Name)
+		}
+		err := r.endGuestConversionFailed(provider, instance, message)
 		if err != nil {
 			return false, err
 		}
@@ -599,6 +701,10 @@ func (r *ReconcileVirtualMachineImport) convertGuest(provider provider.Provider,
 func (r *ReconcileVirtualMachineImport) importDisks(provider provider.Provider, instance *v2vv1.VirtualMachineImport, mapper provider.Mapper, vmName types.NamespacedName) (bool, error) {
 	log := log.WithValues("Request.Namespace", instance.Namespace, "Request.Name", instance.Name)
 
+	if err := r.updatePhase(instance, v2vv1.PhaseCopyingDisks); err != nil {
+		return false, err
+	}
+
 	dvs, err := mapper.MapDataVolumes(&vmName.Name, r.filesystemOverhead)
 	if err != nil {
 		return false, err
@@ -606,6 +712,7 @@ func (r *ReconcileVirtualMachineImport) importDisks(provider provider.Provider,
 
 	dvsDone := make(map[string]bool)
 	dvsImportProgress := make(map[string]float64)
+	foundDvs := make([]*cdiv1.DataVolume, 0, len(dvs))
 	for dvID, dv := range dvs {
 		if err = r.addWatchForImportPod(instance, dvID); err != nil {
 			return false, err
@@ -621,6 +728,16 @@ func (r *ReconcileVirtualMachineImport) importDisks(provider provider.Provider,
 				return false, err
 			}
 			if valid {
+				if shouldSnapshotImport(provider, instance) && copiedFromSnapshot(&dv) {
+					ready, err := r.useSourceSnapshot(provider, instance, &dv)
+					if err != nil {
+						return false, err
+					}
+					if !ready {
+						log.Info("Waiting for the source snapshot", "DataVolume.Name", dv.Name, "VM.Name", vmName)
+						continue
+					}
+				}
 				log.Info("Creating data volume", "DataVolume.Name", dv.Name, "VM.Name", vmName)
 				if _, createErr := r.createDataVolume(provider, mapper, instance, &dv, vmName); createErr != nil {
 					if err = r.endDiskImportFailed(provider, instance, foundDv, createErr.Error()); err != nil {
@@ -691,13 +808,8 @@ func (r *ReconcileVirtualMachineImport) importDisks(provider provider.Provider,
 				}
 			}
 			// Get current progress of the import:
-			progress := string(foundDv.Status.Progress)
-			progressFloat, err := strconv.ParseFloat(strings.TrimRight(progress, "%"), 64)
-			if err != nil {
-				dvsImportProgress[dvID] = 0.0
-			} else {
-				dvsImportProgress[dvID] = progressFloat
-			}
+			dvsImportProgress[dvID] = dataVolumeProgress(foundDv)
+			foundDvs = append(foundDvs, foundDv)
 		} else {
 			return false, err
 		}
@@ -708,6 +820,9 @@ func (r *ReconcileVirtualMachineImport) importDisks(provider provider.Provider,
 	if err := r.updateProgress(instance, currentProgress); err != nil {
 		return false, err
 	}
+	if err := r.updateDisksProgress(types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, foundDvs); err != nil {
+		return false, err
+	}
 
 	done := r.isDoneImport(dvsDone, len(dvs))
 	return done, nil
@@ -750,8 +865,7 @@ func (r *ReconcileVirtualMachineImport) fail(provider provider.Provider, instanc
 		return err
 	}
 
-	// Update progress to done.
-	if err := r.updateProgress(instance, progressDone); err != nil {
+	if err := r.updatePhase(instance, v2vv1.PhaseFailed); err != nil {
 		return err
 	}
 
@@ -804,6 +918,10 @@ func (r *ReconcileVirtualMachineImport) createVM(provider provider.Provider, ins
 	instanceNamespacedName := types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}
 	reqLogger := log.WithValues("Request.Namespace", instance.Namespace, "Request.Name", instance.Name)
 
+	if err := r.updatePhase(instance, v2vv1.PhaseCreating); err != nil {
+		return "", err
+	}
+
 	// Resolve VM Name
 	targetVMName := mapper.ResolveVMName(instance.Spec.TargetVMName)
 
@@ -861,11 +979,6 @@ func (r *ReconcileVirtualMachineImport) createVM(provider provider.Provider, ins
 	// propagate tracking label
 	setTrackerLabel(vmSpec.ObjectMeta, instance)
 
-	// Update progress:
-	if err = r.updateProgress(instance, progressStart); err != nil {
-		return "", err
-	}
-
 	// Set VirtualMachineImport instance as the owner and controller
 	if err := controllerutil.SetControllerReference(instance, vmSpec, r.scheme); err != nil {
 		return "", err
@@ -894,6 +1007,9 @@ func (r *ReconcileVirtualMachineImport) createVM(provider provider.Provider, ins
 		if err = r.upsertStatusConditions(instanceNamespacedName, succeededCond, processingCond); err != nil {
 			return "", err
 		}
+		if err = r.updatePhase(instance, v2vv1.PhaseFailed); err != nil {
+			return "", err
+		}
 
 		// Cleanup after failure
 		if err = r.afterFailure(provider, instance); err != nil {
@@ -944,6 +1060,28 @@ func setAnnotations(instance *v2vv1.VirtualMachineImport, vmSpec *kubevirtv1.Vir
 	}
 }
 
+// createDataVolumeObject creates the data volume. When the mapper decides its preallocation, it is created from its
+// unstructured form, since the CDI API the operator is built with lacks spec.preallocation.
+func (r *ReconcileVirtualMachineImport) createDataVolumeObject(dv *cdiv1.DataVolume) error {
+	preallocation, found := dv.Annotations[utils.AnnPreallocation]
+	if !found {
+		return r.client.Create(context.TODO(), dv)
+	}
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(dv)
+	if err != nil {
+		return err
+	}
+	dvObject := &unstructured.Unstructured{Object: object}
+	dvObject.SetGroupVersionKind(cdiv1.SchemeGroupVersion.WithKind("DataVolume"))
+	if err = unstructured.SetNestedField(dvObject.Object, preallocation == "true", "spec", "preallocation"); err != nil {
+		return err
+	}
+	if err = r.client.Create(context.TODO(), dvObject); err != nil {
+		return err
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(dvObject.Object, dv)
+}
+
 func setDVNetworkAnnotations(instance *v2vv1.VirtualMachineImport, dv *cdiv1.DataVolume) {
 	annotations := instance.GetAnnotations()
 	dvAnnotations := dv.GetAnnotations()
@@ -983,8 +1121,8 @@ func (r *ReconcileVirtualMachineImport) startVM(provider provider.Provider, inst
 	log.Info("startVM method", "VM.Name", vmName)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
-			log.Info("Updating progress while starting vm", "VM.Name", vmName)
-			if err = r.updateProgress(instance, progressStartVM); err != nil {
+			log.Info("Updating phase while starting vm", "VM.Name", vmName)
+			if err = r.updatePhase(instance, v2vv1.PhaseStarting); err != nil {
 				return false, err
 			}
 			log.Info("Starting a vm", "VM.Name", vmName)
@@ -1006,7 +1144,7 @@ func (r *ReconcileVirtualMachineImport) startVM(provider provider.Provider, inst
 		if err = r.updateConditionsAfterSuccess(instance, "Virtual machine running", v2vv1.VirtualMachineRunning); err != nil {
 			return false, err
 		}
-		if err = r.updateProgress(instance, progressDone); err != nil {
+		if err = r.updatePhase(instance, v2vv1.PhaseSucceeded); err != nil {
 			return false, err
 		}
 		if err = r.afterSuccess(vmName, provider, instance); err != nil {
@@ -1035,6 +1173,9 @@ func (r *ReconcileVirtualMachineImport) updateConditionsAfterSuccess(instance *v
 }
 
 func shouldReconcile(instance *v2vv1.VirtualMachineImport) bool {
+	if isFinalPhase(instance.Status.Phase) {
+		return false
+	}
 	cond := conditions.FindConditionOfType(instance.Status.Conditions, v2vv1.Succeeded)
 
 	// If VM is ready, but not yet started run reconcile
@@ -1050,11 +1191,30 @@ func shouldStartVM(instance *v2vv1.VirtualMachineImport) bool {
 	return (instance.Spec.StartVM != nil && *instance.Spec.StartVM) || (instance.Spec.StartVM == nil && instance.Spec.Warm && instance.Annotations[sourceVMInitialState] == string(provider.VMStatusUp))
 }
 
-func shouldConvertGuest(provider provider.Provider, instance *v2vv1.VirtualMachineImport) bool {
-	return provider.NeedsGuestConversion() && !conditions.HasSucceededConditionOfReason(instance.Status.Conditions, v2vv1.VirtualMachineReady, v2vv1.VirtualMachineRunning)
+func shouldConvertGuest(instance *v2vv1.VirtualMachineImport) bool {
+	return instance.Status.GuestConversion != nil && *instance.Status.GuestConversion && !conditions.HasSucceededConditionOfReason(instance.Status.Conditions, v2vv1.VirtualMachineReady, v2vv1.VirtualMachineRunning)
+}
+
+// storeGuestConversion records in the status whether the guest is converted before the target VM is created, so that
+// the VM is mapped and the guest is converted according to the same decision, whatever happens to the source VM
+func (r *ReconcileVirtualMachineImport) storeGuestConversion(instance *v2vv1.VirtualMachineImport, provider provider.Provider) error {
+	if instance.Status.GuestConversion != nil {
+		return nil
+	}
+	convertGuest, err := provider.NeedsGuestConversion()
+	if err != nil {
+		return err
+	}
+	instanceCopy := instance.DeepCopy()
+	instance.Status.GuestConversion = &convertGuest
+	return r.client.Status().Patch(context.TODO(), instance, client.MergeFrom(instanceCopy))
 }
 
 func shouldImportDisks(instance *v2vv1.VirtualMachineImport) bool {
+	// the disks of a PVC source are already populated
+	if instance.Spec.Source.PVC != nil {
+		return false
+	}
 	return !conditions.HasSucceededConditionOfReason(instance.Status.Conditions, v2vv1.VirtualMachineReady, v2vv1.VirtualMachineRunning)
 }
 
@@ -1095,8 +1255,15 @@ func (r *ReconcileVirtualMachineImport) createDataVolume(provider provider.Provi
 
 	// Set transfer network annotations
 	setDVNetworkAnnotations(instance, dv)
+	if err := r.setProviderTransferNetwork(instance, dv); err != nil {
+		return nil, err
+	}
+
+	if err := r.resolveCloneSource(dv); err != nil {
+		return nil, err
+	}
 
-	err = r.client.Create(context.TODO(), dv)
+	err = r.createDataVolumeObject(dv)
 	if err != nil {
 		message := fmt.Sprintf("Data volume %s/%s creation failed: %s", dv.Namespace, dv.Name, err)
 		log.Error(err, message)
@@ -1132,12 +1299,16 @@ func (r *ReconcileVirtualMachineImport) createDataVolume(provider provider.Provi
 }
 
 func (r *ReconcileVirtualMachineImport) fetchSecret(vmImport *v2vv1.VirtualMachineImport) (*corev1.Secret, error) {
+	secretID := vmImport.Spec.ProviderCredentialsSecret
+	if secretID == nil {
+		return nil, fmt.Errorf("either providerCredentialsSecret or provider must be set")
+	}
 	secret := &corev1.Secret{}
 	secretNamespace := vmImport.Namespace
-	if vmImport.Spec.ProviderCredentialsSecret.Namespace != nil {
-		secretNamespace = *vmImport.Spec.ProviderCredentialsSecret.Namespace
+	if secretID.Namespace != nil {
+		secretNamespace = *secretID.Namespace
 	}
-	err := r.client.Get(context.TODO(), types.NamespacedName{Name: vmImport.Spec.ProviderCredentialsSecret.Name, Namespace: secretNamespace}, secret)
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: secretID.Name, Namespace: secretNamespace}, secret)
 	return secret, err
 }
 
@@ -1168,7 +1339,7 @@ func isIncomplete(condition *v2vv1.VirtualMachineImportCondition) bool {
 }
 
 func (r *ReconcileVirtualMachineImport) createProvider(vmi *v2vv1.VirtualMachineImport) (provider.Provider, error) {
-	if vmi.Spec.Source.Ovirt != nil && vmi.Spec.Source.Vmware != nil {
+	if countSources(vmi.Spec.Source) > 1 {
 		return nil, fmt.Errorf("Invalid source. Must only include one source type.")
 	}
 
@@ -1181,8 +1352,44 @@ func (r *ReconcileVirtualMachineImport) createProvider(vmi *v2vv1.VirtualMachine
 		provider := vmware.NewVmwareProvider(vmi.ObjectMeta, vmi.TypeMeta, r.client, r.ocClient, r.factory, r.ctrlConfig)
 		return &provider, nil
 	}
+	if vmi.Spec.Source.PVC != nil {
+		// the source VM, if any, is read by the provider of its type
+		var sourceVMProvider provider.Provider
+		if vmi.Spec.Source.PVC.SourceVM != nil {
+			sourceVMImport := vmi.DeepCopy()
+			sourceVMImport.Spec.Source = pvcprovider.SourceVMSpec(vmi.Spec.Source)
+			var err error
+			sourceVMProvider, err = r.createProvider(sourceVMImport)
+			if err != nil {
+				return nil, err
+			}
+		}
+		provider := pvcprovider.NewPVCProvider(vmi.ObjectMeta, vmi.TypeMeta, r.client, r.ocClient, r.ctrlConfig, sourceVMProvider)
+		return &provider, nil
+	}
+
+	return nil, fmt.Errorf("Invalid source type. Only Ovirt, Vmware and PVC type is supported")
+}
 
-	return nil, fmt.Errorf("Invalid source type. Only Ovirt and Vmware type is supported")
+// needsSourceProvider returns whether the import reads the VM from a source provider
+func needsSourceProvider(instance *v2vv1.VirtualMachineImport) bool {
+	_, ok := provider.SourceTypeOf(instance)
+	return ok
+}
+
+// countSources counts the source types set in the source spec
+func countSources(source v2vv1.VirtualMachineImportSourceSpec) int {
+	count := 0
+	if source.Ovirt != nil {
+		count++
+	}
+	if source.Vmware != nil {
+		count++
+	}
+	if source.PVC != nil {
+		count++
+	}
+	return count
 }
 
 func (r *ReconcileVirtualMachineImport) setRunning(vmName types.NamespacedName, running bool) error {
@@ -1220,7 +1427,7 @@ func (r *ReconcileVirtualMachineImport) updateDVs(vmiName types.NamespacedName,
 	}
 	// Patch the status only in case DV is not already part of the VMImport status:
 	if !dvFound {
-		copy.Status.DataVolumes = append(copy.Status.DataVolumes, v2vv1.DataVolumeItem{Name: dv.Name})
+		copy.Status.DataVolumes = append(copy.Status.DataVolumes, newDataVolumeItem(&dv))
 		err = r.client.Status().Update(context.TODO(), copy)
 		if err != nil {
 			return err
@@ -1288,38 +1495,13 @@ func (r *ReconcileVirtualMachineImport) storeSourceVMStatus(instance *v2vv1.Virt
 	return r.client.Patch(context.TODO(), vmiCopy, patch)
 }
 
-func (r *ReconcileVirtualMachineImport) updateProgress(instance *v2vv1.VirtualMachineImport, progress string) error {
-	currentProgress, ok := instance.Annotations[AnnCurrentProgress]
-	if !ok {
-		currentProgress = "0"
-	}
-	currentProgressInt, err := strconv.Atoi(currentProgress)
-	if err != nil {
-		return err
-	}
-	newProgressInt, err := strconv.Atoi(progress)
-	if err != nil {
-		return err
-	}
-	if currentProgressInt < newProgressInt {
-		vmiCopy := instance.DeepCopy()
-		if vmiCopy.Annotations == nil {
-			vmiCopy.Annotations = make(map[string]string)
-		}
-		vmiCopy.Annotations[AnnCurrentProgress] = progress
-
-		patch := client.MergeFrom(instance)
-		return r.client.Patch(context.TODO(), vmiCopy, patch)
-	}
-	return nil
-}
-
 func (r *ReconcileVirtualMachineImport) afterSuccess(vmName types.NamespacedName, p provider.Provider, instance *v2vv1.VirtualMachineImport) error {
 
 	r.removeFinalizer(utils.CancelledImportFinalizer, instance)
 
 	metrics.ImportMetrics.IncSuccessful()
 	metrics.ImportMetrics.SaveDurationSuccessful(calculateImportDuration(instance))
+	metrics.ImportMetrics.ForgetValidationFailures(instance.UID)
 
 	vmiName := types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}
 	var errs []error
@@ -1346,6 +1528,7 @@ func (r *ReconcileVirtualMachineImport) afterFailure(p provider.Provider, instan
 
 	metrics.ImportMetrics.IncFailed()
 	metrics.ImportMetrics.SaveDurationFailed(calculateImportDuration(instance))
+	metrics.ImportMetrics.ForgetValidationFailures(instance.UID)
 
 	vmiName := types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}
 	var errs []error
@@ -1406,17 +1589,41 @@ func shouldFailWith(conditions []v2vv1.VirtualMachineImportCondition) (bool, str
 }
 
 func (r *ReconcileVirtualMachineImport) initProvider(instance *v2vv1.VirtualMachineImport, provider provider.Provider) (string, error) {
-	// Fetch source provider secret
-	sourceProviderSecretObj, err := r.fetchSecret(instance)
-	if err != nil {
-		if k8serrors.IsNotFound(err) {
-			message := "Secret not found"
-			cerr := r.upsertValidationCondition(instance, v2vv1.SecretNotFound, message, err)
+	// there is no source provider to connect to when the hardware of the VM is described by the import
+	if !needsSourceProvider(instance) {
+		err := provider.Init(nil, instance)
+		if err != nil {
+			message := "Source provider initialization failed"
+			cerr := r.upsertValidationCondition(instance, v2vv1.UninitializedProvider, message, err)
 			if cerr != nil {
 				return message, cerr
 			}
+			return message, err
+		}
+		return "", nil
+	}
+
+	// Fetch source provider secret
+	var sourceProviderSecretObj *corev1.Secret
+	var err error
+	if instance.Spec.Provider != nil {
+		var message string
+		sourceProviderSecretObj, message, err = r.fetchProviderCredentials(instance)
+		if err != nil {
+			return message, err
+		}
+	} else {
+		sourceProviderSecretObj, err = r.fetchSecret(instance)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				message := "Secret not found"
+				cerr := r.upsertValidationCondition(instance, v2vv1.SecretNotFound, message, err)
+				if cerr != nil {
+					return message, cerr
+				}
+			}
+			return "Failed to read the secret", err
 		}
-		return "Failed to read the secret", err
 	}
 
 	err = provider.Init(sourceProviderSecretObj, instance)
@@ -1430,10 +1637,11 @@ func (r *ReconcileVirtualMachineImport) initProvider(instance *v2vv1.VirtualMach
 	}
 
 	if !r.vmImportInProgress(instance) {
+		r.auditInsecureConnection(instance, sourceProviderSecretObj)
 		err = provider.TestConnection()
 		if err != nil {
-			message := "Failed to connect to source provider"
-			cerr := r.upsertValidationCondition(instance, v2vv1.UnreachableProvider, message, err)
+			reason, message := connectionFailureReason(err)
+			cerr := r.upsertValidationCondition(instance, reason, message, err)
 			if cerr != nil {
 				return message, cerr
 			}
@@ -1464,7 +1672,31 @@ func (r *ReconcileVirtualMachineImport) failNewImportProcess(instance *v2vv1.Vir
 	if err != nil {
 		return err
 	}
-	return nil
+	return r.updatePhase(instance, v2vv1.PhaseFailed)
+}
+
+func connectionFailureReason(err error) (v2vv1.ValidConditionReason, string) {
+	if pclient.IsAuthenticationError(err) {
+		return v2vv1.AuthenticationFailed, "Failed to authenticate to source provider"
+	}
+	if pclient.IsCertificateError(err) {
+		return v2vv1.CertificateVerificationFailed, "Failed to verify the certificate of source provider"
+	}
+	return v2vv1.UnreachableProvider, "Failed to connect to source provider"
+}
+
+func loadVMFailureReason(err error) v2vv1.ValidConditionReason {
+	var ambiguous provider.AmbiguousSourceVMError
+	if errors.As(err, &ambiguous) {
+		return v2vv1.AmbiguousSourceVM
+	}
+	if pclient.IsAuthenticationError(err) {
+		return v2vv1.AuthenticationFailed
+	}
+	if pclient.IsCertificateError(err) {
+		return v2vv1.CertificateVerificationFailed
+	}
+	return v2vv1.SourceVMNotFound
 }
 
 func (r *ReconcileVirtualMachineImport) fetchVM(instance *v2vv1.VirtualMachineImport, provider provider.Provider) error {
@@ -1473,7 +1705,7 @@ func (r *ReconcileVirtualMachineImport) fetchVM(instance *v2vv1.VirtualMachineIm
 		// Load source VM:
 		err := provider.LoadVM(instance.Spec.Source)
 		if err != nil {
-			condition := newValidationCondition(v2vv1.SourceVMNotFound, "Failed to load source VM: "+err.Error())
+			condition := newValidationCondition(loadVMFailureReason(err), "Failed to load source VM: "+err.Error())
 			cerr := r.upsertStatusConditions(types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, condition)
 			if cerr != nil {
 				return cerr
@@ -1577,6 +1809,7 @@ func (r *ReconcileVirtualMachineImport) validate(instance *v2vv1.VirtualMachineI
 		err = validateName(instance, vmName)
 		if err != nil {
 			invalidNameCond := conditions.NewCondition(v2vv1.Valid, string(v2vv1.InvalidTargetVMName), err.Error(), corev1.ConditionFalse)
+			saveValidationFailures(instance, invalidNameCond)
 			err := r.upsertStatusConditions(types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, invalidNameCond)
 			return false, err
 		}
@@ -1587,10 +1820,26 @@ func (r *ReconcileVirtualMachineImport) validate(instance *v2vv1.VirtualMachineI
 		}
 		if !unique {
 			duplicateNameCond := conditions.NewCondition(v2vv1.Valid, string(v2vv1.DuplicateTargetVMName), "Virtual machine already exists in target namespace", corev1.ConditionFalse)
+			saveValidationFailures(instance, duplicateNameCond)
 			err := r.upsertStatusConditions(types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, duplicateNameCond)
 			return false, err
 		}
 
+		if message := validateSnapshotImport(provider, instance); message != "" {
+			snapshotImportCond := conditions.NewCondition(v2vv1.Valid, string(v2vv1.SnapshotImportNotSupported), message, corev1.ConditionFalse)
+			saveValidationFailures(instance, snapshotImportCond)
+			err := r.upsertStatusConditions(types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, snapshotImportCond)
+			return false, err
+		}
+
+		err = guestconversion.CheckConversionPodSettings(r.ctrlConfig.ConversionPod(), instance.Spec.ConversionPod, r.ctrlConfig.ConversionPodAllowList())
+		if err != nil {
+			conversionPodCond := conditions.NewCondition(v2vv1.Valid, string(v2vv1.ConversionPodNotAllowed), err.Error(), corev1.ConditionFalse)
+			saveValidationFailures(instance, conversionPodCond)
+			err := r.upsertStatusConditions(types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, conversionPodCond)
+			return false, err
+		}
+
 		conditions, err := provider.Validate()
 		if err != nil {
 			return true, err
@@ -1600,6 +1849,7 @@ func (r *ReconcileVirtualMachineImport) validate(instance *v2vv1.VirtualMachineI
 			return true, err
 		}
 		if valid, message := shouldFailWith(conditions); !valid {
+			saveValidationFailures(instance, conditions...)
 			logger.Info("Import blocked. " + message)
 			// Emit event vm import blocked:
 			// This potentially flood events service, consider checking if event already occurred and don't emit it if it did,
@@ -1625,6 +1875,9 @@ func (r *ReconcileVirtualMachineImport) templateMatchingFailed(errorMessage stri
 	if err := r.upsertStatusConditions(instanceNamespacedName, succeededCond, *processingCond); err != nil {
 		return err
 	}
+	if err := r.updatePhase(instance, v2vv1.PhaseFailed); err != nil {
+		return err
+	}
 
 	// Cleanup after failure
 	if err := r.afterFailure(provider, instance); err != nil {
@@ -1633,11 +1886,6 @@ func (r *ReconcileVirtualMachineImport) templateMatchingFailed(errorMessage stri
 	return nil
 }
 
-func (r *ReconcileVirtualMachineImport) vmImportInProgress(instance *v2vv1.VirtualMachineImport) bool {
-	_, found := instance.Annotations[AnnCurrentProgress]
-	return found
-}
-
 func newValidationCondition(reason v2vv1.ValidConditionReason, message string) v2vv1.VirtualMachineImportCondition {
 	return conditions.NewCondition(
 		v2vv1.Valid,
@@ -1648,15 +1896,14 @@ func newValidationCondition(reason v2vv1.ValidConditionReason, message string) v
 }
 
 // disksImportProgress count progress as progressCopyingDisks + (allProgress / countOfDisks) * (progressForCopyDisk / 100)
-// So for example for two disks of one done on 25% and second for 60% we go as -> 10 + (85 / 2) * 0.75 = 41.875%
-func disksImportProgress(dvsImportProgress map[string]float64, dvCount float64) string {
+// So for example for two disks of one done on 25% and second for 60% we go as -> 10 + (85 / 2) * 0.65 = 37%
+func disksImportProgress(dvsImportProgress map[string]float64, dvCount float64) int {
 	sumProgress := float64(0.0)
 	for _, progress := range dvsImportProgress {
 		sumProgress += progress
 	}
-	startProgress, _ := strconv.Atoi(progressCopyingDisks)
 	disksAverageProgress := sumProgress / dvCount
-	return fmt.Sprintf("%v", startProgress+int(disksAverageProgress*progressCopyDiskRange))
+	return progressCopyingDisks + int(disksAverageProgress*progressCopyDiskRange)
 }
 
 func (r *ReconcileVirtualMachineImport) removeFinalizer(finalizer string, instance *v2vv1.VirtualMachineImport) {
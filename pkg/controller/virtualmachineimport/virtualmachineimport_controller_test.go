@@ -2,7 +2,9 @@ package virtualmachineimport
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
+	"time"
 
 	ctrlConfig "github.com/kubevirt/vm-import-operator/pkg/config/controller"
 	"github.com/kubevirt/vm-import-operator/pkg/metrics"
@@ -11,14 +13,19 @@ import (
 
 	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
 	pclient "github.com/kubevirt/vm-import-operator/pkg/client"
+	"github.com/kubevirt/vm-import-operator/pkg/conditions"
+	"github.com/kubevirt/vm-import-operator/pkg/guestconversion"
 	"github.com/kubevirt/vm-import-operator/pkg/mappings"
 	"github.com/kubevirt/vm-import-operator/pkg/ownerreferences"
 	provider "github.com/kubevirt/vm-import-operator/pkg/providers"
+	"github.com/kubevirt/vm-import-operator/pkg/utils"
 	oapiv1 "github.com/openshift/api/template/v1"
 	ovirtsdk "github.com/ovirt/go-ovirt"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -55,15 +62,22 @@ var (
 	mapDisks                 func() (map[string]cdiv1.DataVolume, error)
 	getVM                    func(id *string, name *string, cluster *string, clusterID *string) (interface{}, error)
 	stopVM                   func(id string) error
+	stopSourceVM             func(v2vv1.SourceShutdownMethod) error
 	list                     func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error
+	testConnection           func() error
 	getKvConfig              func() kvConfig.KubeVirtConfig
 	getCtrlConfig            func() ctrlConfig.ControllerConfig
-	needsGuestConversion     func() bool
+	needsGuestConversion     func() (bool, error)
 	getGuestConversionPod    func() (*corev1.Pod, error)
 	launchGuestConversionPod func() (*corev1.Pod, error)
 	supportsWarmMigration    func() bool
+	supportsSnapshotImport   func() bool
 	createVMSnapshot         func() (string, error)
+	vmSnapshotReady          func(string) (bool, error)
 	removeVMSnapshot         func(string, bool) error
+	selectVMs                func(string) ([]provider.SelectedVM, error)
+	tailLogs                 func(pod *corev1.Pod, container string) ([]byte, error)
+	findTemplates            func(namespace string, os string, workload *string, flavor *string) (*oapiv1.TemplateList, error)
 )
 
 var _ = Describe("Reconcile steps", func() {
@@ -96,7 +110,7 @@ var _ = Describe("Reconcile steps", func() {
 		get = func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
 			switch obj.(type) {
 			case *v2vv1.VirtualMachineImport:
-				obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{}
+				obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "test"}}
 			case *v2vv1.ResourceMapping:
 				obj.(*v2vv1.ResourceMapping).Spec = v2vv1.ResourceMappingSpec{}
 			case *corev1.Secret:
@@ -113,6 +127,9 @@ var _ = Describe("Reconcile steps", func() {
 		getVMStatus = func() (provider.VMStatus, error) {
 			return provider.VMStatusDown, nil
 		}
+		stopSourceVM = func(v2vv1.SourceShutdownMethod) error {
+			return nil
+		}
 		create = func(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
 			return nil
 		}
@@ -128,8 +145,14 @@ var _ = Describe("Reconcile steps", func() {
 		update = func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
 			return nil
 		}
-		needsGuestConversion = func() bool {
-			return false
+		needsGuestConversion = func() (bool, error) {
+			return false, nil
+		}
+		testConnection = func() error {
+			return nil
+		}
+		tailLogs = func(pod *corev1.Pod, container string) ([]byte, error) {
+			return []byte("virt-v2v log"), nil
 		}
 		vmName = types.NamespacedName{Name: "test", Namespace: "default"}
 		rec := record.NewFakeRecorder(2)
@@ -147,6 +170,7 @@ var _ = Describe("Reconcile steps", func() {
 	Describe("Init steps", func() {
 		BeforeEach(func() {
 			instance.Spec.Source.Ovirt = &v2vv1.VirtualMachineImportOvirtSourceSpec{}
+			instance.Spec.ProviderCredentialsSecret = &v2vv1.ObjectIdentifier{Name: "test"}
 			instance.Name = "test"
 			instance.Namespace = "test"
 		})
@@ -158,7 +182,7 @@ var _ = Describe("Reconcile steps", func() {
 
 			Expect(provider).To(BeNil())
 			Expect(err).To(Not(BeNil()))
-			Expect(err.Error()).To(Equal("Invalid source type. Only Ovirt and Vmware type is supported"))
+			Expect(err.Error()).To(Equal("Invalid source type. Only Ovirt, Vmware and PVC type is supported"))
 		})
 
 		It("should fail to create provider if more than one source is provided: ", func() {
@@ -178,15 +202,33 @@ var _ = Describe("Reconcile steps", func() {
 			Expect(provider).To(Not(BeNil()))
 			Expect(err).To(BeNil())
 		})
+
+		It("should create provider of persistent volume claims reading the source VM: ", func() {
+			instance.Spec.Source.PVC = &v2vv1.VirtualMachineImportPVCSourceSpec{
+				Disks:    []v2vv1.PVCDisk{{ClaimName: "disk-1"}},
+				SourceVM: &v2vv1.PVCSourceVMSpec{Ovirt: instance.Spec.Source.Ovirt},
+			}
+			instance.Spec.Source.Ovirt = nil
+
+			provider, err := reconciler.createProvider(instance)
+
+			Expect(provider).To(Not(BeNil()))
+			Expect(err).To(BeNil())
+			Expect(shouldImportDisks(instance)).To(BeFalse())
+		})
 	})
 
 	Describe("Create steps", func() {
 
 		BeforeEach(func() {
 			instance.Spec.Source.Ovirt = &v2vv1.VirtualMachineImportOvirtSourceSpec{}
+			instance.Spec.ProviderCredentialsSecret = &v2vv1.ObjectIdentifier{Name: "test"}
 			instance.Name = "test"
 			instance.Namespace = "test"
 
+			pinit = func(*corev1.Secret, *v2vv1.VirtualMachineImport) error {
+				return nil
+			}
 			mock = &mockProvider{}
 		})
 
@@ -200,6 +242,160 @@ var _ = Describe("Reconcile steps", func() {
 			Expect(msg).To(Equal("Failed to read the secret"))
 			Expect(err).To(Not(BeNil()))
 		})
+
+		It("should report an unreachable provider: ", func() {
+			var reason *string
+			testConnection = func() error {
+				return fmt.Errorf("connection refused")
+			}
+			update = func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+				reason = obj.(*v2vv1.VirtualMachineImport).Status.Conditions[0].Reason
+				return nil
+			}
+
+			msg, err := reconciler.initProvider(instance, mock)
+
+			Expect(msg).To(Equal("Failed to connect to source provider"))
+			Expect(err).To(Not(BeNil()))
+			Expect(*reason).To(Equal(string(v2vv1.UnreachableProvider)))
+		})
+
+		It("should report rejected credentials: ", func() {
+			var reason *string
+			testConnection = func() error {
+				return fmt.Errorf("failed to connect: %w", &ovirtsdk.AuthError{})
+			}
+			update = func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+				reason = obj.(*v2vv1.VirtualMachineImport).Status.Conditions[0].Reason
+				return nil
+			}
+
+			msg, err := reconciler.initProvider(instance, mock)
+
+			Expect(msg).To(Equal("Failed to authenticate to source provider"))
+			Expect(err).To(Not(BeNil()))
+			Expect(*reason).To(Equal(string(v2vv1.AuthenticationFailed)))
+		})
+		It("should report a certificate that couldn't be verified: ", func() {
+			var reason *string
+			testConnection = func() error {
+				return fmt.Errorf("failed to connect: %w", x509.UnknownAuthorityError{})
+			}
+			update = func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+				reason = obj.(*v2vv1.VirtualMachineImport).Status.Conditions[0].Reason
+				return nil
+			}
+
+			msg, err := reconciler.initProvider(instance, mock)
+
+			Expect(msg).To(Equal("Failed to verify the certificate of source provider"))
+			Expect(err).To(Not(BeNil()))
+			Expect(*reason).To(Equal(string(v2vv1.CertificateVerificationFailed)))
+		})
+
+		It("should record connecting without verifying the certificate: ", func() {
+			get = func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+				if secret, ok := obj.(*corev1.Secret); ok {
+					secret.Data = map[string][]byte{"ovirt": []byte("apiUrl: https://engine/ovirt-engine/api\ninsecureSkipVerify: true\n")}
+				}
+				return nil
+			}
+
+			_, err := reconciler.initProvider(instance, mock)
+
+			Expect(err).To(BeNil())
+			Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(EventInsecureConnection)))
+		})
+	})
+
+	Describe("importsOfSecret", func() {
+		var secret *corev1.Secret
+
+		BeforeEach(func() {
+			secret = &corev1.Secret{}
+			secret.Name = "credentials"
+			secret.Namespace = "test"
+		})
+
+		It("should map the secret to the imports referencing it: ", func() {
+			otherNamespace := "other"
+			list = func(ctx context.Context, objectList runtime.Object, opts ...client.ListOption) error {
+				switch objectList := objectList.(type) {
+				case *v2vv1.ProviderList:
+					objectList.Items = []v2vv1.Provider{
+						{ObjectMeta: v1.ObjectMeta{Name: "engine", Namespace: "test"}, Spec: v2vv1.ProviderSpec{SecretRef: v2vv1.ObjectIdentifier{Name: "credentials"}}},
+						{ObjectMeta: v1.ObjectMeta{Name: "vcenter", Namespace: "test"}, Spec: v2vv1.ProviderSpec{SecretRef: v2vv1.ObjectIdentifier{Name: "other"}}},
+					}
+				case *v2vv1.VirtualMachineImportList:
+					objectList.Items = []v2vv1.VirtualMachineImport{
+						{ObjectMeta: v1.ObjectMeta{Name: "direct", Namespace: "test"}, Spec: v2vv1.VirtualMachineImportSpec{ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "credentials"}}},
+						{ObjectMeta: v1.ObjectMeta{Name: "other-namespace", Namespace: "other"}, Spec: v2vv1.VirtualMachineImportSpec{ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "credentials"}}},
+						{ObjectMeta: v1.ObjectMeta{Name: "through-provider", Namespace: "other"}, Spec: v2vv1.VirtualMachineImportSpec{Provider: &v2vv1.ObjectIdentifier{Name: "engine", Namespace: &secret.Namespace}}},
+						{ObjectMeta: v1.ObjectMeta{Name: "other-provider", Namespace: "test"}, Spec: v2vv1.VirtualMachineImportSpec{Provider: &v2vv1.ObjectIdentifier{Name: "vcenter"}}},
+						{ObjectMeta: v1.ObjectMeta{Name: "other-secret", Namespace: "test"}, Spec: v2vv1.VirtualMachineImportSpec{ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "credentials", Namespace: &otherNamespace}}},
+						{ObjectMeta: v1.ObjectMeta{Name: "direct-and-other-provider", Namespace: "test"}, Spec: v2vv1.VirtualMachineImportSpec{ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "credentials"}, Provider: &v2vv1.ObjectIdentifier{Name: "vcenter"}}},
+					}
+				}
+				return nil
+			}
+
+			requests := reconciler.importsOfSecret(handler.MapObject{Meta: secret, Object: secret})
+
+			Expect(requests).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Name: "direct", Namespace: "test"}},
+				reconcile.Request{NamespacedName: types.NamespacedName{Name: "through-provider", Namespace: "other"}},
+				reconcile.Request{NamespacedName: types.NamespacedName{Name: "direct-and-other-provider", Namespace: "test"}},
+			))
+		})
+
+		It("should look the referencing objects up through the indexes: ", func() {
+			var selectors []string
+			list = func(ctx context.Context, objectList runtime.Object, opts ...client.ListOption) error {
+				listOpts := &client.ListOptions{}
+				listOpts.ApplyOptions(opts)
+				selectors = append(selectors, listOpts.FieldSelector.String())
+				if objectList, ok := objectList.(*v2vv1.ProviderList); ok {
+					objectList.Items = []v2vv1.Provider{
+						{ObjectMeta: v1.ObjectMeta{Name: "engine", Namespace: "test"}, Spec: v2vv1.ProviderSpec{SecretRef: v2vv1.ObjectIdentifier{Name: "credentials"}}},
+					}
+				}
+				return nil
+			}
+
+			reconciler.importsOfSecret(handler.MapObject{Meta: secret, Object: secret})
+
+			Expect(selectors).To(ConsistOf(
+				"providerReferences=Secret/test/credentials",
+				"importReferences=Secret/test/credentials",
+				"importReferences=Provider/test/engine",
+			))
+		})
+
+		It("should map a config map to the imports of the providers whose CA bundle it holds: ", func() {
+			configMap := &corev1.ConfigMap{}
+			configMap.Name = "trusted-ca"
+			configMap.Namespace = "test"
+			list = func(ctx context.Context, objectList runtime.Object, opts ...client.ListOption) error {
+				switch objectList := objectList.(type) {
+				case *v2vv1.ProviderList:
+					objectList.Items = []v2vv1.Provider{
+						{ObjectMeta: v1.ObjectMeta{Name: "engine", Namespace: "test"}, Spec: v2vv1.ProviderSpec{TLS: &v2vv1.ProviderTLSSpec{CABundleRef: &v2vv1.CABundleReference{Kind: v2vv1.ConfigMapCABundle, Name: "trusted-ca"}}}},
+					}
+				case *v2vv1.VirtualMachineImportList:
+					objectList.Items = []v2vv1.VirtualMachineImport{
+						{ObjectMeta: v1.ObjectMeta{Name: "through-provider", Namespace: "test"}, Spec: v2vv1.VirtualMachineImportSpec{Provider: &v2vv1.ObjectIdentifier{Name: "engine"}}},
+						{ObjectMeta: v1.ObjectMeta{Name: "direct", Namespace: "test"}, Spec: v2vv1.VirtualMachineImportSpec{ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "trusted-ca"}}},
+					}
+				}
+				return nil
+			}
+
+			requests := reconciler.importsOfConfigMap(handler.MapObject{Meta: configMap, Object: configMap})
+
+			Expect(requests).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Name: "through-provider", Namespace: "test"}},
+			))
+		})
 	})
 
 	Describe("fetchVM step", func() {
@@ -217,6 +413,7 @@ var _ = Describe("Reconcile steps", func() {
 				}, nil
 			}
 			instance.Spec.Source.Ovirt = &v2vv1.VirtualMachineImportOvirtSourceSpec{}
+			instance.Spec.ProviderCredentialsSecret = &v2vv1.ObjectIdentifier{Name: "test"}
 			instance.Name = "test"
 			instance.Namespace = "test"
 
@@ -244,6 +441,28 @@ var _ = Describe("Reconcile steps", func() {
 			Expect(err).To(Not(BeNil()))
 		})
 
+		table.DescribeTable("should report why the VM failed to load: ", func(loadErr error, reason v2vv1.ValidConditionReason) {
+			loadVM = func(v2vv1.VirtualMachineImportSourceSpec) error {
+				return loadErr
+			}
+			var validCondition *v2vv1.VirtualMachineImportCondition
+			update = func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+				if vmi, ok := obj.(*v2vv1.VirtualMachineImport); ok {
+					validCondition = conditions.FindConditionOfType(vmi.Status.Conditions, v2vv1.Valid)
+				}
+				return nil
+			}
+
+			err := reconciler.fetchVM(instance, mock)
+
+			Expect(err).To(Not(BeNil()))
+			Expect(validCondition).To(Not(BeNil()))
+			Expect(*validCondition.Reason).To(Equal(string(reason)))
+		},
+			table.Entry("not found", fmt.Errorf("vm 'test' not found"), v2vv1.SourceVMNotFound),
+			table.Entry("ambiguous", provider.AmbiguousSourceVMError{Err: fmt.Errorf("vm 'test' matches more than one VM")}, v2vv1.AmbiguousSourceVM),
+		)
+
 		It("should fail to fetch resource mapping: ", func() {
 			getResourceMapping = func(namespacedName types.NamespacedName) (*v2vv1.ResourceMapping, error) {
 				return nil, fmt.Errorf("Not there")
@@ -265,6 +484,251 @@ var _ = Describe("Reconcile steps", func() {
 
 	})
 
+	Describe("expandVMSelector step", func() {
+		var updated *v2vv1.VirtualMachineImport
+
+		BeforeEach(func() {
+			instance.Name = "test"
+			instance.Namespace = "test"
+			pattern := "{cluster}-{name}"
+			instance.Spec.Source.Ovirt = &v2vv1.VirtualMachineImportOvirtSourceSpec{
+				VMSelector: &v2vv1.VirtualMachineImportOvirtSourceVMSelectorSpec{
+					Search:              "name=web*",
+					TargetVMNamePattern: &pattern,
+				},
+			}
+			instance.Spec.ResourceMapping = &v2vv1.ObjectIdentifier{Name: "mapping"}
+			selectVMs = func(string) ([]provider.SelectedVM, error) {
+				return []provider.SelectedVM{
+					{ID: "123", Name: "Web_1", Cluster: "prod"},
+					{ID: "456", Name: "web2", Cluster: "prod"},
+				}, nil
+			}
+			updated = nil
+			update = func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+				if vmi, ok := obj.(*v2vv1.VirtualMachineImport); ok {
+					updated = vmi
+				}
+				return nil
+			}
+			statusPatch = func(ctx context.Context, obj runtime.Object, patch client.Patch) error {
+				if vmi, ok := obj.(*v2vv1.VirtualMachineImport); ok {
+					updated = vmi
+				}
+				return nil
+			}
+			mock = &mockProvider{}
+		})
+
+		It("should create an import for each selected VM: ", func() {
+			var children []*v2vv1.VirtualMachineImport
+			create = func(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+				children = append(children, obj.(*v2vv1.VirtualMachineImport))
+				return nil
+			}
+
+			selected, err := reconciler.expandVMSelector(instance, mock)
+
+			Expect(err).To(BeNil())
+			Expect(selected).To(BeTrue())
+			Expect(children).To(HaveLen(2))
+			Expect(children[0].Name).To(Equal("test-123"))
+			Expect(children[0].Labels[SelectedByLabel]).To(Equal("test"))
+			Expect(children[0].Spec.Source.Ovirt.VMSelector).To(BeNil())
+			Expect(*children[0].Spec.Source.Ovirt.VM.ID).To(Equal("123"))
+			Expect(*children[0].Spec.TargetVMName).To(Equal("prod-web1"))
+			Expect(children[0].Spec.ResourceMapping.Name).To(Equal("mapping"))
+			Expect(*children[1].Spec.TargetVMName).To(Equal("prod-web2"))
+			Expect(updated.Status.VirtualMachineImports).To(HaveLen(2))
+			Expect(updated.Status.Phase).To(Equal(v2vv1.PhaseSucceeded))
+		})
+
+		It("should tolerate already existing imports: ", func() {
+			create = func(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+				return errors.NewAlreadyExists(schema.GroupResource{}, "test-123")
+			}
+
+			selected, err := reconciler.expandVMSelector(instance, mock)
+
+			Expect(err).To(BeNil())
+			Expect(selected).To(BeTrue())
+			Expect(updated.Status.VirtualMachineImports).To(HaveLen(2))
+		})
+
+		It("should report that no VM matches: ", func() {
+			selectVMs = func(string) ([]provider.SelectedVM, error) {
+				return []provider.SelectedVM{}, nil
+			}
+
+			selected, err := reconciler.expandVMSelector(instance, mock)
+
+			Expect(err).To(BeNil())
+			Expect(selected).To(BeFalse())
+			validCondition := conditions.FindConditionOfType(updated.Status.Conditions, v2vv1.Valid)
+			Expect(*validCondition.Reason).To(Equal(string(v2vv1.SourceVMNotFound)))
+		})
+	})
+
+	Describe("stopSourceVM step", func() {
+		var processingCondition *v2vv1.VirtualMachineImportCondition
+
+		BeforeEach(func() {
+			instance.Name = "test"
+			instance.Namespace = "test"
+			mock = &mockProvider{}
+			processingCondition = nil
+			update = func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+				if vmi, ok := obj.(*v2vv1.VirtualMachineImport); ok {
+					processingCondition = conditions.FindConditionOfType(vmi.Status.Conditions, v2vv1.Processing)
+				}
+				return nil
+			}
+		})
+
+		It("should request the shut down of the source VM: ", func() {
+			getVMStatus = func() (provider.VMStatus, error) {
+				return provider.VMStatusUp, nil
+			}
+			var requestedMethod v2vv1.SourceShutdownMethod
+			stopSourceVM = func(method v2vv1.SourceShutdownMethod) error {
+				requestedMethod = method
+				return nil
+			}
+			method := v2vv1.HardShutdown
+			instance.Spec.SourceShutdown = &v2vv1.SourceShutdownSpec{
+				Method:  &method,
+				Timeout: &v1.Duration{Duration: time.Minute},
+			}
+
+			stopped, err := reconciler.stopSourceVM(mock, instance)
+
+			Expect(err).To(BeNil())
+			Expect(stopped).To(BeFalse())
+			Expect(requestedMethod).To(Equal(v2vv1.HardShutdown))
+			Expect(instance.Status.SourceShutdown.RequestedAt).ToNot(BeNil())
+			Expect(instance.Status.SourceShutdown.Method).To(Equal(v2vv1.HardShutdown))
+			Expect(*processingCondition.Reason).To(Equal(string(v2vv1.StoppingSourceVM)))
+			Expect(*processingCondition.Message).To(ContainSubstring("hard shutdown, timeout 1m0s"))
+		})
+
+		It("should wait for the timeout when the guest shut down can't be requested: ", func() {
+			getVMStatus = func() (provider.VMStatus, error) {
+				return provider.VMStatusUp, nil
+			}
+			stopSourceVM = func(v2vv1.SourceShutdownMethod) error {
+				return fmt.Errorf("VMware Tools aren't running")
+			}
+			instance.Spec.SourceShutdown = &v2vv1.SourceShutdownSpec{
+				Timeout: &v1.Duration{Duration: time.Minute},
+				Force:   true,
+			}
+
+			stopped, err := reconciler.stopSourceVM(mock, instance)
+
+			Expect(err).To(BeNil())
+			Expect(stopped).To(BeFalse())
+			Expect(instance.Status.SourceShutdown.RequestedAt).ToNot(BeNil())
+			Expect(instance.Status.SourceShutdown.Method).To(Equal(v2vv1.GuestShutdown))
+		})
+
+		It("should report a failure to power the source VM off: ", func() {
+			getVMStatus = func() (provider.VMStatus, error) {
+				return provider.VMStatusUp, nil
+			}
+			stopSourceVM = func(v2vv1.SourceShutdownMethod) error {
+				return fmt.Errorf("connection refused")
+			}
+			method := v2vv1.HardShutdown
+			instance.Spec.SourceShutdown = &v2vv1.SourceShutdownSpec{Method: &method}
+
+			_, err := reconciler.stopSourceVM(mock, instance)
+
+			Expect(err).To(HaveOccurred())
+			Expect(instance.Status.SourceShutdown.RequestedAt).ToNot(BeNil())
+		})
+
+		It("should wait for the requested shut down of the source VM: ", func() {
+			getVMStatus = func() (provider.VMStatus, error) {
+				return provider.VMStatusUp, nil
+			}
+			stopSourceVM = func(v2vv1.SourceShutdownMethod) error {
+				Fail("the shut down must be requested only once")
+				return nil
+			}
+			requestedAt := v1.Now()
+			instance.Status.SourceShutdown = &v2vv1.SourceShutdownStatus{RequestedAt: &requestedAt}
+
+			stopped, err := reconciler.stopSourceVM(mock, instance)
+
+			Expect(err).To(BeNil())
+			Expect(stopped).To(BeFalse())
+			Expect(processingCondition).To(BeNil())
+		})
+
+		It("should power the source VM off when the shut down times out: ", func() {
+			getVMStatus = func() (provider.VMStatus, error) {
+				return provider.VMStatusUp, nil
+			}
+			var requestedMethod v2vv1.SourceShutdownMethod
+			stopSourceVM = func(method v2vv1.SourceShutdownMethod) error {
+				requestedMethod = method
+				return nil
+			}
+			instance.Spec.SourceShutdown = &v2vv1.SourceShutdownSpec{
+				Timeout: &v1.Duration{Duration: time.Minute},
+				Force:   true,
+			}
+			requestedAt := v1.NewTime(time.Now().Add(-2 * time.Minute))
+			instance.Status.SourceShutdown = &v2vv1.SourceShutdownStatus{RequestedAt: &requestedAt}
+
+			stopped, err := reconciler.stopSourceVM(mock, instance)
+
+			Expect(err).To(BeNil())
+			Expect(stopped).To(BeFalse())
+			Expect(requestedMethod).To(Equal(v2vv1.HardShutdown))
+			Expect(instance.Status.SourceShutdown.ForcedAt).ToNot(BeNil())
+			Expect(*processingCondition.Message).To(ContainSubstring("wasn't shut down within 1m0s"))
+		})
+
+		It("should fail when the shut down times out without forcing: ", func() {
+			getVMStatus = func() (provider.VMStatus, error) {
+				return provider.VMStatusUp, nil
+			}
+			var succeededCondition *v2vv1.VirtualMachineImportCondition
+			update = func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+				if vmi, ok := obj.(*v2vv1.VirtualMachineImport); ok {
+					succeededCondition = conditions.FindConditionOfType(vmi.Status.Conditions, v2vv1.Succeeded)
+				}
+				return nil
+			}
+			instance.Spec.SourceShutdown = &v2vv1.SourceShutdownSpec{
+				Timeout: &v1.Duration{Duration: time.Minute},
+			}
+			get = func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+				if vmi, ok := obj.(*v2vv1.VirtualMachineImport); ok {
+					vmi.Annotations = map[string]string{sourceVMInitialState: string(provider.VMStatusUp)}
+				}
+				return nil
+			}
+			requestedAt := v1.NewTime(time.Now().Add(-2 * time.Minute))
+			instance.Status.SourceShutdown = &v2vv1.SourceShutdownStatus{RequestedAt: &requestedAt}
+
+			stopped, err := reconciler.stopSourceVM(mock, instance)
+
+			Expect(err).To(BeNil())
+			Expect(stopped).To(BeFalse())
+			Expect(*succeededCondition.Reason).To(Equal(string(v2vv1.SourceVMShutdownFailed)))
+		})
+
+		It("should not stop a source VM that is down: ", func() {
+			stopped, err := reconciler.stopSourceVM(mock, instance)
+
+			Expect(err).To(BeNil())
+			Expect(stopped).To(BeTrue())
+			Expect(processingCondition).To(BeNil())
+		})
+	})
+
 	Describe("validate name", func() {
 		It("should fail with a target VM name that is provided but empty: ", func() {
 			emptyName := ""
@@ -326,6 +790,7 @@ var _ = Describe("Reconcile steps", func() {
 	Describe("validate uniqueness", func() {
 		BeforeEach(func() {
 			instance.Spec.Source.Ovirt = &v2vv1.VirtualMachineImportOvirtSourceSpec{}
+			instance.Spec.ProviderCredentialsSecret = &v2vv1.ObjectIdentifier{Name: "test"}
 			instance.Name = "test"
 			instance.Namespace = "test"
 			mock = &mockProvider{}
@@ -369,7 +834,7 @@ var _ = Describe("Reconcile steps", func() {
 			get = func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
 				switch obj.(type) {
 				case *v2vv1.VirtualMachineImport:
-					obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{}
+					obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "test"}}
 				case *v2vv1.ResourceMapping:
 					obj.(*v2vv1.ResourceMapping).Spec = v2vv1.ResourceMappingSpec{}
 				case *corev1.Secret:
@@ -389,6 +854,59 @@ var _ = Describe("Reconcile steps", func() {
 			Expect(validated).To(Equal(true))
 		})
 
+		table.DescribeTable("should reject a snapshot import: ", func(warm bool, supported bool) {
+			instance.Spec.Snapshot = true
+			instance.Spec.Warm = warm
+			supportsSnapshotImport = func() bool {
+				return supported
+			}
+			var validCondition *v2vv1.VirtualMachineImportCondition
+			update = func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+				if vmi, ok := obj.(*v2vv1.VirtualMachineImport); ok {
+					validCondition = conditions.FindConditionOfType(vmi.Status.Conditions, v2vv1.Valid)
+				}
+				return nil
+			}
+
+			validated, err := reconciler.validate(instance, mock)
+
+			Expect(err).To(BeNil())
+			Expect(validated).To(BeFalse())
+			Expect(*validCondition.Reason).To(Equal(string(v2vv1.SnapshotImportNotSupported)))
+		},
+			table.Entry("combined with warm import", true, true),
+			table.Entry("on an unsupported provider", false, false),
+		)
+
+		It("should not validate a conversion pod image that isn't allowed: ", func() {
+			image := "attacker/virt-v2v:latest"
+			instance.Spec.ConversionPod = &v2vv1.ConversionPodSpec{Image: &image}
+			var validCondition *v2vv1.VirtualMachineImportCondition
+			update = func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+				if vmi, ok := obj.(*v2vv1.VirtualMachineImport); ok {
+					validCondition = conditions.FindConditionOfType(vmi.Status.Conditions, v2vv1.Valid)
+				}
+				return nil
+			}
+
+			validated, err := reconciler.validate(instance, mock)
+
+			Expect(err).To(BeNil())
+			Expect(validated).To(BeFalse())
+			Expect(*validCondition.Reason).To(Equal(string(v2vv1.ConversionPodNotAllowed)))
+		})
+
+		It("should validate a conversion pod image allowed by the controller config: ", func() {
+			image := "quay.io/kubevirt/vm-import-virtv2v:debug"
+			instance.Spec.ConversionPod = &v2vv1.ConversionPodSpec{Image: &image}
+			reconciler.ctrlConfig.ConfigMap.Data = map[string]string{ctrlConfig.ConversionPodAllowedImagesKey: "- " + image}
+
+			validated, err := reconciler.validate(instance, mock)
+
+			Expect(err).To(BeNil())
+			Expect(validated).To(BeTrue())
+		})
+
 		It("should fail to validate: ", func() {
 			validate = func() ([]v2vv1.VirtualMachineImportCondition, error) {
 				return nil, fmt.Errorf("Failed")
@@ -480,6 +998,32 @@ var _ = Describe("Reconcile steps", func() {
 			Expect(err).To(BeNil())
 			Expect(validated).To(Equal(false))
 		})
+
+		It("should count the failed validation conditions once per import: ", func() {
+			reason := string(v2vv1.IncompleteMappingRules)
+			validate = func() ([]v2vv1.VirtualMachineImportCondition, error) {
+				return []v2vv1.VirtualMachineImportCondition{
+					conditions.NewCondition(v2vv1.Valid, reason, "Mapping missing", corev1.ConditionFalse),
+					conditions.NewCondition(v2vv1.MappingRulesVerified, string(v2vv1.MappingRulesVerificationCompleted), "All mapping rules checks passed", corev1.ConditionTrue),
+				}, nil
+			}
+			before, err := metrics.ImportMetrics.GetValidationFailures(reason)
+			Expect(err).To(BeNil())
+
+			for _, uid := range []types.UID{"first-import", "first-import", "second-import"} {
+				instance.UID = uid
+				_, err = reconciler.validate(instance, mock)
+				Expect(err).To(BeNil())
+				Expect(reconciler.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("import blocked")))
+			}
+
+			after, err := metrics.ImportMetrics.GetValidationFailures(reason)
+			Expect(err).To(BeNil())
+			Expect(after).To(Equal(before + 2))
+			passed, err := metrics.ImportMetrics.GetValidationFailures(string(v2vv1.MappingRulesVerificationCompleted))
+			Expect(err).To(BeNil())
+			Expect(passed).To(BeZero())
+		})
 	})
 
 	Describe("createVM step", func() {
@@ -572,7 +1116,7 @@ var _ = Describe("Reconcile steps", func() {
 			get = func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
 				switch obj.(type) {
 				case *v2vv1.VirtualMachineImport:
-					obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{}
+					obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "test"}}
 					obj.(*v2vv1.VirtualMachineImport).Annotations = map[string]string{sourceVMInitialState: string(provider.VMStatusUp)}
 				}
 				return nil
@@ -685,6 +1229,24 @@ var _ = Describe("Reconcile steps", func() {
 			Expect(err).To(BeNil())
 		})
 
+		It("should start the VM right after creating it when there is nothing to copy nor convert: ", func() {
+			instance.Status.Phase = v2vv1.PhaseCreating
+			get = func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+				if _, ok := obj.(*kubevirtv1.VirtualMachineInstance); ok {
+					return errors.NewNotFound(schema.GroupResource{}, "")
+				}
+				return nil
+			}
+
+			requeue, err := reconciler.startVM(mock, instance, vmName)
+
+			Expect(err).To(BeNil())
+			Expect(requeue).To(BeTrue())
+			Expect(instance.Status.Phase).To(Equal(v2vv1.PhaseStarting))
+			Expect(instance.Status.Progress).To(Equal(progressStartVM))
+			Expect(instance.Annotations[AnnCurrentProgress]).To(Equal("90"))
+		})
+
 		It("should fail to start: ", func() {
 			get = func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
 				return fmt.Errorf("Not found")
@@ -1066,6 +1628,289 @@ var _ = Describe("Reconcile steps", func() {
 			Expect(err).To(BeNil())
 		})
 
+		table.DescribeTable("should create new dv from the source snapshot: ", func(source cdiv1.DataVolumeSource) {
+			instance.Spec.Snapshot = true
+			supportsSnapshotImport = func() bool {
+				return true
+			}
+			mockMap.dataVolumes = map[string]cdiv1.DataVolume{
+				"123": {Spec: cdiv1.DataVolumeSpec{Source: source}},
+			}
+			snapshots := 0
+			createVMSnapshot = func() (string, error) {
+				snapshots++
+				return "snapshot-1", nil
+			}
+			vmSnapshotReady = func(snapshotID string) (bool, error) {
+				return true, nil
+			}
+			get = func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+				switch obj.(type) {
+				case *cdiv1.DataVolume:
+					return errors.NewNotFound(schema.GroupResource{}, "")
+				}
+				return nil
+			}
+			var created *cdiv1.DataVolume
+			create = func(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+				if dv, ok := obj.(*cdiv1.DataVolume); ok {
+					created = dv
+				}
+				return nil
+			}
+
+			_, err := reconciler.importDisks(mock, instance, mockMap, vmName)
+
+			Expect(err).To(BeNil())
+			Expect(snapshots).To(Equal(1))
+			Expect(*instance.Status.SourceSnapshot).To(Equal("snapshot-1"))
+			Expect(created.Spec.Checkpoints).To(Equal([]cdiv1.DataVolumeCheckpoint{{Previous: "", Current: "snapshot-1"}}))
+			Expect(created.Spec.FinalCheckpoint).To(BeTrue())
+		},
+			table.Entry("VMware", cdiv1.DataVolumeSource{VDDK: &cdiv1.DataVolumeSourceVDDK{}}),
+			table.Entry("oVirt", cdiv1.DataVolumeSource{Imageio: &cdiv1.DataVolumeSourceImageIO{}}),
+		)
+
+		It("should not create the dv until the source snapshot is ready: ", func() {
+			instance.Spec.Snapshot = true
+			supportsSnapshotImport = func() bool {
+				return true
+			}
+			mockMap.dataVolumes = map[string]cdiv1.DataVolume{
+				"123": {Spec: cdiv1.DataVolumeSpec{Source: cdiv1.DataVolumeSource{Imageio: &cdiv1.DataVolumeSourceImageIO{}}}},
+			}
+			snapshots := 0
+			createVMSnapshot = func() (string, error) {
+				snapshots++
+				return "snapshot-1", nil
+			}
+			vmSnapshotReady = func(snapshotID string) (bool, error) {
+				Expect(snapshotID).To(Equal("snapshot-1"))
+				return false, nil
+			}
+			get = func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+				switch obj.(type) {
+				case *cdiv1.DataVolume:
+					return errors.NewNotFound(schema.GroupResource{}, "")
+				}
+				return nil
+			}
+			var created *cdiv1.DataVolume
+			create = func(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+				if dv, ok := obj.(*cdiv1.DataVolume); ok {
+					created = dv
+				}
+				return nil
+			}
+
+			done, err := reconciler.importDisks(mock, instance, mockMap, vmName)
+
+			Expect(err).To(BeNil())
+			Expect(done).To(BeFalse())
+			Expect(*instance.Status.SourceSnapshot).To(Equal("snapshot-1"))
+			Expect(created).To(BeNil())
+
+			_, err = reconciler.importDisks(mock, instance, mockMap, vmName)
+
+			Expect(err).To(BeNil())
+			Expect(snapshots).To(Equal(1))
+		})
+
+		It("should not copy the ISO image of a CD-ROM from the source snapshot: ", func() {
+			instance.Spec.Snapshot = true
+			supportsSnapshotImport = func() bool {
+				return true
+			}
+			snapshots := 0
+			createVMSnapshot = func() (string, error) {
+				snapshots++
+				return "snapshot-1", nil
+			}
+			mockMap.dataVolumes = map[string]cdiv1.DataVolume{
+				"123": {Spec: cdiv1.DataVolumeSpec{Source: cdiv1.DataVolumeSource{HTTP: &cdiv1.DataVolumeSourceHTTP{}}}},
+			}
+			get = func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+				switch obj.(type) {
+				case *cdiv1.DataVolume:
+					return errors.NewNotFound(schema.GroupResource{}, "")
+				}
+				return nil
+			}
+			var created *cdiv1.DataVolume
+			create = func(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+				if dv, ok := obj.(*cdiv1.DataVolume); ok {
+					created = dv
+				}
+				return nil
+			}
+
+			_, err := reconciler.importDisks(mock, instance, mockMap, vmName)
+
+			Expect(err).To(BeNil())
+			Expect(snapshots).To(Equal(0))
+			Expect(created.Spec.Checkpoints).To(BeEmpty())
+		})
+
+		It("should clone the PVC of the DataSource the CD-ROM is mapped to: ", func() {
+			get = func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+				switch o := obj.(type) {
+				case *unstructured.Unstructured:
+					Expect(o.GetKind()).To(Equal("DataSource"))
+					Expect(key).To(Equal(client.ObjectKey{Namespace: "images", Name: "fedora-iso"}))
+					o.Object["spec"] = map[string]interface{}{
+						"source": map[string]interface{}{
+							"pvc": map[string]interface{}{"name": "fedora-iso-v1"},
+						},
+					}
+				case *corev1.PersistentVolumeClaim:
+					Expect(key).To(Equal(client.ObjectKey{Namespace: "images", Name: "fedora-iso-v1"}))
+					o.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("2Gi")}
+				}
+				return nil
+			}
+			dv := &cdiv1.DataVolume{
+				ObjectMeta: v1.ObjectMeta{
+					Name:        "dv-1",
+					Namespace:   "images",
+					Annotations: map[string]string{utils.AnnDataSource: "images/fedora-iso"},
+				},
+				Spec: cdiv1.DataVolumeSpec{PVC: &corev1.PersistentVolumeClaimSpec{}},
+			}
+
+			err := reconciler.resolveCloneSource(dv)
+
+			Expect(err).To(BeNil())
+			Expect(dv.Spec.Source.PVC).To(Equal(&cdiv1.DataVolumeSourcePVC{Namespace: "images", Name: "fedora-iso-v1"}))
+			Expect(dv.Spec.PVC.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("2Gi")))
+		})
+
+		It("should fail to clone a DataSource without a PVC: ", func() {
+			get = func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+				return nil
+			}
+			dv := &cdiv1.DataVolume{
+				ObjectMeta: v1.ObjectMeta{
+					Name:        "dv-1",
+					Namespace:   "images",
+					Annotations: map[string]string{utils.AnnDataSource: "images/fedora-iso"},
+				},
+				Spec: cdiv1.DataVolumeSpec{PVC: &corev1.PersistentVolumeClaimSpec{}},
+			}
+
+			err := reconciler.resolveCloneSource(dv)
+
+			Expect(err).ToNot(BeNil())
+		})
+
+		It("should not clone a DataSource of another namespace: ", func() {
+			get = func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+				Fail("the DataSource of another namespace must not be read")
+				return nil
+			}
+			dv := &cdiv1.DataVolume{
+				ObjectMeta: v1.ObjectMeta{
+					Name:        "dv-1",
+					Namespace:   "vms",
+					Annotations: map[string]string{utils.AnnDataSource: "images/fedora-iso"},
+				},
+				Spec: cdiv1.DataVolumeSpec{PVC: &corev1.PersistentVolumeClaimSpec{}},
+			}
+
+			err := reconciler.resolveCloneSource(dv)
+
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("DataSource images/fedora-iso can't be cloned"))
+		})
+
+		It("should not clone the PVC of another namespace a DataSource points to: ", func() {
+			get = func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+				switch o := obj.(type) {
+				case *unstructured.Unstructured:
+					o.Object["spec"] = map[string]interface{}{
+						"source": map[string]interface{}{
+							"pvc": map[string]interface{}{"name": "fedora-iso-v1", "namespace": "images"},
+						},
+					}
+				case *corev1.PersistentVolumeClaim:
+					Fail("the PVC of another namespace must not be read")
+				}
+				return nil
+			}
+			dv := &cdiv1.DataVolume{
+				ObjectMeta: v1.ObjectMeta{
+					Name:        "dv-1",
+					Namespace:   "vms",
+					Annotations: map[string]string{utils.AnnDataSource: "vms/fedora-iso"},
+				},
+				Spec: cdiv1.DataVolumeSpec{PVC: &corev1.PersistentVolumeClaimSpec{}},
+			}
+
+			err := reconciler.resolveCloneSource(dv)
+
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("PVC images/fedora-iso-v1 can't be cloned"))
+		})
+
+		It("should not clone a PVC of another namespace: ", func() {
+			dv := &cdiv1.DataVolume{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "dv-1",
+					Namespace: "vms",
+				},
+				Spec: cdiv1.DataVolumeSpec{
+					Source: cdiv1.DataVolumeSource{PVC: &cdiv1.DataVolumeSourcePVC{Namespace: "images", Name: "fedora-iso"}},
+					PVC:    &corev1.PersistentVolumeClaimSpec{},
+				},
+			}
+
+			err := reconciler.resolveCloneSource(dv)
+
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(ContainSubstring("PVC images/fedora-iso can't be cloned"))
+		})
+
+		It("should create the dv with the preallocation decided by the mapper: ", func() {
+			var created *unstructured.Unstructured
+			create = func(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+				if dvObject, ok := obj.(*unstructured.Unstructured); ok {
+					created = dvObject
+				}
+				return nil
+			}
+			dv := &cdiv1.DataVolume{
+				ObjectMeta: v1.ObjectMeta{
+					Name:        "dv-1",
+					Annotations: map[string]string{utils.AnnPreallocation: "true"},
+				},
+			}
+
+			err := reconciler.createDataVolumeObject(dv)
+
+			Expect(err).To(BeNil())
+			Expect(created.GetName()).To(Equal("dv-1"))
+			Expect(created.GetKind()).To(Equal("DataVolume"))
+			preallocation, found, err := unstructured.NestedBool(created.Object, "spec", "preallocation")
+			Expect(err).To(BeNil())
+			Expect(found).To(BeTrue())
+			Expect(preallocation).To(BeTrue())
+		})
+
+		It("should remove the source snapshot: ", func() {
+			snapshot := "snapshot-1"
+			instance.Status.SourceSnapshot = &snapshot
+			var removed string
+			removeVMSnapshot = func(snapshotID string, removeChildren bool) error {
+				removed = snapshotID
+				return nil
+			}
+
+			err := reconciler.removeSourceSnapshot(mock, instance)
+
+			Expect(err).To(BeNil())
+			Expect(removed).To(Equal("snapshot-1"))
+			Expect(instance.Status.SourceSnapshot).To(BeNil())
+		})
+
 		It("should not find a dv: ", func() {
 			get = func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
 				switch obj.(type) {
@@ -1147,7 +1992,7 @@ var _ = Describe("Reconcile steps", func() {
 						Phase: cdiv1.Failed,
 					}
 				case *v2vv1.VirtualMachineImport:
-					obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{}
+					obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "test"}}
 					obj.(*v2vv1.VirtualMachineImport).Annotations = map[string]string{sourceVMInitialState: string(provider.VMStatusDown)}
 				}
 				return nil
@@ -1159,6 +2004,44 @@ var _ = Describe("Reconcile steps", func() {
 		})
 	})
 
+	Describe("storeGuestConversion step", func() {
+		It("should record whether the guest is converted: ", func() {
+			needsGuestConversion = func() (bool, error) {
+				return true, nil
+			}
+
+			err := reconciler.storeGuestConversion(instance, mock)
+
+			Expect(err).To(BeNil())
+			Expect(*instance.Status.GuestConversion).To(BeTrue())
+			Expect(shouldConvertGuest(instance)).To(BeTrue())
+		})
+
+		It("should keep the recorded decision: ", func() {
+			recorded := false
+			instance.Status.GuestConversion = &recorded
+			needsGuestConversion = func() (bool, error) {
+				return true, nil
+			}
+
+			err := reconciler.storeGuestConversion(instance, mock)
+
+			Expect(err).To(BeNil())
+			Expect(shouldConvertGuest(instance)).To(BeFalse())
+		})
+
+		It("should fail when the decision can't be made: ", func() {
+			needsGuestConversion = func() (bool, error) {
+				return false, fmt.Errorf("source VM not found")
+			}
+
+			err := reconciler.storeGuestConversion(instance, mock)
+
+			Expect(err).To(HaveOccurred())
+			Expect(instance.Status.GuestConversion).To(BeNil())
+		})
+	})
+
 	Describe("convertGuest step", func() {
 		var (
 			prov   *mockProvider
@@ -1207,52 +2090,236 @@ var _ = Describe("Reconcile steps", func() {
 					})
 					obj.(*kubevirtv1.VirtualMachine).ObjectMeta.OwnerReferences = refs
 				case *v2vv1.VirtualMachineImport:
-					obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{}
+					obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "test"}}
 					obj.(*v2vv1.VirtualMachineImport).Annotations = map[string]string{sourceVMInitialState: string(provider.VMStatusUp)}
 				case *corev1.Pod:
 					obj = pod
 				}
-				return nil
-			}
-			needsGuestConversion = func() bool {
-				return true
+				return nil
+			}
+			needsGuestConversion = func() (bool, error) {
+				return true, nil
+			}
+			getGuestConversionPod = func() (*corev1.Pod, error) {
+				return nil, nil
+			}
+			launchGuestConversionPod = func() (*corev1.Pod, error) {
+				return pod, nil
+			}
+		})
+
+		It("should return false with no error when the pod is pending", func() {
+			pod.Status.Phase = corev1.PodPending
+
+			done, err := reconciler.convertGuest(prov, instance, mapper, vmName)
+			Expect(err).To(BeNil())
+			Expect(done).To(BeFalse())
+		})
+
+		It("should return false with no error when the pod is running", func() {
+			pod.Status.Phase = corev1.PodRunning
+
+			done, err := reconciler.convertGuest(prov, instance, mapper, vmName)
+			Expect(err).To(BeNil())
+			Expect(done).To(BeFalse())
+		})
+
+		It("should return false with no error when the pod is failed", func() {
+			pod.Status.Phase = corev1.PodFailed
+
+			done, err := reconciler.convertGuest(prov, instance, mapper, vmName)
+			Expect(err).To(BeNil())
+			Expect(done).To(BeFalse())
+		})
+
+		It("should return true with no error when the pod is successful", func() {
+			pod.Status.Phase = corev1.PodSucceeded
+
+			done, err := reconciler.convertGuest(prov, instance, mapper, vmName)
+			Expect(err).To(BeNil())
+			Expect(done).To(BeTrue())
+		})
+
+		It("should store the guest inspection and the log of the conversion: ", func() {
+			pod.Namespace = "default"
+			pod.Status.Phase = corev1.PodSucceeded
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+				{
+					Name: "virt-v2v",
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							Message: `{"type":"linux","distro":"rhel","majorVersion":8,"minorVersion":2,"osinfo":"rhel8.2","firmware":"bios","drivers":["virtio_blk"],"applications":["kernel"]}`,
+						},
+					},
+				},
+			}
+			var configMap *corev1.ConfigMap
+			create = func(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+				configMap = obj.(*corev1.ConfigMap)
+				return nil
+			}
+			var patched *v2vv1.VirtualMachineImport
+			statusPatch = func(ctx context.Context, obj runtime.Object, patch client.Patch) error {
+				patched = obj.(*v2vv1.VirtualMachineImport)
+				return nil
+			}
+
+			done, err := reconciler.convertGuest(prov, instance, mapper, vmName)
+
+			Expect(err).To(BeNil())
+			Expect(done).To(BeTrue())
+			Expect(configMap.Name).To(Equal("test-pod-log"))
+			Expect(configMap.Data).To(HaveKeyWithValue(ConversionLogKey, "virt-v2v log"))
+			Expect(patched.Status.ConversionLog.Name).To(Equal("test-pod-log"))
+			Expect(*patched.Status.GuestInspection).To(Equal(v2vv1.GuestInspection{
+				Type:         "linux",
+				Distro:       "rhel",
+				MajorVersion: 8,
+				MinorVersion: 2,
+				OSInfo:       "rhel8.2",
+				Firmware:     "bios",
+				Drivers:      []string{"virtio_blk"},
+				Applications: []string{"kernel"},
+			}))
+		})
+
+		It("should store the log of a failed conversion: ", func() {
+			pod.Status.Phase = corev1.PodFailed
+			tailLogs = func(pod *corev1.Pod, container string) ([]byte, error) {
+				return nil, fmt.Errorf("forbidden")
+			}
+			var configMap *corev1.ConfigMap
+			create = func(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+				if cm, ok := obj.(*corev1.ConfigMap); ok {
+					configMap = cm
+				}
+				return nil
+			}
+
+			_, err := reconciler.convertGuest(prov, instance, mapper, vmName)
+
+			Expect(err).To(BeNil())
+			Expect(configMap.Data[ConversionLogKey]).To(ContainSubstring("forbidden"))
+			Expect(instance.Status.GuestInspection).To(BeNil())
+		})
+
+		It("should fail the import when the guest customization config map is missing: ", func() {
+			instance.Spec.GuestCustomization = &v2vv1.GuestCustomizationSpec{ConfigMap: corev1.LocalObjectReference{Name: "customization"}}
+			vmGet := get
+			get = func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+				if _, ok := obj.(*corev1.ConfigMap); ok {
+					return errors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, key.Name)
+				}
+				return vmGet(ctx, key, obj)
 			}
-			getGuestConversionPod = func() (*corev1.Pod, error) {
+			launchGuestConversionPod = func() (*corev1.Pod, error) {
+				Fail("the conversion pod should not be launched")
 				return nil, nil
 			}
-			launchGuestConversionPod = func() (*corev1.Pod, error) {
-				return pod, nil
+			var succeeded *v2vv1.VirtualMachineImportCondition
+			update = func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+				if condition := conditions.FindConditionOfType(obj.(*v2vv1.VirtualMachineImport).Status.Conditions, v2vv1.Succeeded); condition != nil {
+					succeeded = condition
+				}
+				return nil
 			}
-		})
-
-		It("should return false with no error when the pod is pending", func() {
-			pod.Status.Phase = corev1.PodPending
 
 			done, err := reconciler.convertGuest(prov, instance, mapper, vmName)
+
 			Expect(err).To(BeNil())
 			Expect(done).To(BeFalse())
+			Expect(*succeeded.Reason).To(Equal(string(v2vv1.GuestConversionFailed)))
+			Expect(*succeeded.Message).To(ContainSubstring("guest customization config map customization not found"))
 		})
 
-		It("should return false with no error when the pod is running", func() {
-			pod.Status.Phase = corev1.PodRunning
+		It("should report the applied guest customization: ", func() {
+			instance.Spec.GuestCustomization = &v2vv1.GuestCustomizationSpec{ConfigMap: corev1.LocalObjectReference{Name: "customization"}}
+			vmGet := get
+			get = func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+				if configMap, ok := obj.(*corev1.ConfigMap); ok {
+					configMap.Data = map[string]string{
+						"10-machine-id.sh": "truncate -s 0 /etc/machine-id",
+						"chrony.conf":      "server ntp.example.com",
+						"files":            "chrony.conf /etc/chrony.conf",
+						"packages":         "qemu-guest-agent",
+					}
+					return nil
+				}
+				return vmGet(ctx, key, obj)
+			}
+			pod.Status.Phase = corev1.PodSucceeded
+			var patched *v2vv1.VirtualMachineImport
+			statusPatch = func(ctx context.Context, obj runtime.Object, patch client.Patch) error {
+				patched = obj.(*v2vv1.VirtualMachineImport)
+				return nil
+			}
 
 			done, err := reconciler.convertGuest(prov, instance, mapper, vmName)
+
 			Expect(err).To(BeNil())
-			Expect(done).To(BeFalse())
+			Expect(done).To(BeTrue())
+			Expect(*patched.Status.GuestCustomization).To(Equal(v2vv1.GuestCustomizationStatus{
+				Phase:    v2vv1.GuestCustomizationApplied,
+				Scripts:  []string{"10-machine-id.sh"},
+				Files:    []string{"/etc/chrony.conf"},
+				Packages: []string{"qemu-guest-agent"},
+			}))
 		})
 
-		It("should return false with no error when the pod is failed", func() {
+		It("should report the failed guest customization: ", func() {
+			instance.Spec.GuestCustomization = &v2vv1.GuestCustomizationSpec{ConfigMap: corev1.LocalObjectReference{Name: "customization"}}
+			pod.Status.Phase = corev1.PodFailed
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+				{
+					Name: "virt-v2v",
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: customizationFailedExitCode},
+					},
+				},
+			}
+
+			var customization *v2vv1.GuestCustomizationStatus
+			statusPatch = func(ctx context.Context, obj runtime.Object, patch client.Patch) error {
+				if patched := obj.(*v2vv1.VirtualMachineImport); patched.Status.GuestCustomization != nil {
+					customization = patched.Status.GuestCustomization
+				}
+				return nil
+			}
+			var succeeded *v2vv1.VirtualMachineImportCondition
+			update = func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+				if condition := conditions.FindConditionOfType(obj.(*v2vv1.VirtualMachineImport).Status.Conditions, v2vv1.Succeeded); condition != nil {
+					succeeded = condition
+				}
+				return nil
+			}
+
+			_, err := reconciler.convertGuest(prov, instance, mapper, vmName)
+
+			Expect(err).To(BeNil())
+			Expect(customization.Phase).To(Equal(v2vv1.GuestCustomizationFailed))
+			Expect(*succeeded.Message).To(ContainSubstring("failed to customize the guest"))
+		})
+
+		It("should not report the guest customization when the conversion failed: ", func() {
+			instance.Spec.GuestCustomization = &v2vv1.GuestCustomizationSpec{ConfigMap: corev1.LocalObjectReference{Name: "customization"}}
 			pod.Status.Phase = corev1.PodFailed
 
-			done, err := reconciler.convertGuest(prov, instance, mapper, vmName)
+			_, err := reconciler.convertGuest(prov, instance, mapper, vmName)
+
 			Expect(err).To(BeNil())
-			Expect(done).To(BeFalse())
+			Expect(instance.Status.GuestCustomization).To(BeNil())
 		})
 
-		It("should return true with no error when the pod is successful", func() {
+		It("should not collect the conversion results twice: ", func() {
 			pod.Status.Phase = corev1.PodSucceeded
+			instance.Status.ConversionLog = &corev1.LocalObjectReference{Name: "test-pod-log"}
+			create = func(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+				Fail("the conversion log config map should not be created again")
+				return nil
+			}
 
 			done, err := reconciler.convertGuest(prov, instance, mapper, vmName)
+
 			Expect(err).To(BeNil())
 			Expect(done).To(BeTrue())
 		})
@@ -1265,6 +2332,7 @@ var _ = Describe("Reconcile steps", func() {
 		BeforeEach(func() {
 			config = &v2vv1.VirtualMachineImport{}
 			config.Spec = v2vv1.VirtualMachineImportSpec{
+				ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "test"},
 				Source: v2vv1.VirtualMachineImportSourceSpec{
 					Ovirt: &v2vv1.VirtualMachineImportOvirtSourceSpec{},
 				},
@@ -1306,7 +2374,7 @@ var _ = Describe("Reconcile steps", func() {
 					})
 					obj.(*kubevirtv1.VirtualMachine).ObjectMeta.OwnerReferences = refs
 				case *v2vv1.VirtualMachineImport:
-					obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{}
+					obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "test"}}
 					obj.(*v2vv1.VirtualMachineImport).Annotations = map[string]string{sourceVMInitialState: string(provider.VMStatusUp)}
 				}
 				return nil
@@ -1362,6 +2430,7 @@ var _ = Describe("Reconcile steps", func() {
 				switch obj.(type) {
 				case *v2vv1.VirtualMachineImport:
 					obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{
+						ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "test"},
 						Source: v2vv1.VirtualMachineImportSourceSpec{
 							Ovirt: &v2vv1.VirtualMachineImportOvirtSourceSpec{},
 						},
@@ -1445,6 +2514,7 @@ var _ = Describe("Reconcile steps", func() {
 				switch obj.(type) {
 				case *v2vv1.VirtualMachineImport:
 					obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{
+						ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "test"},
 						Source: v2vv1.VirtualMachineImportSourceSpec{
 							Ovirt: nil,
 						},
@@ -1464,6 +2534,7 @@ var _ = Describe("Reconcile steps", func() {
 				switch obj.(type) {
 				case *v2vv1.VirtualMachineImport:
 					obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{
+						ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "test"},
 						Source: v2vv1.VirtualMachineImportSourceSpec{
 							Ovirt: &v2vv1.VirtualMachineImportOvirtSourceSpec{},
 						},
@@ -1486,6 +2557,7 @@ var _ = Describe("Reconcile steps", func() {
 				switch obj.(type) {
 				case *v2vv1.VirtualMachineImport:
 					obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{
+						ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "test"},
 						Source: v2vv1.VirtualMachineImportSourceSpec{
 							Ovirt: &v2vv1.VirtualMachineImportOvirtSourceSpec{},
 						},
@@ -1510,6 +2582,7 @@ var _ = Describe("Reconcile steps", func() {
 				case *v2vv1.VirtualMachineImport:
 
 					obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{
+						ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "test"},
 						Source: v2vv1.VirtualMachineImportSourceSpec{
 							Ovirt: &v2vv1.VirtualMachineImportOvirtSourceSpec{},
 						},
@@ -1533,15 +2606,20 @@ var _ = Describe("Reconcile steps", func() {
 			Expect(result).To(Equal(reconcile.Result{RequeueAfter: requeueAfterValidationFailureTime}))
 		})
 
-		It("should fail to stop vm: ", func() {
+		It("should wait for the shut down timeout when the vm fails to stop: ", func() {
+			getVM = func(id *string, name *string, cluster *string, clusterID *string) (interface{}, error) {
+				vm := newVM()
+				vm.SetStatus(ovirtsdk.VMSTATUS_UP)
+				return vm, nil
+			}
 			stopVM = func(id string) error {
 				return fmt.Errorf("Not so fast")
 			}
 
 			result, err := reconciler.Reconcile(request)
 
-			Expect(err).To(Not(BeNil()))
-			Expect(result).To(Equal(reconcile.Result{}))
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(reconcile.Result{RequeueAfter: SlowReQ}))
 		})
 
 		It("should fail to create mapper: ", func() {
@@ -1573,6 +2651,7 @@ var _ = Describe("Reconcile steps", func() {
 				switch obj.(type) {
 				case *v2vv1.VirtualMachineImport:
 					obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{
+						ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "test"},
 						Source: v2vv1.VirtualMachineImportSourceSpec{
 							Ovirt: &v2vv1.VirtualMachineImportOvirtSourceSpec{},
 						},
@@ -1625,6 +2704,7 @@ var _ = Describe("Reconcile steps", func() {
 				case *v2vv1.VirtualMachineImport:
 					vmImport := obj.(*v2vv1.VirtualMachineImport)
 					vmImport.Spec = v2vv1.VirtualMachineImportSpec{
+						ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "test"},
 						Source: v2vv1.VirtualMachineImportSourceSpec{
 							Ovirt: &v2vv1.VirtualMachineImportOvirtSourceSpec{},
 						},
@@ -1694,6 +2774,7 @@ var _ = Describe("Reconcile steps", func() {
 					})
 					obj.(*v2vv1.VirtualMachineImport).Status.Conditions = conditions
 					obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{
+						ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "test"},
 						Source: v2vv1.VirtualMachineImportSourceSpec{
 							Ovirt: &v2vv1.VirtualMachineImportOvirtSourceSpec{},
 						},
@@ -1735,6 +2816,7 @@ var _ = Describe("Reconcile steps", func() {
 					})
 					obj.(*v2vv1.VirtualMachineImport).Status.Conditions = conditions
 					obj.(*v2vv1.VirtualMachineImport).Spec = v2vv1.VirtualMachineImportSpec{
+						ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "test"},
 						Source: v2vv1.VirtualMachineImportSourceSpec{
 							Ovirt: &v2vv1.VirtualMachineImportOvirtSourceSpec{},
 						},
@@ -1763,6 +2845,7 @@ var _ = Describe("Reconcile steps", func() {
 				config = &v2vv1.VirtualMachineImport{}
 				config.Annotations = map[string]string{"vmimport.v2v.kubevirt.io/source-vm-initial-state": "up"}
 				config.Spec = v2vv1.VirtualMachineImportSpec{
+					ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "test"},
 					Source: v2vv1.VirtualMachineImportSourceSpec{
 						Ovirt: &v2vv1.VirtualMachineImportOvirtSourceSpec{},
 					},
@@ -1821,7 +2904,7 @@ var _ = Describe("Reconcile steps", func() {
 			It("should not increment counter for done import: ", func() {
 				config.SetDeletionTimestamp(&v1.Time{})
 				config.Annotations = make(map[string]string)
-				config.Annotations[AnnCurrentProgress] = progressDone
+				config.Annotations[AnnCurrentProgress] = "100"
 
 				counterValueBefore := getCounterCancelled()
 				durationSamplesBefore := getCountDurationCancelled()
@@ -1833,7 +2916,7 @@ var _ = Describe("Reconcile steps", func() {
 
 				counterValueAfter := getCounterCancelled()
 				Expect(counterValueAfter).To(Equal(counterValueBefore))
-				Expect(config.Annotations[AnnCurrentProgress]).To(Equal(progressDone))
+				Expect(config.Annotations[AnnCurrentProgress]).To(Equal("100"))
 				Expect(config.Finalizers).To(BeNil())
 				durationSamplesAfter := getCountDurationCancelled()
 				Expect(durationSamplesAfter).To(Equal(durationSamplesBefore))
@@ -1849,7 +2932,7 @@ var _ = Describe("Reconcile steps", func() {
 
 				Expect(result).To(Equal(reconcile.Result{RequeueAfter: SlowReQ}))
 				Expect(config.Finalizers).To(Not(BeNil()))
-				Expect(config.Annotations[AnnCurrentProgress]).To(Not(BeNil()))
+				Expect(config.Status.Phase).To(Equal(v2vv1.PhaseCopyingDisks))
 
 				// Now make the resource deleted and call Reocncile
 				config.SetDeletionTimestamp(&v1.Time{})
@@ -1868,18 +2951,422 @@ var _ = Describe("Reconcile steps", func() {
 	})
 })
 
+var _ = Describe("Import phases", func() {
+	var (
+		status v2vv1.VirtualMachineImportStatus
+		start  = v1.NewTime(time.Now().Add(-time.Minute))
+		now    = v1.Now()
+	)
+
+	BeforeEach(func() {
+		status = v2vv1.VirtualMachineImportStatus{}
+		Expect(enterPhase(&status, v2vv1.PhasePending, start)).To(Succeed())
+	})
+
+	It("should record when the phases are entered and left: ", func() {
+		Expect(enterPhase(&status, v2vv1.PhaseValidating, now)).To(Succeed())
+
+		Expect(status.Phase).To(Equal(v2vv1.PhaseValidating))
+		Expect(status.PhaseTransitions).To(HaveLen(2))
+		Expect(status.PhaseTransitions[0].StartedAt).To(Equal(start))
+		Expect(*status.PhaseTransitions[0].CompletedAt).To(Equal(now))
+		Expect(status.PhaseTransitions[1].StartedAt).To(Equal(now))
+		Expect(status.PhaseTransitions[1].CompletedAt).To(BeNil())
+	})
+
+	It("should save the duration of the phase left once the status is updated: ", func() {
+		statusPatch = func(ctx context.Context, obj runtime.Object, patch client.Patch) error {
+			return nil
+		}
+		reconciler := &ReconcileVirtualMachineImport{client: &mockClient{}}
+		instance := &v2vv1.VirtualMachineImport{Status: status}
+		before, err := metrics.ImportMetrics.GetCountPhaseDuration(string(v2vv1.PhasePending))
+		Expect(err).To(BeNil())
+
+		Expect(reconciler.updatePhase(instance, v2vv1.PhaseValidating)).To(Succeed())
+
+		after, err := metrics.ImportMetrics.GetCountPhaseDuration(string(v2vv1.PhasePending))
+		Expect(err).To(BeNil())
+		Expect(after).To(Equal(before + 1))
+	})
+
+	It("should not save the duration of the phase left when the status update fails: ", func() {
+		statusPatch = func(ctx context.Context, obj runtime.Object, patch client.Patch) error {
+			return errors.NewConflict(schema.GroupResource{}, "", fmt.Errorf("conflict"))
+		}
+		reconciler := &ReconcileVirtualMachineImport{client: &mockClient{}}
+		instance := &v2vv1.VirtualMachineImport{Status: status}
+		before, err := metrics.ImportMetrics.GetCountPhaseDuration(string(v2vv1.PhasePending))
+		Expect(err).To(BeNil())
+
+		Expect(reconciler.updatePhase(instance, v2vv1.PhaseValidating)).ToNot(Succeed())
+
+		after, err := metrics.ImportMetrics.GetCountPhaseDuration(string(v2vv1.PhasePending))
+		Expect(err).To(BeNil())
+		Expect(after).To(Equal(before))
+	})
+
+	It("should not save the duration of a phase when only the status changes: ", func() {
+		statusPatch = func(ctx context.Context, obj runtime.Object, patch client.Patch) error {
+			return nil
+		}
+		reconciler := &ReconcileVirtualMachineImport{client: &mockClient{}}
+		instance := &v2vv1.VirtualMachineImport{Status: status}
+		before, err := metrics.ImportMetrics.GetCountPhaseDuration(string(v2vv1.PhasePending))
+		Expect(err).To(BeNil())
+
+		Expect(reconciler.updatePhase(instance, v2vv1.PhasePending, func(i *v2vv1.VirtualMachineImport) {
+			i.Status.Progress = 5
+		})).To(Succeed())
+
+		after, err := metrics.ImportMetrics.GetCountPhaseDuration(string(v2vv1.PhasePending))
+		Expect(err).To(BeNil())
+		Expect(after).To(Equal(before))
+	})
+
+	It("should not record the current phase again: ", func() {
+		Expect(enterPhase(&status, v2vv1.PhasePending, now)).To(Succeed())
+
+		Expect(status.PhaseTransitions).To(HaveLen(1))
+	})
+
+	It("should raise the progress to the one of the phase: ", func() {
+		status.Phase = v2vv1.PhaseCopyingDisks
+		status.Progress = 42
+
+		Expect(enterPhase(&status, v2vv1.PhaseConverting, now)).To(Succeed())
+		Expect(status.Progress).To(Equal(progressConvertingGuest))
+
+		status.Progress = 80
+		Expect(enterPhase(&status, v2vv1.PhaseFailed, now)).To(Succeed())
+		Expect(status.Progress).To(Equal(progressDone))
+		Expect(*status.PhaseTransitions[len(status.PhaseTransitions)-1].CompletedAt).To(Equal(now))
+	})
+
+	It("should not skip the validation: ", func() {
+		Expect(enterPhase(&status, v2vv1.PhaseCopyingDisks, now)).ToNot(Succeed())
+
+		Expect(status.Phase).To(Equal(v2vv1.PhasePending))
+	})
+
+	It("should not leave a final phase: ", func() {
+		Expect(enterPhase(&status, v2vv1.PhaseFailed, now)).To(Succeed())
+
+		Expect(enterPhase(&status, v2vv1.PhaseValidating, now)).ToNot(Succeed())
+	})
+
+	It("should start the VM right after creating it: ", func() {
+		status.Phase = v2vv1.PhaseCreating
+
+		Expect(enterPhase(&status, v2vv1.PhaseStarting, now)).To(Succeed())
+		Expect(status.Phase).To(Equal(v2vv1.PhaseStarting))
+	})
+
+	It("should succeed right after creating the VM: ", func() {
+		status.Phase = v2vv1.PhaseCreating
+
+		Expect(enterPhase(&status, v2vv1.PhaseSucceeded, now)).To(Succeed())
+		Expect(status.Progress).To(Equal(progressDone))
+	})
+
+	It("should move an import started before the phases were recorded to any phase: ", func() {
+		legacy := v2vv1.VirtualMachineImportStatus{}
+
+		Expect(enterPhase(&legacy, v2vv1.PhaseConverting, now)).To(Succeed())
+		Expect(legacy.PhaseTransitions).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("Disk copy progress", func() {
+	var (
+		dv    *cdiv1.DataVolume
+		start = v1.NewTime(time.Now().Add(-100 * time.Second))
+		now   = v1.NewTime(start.Add(100 * time.Second))
+	)
+
+	BeforeEach(func() {
+		dv = &cdiv1.DataVolume{
+			ObjectMeta: v1.ObjectMeta{Name: "dv-1"},
+			Spec: cdiv1.DataVolumeSpec{
+				Source: cdiv1.DataVolumeSource{
+					Imageio: &cdiv1.DataVolumeSourceImageIO{DiskID: "disk-1"},
+				},
+				PVC: &corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1000")},
+					},
+				},
+			},
+		}
+	})
+
+	It("should create the item of the source disk: ", func() {
+		item := newDataVolumeItem(dv)
+
+		Expect(item.Name).To(Equal("dv-1"))
+		Expect(item.SourceDiskID).To(Equal("disk-1"))
+		Expect(item.SizeBytes).To(Equal(int64(1000)))
+	})
+
+	It("should record the access and volume modes of the data volume: ", func() {
+		volumeMode := corev1.PersistentVolumeBlock
+		dv.Spec.PVC.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}
+		dv.Spec.PVC.VolumeMode = &volumeMode
+
+		item := newDataVolumeItem(dv)
+
+		Expect(item.AccessMode).To(Equal(corev1.ReadWriteMany))
+		Expect(item.VolumeMode).To(Equal(corev1.PersistentVolumeBlock))
+	})
+
+	It("should record the preallocation and the estimate of the consumed bytes: ", func() {
+		dv.Annotations = map[string]string{utils.AnnPreallocation: "true", utils.AnnEstimatedBytes: "1000"}
+
+		item := newDataVolumeItem(dv)
+
+		Expect(item.Preallocated).To(BeTrue())
+		Expect(item.EstimatedBytes).To(Equal(int64(1000)))
+	})
+
+	It("should compute the bytes transferred and the throughput: ", func() {
+		item := newDataVolumeItem(dv)
+		item.StartedAt = &start
+		dv.Status.Phase = cdiv1.ImportInProgress
+		dv.Status.Progress = "25.50%"
+
+		updateDataVolumeItem(&item, dv, now)
+
+		Expect(item.Phase).To(Equal(string(cdiv1.ImportInProgress)))
+		Expect(item.Progress).To(Equal(25))
+		Expect(item.BytesTransferred).To(Equal(int64(255)))
+		Expect(item.Throughput).To(Equal(int64(2)))
+		Expect(item.CompletedAt).To(BeNil())
+	})
+
+	It("should compute the bytes transferred from the size of the source disk: ", func() {
+		dv.Annotations = map[string]string{utils.AnnSourceBytes: "800"}
+		item := newDataVolumeItem(dv)
+		item.StartedAt = &start
+		dv.Status.Phase = cdiv1.ImportInProgress
+		dv.Status.Progress = "25.00%"
+
+		updateDataVolumeItem(&item, dv, now)
+
+		Expect(item.SourceBytes).To(Equal(int64(800)))
+		Expect(item.BytesTransferred).To(Equal(int64(200)))
+		Expect(item.Throughput).To(Equal(int64(2)))
+	})
+
+	It("should record when the copy starts and completes: ", func() {
+		item := newDataVolumeItem(dv)
+		dv.Status.Phase = cdiv1.ImportInProgress
+		dv.Status.Progress = "N/A"
+
+		updateDataVolumeItem(&item, dv, start)
+		Expect(*item.StartedAt).To(Equal(start))
+		Expect(item.Progress).To(Equal(0))
+
+		dv.Status.Phase = cdiv1.Succeeded
+		updateDataVolumeItem(&item, dv, now)
+		Expect(*item.CompletedAt).To(Equal(now))
+		Expect(item.Progress).To(Equal(100))
+		Expect(item.Throughput).To(Equal(int64(10)))
+	})
+
+	It("should estimate the completion at the throughput of the disks being copied: ", func() {
+		items := []v2vv1.DataVolumeItem{
+			{Phase: string(cdiv1.ImportInProgress), SizeBytes: 1000, BytesTransferred: 500, Throughput: 5},
+			{Phase: string(cdiv1.ImportInProgress), SizeBytes: 1000, BytesTransferred: 700, Throughput: 5},
+			{Phase: string(cdiv1.ImportScheduled), SizeBytes: 250, SourceBytes: 200},
+			{Phase: string(cdiv1.Succeeded), SizeBytes: 5000, BytesTransferred: 5000, Throughput: 50, CompletedAt: &start},
+		}
+
+		eta := estimateCompletionTime(items, now)
+
+		Expect(eta.Time).To(Equal(now.Add(100 * time.Second)))
+	})
+
+	It("should not estimate the completion when no disk is being copied: ", func() {
+		items := []v2vv1.DataVolumeItem{
+			{Phase: string(cdiv1.Pending), SizeBytes: 1000},
+		}
+
+		Expect(estimateCompletionTime(items, now)).To(BeNil())
+	})
+})
+
+var _ = Describe("Guest inspection warnings", func() {
+	vmWithTemplateOS := func(os string) *kubevirtv1.VirtualMachine {
+		return &kubevirtv1.VirtualMachine{
+			ObjectMeta: v1.ObjectMeta{Labels: map[string]string{"os.template.kubevirt.io/" + os: "true"}},
+		}
+	}
+
+	table.DescribeTable("should warn about the converted guest: ",
+		func(inspection v2vv1.GuestInspection, expected int) {
+			Expect(guestInspectionWarnings(&inspection)).To(HaveLen(expected))
+		},
+		table.Entry("Windows with the viostor driver", v2vv1.GuestInspection{Type: "windows", OSInfo: "win2k19", Drivers: []string{"viostor", "netkvm"}}, 0),
+		table.Entry("Windows without the storage drivers", v2vv1.GuestInspection{Type: "windows", OSInfo: "win2k19", Drivers: []string{"netkvm"}}, 1),
+		table.Entry("Linux without virtio drivers", v2vv1.GuestInspection{Type: "linux", OSInfo: "rhel8.2"}, 0),
+		table.Entry("Unknown OS", v2vv1.GuestInspection{Type: "windows"}, 1),
+	)
+
+	Describe("Template mismatch", func() {
+		var reconciler *ReconcileVirtualMachineImport
+
+		BeforeEach(func() {
+			reconciler = &ReconcileVirtualMachineImport{client: &mockClient{}, templateProvider: &mockTemplateProvider{}}
+			statusPatch = func(ctx context.Context, obj runtime.Object, patch client.Patch) error {
+				Fail("the VM must not be patched")
+				return nil
+			}
+			findTemplates = func(namespace string, os string, workload *string, flavor *string) (*oapiv1.TemplateList, error) {
+				return &oapiv1.TemplateList{}, nil
+			}
+		})
+
+		It("should not warn when the template of the VM matches the operating system of the guest: ", func() {
+			warning, err := reconciler.templateMismatchWarning(vmWithTemplateOS("rhel8.2"), &v2vv1.GuestInspection{OSInfo: "rhel8.2"})
+
+			Expect(err).To(BeNil())
+			Expect(warning).To(BeEmpty())
+		})
+
+		It("should not warn about a VM created without template: ", func() {
+			warning, err := reconciler.templateMismatchWarning(&kubevirtv1.VirtualMachine{}, &v2vv1.GuestInspection{OSInfo: "rhel8.2"})
+
+			Expect(err).To(BeNil())
+			Expect(warning).To(BeEmpty())
+		})
+
+		It("should name the newest template for the operating system of the guest: ", func() {
+			vm := vmWithTemplateOS("rhel7.9")
+			vm.Labels["workload.template.kubevirt.io/server"] = "true"
+			vm.Labels["flavor.template.kubevirt.io/medium"] = "true"
+			vm.Labels["vm.kubevirt.io/template"] = "rhel7-server-medium"
+			vm.Labels["vm.kubevirt.io/template.namespace"] = "templates"
+			labels := map[string]string{}
+			for k, v := range vm.Labels {
+				labels[k] = v
+			}
+			var searched []string
+			findTemplates = func(namespace string, os string, workload *string, flavor *string) (*oapiv1.TemplateList, error) {
+				searched = []string{namespace, os, *workload, *flavor}
+				return &oapiv1.TemplateList{Items: []oapiv1.Template{
+					{ObjectMeta: v1.ObjectMeta{Name: "rhel8-server-medium-old", Namespace: namespace, CreationTimestamp: v1.Unix(1000, 0)}},
+					{ObjectMeta: v1.ObjectMeta{Name: "rhel8-server-medium", Namespace: namespace, CreationTimestamp: v1.Unix(2000, 0)}},
+				}}, nil
+			}
+
+			warning, err := reconciler.templateMismatchWarning(vm, &v2vv1.GuestInspection{OSInfo: "rhel8.2"})
+
+			Expect(err).To(BeNil())
+			Expect(searched).To(Equal([]string{"templates", "rhel8.2", "server", "medium"}))
+			Expect(warning).To(ContainSubstring("template templates/rhel8-server-medium matches rhel8.2"))
+			Expect(vm.Labels).To(Equal(labels))
+		})
+
+		It("should warn when no template matches the operating system of the guest: ", func() {
+			var searchedNamespace string
+			findTemplates = func(namespace string, os string, workload *string, flavor *string) (*oapiv1.TemplateList, error) {
+				searchedNamespace = namespace
+				return &oapiv1.TemplateList{}, nil
+			}
+
+			warning, err := reconciler.templateMismatchWarning(vmWithTemplateOS("rhel7.9"), &v2vv1.GuestInspection{OSInfo: "rhel8.2"})
+
+			Expect(err).To(BeNil())
+			Expect(searchedNamespace).To(Equal("openshift"))
+			Expect(warning).To(ContainSubstring("no template matches rhel8.2"))
+		})
+
+		It("should fail when the templates cannot be listed: ", func() {
+			findTemplates = func(namespace string, os string, workload *string, flavor *string) (*oapiv1.TemplateList, error) {
+				return nil, fmt.Errorf("forbidden")
+			}
+
+			_, err := reconciler.templateMismatchWarning(vmWithTemplateOS("rhel7.9"), &v2vv1.GuestInspection{OSInfo: "rhel8.2"})
+
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	It("should add the warnings to the ones of the validation: ", func() {
+		reason := string(v2vv1.ValidationReportedWarnings)
+		message := "VM is on a standalone host"
+		instance := &v2vv1.VirtualMachineImport{
+			Status: v2vv1.VirtualMachineImportStatus{
+				Conditions: []v2vv1.VirtualMachineImportCondition{
+					{Type: v2vv1.Valid, Status: corev1.ConditionTrue, Reason: &reason, Message: &message},
+				},
+			},
+		}
+
+		condition := validConditionWithWarnings(instance, []string{"guest warning"})
+
+		Expect(*condition.Reason).To(Equal(reason))
+		Expect(*condition.Message).To(Equal("VM is on a standalone host; guest warning"))
+	})
+})
+
+var _ = Describe("Import metrics", func() {
+	It("should count the imports in progress by phase and provider: ", func() {
+		list = func(ctx context.Context, objectList runtime.Object, opts ...client.ListOption) error {
+			objectList.(*v2vv1.VirtualMachineImportList).Items = []v2vv1.VirtualMachineImport{
+				{Spec: v2vv1.VirtualMachineImportSpec{Source: v2vv1.VirtualMachineImportSourceSpec{Ovirt: &v2vv1.VirtualMachineImportOvirtSourceSpec{}}}, Status: v2vv1.VirtualMachineImportStatus{Phase: v2vv1.PhaseCopyingDisks}},
+				{Spec: v2vv1.VirtualMachineImportSpec{Source: v2vv1.VirtualMachineImportSourceSpec{Ovirt: &v2vv1.VirtualMachineImportOvirtSourceSpec{}}}, Status: v2vv1.VirtualMachineImportStatus{Phase: v2vv1.PhaseCopyingDisks}},
+				{Spec: v2vv1.VirtualMachineImportSpec{Source: v2vv1.VirtualMachineImportSourceSpec{Vmware: &v2vv1.VirtualMachineImportVmwareSourceSpec{}}}, Status: v2vv1.VirtualMachineImportStatus{Phase: v2vv1.PhaseConverting}},
+				{Spec: v2vv1.VirtualMachineImportSpec{Source: v2vv1.VirtualMachineImportSourceSpec{Vmware: &v2vv1.VirtualMachineImportVmwareSourceSpec{}}}, Status: v2vv1.VirtualMachineImportStatus{Phase: v2vv1.PhaseSucceeded}},
+				{Spec: v2vv1.VirtualMachineImportSpec{Source: v2vv1.VirtualMachineImportSourceSpec{Vmware: &v2vv1.VirtualMachineImportVmwareSourceSpec{}}}},
+			}
+			return nil
+		}
+		reconciler := &ReconcileVirtualMachineImport{client: &mockClient{}}
+
+		counts, err := reconciler.countImportsInProgress()
+
+		Expect(err).To(BeNil())
+		Expect(counts).To(Equal(map[metrics.ImportsInProgress]int{
+			{Phase: string(v2vv1.PhaseCopyingDisks), Provider: "ovirt"}: 2,
+			{Phase: string(v2vv1.PhaseConverting), Provider: "vmware"}:  1,
+		}))
+	})
+
+	It("should count the bytes copied since the previous status: ", func() {
+		storageClass := "metrics-test"
+		dv := &cdiv1.DataVolume{
+			ObjectMeta: v1.ObjectMeta{Name: "dv-1"},
+			Spec:       cdiv1.DataVolumeSpec{PVC: &corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClass}},
+		}
+		instance := &v2vv1.VirtualMachineImport{
+			Spec: v2vv1.VirtualMachineImportSpec{Source: v2vv1.VirtualMachineImportSourceSpec{Ovirt: &v2vv1.VirtualMachineImportOvirtSourceSpec{}}},
+			Status: v2vv1.VirtualMachineImportStatus{
+				DataVolumes: []v2vv1.DataVolumeItem{{Name: "dv-1", BytesTransferred: 700}},
+			},
+		}
+
+		saveCopiedBytes(instance, []v2vv1.DataVolumeItem{{Name: "dv-1", BytesTransferred: 200}}, []*cdiv1.DataVolume{dv})
+		saveCopiedBytes(instance, nil, []*cdiv1.DataVolume{dv})
+
+		copied, err := metrics.ImportMetrics.GetCopiedBytes("ovirt", storageClass)
+		Expect(err).To(BeNil())
+		Expect(copied).To(Equal(float64(1200)))
+	})
+})
+
 var _ = Describe("Disks import progress", func() {
 	table.DescribeTable("Percentage count",
-		func(disks map[string]float64, expected string) {
+		func(disks map[string]float64, expected int) {
 			result := disksImportProgress(disks, float64(len(disks)))
 			Expect(result).To(Equal(expected))
 		},
-		table.Entry("No progress", map[string]float64{"1": 0.0}, "10"),
-		table.Entry("Two disks no progress", map[string]float64{"1": 0.0, "2": 0.0}, "10"),
-		table.Entry("Two disks done progress", map[string]float64{"1": 100, "2": 100}, "75"),
-		table.Entry("Two disks half done", map[string]float64{"1": 50, "2": 50}, "42"),
-		table.Entry("Two disks one done", map[string]float64{"1": 50, "2": 100}, "58"),
-		table.Entry("Done progress", map[string]float64{"1": 100}, "75"),
+		table.Entry("No progress", map[string]float64{"1": 0.0}, 10),
+		table.Entry("Two disks no progress", map[string]float64{"1": 0.0, "2": 0.0}, 10),
+		table.Entry("Two disks done progress", map[string]float64{"1": 100, "2": 100}, 75),
+		table.Entry("Two disks half done", map[string]float64{"1": 50, "2": 50}, 42),
+		table.Entry("Two disks one done", map[string]float64{"1": 50, "2": 100}, 58),
+		table.Entry("Done progress", map[string]float64{"1": 100}, 75),
 	)
 })
 
@@ -1895,6 +3382,8 @@ func NewReconciler(client client.Client, finder mappings.ResourceFinder, scheme
 		recorder:               recorder,
 		controller:             controller,
 		ctrlConfigProvider:     ctrlConfigProvider,
+		podLogs:                &mockPodLogReader{},
+		templateProvider:       &mockTemplateProvider{},
 	}
 }
 
@@ -1904,7 +3393,10 @@ type mockFinder struct{}
 
 type mockProvider struct{}
 
-type mockMapper struct{}
+type mockMapper struct {
+	// dataVolumes are the mapped data volumes, a single empty one when it is nil
+	dataVolumes map[string]cdiv1.DataVolume
+}
 
 type mockFactory struct{}
 
@@ -1918,6 +3410,10 @@ type mockOvirtClient struct{}
 
 type mockVmwareClient struct{}
 
+type mockPodLogReader struct{}
+
+type mockTemplateProvider struct{}
+
 // Create implements client.Client
 func (c *mockClient) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
 	return create(ctx, obj)
@@ -1950,7 +3446,7 @@ func (c *mockClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.
 
 // List implements client.Client
 func (c *mockClient) List(ctx context.Context, objectList runtime.Object, opts ...client.ListOption) error {
-	return list(ctx, objectList)
+	return list(ctx, objectList, opts...)
 }
 
 // Status implements client.StatusClient
@@ -1970,7 +3466,7 @@ func (p *mockProvider) Init(secret *corev1.Secret, instance *v2vv1.VirtualMachin
 
 // TestConnection implements Provider.TestConnection
 func (p *mockProvider) TestConnection() error {
-	return nil
+	return testConnection()
 }
 
 // ValidateDiskStatus return true if disk is valid
@@ -1996,8 +3492,8 @@ func (p *mockProvider) Validate() ([]v2vv1.VirtualMachineImportCondition, error)
 }
 
 // StopVM implements Provider.StopVM
-func (p *mockProvider) StopVM(cr *v2vv1.VirtualMachineImport, client rclient.Client) error {
-	return nil
+func (p *mockProvider) StopVM(cr *v2vv1.VirtualMachineImport, client rclient.Client, method v2vv1.SourceShutdownMethod) error {
+	return stopSourceVM(method)
 }
 
 // UpdateVM implements Provider.UpdateVM
@@ -2040,7 +3536,7 @@ func (p *mockProvider) ProcessTemplate(template *oapiv1.Template, name *string,
 	return processTemplate(template, name, namespace)
 }
 
-func (p *mockProvider) NeedsGuestConversion() bool {
+func (p *mockProvider) NeedsGuestConversion() (bool, error) {
 	return needsGuestConversion()
 }
 
@@ -2048,7 +3544,7 @@ func (p *mockProvider) GetGuestConversionPod() (*corev1.Pod, error) {
 	return getGuestConversionPod()
 }
 
-func (p *mockProvider) LaunchGuestConversionPod(_ *kubevirtv1.VirtualMachine, _ map[string]cdiv1.DataVolume) (*corev1.Pod, error) {
+func (p *mockProvider) LaunchGuestConversionPod(_ *kubevirtv1.VirtualMachine, _ map[string]cdiv1.DataVolume, _ *guestconversion.GuestCustomization) (*corev1.Pod, error) {
 	return launchGuestConversionPod()
 }
 
@@ -2056,14 +3552,27 @@ func (p *mockProvider) SupportsWarmMigration() bool {
 	return supportsWarmMigration()
 }
 
+func (p *mockProvider) SupportsSnapshotImport() bool {
+	return supportsSnapshotImport()
+}
+
 func (p *mockProvider) CreateVMSnapshot() (string, error) {
 	return createVMSnapshot()
 }
 
+func (p *mockProvider) VMSnapshotReady(snapshotID string) (bool, error) {
+	return vmSnapshotReady(snapshotID)
+}
+
 func (p *mockProvider) RemoveVMSnapshot(snapshotID string, removeChildren bool) error {
 	return removeVMSnapshot(snapshotID, removeChildren)
 }
 
+// SelectVMs implements VMSelector.SelectVMs
+func (p *mockProvider) SelectVMs(search string) ([]provider.SelectedVM, error) {
+	return selectVMs(search)
+}
+
 // CreateEmptyVM implements Mapper.CreateEmptyVM
 func (m *mockMapper) CreateEmptyVM(vmName *string) *kubevirtv1.VirtualMachine {
 	return &kubevirtv1.VirtualMachine{}
@@ -2081,6 +3590,9 @@ func (m *mockMapper) MapVM(targetVMName *string, vmSpec *kubevirtv1.VirtualMachi
 
 // MapDataVolumes implements Mapper.MapDataVolumes
 func (m *mockMapper) MapDataVolumes(targetVMName *string, overhead cdiv1.FilesystemOverhead) (map[string]cdiv1.DataVolume, error) {
+	if m.dataVolumes != nil {
+		return m.dataVolumes, nil
+	}
 	return map[string]cdiv1.DataVolume{"123": {}}, nil
 }
 
@@ -2094,7 +3606,7 @@ func (m *mockMapper) RunningState() bool {
 }
 
 // NewOvirtClient implements Factory.NewOvirtClient
-func (f *mockFactory) NewOvirtClient(dataMap map[string]string) (pclient.VMClient, error) {
+func (f *mockFactory) NewOvirtClient(dataMap map[string]string) (pclient.OvirtClient, error) {
 	return &mockOvirtClient{}, nil
 }
 
@@ -2103,6 +3615,11 @@ func (f *mockFactory) NewVmwareClient(dataMap map[string]string) (pclient.VMClie
 	return &mockVmwareClient{}, nil
 }
 
+// Release implements Factory.Release
+func (f *mockFactory) Release(client pclient.VMClient) error {
+	return nil
+}
+
 func (f *mockController) Watch(src source.Source, eventhandler handler.EventHandler, predicates ...predicate.Predicate) error {
 	return nil
 }
@@ -2119,7 +3636,7 @@ func (c *mockOvirtClient) GetVM(id *string, name *string, cluster *string, clust
 	return getVM(id, name, cluster, clusterID)
 }
 
-func (c *mockOvirtClient) StopVM(id string) error {
+func (c *mockOvirtClient) StopVM(id string, _ v2vv1.SourceShutdownMethod) error {
 	return stopVM(id)
 }
 
@@ -2135,11 +3652,35 @@ func (c *mockOvirtClient) Close() error {
 	return nil
 }
 
+func (c *mockOvirtClient) ListVMs(search string) ([]*ovirtsdk.Vm, error) {
+	return nil, nil
+}
+
+func (c *mockOvirtClient) GetDisk(id string) (*ovirtsdk.Disk, error) {
+	return nil, nil
+}
+
+func (c *mockOvirtClient) GetEngineCA() (string, error) {
+	return "", nil
+}
+
+func (c *mockOvirtClient) CreateVMSnapshot(vmID string, description string) (string, error) {
+	return "", nil
+}
+
+func (c *mockOvirtClient) GetVMSnapshot(vmID string, snapshotID string) (*ovirtsdk.Snapshot, error) {
+	return nil, nil
+}
+
+func (c *mockOvirtClient) RemoveVMSnapshot(vmID string, snapshotID string) error {
+	return nil
+}
+
 func (c *mockVmwareClient) GetVM(id *string, name *string, cluster *string, clusterID *string) (interface{}, error) {
 	return getVM(id, name, cluster, clusterID)
 }
 
-func (c *mockVmwareClient) StopVM(id string) error {
+func (c *mockVmwareClient) StopVM(id string, _ v2vv1.SourceShutdownMethod) error {
 	return stopVM(id)
 }
 
@@ -2155,6 +3696,21 @@ func (c *mockVmwareClient) TestConnection() error {
 	return nil
 }
 
+// TailLogs implements PodLogReader.TailLogs
+func (r *mockPodLogReader) TailLogs(pod *corev1.Pod, container string, _ int64, _ int64) ([]byte, error) {
+	return tailLogs(pod, container)
+}
+
+// Find implements templates.TemplateProvider.Find
+func (t *mockTemplateProvider) Find(namespace *string, os *string, workload *string, flavor *string) (*oapiv1.TemplateList, error) {
+	return findTemplates(*namespace, *os, workload, flavor)
+}
+
+// Process implements templates.TemplateProvider.Process
+func (t *mockTemplateProvider) Process(namespace string, vmName *string, template *oapiv1.Template) (*oapiv1.Template, error) {
+	return template, nil
+}
+
 func (c *mockKubeVirtConfigProvider) GetConfig() (kvConfig.KubeVirtConfig, error) {
 	return getKvConfig(), nil
 }
@@ -2171,6 +3727,7 @@ func getSecret() []byte {
 
 func newVM() *ovirtsdk.Vm {
 	vm := ovirtsdk.Vm{}
+	vm.SetStatus(ovirtsdk.VMSTATUS_DOWN)
 	nicSlice := ovirtsdk.NicSlice{}
 	nicSlice.SetSlice([]*ovirtsdk.Nic{&ovirtsdk.Nic{}})
 	vm.SetNics(&nicSlice)
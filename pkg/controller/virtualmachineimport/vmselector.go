@@ -0,0 +1,123 @@
+package virtualmachineimport
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/conditions"
+	"github.com/kubevirt/vm-import-operator/pkg/ownerreferences"
+	provider "github.com/kubevirt/vm-import-operator/pkg/providers"
+	"github.com/kubevirt/vm-import-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// SelectedByLabel is set on the imports created for the VMs matched by a VM selector, its value is the name of the selecting import.
+	SelectedByLabel = annAPIGroup + "/selected-by"
+	// EventVMsSelected is emitted when the imports of the VMs matched by a VM selector are created.
+	EventVMsSelected = "VMsSelected"
+)
+
+func isSelectorImport(instance *v2vv1.VirtualMachineImport) bool {
+	return instance.Spec.Source.Ovirt != nil && instance.Spec.Source.Ovirt.VMSelector != nil
+}
+
+// expandVMSelector creates a VirtualMachineImport for each of the source VMs matched by the VM selector.
+// It returns false if no VM matches the selector.
+func (r *ReconcileVirtualMachineImport) expandVMSelector(instance *v2vv1.VirtualMachineImport, vmProvider provider.Provider) (bool, error) {
+	vmiName := types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}
+	search := instance.Spec.Source.Ovirt.VMSelector.Search
+
+	selector, ok := vmProvider.(provider.VMSelector)
+	if !ok {
+		return false, fmt.Errorf("provider doesn't support selecting VMs")
+	}
+	vms, err := selector.SelectVMs(search)
+	if err != nil {
+		condition := newValidationCondition(v2vv1.SourceVMNotFound, fmt.Sprintf("Failed to select source VMs with '%s': %s", search, err.Error()))
+		if cerr := r.upsertStatusConditions(vmiName, condition); cerr != nil {
+			return false, cerr
+		}
+		return false, err
+	}
+	if len(vms) == 0 {
+		condition := newValidationCondition(v2vv1.SourceVMNotFound, fmt.Sprintf("No source VM matches '%s'", search))
+		return false, r.upsertStatusConditions(vmiName, condition)
+	}
+
+	created := make([]v2vv1.ObjectIdentifier, 0, len(vms))
+	for _, vm := range vms {
+		child, err := newSelectedVMImport(instance, vm)
+		if err != nil {
+			return false, err
+		}
+		err = r.client.Create(context.TODO(), child)
+		if err != nil && !k8serrors.IsAlreadyExists(err) {
+			return false, err
+		}
+		namespace := child.Namespace
+		created = append(created, v2vv1.ObjectIdentifier{Name: child.Name, Namespace: &namespace})
+	}
+
+	err = r.storeSelectedImports(vmiName, created)
+	if err != nil {
+		return false, err
+	}
+	r.removeFinalizer(utils.CancelledImportFinalizer, instance)
+	r.recorder.Eventf(instance, corev1.EventTypeNormal, EventVMsSelected, "Created %d virtual machine imports for the VMs matching '%s'", len(created), search)
+	return true, nil
+}
+
+func (r *ReconcileVirtualMachineImport) storeSelectedImports(vmiName types.NamespacedName, imports []v2vv1.ObjectIdentifier) error {
+	var instance v2vv1.VirtualMachineImport
+	err := r.apiReader.Get(context.TODO(), vmiName, &instance)
+	if err != nil {
+		return err
+	}
+	return r.updatePhase(&instance, v2vv1.PhaseSucceeded, func(instance *v2vv1.VirtualMachineImport) {
+		instance.Status.VirtualMachineImports = imports
+		conditions.UpsertCondition(instance, conditions.NewCondition(v2vv1.Valid, string(v2vv1.ValidationCompleted), "Validation completed successfully", corev1.ConditionTrue))
+		conditions.UpsertCondition(instance, conditions.NewSucceededCondition(string(v2vv1.VirtualMachineImportsCreated), fmt.Sprintf("Created %d virtual machine imports", len(imports)), corev1.ConditionTrue))
+	})
+}
+
+// newSelectedVMImport creates the import of a single VM matched by the selector of the given import. It shares
+// the credentials, the resource mapping and the rest of the spec of the selecting import.
+func newSelectedVMImport(instance *v2vv1.VirtualMachineImport, vm provider.SelectedVM) (*v2vv1.VirtualMachineImport, error) {
+	name, err := utils.NormalizeName(fmt.Sprintf("%s-%s", instance.Name, vm.ID))
+	if err != nil {
+		return nil, err
+	}
+	spec := instance.Spec.DeepCopy()
+	pattern := spec.Source.Ovirt.VMSelector.TargetVMNamePattern
+	spec.Source.Ovirt.VMSelector = nil
+	id := vm.ID
+	spec.Source.Ovirt.VM = v2vv1.VirtualMachineImportOvirtSourceVMSpec{ID: &id}
+	spec.TargetVMName = nil
+	if pattern != nil {
+		targetVMName, err := utils.NormalizeLabel(strings.NewReplacer("{name}", vm.Name, "{id}", vm.ID, "{cluster}", vm.Cluster).Replace(*pattern))
+		if err != nil {
+			return nil, err
+		}
+		spec.TargetVMName = &targetVMName
+	}
+
+	return &v2vv1.VirtualMachineImport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.Namespace,
+			Labels: map[string]string{
+				SelectedByLabel: instance.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				ownerreferences.NewVMImportOwnerReference(instance.TypeMeta, instance.ObjectMeta),
+			},
+		},
+		Spec: *spec,
+	}, nil
+}
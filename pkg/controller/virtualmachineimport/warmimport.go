@@ -10,6 +10,7 @@ import (
 	"github.com/go-logr/logr"
 	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
 	"github.com/kubevirt/vm-import-operator/pkg/conditions"
+	"github.com/kubevirt/vm-import-operator/pkg/metrics"
 	provider "github.com/kubevirt/vm-import-operator/pkg/providers"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,12 +36,15 @@ func shouldFinalizeWarmImport(instance *v2vv1.VirtualMachineImport) bool {
 func (r *ReconcileVirtualMachineImport) warmImport(provider provider.Provider, instance *v2vv1.VirtualMachineImport, mapper provider.Mapper, vmName types.NamespacedName, log logr.Logger) (time.Duration, error) {
 	if instance.Status.WarmImport.Failures > r.ctrlConfig.WarmImportMaxFailures() || instance.Status.WarmImport.ConsecutiveFailures > r.ctrlConfig.WarmImportConsecutiveFailures() {
 		err := r.endWarmImportFailed(provider, instance, "warm import retry limit reached")
-		if err != nil {
-			return NoReQ, err
-		}
+		return NoReQ, err
+	}
+
+	err := r.updatePhase(instance, v2vv1.PhaseWarmCopying)
+	if err != nil {
+		return FastReQ, err
 	}
 
-	err := utils.AddFinalizer(instance, utils.CleanupSnapshotsFinalizer, r.client)
+	err = utils.AddFinalizer(instance, utils.CleanupSnapshotsFinalizer, r.client)
 	if err != nil {
 		return FastReQ, err
 	}
@@ -92,6 +96,13 @@ func (r *ReconcileVirtualMachineImport) warmImport(provider provider.Provider, i
 		return SlowReQ, nil
 	}
 
+	if instance.Status.WarmImport.StageStartTime != nil {
+		err = r.completeWarmImportStage(instance)
+		if err != nil {
+			return FastReQ, err
+		}
+	}
+
 	// should only run after the very first stage is completed.
 	if instance.Status.WarmImport.NextStageTime == nil {
 		err = r.setNextStageTime(instance)
@@ -113,6 +124,11 @@ func (r *ReconcileVirtualMachineImport) warmImport(provider provider.Provider, i
 		return FastReQ, err
 	}
 
+	err = r.startWarmImportStage(instance)
+	if err != nil {
+		return FastReQ, err
+	}
+
 	err = r.setNextStageTime(instance)
 	if err != nil {
 		return FastReQ, err
@@ -153,25 +169,27 @@ func (r *ReconcileVirtualMachineImport) ensureDisksExist(provider provider.Provi
 			}
 		}
 
-		// only take a snapshot if one hasn't been taken yet
-		var snapshotRef string
-		if instance.Status.WarmImport.RootSnapshot != nil {
-			snapshotRef = *instance.Status.WarmImport.RootSnapshot
-		} else {
-			snapshotRef, err = provider.CreateVMSnapshot()
-			if err != nil {
-				_ = r.incrementWarmImportFailures(instance)
-				return false, err
-			}
-			err = r.setRootSnapshot(instance, snapshotRef)
-			if err != nil {
-				return false, err
+		if copiedInStages(&dvDef) {
+			// only take a snapshot if one hasn't been taken yet
+			var snapshotRef string
+			if instance.Status.WarmImport.RootSnapshot != nil {
+				snapshotRef = *instance.Status.WarmImport.RootSnapshot
+			} else {
+				snapshotRef, err = provider.CreateVMSnapshot()
+				if err != nil {
+					_ = r.incrementWarmImportFailures(instance)
+					return false, err
+				}
+				err = r.setRootSnapshot(instance, snapshotRef)
+				if err != nil {
+					return false, err
+				}
 			}
-		}
 
-		dvDef.Spec.FinalCheckpoint = false
-		dvDef.Spec.Checkpoints = []cdiv1.DataVolumeCheckpoint{
-			{Previous: "", Current: snapshotRef},
+			dvDef.Spec.FinalCheckpoint = false
+			dvDef.Spec.Checkpoints = []cdiv1.DataVolumeCheckpoint{
+				{Previous: "", Current: snapshotRef},
+			}
 		}
 		dv, err = r.createDataVolume(provider, mapper, instance, &dvDef, vmName)
 		if err != nil {
@@ -226,6 +244,9 @@ func (r *ReconcileVirtualMachineImport) setupNextStage(provider provider.Provide
 		if err != nil {
 			return err
 		}
+		if !copiedInStages(dv) {
+			continue
+		}
 		if dv.Spec.FinalCheckpoint {
 			return nil
 		}
@@ -263,9 +284,18 @@ func (r *ReconcileVirtualMachineImport) setupNextStage(provider provider.Provide
 	return nil
 }
 
+// copiedInStages tells whether the data volume copies a disk of the source VM from its snapshots, a stage at a time.
+// The ISO images of the CD-ROMs are copied at once.
+func copiedInStages(dv *cdiv1.DataVolume) bool {
+	return dv.Spec.Source.VDDK != nil
+}
+
 func (r *ReconcileVirtualMachineImport) setRootSnapshot(instance *v2vv1.VirtualMachineImport, snapshotRef string) error {
 	instanceCopy := instance.DeepCopy()
+	now := metav1.Now()
 	instance.Status.WarmImport.RootSnapshot = &snapshotRef
+	// the first stage starts with the root snapshot
+	instance.Status.WarmImport.StageStartTime = &now
 
 	patch := client.MergeFrom(instanceCopy)
 	err := r.client.Status().Patch(context.TODO(), instance, patch)
@@ -275,6 +305,30 @@ func (r *ReconcileVirtualMachineImport) setRootSnapshot(instance *v2vv1.VirtualM
 	return nil
 }
 
+// startWarmImportStage records when the stage being copied started
+func (r *ReconcileVirtualMachineImport) startWarmImportStage(instance *v2vv1.VirtualMachineImport) error {
+	instanceCopy := instance.DeepCopy()
+	now := metav1.Now()
+	instance.Status.WarmImport.StageStartTime = &now
+
+	return r.client.Status().Patch(context.TODO(), instance, client.MergeFrom(instanceCopy))
+}
+
+// completeWarmImportStage saves the duration of the stage that was copied in the metrics
+func (r *ReconcileVirtualMachineImport) completeWarmImportStage(instance *v2vv1.VirtualMachineImport) error {
+	instanceCopy := instance.DeepCopy()
+	duration := time.Since(instance.Status.WarmImport.StageStartTime.Time).Seconds()
+	instance.Status.WarmImport.StageStartTime = nil
+
+	err := r.client.Status().Patch(context.TODO(), instance, client.MergeFrom(instanceCopy))
+	if err != nil {
+		return err
+	}
+	metrics.ImportMetrics.IncWarmStageSuccessful()
+	metrics.ImportMetrics.SaveWarmStageDuration(duration)
+	return nil
+}
+
 func (r *ReconcileVirtualMachineImport) setNextStageTime(instance *v2vv1.VirtualMachineImport) error {
 	// we already have the next stage scheduled
 	if instance.Status.WarmImport.NextStageTime != nil && instance.Status.WarmImport.NextStageTime.After(time.Now()) {
@@ -309,6 +363,7 @@ func (r *ReconcileVirtualMachineImport) setWarmImportCondition(instance *v2vv1.V
 }
 
 func (r *ReconcileVirtualMachineImport) incrementWarmImportFailures(instance *v2vv1.VirtualMachineImport) error {
+	metrics.ImportMetrics.IncWarmStageFailed()
 	instance.Status.WarmImport.Failures += 1
 	instance.Status.WarmImport.ConsecutiveFailures += 1
 	return r.client.Status().Update(context.TODO(), instance)
@@ -1,9 +1,14 @@
 package guestconversion
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	ctrlConfig "github.com/kubevirt/vm-import-operator/pkg/config/controller"
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
 
 	"kubevirt.io/containerized-data-importer/pkg/common"
@@ -14,7 +19,44 @@ import (
 	libvirtxml "libvirt.org/libvirt-go-xml"
 )
 
-const configMapVolumeName = "libvirt-domain-xml"
+const (
+	configMapVolumeName     = "libvirt-domain-xml"
+	customizationVolumeName = "guest-customization"
+
+	// CustomizationPackagesKey is the key of the packages installed at first boot in the guest customization config map
+	CustomizationPackagesKey = "packages"
+	// CustomizationFilesKey is the key of the files injected in the guest in the guest customization config map. Each
+	// line holds the key of a file of the config map and the path it is injected at in the guest.
+	CustomizationFilesKey = "files"
+	// CustomizationScriptSuffix is the suffix of the keys of the firstboot scripts in the guest customization config map
+	CustomizationScriptSuffix = ".sh"
+	// CustomizationEnvVar holds the guest customization parsed by the controller, as JSON, in the conversion pod
+	CustomizationEnvVar = "GUEST_CUSTOMIZATION"
+
+	// LibvirtDomainKey is the key of the libvirt domain XML in the config map of the import, where the conversion pod
+	// expects it
+	LibvirtDomainKey = "input.xml"
+	// AnnRetainConversionPod keeps the conversion pod after a successful import
+	AnnRetainConversionPod = "vmimport.v2v.kubevirt.io/retain-conversion-pod"
+)
+
+// the OVMF firmware of the virt-v2v image
+const (
+	ovmfCode           = "/usr/share/OVMF/OVMF_CODE.fd"
+	ovmfVars           = "/usr/share/OVMF/OVMF_VARS.fd"
+	ovmfSecureBootCode = "/usr/share/OVMF/OVMF_CODE.secboot.fd"
+	ovmfSecureBootVars = "/usr/share/OVMF/OVMF_VARS.secboot.fd"
+)
+
+// busDevicePrefixes are the prefixes of the target device names of the disks on each bus
+var busDevicePrefixes = map[string]string{
+	"virtio": "vd",
+	"sata":   "sd",
+	"scsi":   "sd",
+	"usb":    "sd",
+	"ide":    "hd",
+	"fdc":    "fd",
+}
 
 var (
 	virtV2vImage    = os.Getenv("VIRTV2V_IMAGE")
@@ -24,13 +66,63 @@ var (
 // MakeGuestConversionPodSpec creates a pod spec for a virt-v2v pod,
 // containing a volume and a mount for each volume on the VM, as well
 // as a volume and mount for the config map containing the libvirt domain XML.
-func MakeGuestConversionPodSpec(vmSpec *v1.VirtualMachine, dataVolumes map[string]cdiv1.DataVolume, libvirtConfigMap *corev1.ConfigMap) *corev1.Pod {
+// The settings define the placement, the resources and the image of the pod. The guest customization,
+// if any, is passed to the pod as it was parsed, and only the keys of its scripts and files are mounted
+// from its config map next to the libvirt domain XML.
+func MakeGuestConversionPodSpec(vmSpec *v1.VirtualMachine, dataVolumes map[string]cdiv1.DataVolume, libvirtConfigMap *corev1.ConfigMap, settings v2vv1.ConversionPodSpec, customization *GuestCustomization) (*corev1.Pod, error) {
 	// this is the fsGroup that the CDI importer pod uses
 	fsGroup := common.QemuSubGid
 
 	volumes, volumeMounts, volumeDevices := makePodVolumeMounts(vmSpec, dataVolumes, libvirtConfigMap)
+	var env []corev1.EnvVar
+	if customization != nil {
+		parsed, err := json.Marshal(customization)
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, corev1.EnvVar{Name: CustomizationEnvVar, Value: string(parsed)})
+		// the virt-v2v pod expects to see the customization at /mnt/customization
+		volumes = append(volumes, corev1.Volume{
+			Name: customizationVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: customization.ConfigMap,
+					Items:                customization.keysToPaths(),
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      customizationVolumeName,
+			MountPath: "/mnt/customization",
+			ReadOnly:  true,
+		})
+	}
+
+	image := virtV2vImage
+	if settings.Image != nil {
+		image = *settings.Image
+	}
+
+	// Request access to /dev/kvm via Kubevirt's Device Manager
+	resources := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{},
+	}
+	if settings.Resources != nil {
+		settings.Resources.DeepCopyInto(&resources)
+		if resources.Limits == nil {
+			resources.Limits = corev1.ResourceList{}
+		}
+	}
+	resources.Limits["devices.kubevirt.io/kvm"] = resource.MustParse("1")
+
+	// Ensure that the pod is deployed on a node where /dev/kvm is present.
+	nodeSelector := map[string]string{}
+	for key, value := range settings.NodeSelector {
+		nodeSelector[key] = value
+	}
+	nodeSelector["kubevirt.io/schedulable"] = "true"
 
-	return &corev1.Pod{
+	pod := &corev1.Pod{
 		Spec: corev1.PodSpec{
 			SecurityContext: &corev1.PodSecurityContext{
 				FSGroup: &fsGroup,
@@ -39,25 +131,183 @@ func MakeGuestConversionPodSpec(vmSpec *v1.VirtualMachine, dataVolumes map[strin
 			Containers: []corev1.Container{
 				{
 					Name:            "virt-v2v",
-					Image:           virtV2vImage,
+					Image:           image,
 					VolumeMounts:    volumeMounts,
 					VolumeDevices:   volumeDevices,
+					Env:             env,
 					ImagePullPolicy: imagePullPolicy,
-					// Request access to /dev/kvm via Kubevirt's Device Manager
-					Resources: corev1.ResourceRequirements{
-						Limits: corev1.ResourceList{
-							"devices.kubevirt.io/kvm": resource.MustParse("1"),
-						},
-					},
+					Resources:       resources,
 				},
 			},
-			Volumes: volumes,
-			// Ensure that the pod is deployed on a node where /dev/kvm is present.
-			NodeSelector: map[string]string{
-				"kubevirt.io/schedulable": "true",
-			},
+			Volumes:      volumes,
+			NodeSelector: nodeSelector,
+			Tolerations:  settings.Tolerations,
 		},
 	}
+	if settings.PriorityClassName != nil {
+		pod.Spec.PriorityClassName = *settings.PriorityClassName
+	}
+	if settings.ServiceAccountName != nil {
+		pod.Spec.ServiceAccountName = *settings.ServiceAccountName
+	}
+	return pod, nil
+}
+
+// CheckConversionPodSettings checks that the override only runs the conversion pod with the image, the service
+// account and the placement of the controller configuration, or with the ones of the allow list. The default image
+// of the controller is always allowed. The pod is created by the controller, with the kvm device and the disks of the
+// VM, so any other image or identity would let the author of the import run code they may not be allowed to run, as
+// a service account they may not be allowed to use, and any other placement would let them schedule it on nodes or
+// at a priority reserved to other workloads.
+func CheckConversionPodSettings(config v2vv1.ConversionPodSpec, override *v2vv1.ConversionPodSpec, allowList ctrlConfig.ConversionPodAllowList) error {
+	if override == nil {
+		return nil
+	}
+	if override.Image != nil && *override.Image != virtV2vImage && !isAllowed(*override.Image, config.Image, allowList.Images) {
+		return fmt.Errorf("the image %s of the conversion pod isn't allowed by the controller configuration", *override.Image)
+	}
+	if override.ServiceAccountName != nil && !isAllowed(*override.ServiceAccountName, config.ServiceAccountName, allowList.ServiceAccountNames) {
+		return fmt.Errorf("the service account %s of the conversion pod isn't allowed by the controller configuration", *override.ServiceAccountName)
+	}
+	for key, value := range override.NodeSelector {
+		if !isAllowedNodeSelectorLabel(key, value, config.NodeSelector, allowList.NodeSelectorLabels) {
+			return fmt.Errorf("the node selector %s=%s of the conversion pod isn't allowed by the controller configuration", key, value)
+		}
+	}
+	for i := range override.Tolerations {
+		if !isAllowedToleration(&override.Tolerations[i], config.Tolerations, allowList.Tolerations) {
+			return fmt.Errorf("the toleration of taint %s of the conversion pod isn't allowed by the controller configuration", override.Tolerations[i].Key)
+		}
+	}
+	if override.PriorityClassName != nil && !isAllowed(*override.PriorityClassName, config.PriorityClassName, allowList.PriorityClassNames) {
+		return fmt.Errorf("the priority class %s of the conversion pod isn't allowed by the controller configuration", *override.PriorityClassName)
+	}
+	return nil
+}
+
+func isAllowed(value string, configured *string, allowed []string) bool {
+	if configured != nil && *configured == value {
+		return true
+	}
+	for _, a := range allowed {
+		if a == value {
+			return true
+		}
+	}
+	return false
+}
+
+func isAllowedNodeSelectorLabel(key string, value string, configured map[string]string, allowed map[string][]string) bool {
+	if configuredValue, found := configured[key]; found && configuredValue == value {
+		return true
+	}
+	for _, a := range allowed[key] {
+		if a == value {
+			return true
+		}
+	}
+	return false
+}
+
+func isAllowedToleration(toleration *corev1.Toleration, configured []corev1.Toleration, allowed []corev1.Toleration) bool {
+	for _, tolerations := range [][]corev1.Toleration{configured, allowed} {
+		for i := range tolerations {
+			if tolerations[i].MatchToleration(toleration) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MergeConversionPodSettings returns the conversion pod settings of the controller configuration with the ones set
+// in the override replacing them. It fails when the override isn't allowed, see CheckConversionPodSettings.
+func MergeConversionPodSettings(config v2vv1.ConversionPodSpec, override *v2vv1.ConversionPodSpec, allowList ctrlConfig.ConversionPodAllowList) (v2vv1.ConversionPodSpec, error) {
+	merged := *config.DeepCopy()
+	if override == nil {
+		return merged, nil
+	}
+	err := CheckConversionPodSettings(config, override, allowList)
+	if err != nil {
+		return merged, err
+	}
+	if override.Image != nil {
+		merged.Image = override.Image
+	}
+	if override.NodeSelector != nil {
+		merged.NodeSelector = override.NodeSelector
+	}
+	if override.Tolerations != nil {
+		merged.Tolerations = override.Tolerations
+	}
+	if override.Resources != nil {
+		merged.Resources = override.Resources
+	}
+	if override.PriorityClassName != nil {
+		merged.PriorityClassName = override.PriorityClassName
+	}
+	if override.ServiceAccountName != nil {
+		merged.ServiceAccountName = override.ServiceAccountName
+	}
+	return merged, nil
+}
+
+// GuestCustomization is the content of a guest customization config map
+type GuestCustomization struct {
+	// ConfigMap is the config map holding the scripts and the files
+	ConfigMap corev1.LocalObjectReference `json:"-"`
+	// Scripts are the keys of the firstboot scripts, in the order they run
+	Scripts []string `json:"scripts"`
+	// Files maps the keys of the files injected in the guest to their path in the guest
+	Files map[string]string `json:"files"`
+	// Packages are installed at first boot
+	Packages []string `json:"packages"`
+}
+
+// keysToPaths returns the keys of the config map the scripts and the files are read from, mounted at their own name
+func (c *GuestCustomization) keysToPaths() []corev1.KeyToPath {
+	keys := append([]string{}, c.Scripts...)
+	for key := range c.Files {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	items := make([]corev1.KeyToPath, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, corev1.KeyToPath{Key: key, Path: key})
+	}
+	return items
+}
+
+// ParseGuestCustomization reads the firstboot scripts, the files and the packages of a guest customization config map.
+// The keys ending with .sh are the scripts, unless they are injected as files.
+func ParseGuestCustomization(configMap *corev1.ConfigMap) (*GuestCustomization, error) {
+	customization := &GuestCustomization{
+		ConfigMap: corev1.LocalObjectReference{Name: configMap.Name},
+		Files:     map[string]string{},
+		Packages:  strings.Fields(configMap.Data[CustomizationPackagesKey]),
+	}
+	for _, line := range strings.Split(configMap.Data[CustomizationFilesKey], "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid line '%s' in the %s of the guest customization, expected '<key> <path>'", line, CustomizationFilesKey)
+		}
+		if _, found := configMap.Data[fields[0]]; !found {
+			if _, found = configMap.BinaryData[fields[0]]; !found {
+				return nil, fmt.Errorf("file %s of the guest customization is missing in config map %s", fields[0], configMap.Name)
+			}
+		}
+		customization.Files[fields[0]] = fields[1]
+	}
+	for key := range configMap.Data {
+		if _, isFile := customization.Files[key]; strings.HasSuffix(key, CustomizationScriptSuffix) && !isFile {
+			customization.Scripts = append(customization.Scripts, key)
+		}
+	}
+	sort.Strings(customization.Scripts)
+	return customization, nil
 }
 
 func makePodVolumeMounts(vmSpec *v1.VirtualMachine, dataVolumes map[string]cdiv1.DataVolume, libvirtConfigMap *corev1.ConfigMap) ([]corev1.Volume, []corev1.VolumeMount, []corev1.VolumeDevice) {
@@ -67,9 +317,16 @@ func makePodVolumeMounts(vmSpec *v1.VirtualMachine, dataVolumes map[string]cdiv1
 
 	// add volumes and mounts for each of the VM's disks.
 	// the virt-v2v pod expects to see the disks mounted at /mnt/disks/diskX
+	disks := disksByVolume(vmSpec)
 	for i, v := range vmSpec.Spec.Template.Spec.Volumes {
+		// only the imported disks are converted. The media of the CD-ROMs and floppies, which may be claims
+		// shared with other VMs in another volume mode, are left out of the pod.
+		claimName, found := volumeClaimName(v)
+		if device, _ := diskDeviceAndBus(disks[v.Name]); !found || device != "disk" {
+			continue
+		}
 		var volumeMode corev1.PersistentVolumeMode
-		dv, ok := dataVolumes[v.DataVolume.Name]
+		dv, ok := dataVolumes[claimName]
 		if ok && dv.Spec.PVC != nil && dv.Spec.PVC.VolumeMode != nil {
 			volumeMode = *dv.Spec.PVC.VolumeMode
 		} else {
@@ -78,10 +335,10 @@ func makePodVolumeMounts(vmSpec *v1.VirtualMachine, dataVolumes map[string]cdiv1
 		}
 
 		vol := corev1.Volume{
-			Name: dv.Name,
+			Name: claimName,
 			VolumeSource: corev1.VolumeSource{
 				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-					ClaimName: dv.Name,
+					ClaimName: claimName,
 					ReadOnly:  false,
 				},
 			},
@@ -90,13 +347,13 @@ func makePodVolumeMounts(vmSpec *v1.VirtualMachine, dataVolumes map[string]cdiv1
 
 		if volumeMode == corev1.PersistentVolumeBlock {
 			volDevice := corev1.VolumeDevice{
-				Name:       v.DataVolume.Name,
+				Name:       claimName,
 				DevicePath: fmt.Sprintf("/dev/block%v", i),
 			}
 			volumeDevices = append(volumeDevices, volDevice)
 		} else {
 			volMount := corev1.VolumeMount{
-				Name:      v.DataVolume.Name,
+				Name:      claimName,
 				MountPath: fmt.Sprintf("/mnt/disks/disk%v", i),
 			}
 			volumeMounts = append(volumeMounts, volMount)
@@ -122,45 +379,69 @@ func makePodVolumeMounts(vmSpec *v1.VirtualMachine, dataVolumes map[string]cdiv1
 	return volumes, volumeMounts, volumeDevices
 }
 
-// MakeLibvirtDomain makes a minimal libvirt domain for a VM to be used by the guest conversion pod
+// MakeLibvirtDomain makes a minimal libvirt domain for a VM to be used by the guest conversion pod. The domain has the
+// firmware, the boot order and the disk buses of the VM, so that virt-v2v inspects the guest the way it boots.
 func MakeLibvirtDomain(vmSpec *v1.VirtualMachine, dataVolumes map[string]cdiv1.DataVolume) *libvirtxml.Domain {
 	// virt-v2v needs a very minimal libvirt domain XML file to be provided
 	// with the locations of each of the disks on the VM that is to be converted.
+	domain := vmSpec.Spec.Template.Spec.Domain
+	disks := disksByVolume(vmSpec)
+
 	libvirtDisks := make([]libvirtxml.DomainDisk, 0)
+	devices := make(map[string]int)
+	bootOrder := false
 	for i, vol := range vmSpec.Spec.Template.Spec.Volumes {
-		diskSource := libvirtxml.DomainDiskSource{}
-
-		dv := dataVolumes[vol.DataVolume.Name]
-		if *dv.Spec.PVC.VolumeMode == corev1.PersistentVolumeBlock {
-			diskSource.Block = &libvirtxml.DomainDiskSourceBlock{
-				Dev: fmt.Sprintf("/dev/block%v", i),
-			}
-		} else {
-			diskSource.File = &libvirtxml.DomainDiskSourceFile{
-				// the location where the disk images will be found on
-				// the virt-v2v pod. See also makePodVolumeMounts.
-				File: fmt.Sprintf("/mnt/disks/disk%v/disk.img", i),
-			}
+		disk, found := disks[vol.Name]
+		if !found {
+			disk = v1.Disk{Name: vol.Name}
+		}
+		device, bus := diskDeviceAndBus(disk)
+		// only the imported disks are converted, the CD-ROMs and floppies are kept without their media so that
+		// virt-v2v sees the devices of the guest
+		claimName, imported := volumeClaimName(vol)
+		if !imported && device == "disk" {
+			continue
 		}
 
 		libvirtDisk := libvirtxml.DomainDisk{
-			Device: "disk",
+			Device: device,
 			Driver: &libvirtxml.DomainDiskDriver{
 				Name: "qemu",
 				Type: "raw",
 			},
-			Source: &diskSource,
 			Target: &libvirtxml.DomainDiskTarget{
-				Dev: "hd" + string(rune('a'+i)),
-				Bus: "virtio",
+				Dev: diskTargetDev(bus, devices),
+				Bus: bus,
 			},
 		}
+		if device == "disk" {
+			libvirtDisk.Source = diskSource(i, dataVolumes[claimName])
+		} else {
+			libvirtDisk.ReadOnly = &libvirtxml.DomainDiskReadOnly{}
+		}
+		if disk.BootOrder != nil {
+			libvirtDisk.Boot = &libvirtxml.DomainDeviceBoot{Order: *disk.BootOrder}
+			bootOrder = true
+		}
 		libvirtDisks = append(libvirtDisks, libvirtDisk)
 	}
 
 	// generate libvirt domain xml
-	domain := vmSpec.Spec.Template.Spec.Domain
-	return &libvirtxml.Domain{
+	domainOS := &libvirtxml.DomainOS{
+		Type: &libvirtxml.DomainOSType{
+			Type:    "hvm",
+			Machine: domain.Machine.Type,
+		},
+	}
+	// libvirt doesn't allow both the boot order of the devices and the boot devices of the OS
+	if !bootOrder {
+		domainOS.BootDevices = []libvirtxml.DomainBootDevice{
+			{
+				Dev: "hd",
+			},
+		}
+	}
+	libvirtDomain := &libvirtxml.Domain{
 		Type: "kvm",
 		Name: vmSpec.Name,
 		Memory: &libvirtxml.DomainMemory{
@@ -172,18 +453,114 @@ func MakeLibvirtDomain(vmSpec *v1.VirtualMachine, dataVolumes map[string]cdiv1.D
 				Cores:   int(domain.CPU.Cores),
 			},
 		},
-		OS: &libvirtxml.DomainOS{
-			Type: &libvirtxml.DomainOSType{
-				Type: "hvm",
-			},
-			BootDevices: []libvirtxml.DomainBootDevice{
-				{
-					Dev: "hd",
-				},
-			},
-		},
+		OS: domainOS,
 		Devices: &libvirtxml.DomainDeviceList{
 			Disks: libvirtDisks,
 		},
 	}
+	setFirmware(libvirtDomain, vmSpec)
+	return libvirtDomain
+}
+
+// disksByVolume returns the disks of the VM by the name of their volume
+func disksByVolume(vmSpec *v1.VirtualMachine) map[string]v1.Disk {
+	disks := make(map[string]v1.Disk)
+	for _, disk := range vmSpec.Spec.Template.Spec.Domain.Devices.Disks {
+		disks[disk.Name] = disk
+	}
+	return disks
+}
+
+// volumeClaimName returns the name of the persistent volume claim of an imported disk of the VM, either the claim of
+// its data volume or a claim holding the disk already. The data volumes of the VM are keyed by this name.
+func volumeClaimName(vol v1.Volume) (string, bool) {
+	switch {
+	case vol.DataVolume != nil:
+		return vol.DataVolume.Name, true
+	case vol.PersistentVolumeClaim != nil:
+		return vol.PersistentVolumeClaim.ClaimName, true
+	}
+	return "", false
+}
+
+// setFirmware adds the OVMF loader and nvram to the domain of a VM that boots with EFI
+func setFirmware(libvirtDomain *libvirtxml.Domain, vmSpec *v1.VirtualMachine) {
+	firmware := vmSpec.Spec.Template.Spec.Domain.Firmware
+	if firmware == nil || firmware.Bootloader == nil || firmware.Bootloader.EFI == nil {
+		return
+	}
+	// like KubeVirt, secure boot is enabled unless it is disabled explicitly
+	secureBoot := firmware.Bootloader.EFI.SecureBoot == nil || *firmware.Bootloader.EFI.SecureBoot
+	loader := &libvirtxml.DomainLoader{
+		Path:     ovmfCode,
+		Readonly: "yes",
+		Type:     "pflash",
+	}
+	nvram := &libvirtxml.DomainNVRam{
+		NVRam:    fmt.Sprintf("/var/tmp/%s_VARS.fd", vmSpec.Name),
+		Template: ovmfVars,
+	}
+	if secureBoot {
+		loader.Path = ovmfSecureBootCode
+		loader.Secure = "yes"
+		nvram.Template = ovmfSecureBootVars
+		libvirtDomain.Features = &libvirtxml.DomainFeatureList{
+			SMM: &libvirtxml.DomainFeatureSMM{State: "on"},
+		}
+	}
+	libvirtDomain.OS.Loader = loader
+	libvirtDomain.OS.NVRam = nvram
+}
+
+// diskDeviceAndBus returns the libvirt device and bus of a disk of the VM, defaulting the bus the way KubeVirt does
+func diskDeviceAndBus(disk v1.Disk) (string, string) {
+	switch {
+	case disk.CDRom != nil:
+		return "cdrom", busOrDefault(disk.CDRom.Bus, "sata")
+	case disk.Floppy != nil:
+		return "floppy", "fdc"
+	case disk.LUN != nil:
+		// virt-v2v converts the content of a LUN like the one of a disk
+		return "disk", busOrDefault(disk.LUN.Bus, "virtio")
+	case disk.Disk != nil:
+		return "disk", busOrDefault(disk.Disk.Bus, "virtio")
+	}
+	return "disk", "virtio"
+}
+
+func busOrDefault(bus string, defaultBus string) string {
+	if bus == "" {
+		return defaultBus
+	}
+	return bus
+}
+
+// diskTargetDev returns the next free target device name of the bus, e.g. vda for the first virtio disk and sdb for
+// the second sata or scsi disk
+func diskTargetDev(bus string, devices map[string]int) string {
+	prefix, found := busDevicePrefixes[bus]
+	if !found {
+		prefix = "sd"
+	}
+	index := devices[prefix]
+	devices[prefix] = index + 1
+	return prefix + string(rune('a'+index))
+}
+
+// diskSource returns the location of the volume of the disk in the virt-v2v pod. See also makePodVolumeMounts.
+func diskSource(index int, dv cdiv1.DataVolume) *libvirtxml.DomainDiskSource {
+	if dv.Spec.PVC != nil && dv.Spec.PVC.VolumeMode != nil && *dv.Spec.PVC.VolumeMode == corev1.PersistentVolumeBlock {
+		return &libvirtxml.DomainDiskSource{
+			Block: &libvirtxml.DomainDiskSourceBlock{
+				Dev: fmt.Sprintf("/dev/block%v", index),
+			},
+		}
+	}
+	return &libvirtxml.DomainDiskSource{
+		File: &libvirtxml.DomainDiskSourceFile{
+			// the location where the disk images will be found on
+			// the virt-v2v pod. See also makePodVolumeMounts.
+			File: fmt.Sprintf("/mnt/disks/disk%v/disk.img", index),
+		},
+	}
 }
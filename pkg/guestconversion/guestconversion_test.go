@@ -1,13 +1,17 @@
 package guestconversion
 
 import (
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	ctrlConfig "github.com/kubevirt/vm-import-operator/pkg/config/controller"
 	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubevirtv1 "kubevirt.io/client-go/api/v1"
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+	libvirtxml "libvirt.org/libvirt-go-xml"
 )
 
 var _ = Describe("GuestConversion", func() {
@@ -61,7 +65,7 @@ var _ = Describe("GuestConversion", func() {
 		})
 
 		It("should create a volume and mount for the libvirt domain config map", func() {
-			pod := MakeGuestConversionPodSpec(vmSpec, dataVolumes, configMap)
+			pod, _ := MakeGuestConversionPodSpec(vmSpec, dataVolumes, configMap, v2vv1.ConversionPodSpec{}, nil)
 			Expect(len(pod.Spec.Volumes)).To(Equal(1))
 			Expect(pod.Spec.Volumes[0].Name).To(Equal(configMapVolumeName))
 			Expect(pod.Spec.Volumes[0].ConfigMap).ToNot(BeNil())
@@ -91,7 +95,7 @@ var _ = Describe("GuestConversion", func() {
 					},
 				},
 			}
-			pod := MakeGuestConversionPodSpec(vmSpec, dataVolumes, configMap)
+			pod, _ := MakeGuestConversionPodSpec(vmSpec, dataVolumes, configMap, v2vv1.ConversionPodSpec{}, nil)
 			Expect(len(pod.Spec.Volumes)).To(Equal(4))
 			Expect(pod.Spec.Volumes[0].Name).To(Equal("dv-1"))
 			Expect(pod.Spec.Volumes[0].VolumeSource.PersistentVolumeClaim.ClaimName).To(Equal("dv-1"))
@@ -115,6 +119,261 @@ var _ = Describe("GuestConversion", func() {
 			Expect(pod.Spec.Containers[0].VolumeDevices[0].Name).To(Equal("dv-block"))
 			Expect(pod.Spec.Containers[0].VolumeDevices[0].DevicePath).To(Equal("/dev/block2"))
 		})
+
+		It("should leave the media of the CD-ROMs out of the pod", func() {
+			vmSpec.Spec.Template.Spec.Volumes = []kubevirtv1.Volume{
+				{
+					Name: "dv-1",
+					VolumeSource: kubevirtv1.VolumeSource{
+						DataVolume: &kubevirtv1.DataVolumeSource{Name: "dv-1"},
+					},
+				},
+				{
+					Name: "installer",
+					VolumeSource: kubevirtv1.VolumeSource{
+						PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "installer-iso"},
+					},
+				},
+			}
+			vmSpec.Spec.Template.Spec.Domain.Devices.Disks = []kubevirtv1.Disk{
+				{Name: "dv-1", DiskDevice: kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{}}},
+				{Name: "installer", DiskDevice: kubevirtv1.DiskDevice{CDRom: &kubevirtv1.CDRomTarget{}}},
+			}
+
+			pod, _ := MakeGuestConversionPodSpec(vmSpec, dataVolumes, configMap, v2vv1.ConversionPodSpec{}, nil)
+
+			Expect(pod.Spec.Volumes).To(HaveLen(2))
+			Expect(pod.Spec.Volumes[0].Name).To(Equal("dv-1"))
+			Expect(pod.Spec.Volumes[1].Name).To(Equal(configMapVolumeName))
+			Expect(pod.Spec.Containers[0].VolumeMounts).To(HaveLen(2))
+			Expect(pod.Spec.Containers[0].VolumeDevices).To(BeEmpty())
+		})
+
+		It("should pass the guest customization and mount its scripts and files", func() {
+			customization := &GuestCustomization{
+				ConfigMap: v1.LocalObjectReference{Name: "customization"},
+				Scripts:   []string{"10-machine-id.sh"},
+				Files:     map[string]string{"chrony.conf": "/etc/chrony.conf"},
+				Packages:  []string{"qemu-guest-agent"},
+			}
+
+			pod, err := MakeGuestConversionPodSpec(vmSpec, dataVolumes, configMap, v2vv1.ConversionPodSpec{}, customization)
+
+			Expect(err).To(BeNil())
+			Expect(pod.Spec.Volumes).To(HaveLen(2))
+			Expect(pod.Spec.Volumes[1].Name).To(Equal(customizationVolumeName))
+			Expect(pod.Spec.Volumes[1].ConfigMap.Name).To(Equal("customization"))
+			Expect(pod.Spec.Volumes[1].ConfigMap.Items).To(Equal([]v1.KeyToPath{
+				{Key: "10-machine-id.sh", Path: "10-machine-id.sh"},
+				{Key: "chrony.conf", Path: "chrony.conf"},
+			}))
+			Expect(pod.Spec.Containers[0].VolumeMounts).To(HaveLen(2))
+			Expect(pod.Spec.Containers[0].VolumeMounts[1].MountPath).To(Equal("/mnt/customization"))
+			Expect(pod.Spec.Containers[0].VolumeMounts[1].ReadOnly).To(BeTrue())
+			Expect(pod.Spec.Containers[0].Env).To(Equal([]v1.EnvVar{{
+				Name:  CustomizationEnvVar,
+				Value: `{"scripts":["10-machine-id.sh"],"files":{"chrony.conf":"/etc/chrony.conf"},"packages":["qemu-guest-agent"]}`,
+			}}))
+		})
+
+		It("should schedule the pod on a node with kvm by default", func() {
+			pod, _ := MakeGuestConversionPodSpec(vmSpec, dataVolumes, configMap, v2vv1.ConversionPodSpec{}, nil)
+			Expect(pod.Spec.NodeSelector).To(Equal(map[string]string{"kubevirt.io/schedulable": "true"}))
+			Expect(pod.Spec.Containers[0].Resources.Limits).To(HaveLen(1))
+			Expect(pod.Spec.Containers[0].Resources.Limits).To(HaveKey(v1.ResourceName("devices.kubevirt.io/kvm")))
+			Expect(pod.Spec.Containers[0].Resources.Requests).To(BeEmpty())
+			Expect(pod.Spec.Tolerations).To(BeEmpty())
+			Expect(pod.Spec.PriorityClassName).To(BeEmpty())
+			Expect(pod.Spec.ServiceAccountName).To(BeEmpty())
+		})
+
+		It("should apply the conversion pod settings", func() {
+			image := "quay.io/kubevirt/vm-import-virtv2v:custom"
+			priorityClass := "low"
+			serviceAccount := "virt-v2v"
+			tolerations := []v1.Toleration{{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "conversion", Effect: v1.TaintEffectNoSchedule}}
+			settings := v2vv1.ConversionPodSpec{
+				Image:        &image,
+				NodeSelector: map[string]string{"node-role.kubernetes.io/conversion": ""},
+				Tolerations:  tolerations,
+				Resources: &v1.ResourceRequirements{
+					Requests: v1.ResourceList{
+						v1.ResourceCPU:    resource.MustParse("1"),
+						v1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+					Limits: v1.ResourceList{
+						v1.ResourceMemory: resource.MustParse("2Gi"),
+					},
+				},
+				PriorityClassName:  &priorityClass,
+				ServiceAccountName: &serviceAccount,
+			}
+
+			pod, _ := MakeGuestConversionPodSpec(vmSpec, dataVolumes, configMap, settings, nil)
+
+			container := pod.Spec.Containers[0]
+			Expect(container.Image).To(Equal(image))
+			Expect(pod.Spec.NodeSelector).To(Equal(map[string]string{
+				"kubevirt.io/schedulable":            "true",
+				"node-role.kubernetes.io/conversion": "",
+			}))
+			Expect(pod.Spec.Tolerations).To(Equal(tolerations))
+			Expect(container.Resources.Requests).To(HaveLen(2))
+			Expect(container.Resources.Limits).To(HaveLen(2))
+			Expect(container.Resources.Limits).To(HaveKey(v1.ResourceName("devices.kubevirt.io/kvm")))
+			Expect(container.Resources.Limits).To(HaveKey(v1.ResourceMemory))
+			Expect(pod.Spec.PriorityClassName).To(Equal(priorityClass))
+			Expect(pod.Spec.ServiceAccountName).To(Equal(serviceAccount))
+			Expect(settings.Resources.Limits).To(HaveLen(1))
+		})
+	})
+
+	Describe("ParseGuestCustomization", func() {
+		It("should read the scripts, the files and the packages", func() {
+			configMap := &v1.ConfigMap{
+				Data: map[string]string{
+					"20-network.sh":          "rm -f /etc/udev/rules.d/70-persistent-net.rules",
+					"10-machine-id.sh":       "truncate -s 0 /etc/machine-id",
+					"install-agent.sh":       "echo injected",
+					"chrony.conf":            "server ntp.example.com",
+					CustomizationFilesKey:    "chrony.conf /etc/chrony.conf\n\ninstall-agent.sh /usr/local/bin/install-agent.sh\n",
+					CustomizationPackagesKey: "qemu-guest-agent\ncloud-init",
+				},
+			}
+
+			customization, err := ParseGuestCustomization(configMap)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(customization.Scripts).To(Equal([]string{"10-machine-id.sh", "20-network.sh"}))
+			Expect(customization.Files).To(Equal(map[string]string{
+				"chrony.conf":      "/etc/chrony.conf",
+				"install-agent.sh": "/usr/local/bin/install-agent.sh",
+			}))
+			Expect(customization.Packages).To(Equal([]string{"qemu-guest-agent", "cloud-init"}))
+		})
+
+		It("should fail when a file is missing", func() {
+			configMap := &v1.ConfigMap{
+				Data: map[string]string{CustomizationFilesKey: "chrony.conf /etc/chrony.conf"},
+			}
+
+			_, err := ParseGuestCustomization(configMap)
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail when a file has no path", func() {
+			configMap := &v1.ConfigMap{
+				Data: map[string]string{"chrony.conf": "", CustomizationFilesKey: "chrony.conf"},
+			}
+
+			_, err := ParseGuestCustomization(configMap)
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("MergeConversionPodSettings", func() {
+		configImage := "config-image"
+		configPriorityClass := "config-priority"
+		otherImage := "other-image"
+		otherServiceAccount := "cluster-admin"
+		otherPriorityClass := "system-node-critical"
+
+		config := v2vv1.ConversionPodSpec{
+			Image:             &configImage,
+			NodeSelector:      map[string]string{"config": "true"},
+			PriorityClassName: &configPriorityClass,
+		}
+
+		It("should return the config settings without override", func() {
+			merged, err := MergeConversionPodSettings(config, nil, ctrlConfig.ConversionPodAllowList{})
+
+			Expect(err).To(BeNil())
+			Expect(merged).To(Equal(config))
+		})
+
+		It("should replace the config settings set in the override", func() {
+			overrideImage := "override-image"
+			serviceAccount := "override-account"
+			override := &v2vv1.ConversionPodSpec{
+				Image:              &overrideImage,
+				ServiceAccountName: &serviceAccount,
+			}
+			allowList := ctrlConfig.ConversionPodAllowList{
+				Images:              []string{overrideImage},
+				ServiceAccountNames: []string{serviceAccount},
+			}
+
+			merged, err := MergeConversionPodSettings(config, override, allowList)
+
+			Expect(err).To(BeNil())
+			Expect(*merged.Image).To(Equal(overrideImage))
+			Expect(*merged.ServiceAccountName).To(Equal(serviceAccount))
+			Expect(merged.NodeSelector).To(Equal(config.NodeSelector))
+			Expect(*merged.PriorityClassName).To(Equal(configPriorityClass))
+		})
+
+		It("should accept the image of the config in the override", func() {
+			override := &v2vv1.ConversionPodSpec{Image: &configImage}
+
+			Expect(CheckConversionPodSettings(config, override, ctrlConfig.ConversionPodAllowList{})).To(Succeed())
+		})
+
+		It("should accept the default image in the override", func() {
+			defaultImage := "default-image"
+			virtV2vImage = defaultImage
+			defer func() { virtV2vImage = "" }()
+			override := &v2vv1.ConversionPodSpec{Image: &defaultImage}
+
+			Expect(CheckConversionPodSettings(config, override, ctrlConfig.ConversionPodAllowList{})).To(Succeed())
+		})
+
+		It("should accept the placement of the config and of the allow list in the override", func() {
+			priorityClass := "allowed-priority"
+			override := &v2vv1.ConversionPodSpec{
+				NodeSelector: map[string]string{"config": "true", "zone": "b"},
+				Tolerations: []v1.Toleration{
+					{Key: "conversion", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+				},
+				PriorityClassName: &priorityClass,
+			}
+			allowList := ctrlConfig.ConversionPodAllowList{
+				NodeSelectorLabels: map[string][]string{"zone": {"a", "b"}},
+				Tolerations: []v1.Toleration{
+					{Key: "conversion", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+				},
+				PriorityClassNames: []string{priorityClass},
+			}
+
+			merged, err := MergeConversionPodSettings(config, override, allowList)
+
+			Expect(err).To(BeNil())
+			Expect(merged.NodeSelector).To(Equal(override.NodeSelector))
+			Expect(merged.Tolerations).To(Equal(override.Tolerations))
+			Expect(*merged.PriorityClassName).To(Equal(priorityClass))
+		})
+
+		table.DescribeTable("should reject an override that isn't allowed", func(override *v2vv1.ConversionPodSpec) {
+			allowList := ctrlConfig.ConversionPodAllowList{
+				Images:              []string{"allowed-image"},
+				ServiceAccountNames: []string{"allowed-account"},
+				NodeSelectorLabels:  map[string][]string{"zone": {"a"}},
+				Tolerations:         []v1.Toleration{{Key: "conversion", Operator: v1.TolerationOpExists}},
+				PriorityClassNames:  []string{"allowed-priority"},
+			}
+
+			_, err := MergeConversionPodSettings(config, override, allowList)
+
+			Expect(err).To(HaveOccurred())
+		},
+			table.Entry("image", &v2vv1.ConversionPodSpec{Image: &otherImage}),
+			table.Entry("service account", &v2vv1.ConversionPodSpec{ServiceAccountName: &otherServiceAccount}),
+			table.Entry("node selector", &v2vv1.ConversionPodSpec{NodeSelector: map[string]string{"zone": "b"}}),
+			table.Entry("node selector value of the config", &v2vv1.ConversionPodSpec{NodeSelector: map[string]string{"config": "false"}}),
+			table.Entry("toleration", &v2vv1.ConversionPodSpec{Tolerations: []v1.Toleration{{Operator: v1.TolerationOpExists}}}),
+			table.Entry("priority class", &v2vv1.ConversionPodSpec{PriorityClassName: &otherPriorityClass}),
+		)
 	})
 
 	Describe("MakeLibvirtDomain", func() {
@@ -194,11 +453,127 @@ var _ = Describe("GuestConversion", func() {
 			domain := MakeLibvirtDomain(vmSpec, dataVolumes)
 			Expect(len(domain.Devices.Disks)).To(Equal(3))
 			Expect(domain.Devices.Disks[0].Source.File.File).To(Equal("/mnt/disks/disk0/disk.img"))
-			Expect(domain.Devices.Disks[0].Target.Dev).To(Equal("hda"))
+			Expect(domain.Devices.Disks[0].Target.Dev).To(Equal("vda"))
 			Expect(domain.Devices.Disks[1].Source.File.File).To(Equal("/mnt/disks/disk1/disk.img"))
-			Expect(domain.Devices.Disks[1].Target.Dev).To(Equal("hdb"))
+			Expect(domain.Devices.Disks[1].Target.Dev).To(Equal("vdb"))
 			Expect(domain.Devices.Disks[2].Source.Block.Dev).To(Equal("/dev/block2"))
-			Expect(domain.Devices.Disks[2].Target.Dev).To(Equal("hdc"))
+			Expect(domain.Devices.Disks[2].Target.Dev).To(Equal("vdc"))
+		})
+
+		It("should skip the volumes that aren't imported", func() {
+			vmSpec.Spec.Template.Spec.Volumes = append(vmSpec.Spec.Template.Spec.Volumes, kubevirtv1.Volume{
+				Name: "cloudinitdisk",
+				VolumeSource: kubevirtv1.VolumeSource{
+					CloudInitNoCloud: &kubevirtv1.CloudInitNoCloudSource{UserData: "#cloud-config"},
+				},
+			})
+
+			domain := MakeLibvirtDomain(vmSpec, dataVolumes)
+			Expect(len(domain.Devices.Disks)).To(Equal(3))
+		})
+
+		It("should use the buses of the disks of the VM", func() {
+			vmSpec.Spec.Template.Spec.Domain.Devices.Disks = []kubevirtv1.Disk{
+				{Name: "dv-1", DiskDevice: kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{Bus: "sata"}}},
+				{Name: "dv-2", DiskDevice: kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{Bus: "scsi"}}},
+				{Name: "dv-block", DiskDevice: kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{Bus: "virtio"}}},
+			}
+
+			domain := MakeLibvirtDomain(vmSpec, dataVolumes)
+
+			Expect(domain.Devices.Disks[0].Target.Bus).To(Equal("sata"))
+			Expect(domain.Devices.Disks[0].Target.Dev).To(Equal("sda"))
+			Expect(domain.Devices.Disks[1].Target.Bus).To(Equal("scsi"))
+			Expect(domain.Devices.Disks[1].Target.Dev).To(Equal("sdb"))
+			Expect(domain.Devices.Disks[2].Target.Bus).To(Equal("virtio"))
+			Expect(domain.Devices.Disks[2].Target.Dev).To(Equal("vda"))
+		})
+
+		It("should boot from the hard disk when the VM has no boot order", func() {
+			domain := MakeLibvirtDomain(vmSpec, dataVolumes)
+
+			Expect(domain.OS.BootDevices).To(ConsistOf(libvirtxml.DomainBootDevice{Dev: "hd"}))
+			for _, disk := range domain.Devices.Disks {
+				Expect(disk.Boot).To(BeNil())
+			}
+		})
+
+		It("should use the boot order of the disks of the VM", func() {
+			bootOrder := uint(1)
+			vmSpec.Spec.Template.Spec.Domain.Devices.Disks = []kubevirtv1.Disk{
+				{Name: "dv-2", BootOrder: &bootOrder, DiskDevice: kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{Bus: "virtio"}}},
+			}
+
+			domain := MakeLibvirtDomain(vmSpec, dataVolumes)
+
+			Expect(domain.OS.BootDevices).To(BeEmpty())
+			Expect(domain.Devices.Disks[0].Boot).To(BeNil())
+			Expect(domain.Devices.Disks[1].Boot.Order).To(Equal(bootOrder))
+		})
+
+		It("should add the CD-ROMs of the VM", func() {
+			vmSpec.Spec.Template.Spec.Volumes = append(vmSpec.Spec.Template.Spec.Volumes, kubevirtv1.Volume{
+				Name: "installer",
+				VolumeSource: kubevirtv1.VolumeSource{
+					ContainerDisk: &kubevirtv1.ContainerDiskSource{Image: "installer:latest"},
+				},
+			})
+			vmSpec.Spec.Template.Spec.Domain.Devices.Disks = []kubevirtv1.Disk{
+				{Name: "dv-1", DiskDevice: kubevirtv1.DiskDevice{Disk: &kubevirtv1.DiskTarget{Bus: "scsi"}}},
+				{Name: "dv-2", DiskDevice: kubevirtv1.DiskDevice{CDRom: &kubevirtv1.CDRomTarget{}}},
+				{Name: "installer", DiskDevice: kubevirtv1.DiskDevice{CDRom: &kubevirtv1.CDRomTarget{Bus: "scsi"}}},
+			}
+
+			domain := MakeLibvirtDomain(vmSpec, dataVolumes)
+
+			Expect(len(domain.Devices.Disks)).To(Equal(4))
+			Expect(domain.Devices.Disks[1].Device).To(Equal("cdrom"))
+			Expect(domain.Devices.Disks[1].Target.Bus).To(Equal("sata"))
+			Expect(domain.Devices.Disks[1].Target.Dev).To(Equal("sdb"))
+			Expect(domain.Devices.Disks[1].Source).To(BeNil())
+			Expect(domain.Devices.Disks[1].ReadOnly).ToNot(BeNil())
+			Expect(domain.Devices.Disks[3].Device).To(Equal("cdrom"))
+			Expect(domain.Devices.Disks[3].Target.Dev).To(Equal("sdc"))
+			Expect(domain.Devices.Disks[3].Source).To(BeNil())
+		})
+
+		It("should boot a BIOS VM without a loader", func() {
+			vmSpec.Spec.Template.Spec.Domain.Firmware = &kubevirtv1.Firmware{
+				Bootloader: &kubevirtv1.Bootloader{BIOS: &kubevirtv1.BIOS{}},
+			}
+
+			domain := MakeLibvirtDomain(vmSpec, dataVolumes)
+
+			Expect(domain.OS.Loader).To(BeNil())
+			Expect(domain.OS.NVRam).To(BeNil())
+		})
+
+		It("should boot an EFI VM with the OVMF loader", func() {
+			secureBoot := false
+			vmSpec.Spec.Template.Spec.Domain.Firmware = &kubevirtv1.Firmware{
+				Bootloader: &kubevirtv1.Bootloader{EFI: &kubevirtv1.EFI{SecureBoot: &secureBoot}},
+			}
+
+			domain := MakeLibvirtDomain(vmSpec, dataVolumes)
+
+			Expect(domain.OS.Loader.Path).To(Equal("/usr/share/OVMF/OVMF_CODE.fd"))
+			Expect(domain.OS.Loader.Type).To(Equal("pflash"))
+			Expect(domain.OS.Loader.Secure).To(BeEmpty())
+			Expect(domain.OS.NVRam.Template).To(Equal("/usr/share/OVMF/OVMF_VARS.fd"))
+			Expect(domain.Features).To(BeNil())
+		})
+
+		It("should boot an EFI VM with secure boot by default", func() {
+			vmSpec.Spec.Template.Spec.Domain.Firmware = &kubevirtv1.Firmware{
+				Bootloader: &kubevirtv1.Bootloader{EFI: &kubevirtv1.EFI{}},
+			}
+
+			domain := MakeLibvirtDomain(vmSpec, dataVolumes)
+
+			Expect(domain.OS.Loader.Path).To(Equal("/usr/share/OVMF/OVMF_CODE.secboot.fd"))
+			Expect(domain.OS.Loader.Secure).To(Equal("yes"))
+			Expect(domain.OS.NVRam.Template).To(Equal("/usr/share/OVMF/OVMF_VARS.secboot.fd"))
+			Expect(domain.Features.SMM.State).To(Equal("on"))
 		})
 	})
 })
@@ -1,8 +1,13 @@
 package metrics
 
 import (
+	"sync"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
@@ -33,18 +38,123 @@ var (
 		},
 		[]string{"result"},
 	)
+	// Gauge of the imports in progress, counted when the metrics are scraped
+	importsInProgressDesc = prometheus.NewDesc(
+		"kubevirt_vmimport_in_progress",
+		"Number of virtual machine imports in progress",
+		[]string{"phase", "provider"},
+		nil,
+	)
+	// counter vector which count the bytes copied to the target disks
+	copiedBytesCounterVec = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubevirt_vmimport_copied_bytes_total",
+			Help: "Count of bytes copied from the source disks",
+		},
+		[]string{"provider", "storage_class"},
+	)
+	// Histogram for duration of the import phases
+	phaseDurationVec = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kubevirt_vmimport_phase_duration_seconds",
+			Help:    "Statistics of virtual machine import phase duration",
+			Buckets: []float64{10, 60, 5 * 60, 15 * 60, 60 * 60, 4 * 60 * 60},
+		},
+		[]string{"phase"},
+	)
+	// counter vector which count the validation failures of source VMs
+	validationFailureCounterVec = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubevirt_vmimport_validation_failures_total",
+			Help: "Count of source virtual machine validation failures",
+		},
+		[]string{"reason"},
+	)
+	// counter vector which count the warm import stages done
+	warmStageCounterVec = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubevirt_vmimport_warm_stages_total",
+			Help: "Count of warm import stages done",
+		},
+		[]string{"result"},
+	)
+	// Histogram for duration of the warm import stages
+	warmStageDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "kubevirt_vmimport_warm_stage_duration_seconds",
+			Help:    "Statistics of warm import stage duration",
+			Buckets: []float64{60, 5 * 60, 15 * 60, 60 * 60},
+		},
+	)
+	// Histogram for duration of the source provider API requests
+	providerRequestDurationVec = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kubevirt_vmimport_provider_request_duration_seconds",
+			Help:    "Statistics of source provider API request duration",
+			Buckets: []float64{0.1, 0.5, 1, 5, 10, 30},
+		},
+		[]string{"provider", "operation"},
+	)
+	// counter vector which count the failed source provider API requests
+	providerRequestErrorCounterVec = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubevirt_vmimport_provider_request_errors_total",
+			Help: "Count of failed source provider API requests",
+		},
+		[]string{"provider", "operation"},
+	)
+
+	inProgress = &inProgressCollector{}
+
+	countedValidationFailures = &validationFailureSet{failures: map[validationFailure]bool{}}
+
 	// ImportMetrics wrapper for all import metrics
-	ImportMetrics = importMetrics{importCounterVec, importDurationVec}
+	ImportMetrics = importMetrics{
+		importCounterVec:            importCounterVec,
+		importDurationVec:           importDurationVec,
+		inProgress:                  inProgress,
+		copiedBytesCounterVec:       copiedBytesCounterVec,
+		phaseDurationVec:            phaseDurationVec,
+		validationFailureCounterVec: validationFailureCounterVec,
+		countedValidationFailures:   countedValidationFailures,
+		warmStageCounterVec:         warmStageCounterVec,
+		warmStageDuration:           warmStageDuration,
+	}
+	// ProviderMetrics wrapper for all source provider metrics
+	ProviderMetrics = providerMetrics{
+		requestDurationVec:     providerRequestDurationVec,
+		requestErrorCounterVec: providerRequestErrorCounterVec,
+	}
+
+	log = logf.Log.WithName("metrics")
 )
 
 func init() {
-	metrics.Registry.MustRegister(importCounterVec, importDurationVec)
+	metrics.Registry.MustRegister(
+		importCounterVec,
+		importDurationVec,
+		inProgress,
+		copiedBytesCounterVec,
+		phaseDurationVec,
+		validationFailureCounterVec,
+		warmStageCounterVec,
+		warmStageDuration,
+		providerRequestDurationVec,
+		providerRequestErrorCounterVec,
+	)
 }
 
 // importMetrics holds all metrics
 type importMetrics struct {
-	importCounterVec  *prometheus.CounterVec
-	importDurationVec *prometheus.HistogramVec
+	importCounterVec            *prometheus.CounterVec
+	importDurationVec           *prometheus.HistogramVec
+	inProgress                  *inProgressCollector
+	copiedBytesCounterVec       *prometheus.CounterVec
+	phaseDurationVec            *prometheus.HistogramVec
+	validationFailureCounterVec *prometheus.CounterVec
+	countedValidationFailures   *validationFailureSet
+	warmStageCounterVec         *prometheus.CounterVec
+	warmStageDuration           prometheus.Histogram
 }
 
 // Return current value of counter. If error is not nil then value is undefined
@@ -119,3 +229,203 @@ func (ic *importMetrics) GetCountDurationFailed() (uint64, error) {
 func (ic *importMetrics) GetCountDurationCancelled() (uint64, error) {
 	return ic.getCountDurationSamples(prometheus.Labels{"result": "cancelled"})
 }
+
+// SetInProgressCounter sets the function counting the imports in progress when the metrics are scraped
+func (ic *importMetrics) SetInProgressCounter(counter InProgressCounter) {
+	ic.inProgress.setCounter(counter)
+}
+
+// AddCopiedBytes adds bytes copied from a source provider to a storage class
+func (ic *importMetrics) AddCopiedBytes(provider string, storageClass string, bytes int64) {
+	if bytes <= 0 {
+		return
+	}
+	ic.copiedBytesCounterVec.With(prometheus.Labels{"provider": provider, "storage_class": storageClass}).Add(float64(bytes))
+}
+
+// GetCopiedBytes returns the bytes copied from a source provider to a storage class
+func (ic *importMetrics) GetCopiedBytes(provider string, storageClass string) (float64, error) {
+	var m = &dto.Metric{}
+	err := ic.copiedBytesCounterVec.With(prometheus.Labels{"provider": provider, "storage_class": storageClass}).Write(m)
+	return m.Counter.GetValue(), err
+}
+
+// SavePhaseDuration saves how long an import spent in the phase
+func (ic *importMetrics) SavePhaseDuration(phase string, d float64) {
+	ic.phaseDurationVec.With(prometheus.Labels{"phase": phase}).Observe(d)
+}
+
+// GetCountPhaseDuration returns number of duration samples for the phase
+func (ic *importMetrics) GetCountPhaseDuration(phase string) (uint64, error) {
+	var m = &dto.Metric{}
+	err := ic.phaseDurationVec.With(prometheus.Labels{"phase": phase}).(prometheus.Histogram).Write(m)
+	return m.Histogram.GetSampleCount(), err
+}
+
+// IncValidationFailure increment the validation failures with the reason, once per import: an import is validated again
+// on every reconcile until it passes the validation, and failing again for the same reason is not a new failure
+func (ic *importMetrics) IncValidationFailure(importUID types.UID, reason string) {
+	if ic.countedValidationFailures.add(validationFailure{importUID: importUID, reason: reason}) {
+		ic.validationFailureCounterVec.With(prometheus.Labels{"reason": reason}).Inc()
+	}
+}
+
+// ForgetValidationFailures forgets the validation failures counted for the import, once it is done or deleted
+func (ic *importMetrics) ForgetValidationFailures(importUID types.UID) {
+	ic.countedValidationFailures.remove(importUID)
+}
+
+// GetValidationFailures returns the validation failures with the reason
+func (ic *importMetrics) GetValidationFailures(reason string) (float64, error) {
+	var m = &dto.Metric{}
+	err := ic.validationFailureCounterVec.With(prometheus.Labels{"reason": reason}).Write(m)
+	return m.Counter.GetValue(), err
+}
+
+// IncWarmStageSuccessful increment successful warm import stages
+func (ic *importMetrics) IncWarmStageSuccessful() {
+	ic.warmStageCounterVec.With(prometheus.Labels{"result": "successful"}).Inc()
+}
+
+// GetWarmStageSuccessful returns the successful warm import stages
+func (ic *importMetrics) GetWarmStageSuccessful() (float64, error) {
+	var m = &dto.Metric{}
+	err := ic.warmStageCounterVec.With(prometheus.Labels{"result": "successful"}).Write(m)
+	return m.Counter.GetValue(), err
+}
+
+// IncWarmStageFailed increment failed warm import stages
+func (ic *importMetrics) IncWarmStageFailed() {
+	ic.warmStageCounterVec.With(prometheus.Labels{"result": "failed"}).Inc()
+}
+
+// GetWarmStageFailed returns the failed warm import stages
+func (ic *importMetrics) GetWarmStageFailed() (float64, error) {
+	var m = &dto.Metric{}
+	err := ic.warmStageCounterVec.With(prometheus.Labels{"result": "failed"}).Write(m)
+	return m.Counter.GetValue(), err
+}
+
+// SaveWarmStageDuration saves how long a warm import stage took
+func (ic *importMetrics) SaveWarmStageDuration(d float64) {
+	ic.warmStageDuration.Observe(d)
+}
+
+// GetCountWarmStageDuration returns number of duration samples for warm import stages
+func (ic *importMetrics) GetCountWarmStageDuration() (uint64, error) {
+	var m = &dto.Metric{}
+	err := ic.warmStageDuration.Write(m)
+	return m.Histogram.GetSampleCount(), err
+}
+
+// providerMetrics holds the source provider metrics
+type providerMetrics struct {
+	requestDurationVec     *prometheus.HistogramVec
+	requestErrorCounterVec *prometheus.CounterVec
+}
+
+// ObserveRequest saves the duration of a source provider API request started at start, and counts it as failed when
+// err isn't nil. It's meant to be deferred at the beginning of the request.
+func (pm *providerMetrics) ObserveRequest(provider string, operation string, start time.Time, err error) {
+	labels := prometheus.Labels{"provider": provider, "operation": operation}
+	pm.requestDurationVec.With(labels).Observe(time.Since(start).Seconds())
+	if err != nil {
+		pm.requestErrorCounterVec.With(labels).Inc()
+	}
+}
+
+// GetCountRequestDuration returns number of duration samples for the source provider API requests
+func (pm *providerMetrics) GetCountRequestDuration(provider string, operation string) (uint64, error) {
+	var m = &dto.Metric{}
+	err := pm.requestDurationVec.With(prometheus.Labels{"provider": provider, "operation": operation}).(prometheus.Histogram).Write(m)
+	return m.Histogram.GetSampleCount(), err
+}
+
+// GetRequestErrors returns the failed source provider API requests
+func (pm *providerMetrics) GetRequestErrors(provider string, operation string) (float64, error) {
+	var m = &dto.Metric{}
+	err := pm.requestErrorCounterVec.With(prometheus.Labels{"provider": provider, "operation": operation}).Write(m)
+	return m.Counter.GetValue(), err
+}
+
+// ImportsInProgress identifies the imports in progress counted together
+type ImportsInProgress struct {
+	Phase    string
+	Provider string
+}
+
+// InProgressCounter counts the imports in progress by phase and provider
+type InProgressCounter func() (map[ImportsInProgress]int, error)
+
+// validationFailure is the reason an import failed the validation
+type validationFailure struct {
+	importUID types.UID
+	reason    string
+}
+
+// validationFailureSet holds the validation failures already counted. It is kept in memory only: like the counter, it
+// starts again from scratch when the operator is restarted.
+type validationFailureSet struct {
+	lock     sync.Mutex
+	failures map[validationFailure]bool
+}
+
+// add adds the failure to the set and returns whether it was not in it yet
+func (s *validationFailureSet) add(failure validationFailure) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.failures[failure] {
+		return false
+	}
+	s.failures[failure] = true
+	return true
+}
+
+// remove removes the failures of the import from the set
+func (s *validationFailureSet) remove(importUID types.UID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for failure := range s.failures {
+		if failure.importUID == importUID {
+			delete(s.failures, failure)
+		}
+	}
+}
+
+// inProgressCollector reports the imports in progress. They are counted when the metrics are scraped, so that the
+// gauge is right even after the operator is restarted.
+type inProgressCollector struct {
+	lock    sync.Mutex
+	counter InProgressCounter
+}
+
+func (c *inProgressCollector) setCounter(counter InProgressCounter) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.counter = counter
+}
+
+// Describe implements prometheus.Collector
+func (c *inProgressCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- importsInProgressDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *inProgressCollector) Collect(ch chan<- prometheus.Metric) {
+	c.lock.Lock()
+	counter := c.counter
+	c.lock.Unlock()
+	if counter == nil {
+		return
+	}
+
+	counts, err := counter()
+	if err != nil {
+		// failing the whole scrape would hide the other metrics
+		log.Error(err, "Failed to count the imports in progress")
+		return
+	}
+	for imports, count := range counts {
+		ch <- prometheus.MustNewConstMetric(importsInProgressDesc, prometheus.GaugeValue, float64(count), imports.Phase, imports.Provider)
+	}
+}
@@ -0,0 +1,96 @@
+package metrics_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kubevirt/vm-import-operator/pkg/metrics"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("Imports in progress", func() {
+	AfterEach(func() {
+		metrics.ImportMetrics.SetInProgressCounter(nil)
+	})
+
+	It("should report the imports counted at scrape time: ", func() {
+		metrics.ImportMetrics.SetInProgressCounter(func() (map[metrics.ImportsInProgress]int, error) {
+			return map[metrics.ImportsInProgress]int{
+				{Phase: "CopyingDisks", Provider: "ovirt"}: 2,
+				{Phase: "Converting", Provider: "vmware"}:  1,
+			}, nil
+		})
+
+		family := gather("kubevirt_vmimport_in_progress")
+
+		Expect(family).ToNot(BeNil())
+		Expect(family.Metric).To(HaveLen(2))
+		Expect(gaugeValue(family, "CopyingDisks", "ovirt")).To(Equal(float64(2)))
+		Expect(gaugeValue(family, "Converting", "vmware")).To(Equal(float64(1)))
+	})
+
+	It("should not fail the scrape when the imports can't be counted: ", func() {
+		metrics.ImportMetrics.SetInProgressCounter(func() (map[metrics.ImportsInProgress]int, error) {
+			return nil, errors.New("cache not synced")
+		})
+
+		Expect(gather("kubevirt_vmimport_in_progress")).To(BeNil())
+	})
+})
+
+var _ = Describe("Provider requests", func() {
+	It("should save the duration of the requests and count the failed ones: ", func() {
+		before, err := metrics.ProviderMetrics.GetRequestErrors("ovirt", "GetVM")
+		Expect(err).To(BeNil())
+		beforeCount, err := metrics.ProviderMetrics.GetCountRequestDuration("ovirt", "GetVM")
+		Expect(err).To(BeNil())
+
+		metrics.ProviderMetrics.ObserveRequest("ovirt", "GetVM", time.Now(), nil)
+		metrics.ProviderMetrics.ObserveRequest("ovirt", "GetVM", time.Now(), errors.New("timeout"))
+
+		after, err := metrics.ProviderMetrics.GetRequestErrors("ovirt", "GetVM")
+		Expect(err).To(BeNil())
+		Expect(after).To(Equal(before + 1))
+		afterCount, err := metrics.ProviderMetrics.GetCountRequestDuration("ovirt", "GetVM")
+		Expect(err).To(BeNil())
+		Expect(afterCount).To(Equal(beforeCount + 2))
+	})
+})
+
+var _ = Describe("Copied bytes", func() {
+	It("should ignore the copies going back: ", func() {
+		metrics.ImportMetrics.AddCopiedBytes("vmware", "fast", 100)
+		metrics.ImportMetrics.AddCopiedBytes("vmware", "fast", -50)
+
+		copied, err := metrics.ImportMetrics.GetCopiedBytes("vmware", "fast")
+		Expect(err).To(BeNil())
+		Expect(copied).To(Equal(float64(100)))
+	})
+})
+
+func gather(name string) *dto.MetricFamily {
+	families, err := crmetrics.Registry.Gather()
+	Expect(err).To(BeNil())
+	for _, family := range families {
+		if family.GetName() == name {
+			return family
+		}
+	}
+	return nil
+}
+
+func gaugeValue(family *dto.MetricFamily, phase string, provider string) float64 {
+	for _, metric := range family.Metric {
+		labels := make(map[string]string)
+		for _, label := range metric.Label {
+			labels[label.GetName()] = label.GetValue()
+		}
+		if labels["phase"] == phase && labels["provider"] == provider {
+			return metric.Gauge.GetValue()
+		}
+	}
+	return -1
+}
@@ -10,6 +10,7 @@ import (
 
 	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -46,6 +47,7 @@ func (r *ReconcileVMImportConfig) GetDependantResourcesListObjects() []runtime.O
 		&rbacv1.ClusterRoleList{},
 		&appsv1.DeploymentList{},
 		&corev1.ServiceAccountList{},
+		&admissionregistrationv1.ValidatingWebhookConfigurationList{},
 	}
 }
 
@@ -57,24 +59,31 @@ func (r *ReconcileVMImportConfig) IsCreating(cr controllerutil.Object) (bool, er
 
 func (r *ReconcileVMImportConfig) getAllResources(cr *v2vv1.VMImportConfig) ([]runtime.Object, error) {
 	var resultingResources []runtime.Object
+	args := r.getOperatorArgs(cr)
+	caBundle, err := r.webhookCABundle()
+	if err != nil {
+		return nil, err
+	}
 
 	if deployClusterResources() {
-		rs := createCRDResources()
+		rs := createCRDResources(args.Namespace, caBundle)
 		resultingResources = append(resultingResources, rs...)
 	}
 
-	nsrs := createControllerResources(r.getOperatorArgs(cr))
+	nsrs := createControllerResources(args, caBundle)
 	resultingResources = append(resultingResources, nsrs...)
 
 	return resultingResources, nil
 }
 
-func createControllerResources(args *OperatorArgs) []runtime.Object {
+func createControllerResources(args *OperatorArgs, caBundle []byte) []runtime.Object {
 	objs := []runtime.Object{
 		resources.CreateServiceAccount(args.Namespace),
 		resources.CreateControllerRole(),
 		resources.CreateControllerRoleBinding(args.Namespace),
 		resources.CreateControllerDeployment(resources.ControllerName, args.Namespace, args.ControllerImage, args.Virtv2vImage, args.PullPolicy, int32(1), args.InfraNodePlacement),
+		resources.CreateWebhookService(args.Namespace),
+		resources.CreateValidatingWebhookConfiguration(args.Namespace, caBundle),
 	}
 	// Add metrics objects if servicemonitor is available:
 	if ok, err := hasServiceMonitor(); ok && err == nil {
@@ -102,10 +111,11 @@ func hasServiceMonitor() (bool, error) {
 	return k8sutil.ResourceExists(dc, apiVersion, kind)
 }
 
-func createCRDResources() []runtime.Object {
+func createCRDResources(namespace string, caBundle []byte) []runtime.Object {
 	return []runtime.Object{
-		resources.CreateResourceMapping(),
-		resources.CreateVMImport(),
+		resources.WithConversionWebhook(resources.CreateResourceMapping(), namespace, caBundle),
+		resources.WithConversionWebhook(resources.CreateVMImport(), namespace, caBundle),
+		resources.CreateProvider(),
 	}
 }
 
@@ -2,8 +2,10 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	ctrlConfig "github.com/kubevirt/vm-import-operator/pkg/config/controller"
+	"github.com/kubevirt/vm-import-operator/pkg/webhook"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -36,7 +38,62 @@ func (r *ReconcileVMImportConfig) updateControllerConfig(cr controllerutil.Objec
 	return nil
 }
 
+// updateWebhookCertificate provisions the serving certificate of the webhooks of the controller once, so that it
+// survives the restarts of the controller, and renews it before it expires
+func (r *ReconcileVMImportConfig) updateWebhookCertificate(cr controllerutil.Object) error {
+	secret := &corev1.Secret{}
+	secretID := client.ObjectKey{Namespace: r.namespace, Name: webhook.CertificateSecretName}
+	err := r.client.Get(context.TODO(), secretID, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+	if exists && !webhook.NeedsNewCertificate(secret, time.Now()) {
+		return nil
+	}
+
+	certPEM, keyPEM, err := webhook.GenerateCertificate(r.namespace)
+	if err != nil {
+		return err
+	}
+	secret.Type = corev1.SecretTypeTLS
+	secret.Data = map[string][]byte{
+		corev1.TLSCertKey:       certPEM,
+		corev1.TLSPrivateKeyKey: keyPEM,
+	}
+	if exists {
+		log.Info("Renewing the serving certificate of the webhooks", "Secret", secretID.Name)
+		return r.client.Update(context.TODO(), secret)
+	}
+	secret.Name = secretID.Name
+	secret.Namespace = secretID.Namespace
+	if err = controllerutil.SetControllerReference(cr, secret, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Create(context.TODO(), secret)
+}
+
+// webhookCABundle returns the CA bundle of the serving certificate of the webhooks, nil until it is provisioned
+func (r *ReconcileVMImportConfig) webhookCABundle() ([]byte, error) {
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), client.ObjectKey{Namespace: r.namespace, Name: webhook.CertificateSecretName}, secret)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return webhook.CABundleOf(secret), nil
+}
+
+func (r *ReconcileVMImportConfig) updateControllerConfiguration(cr controllerutil.Object) error {
+	if err := r.updateControllerConfig(cr); err != nil {
+		return err
+	}
+	return r.updateWebhookCertificate(cr)
+}
+
 func (r *ReconcileVMImportConfig) registerHooks() {
 	r.reconciler.
-		WithControllerConfigUpdater(r.updateControllerConfig)
+		WithControllerConfigUpdater(r.updateControllerConfiguration)
 }
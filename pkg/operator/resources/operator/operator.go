@@ -15,8 +15,10 @@ import (
 	"github.com/coreos/go-semver/semver"
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	vmimportmetrics "github.com/kubevirt/vm-import-operator/pkg/metrics"
+	"github.com/kubevirt/vm-import-operator/pkg/webhook"
 	csvv1 "github.com/operator-framework/operator-lifecycle-manager/pkg/api/apis/operators/v1alpha1"
 	"github.com/operator-framework/operator-sdk/pkg/metrics"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
@@ -87,6 +89,7 @@ func getControllerPolicyRules() []rbacv1.PolicyRule {
 			},
 			Resources: []string{
 				"pods",
+				"pods/log",
 				"services",
 				"services/finalizers",
 				"endpoints",
@@ -175,12 +178,25 @@ func getControllerPolicyRules() []rbacv1.PolicyRule {
 				"*",
 			},
 		},
+		{
+			APIGroups: []string{
+				"cdi.kubevirt.io",
+			},
+			Resources: []string{
+				"datavolumes/source",
+			},
+			Verbs: []string{
+				"create",
+			},
+		},
 		{
 			APIGroups: []string{
 				"cdi.kubevirt.io",
 			},
 			Resources: []string{
 				"cdiconfigs",
+				"storageprofiles",
+				"datasources",
 			},
 			Verbs: []string{
 				"get",
@@ -261,6 +277,30 @@ func getControllerPolicyRules() []rbacv1.PolicyRule {
 				"use",
 			},
 		},
+		{
+			APIGroups: []string{
+				"apiextensions.k8s.io",
+			},
+			Resources: []string{
+				"customresourcedefinitions",
+			},
+			ResourceNames: webhook.ConvertedCRDs,
+			Verbs: []string{
+				"get",
+			},
+		},
+		{
+			APIGroups: []string{
+				"apiextensions.k8s.io",
+			},
+			Resources: []string{
+				"customresourcedefinitions/status",
+			},
+			ResourceNames: webhook.ConvertedCRDs,
+			Verbs: []string{
+				"update",
+			},
+		},
 	}
 	return rules
 }
@@ -273,6 +313,7 @@ func getOperatorPolicyRules() []rbacv1.PolicyRule {
 			},
 			Resources: []string{
 				"pods",
+				"pods/log",
 				"events",
 				"configmaps",
 				"secrets",
@@ -332,6 +373,17 @@ func getOperatorPolicyRules() []rbacv1.PolicyRule {
 				"*",
 			},
 		},
+		{
+			APIGroups: []string{
+				"admissionregistration.k8s.io",
+			},
+			Resources: []string{
+				"validatingwebhookconfigurations",
+			},
+			Verbs: []string{
+				"*",
+			},
+		},
 		{
 			APIGroups: []string{
 				"monitoring.coreos.com",
@@ -352,15 +404,30 @@ func CreateControllerDeployment(name, namespace, image, virtV2vImage, pullPolicy
 	podSpec := corev1.PodSpec{
 		ServiceAccountName: ControllerName,
 		Containers:         createControllerContainers(image, virtV2vImage, pullPolicy),
+		Volumes: []corev1.Volume{
+			{
+				Name: "webhook-cert",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: webhook.CertificateSecretName,
+					},
+				},
+			},
+		},
 	}
 	selectorMatchMap := resourceBuilder.WithOperatorLabels(map[string]string{"v2v.kubevirt.io": ControllerName})
 	return resources.CreateDeployment(name, namespace, selectorMatchMap, selectorMatchMap, numReplicas, podSpec, ControllerName, policy)
 }
 
 func createControllerContainers(image, virtV2vImage, pullPolicy string) []v1.Container {
-	container := resourceBuilder.CreateContainer(ControllerName, image, pullPolicy)
+	container := resourceBuilder.CreatePortsContainer(ControllerName, image, pullPolicy, []corev1.ContainerPort{
+		{Name: "webhook", ContainerPort: webhook.Port, Protocol: corev1.ProtocolTCP},
+	})
 	container.Env = createControllerEnv(virtV2vImage, pullPolicy)
 	container.Command = []string{ControllerName}
+	container.VolumeMounts = []corev1.VolumeMount{
+		{Name: "webhook-cert", MountPath: webhook.CertDir, ReadOnly: true},
+	}
 	return []corev1.Container{*container}
 }
 
@@ -1760,7 +1827,9 @@ func CreateVMImportConfig() *extv1.CustomResourceDefinition {
 // CreateVMImport creates the VM Import CRD
 func CreateVMImport() *extv1.CustomResourceDefinition {
 	maxTargetVMName := int64(validation.LabelValueMaxLength)
-	return &extv1.CustomResourceDefinition{
+	minSearchLength := int64(1)
+	minDisks := int64(1)
+	return withPVCSourceVMSchema(&extv1.CustomResourceDefinition{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "apiextensions.k8s.io/v1",
 			Kind:       "CustomResourceDefinition",
@@ -2089,6 +2158,28 @@ func CreateVMImport() *extv1.CustomResourceDefinition {
 					Subresources: &extv1.CustomResourceSubresources{
 						Status: &extv1.CustomResourceSubresourceStatus{},
 					},
+					AdditionalPrinterColumns: []extv1.CustomResourceColumnDefinition{
+						{
+							Name:     "Phase",
+							Type:     "string",
+							JSONPath: ".status.phase",
+						},
+						{
+							Name:     "Progress",
+							Type:     "integer",
+							JSONPath: ".status.progress",
+						},
+						{
+							Name:     "Target VM",
+							Type:     "string",
+							JSONPath: ".status.targetVmName",
+						},
+						{
+							Name:     "Age",
+							Type:     "date",
+							JSONPath: ".metadata.creationTimestamp",
+						},
+					},
 					Schema: &extv1.CustomResourceValidation{
 						OpenAPIV3Schema: &extv1.JSONSchemaProps{
 							Type: "object",
@@ -2127,6 +2218,21 @@ func CreateVMImport() *extv1.CustomResourceDefinition {
 											},
 											Required: []string{"name"},
 										},
+										"provider": {
+											Type:        "object",
+											Description: "Provider identifies the Provider resource of the source provider",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"name": {
+													Description: "Name of the Provider to be used for the virtual machine import",
+													Type:        "string",
+												},
+												"namespace": {
+													Description: "Namespace of the Provider to be used for the virtual machine import",
+													Type:        "string",
+												},
+											},
+											Required: []string{"name"},
+										},
 										"resourceMapping": {
 											Type:        "object",
 											Description: "ObjectIdentifier defines how a resource should be identified",
@@ -2151,6 +2257,10 @@ func CreateVMImport() *extv1.CustomResourceDefinition {
 											Format:      "date-time",
 											Description: "Indicates when to stop incrementally copying and finalize a warm import.",
 										},
+										"snapshot": {
+											Type:        "boolean",
+											Description: "Indicates whether the disks are imported from a snapshot of the source VM instead of stopping it.",
+										},
 										"source": {
 											Type:        "object",
 											Description: "VirtualMachineImportSourceSpec defines the source provider and the internal mapping resources",
@@ -2249,6 +2359,20 @@ func CreateVMImport() *extv1.CustomResourceDefinition {
 																				"accessMode": {
 																					Type: "string",
 																				},
+																				"preallocation": {
+																					Type: "string",
+																					Enum: []extv1.JSON{
+																						{
+																							Raw: []byte(`"sparse"`),
+																						},
+																						{
+																							Raw: []byte(`"preallocated"`),
+																						},
+																						{
+																							Raw: []byte(`"inheritFromSource"`),
+																						},
+																					},
+																				},
 																			},
 																			Required: []string{"source"},
 																		},
@@ -2298,11 +2422,80 @@ func CreateVMImport() *extv1.CustomResourceDefinition {
 																				"accessMode": {
 																					Type: "string",
 																				},
+																				"preallocation": {
+																					Type: "string",
+																					Enum: []extv1.JSON{
+																						{
+																							Raw: []byte(`"sparse"`),
+																						},
+																						{
+																							Raw: []byte(`"preallocated"`),
+																						},
+																						{
+																							Raw: []byte(`"inheritFromSource"`),
+																						},
+																					},
+																				},
 																			},
 																			Required: []string{"source"},
 																		},
 																	},
 																},
+																"cdromMappings": {
+																	Type: "array",
+																	Description: `CdromMappings defines the mapping of the ISO images inserted in the CD-ROMs of the VM
+		CdromMappings.Source.ID represents the ID of the ISO image on ovirt, i.e. the ID of its disk in a data domain or its file name in an ISO domain
+		CdromMappings.Source.Name represents the name of the ISO image on ovirt`,
+																	Items: &extv1.JSONSchemaPropsOrArray{
+																		Schema: &extv1.JSONSchemaProps{
+																			Type:        "object",
+																			Description: `CdromResourceMappingItem defines the mapping of the ISO image of a CD-ROM from the provider to kubevirt`,
+																			Properties: map[string]extv1.JSONSchemaProps{
+																				"source": {
+																					Description: `Source defines how to identify a resource on the provider, either by ID or by name`,
+																					Type:        "object",
+																					Properties: map[string]extv1.JSONSchemaProps{
+																						"id": {
+																							Type: "string",
+																						},
+																						"name": {
+																							Type: "string",
+																						},
+																					},
+																				},
+																				"target": {
+																					Description: `Target is the storage class the ISO image is imported to, or the PVC or DataSource holding the ISO image`,
+																					Type:        "object",
+																					Properties: map[string]extv1.JSONSchemaProps{
+																						"name": {
+																							Type: "string",
+																						},
+																						"namespace": {
+																							Type: "string",
+																						},
+																					},
+																					Required: []string{"name"},
+																				},
+																				"type": {
+																					Description: `Type defines what the target is, the ISO image is imported to a data volume of the target storage class when it isn't set`,
+																					Type:        "string",
+																					Enum: []extv1.JSON{
+																						{
+																							Raw: []byte(`"import"`),
+																						},
+																						{
+																							Raw: []byte(`"persistentVolumeClaim"`),
+																						},
+																						{
+																							Raw: []byte(`"dataSource"`),
+																						},
+																					},
+																				},
+																			},
+																			Required: []string{"source", "target"},
+																		},
+																	},
+																},
 															},
 														},
 														"vm": {
@@ -2333,8 +2526,38 @@ func CreateVMImport() *extv1.CustomResourceDefinition {
 																},
 															},
 														},
+														"vmSelector": {
+															Type:        "object",
+															Description: `VirtualMachineImportOvirtSourceVMSelectorSpec defines how to select several VMs in oVirt, one VirtualMachineImport is created for each of them`,
+															Properties: map[string]extv1.JSONSchemaProps{
+																"search": {
+																	Description: `oVirt search query selecting the VMs, e.g. "tag=wave3 and cluster=prod"`,
+																	Type:        "string",
+																	MinLength:   &minSearchLength,
+																},
+																"targetVmNamePattern": {
+																	Description: `Pattern of the target VM names, in which {name}, {id} and {cluster} are replaced with the source VM's values`,
+																	Type:        "string",
+																},
+															},
+															Required: []string{"search"},
+														},
+														"guestConversion": {
+															Description: `Defines whether the guest is converted with virt-v2v after its disks are imported, Auto converts it when it was imported to oVirt from VMware or Xen, or when it's a Windows guest without virtio drivers`,
+															Type:        "string",
+															Enum: []extv1.JSON{
+																{
+																	Raw: []byte(`"Auto"`),
+																},
+																{
+																	Raw: []byte(`"Always"`),
+																},
+																{
+																	Raw: []byte(`"Never"`),
+																},
+															},
+														},
 													},
-													Required: []string{"vm"},
 												},
 												"vmware": {
 													Type:        "object",
@@ -2429,6 +2652,20 @@ NetworkMappings.Source.ID represents the macAddress field of the network adapter
 																				"accessMode": {
 																					Type: "string",
 																				},
+																				"preallocation": {
+																					Type: "string",
+																					Enum: []extv1.JSON{
+																						{
+																							Raw: []byte(`"sparse"`),
+																						},
+																						{
+																							Raw: []byte(`"preallocated"`),
+																						},
+																						{
+																							Raw: []byte(`"inheritFromSource"`),
+																						},
+																					},
+																				},
 																			},
 																			Required: []string{"source"},
 																		},
@@ -2478,11 +2715,79 @@ DiskMappings.Source.ID represents the DiskObjectId or vDiskID of the VirtualDisk
 																				"accessMode": {
 																					Type: "string",
 																				},
+																				"preallocation": {
+																					Type: "string",
+																					Enum: []extv1.JSON{
+																						{
+																							Raw: []byte(`"sparse"`),
+																						},
+																						{
+																							Raw: []byte(`"preallocated"`),
+																						},
+																						{
+																							Raw: []byte(`"inheritFromSource"`),
+																						},
+																					},
+																				},
 																			},
 																			Required: []string{"source"},
 																		},
 																	},
 																},
+																"cdromMappings": {
+																	Type: "array",
+																	Description: `CdromMappings defines the mapping of the ISO images inserted in the CD-ROMs of the VM
+		CdromMappings.Source.Name represents the datastore path of the ISO image, e.g. "[datastore1] iso/image.iso", or the name of the CD-ROM in vCenter`,
+																	Items: &extv1.JSONSchemaPropsOrArray{
+																		Schema: &extv1.JSONSchemaProps{
+																			Type:        "object",
+																			Description: `CdromResourceMappingItem defines the mapping of the ISO image of a CD-ROM from the provider to kubevirt`,
+																			Properties: map[string]extv1.JSONSchemaProps{
+																				"source": {
+																					Description: `Source defines how to identify a resource on the provider, either by ID or by name`,
+																					Type:        "object",
+																					Properties: map[string]extv1.JSONSchemaProps{
+																						"id": {
+																							Type: "string",
+																						},
+																						"name": {
+																							Type: "string",
+																						},
+																					},
+																				},
+																				"target": {
+																					Description: `Target is the storage class the ISO image is imported to, or the PVC or DataSource holding the ISO image`,
+																					Type:        "object",
+																					Properties: map[string]extv1.JSONSchemaProps{
+																						"name": {
+																							Type: "string",
+																						},
+																						"namespace": {
+																							Type: "string",
+																						},
+																					},
+																					Required: []string{"name"},
+																				},
+																				"type": {
+																					Description: `Type defines what the target is, the ISO image is imported to a data volume of the target storage class when it isn't set`,
+																					Type:        "string",
+																					Enum: []extv1.JSON{
+																						{
+																							Raw: []byte(`"import"`),
+																						},
+																						{
+																							Raw: []byte(`"persistentVolumeClaim"`),
+																						},
+																						{
+																							Raw: []byte(`"dataSource"`),
+																						},
+																					},
+																				},
+																			},
+																			Required: []string{"source", "target"},
+																		},
+																	},
+																},
 															},
 														},
 														"vm": {
@@ -2490,29 +2795,340 @@ DiskMappings.Source.ID represents the DiskObjectId or vDiskID of the VirtualDisk
 															Description: `VirtualMachineImportVmwareSourceVMSpec defines how to identify the VM in vCenter`,
 															Properties: map[string]extv1.JSONSchemaProps{
 																"id": {
-																	Type: "string",
+																	Type:        "string",
+																	Description: `UUID of virtual machine`,
+																},
+																"uuidType": {
+																	Type:        "string",
+																	Description: `Type of the UUID used to identify the virtual machine, either bios (default) or instance`,
+																	Enum: []extv1.JSON{
+																		{
+																			Raw: []byte(`"bios"`),
+																		},
+																		{
+																			Raw: []byte(`"instance"`),
+																		},
+																	},
+																},
+																"moRef": {
+																	Type:        "string",
+																	Description: `Managed object reference of the virtual machine, e.g. vm-42`,
 																},
 																"name": {
 																	Type: "string",
 																},
+																"datacenter": {
+																	Type:        "string",
+																	Description: `Name of the datacenter the VM lookup is restricted to`,
+																},
+																"cluster": {
+																	Type:        "string",
+																	Description: `Name or inventory path of the cluster the VM lookup is restricted to`,
+																},
+																"folder": {
+																	Type:        "string",
+																	Description: `Name or inventory path of the VM folder the VM lookup is restricted to`,
+																},
+																"resourcePool": {
+																	Type:        "string",
+																	Description: `Name or inventory path of the resource pool the VM lookup is restricted to`,
+																},
 															},
 														},
 													},
 													Required: []string{"vm"},
 												},
+												"pvc": {
+													Type:        "object",
+													Description: `VirtualMachineImportPVCSourceSpec defines the persistent volume claims holding the disks of the VM and how the rest of the VM is described, either by its hardware or by the source VM the disks come from`,
+													Properties: map[string]extv1.JSONSchemaProps{
+														"disks": {
+															Type:        "array",
+															Description: `Disks are the persistent volume claims holding the disks of the VM, in the namespace of the import. The VM boots from the first one.`,
+															MinItems:    &minDisks,
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:        "object",
+																	Description: `PVCDisk defines a persistent volume claim holding a disk of the VM`,
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"claimName": {
+																			Type:        "string",
+																			Description: `ClaimName is the name of the persistent volume claim`,
+																		},
+																		"bus": {
+																			Type:        "string",
+																			Description: `Bus of the disk in the VM, virtio by default`,
+																			Enum: []extv1.JSON{
+																				{
+																					Raw: []byte(`"virtio"`),
+																				},
+																				{
+																					Raw: []byte(`"sata"`),
+																				},
+																				{
+																					Raw: []byte(`"scsi"`),
+																				},
+																			},
+																		},
+																	},
+																	Required: []string{"claimName"},
+																},
+															},
+														},
+														"hardware": {
+															Type:        "object",
+															Description: `VirtualMachineHardwareSpec describes the hardware of a VM`,
+															Properties: map[string]extv1.JSONSchemaProps{
+																"sockets": {
+																	Type:        "integer",
+																	Format:      "int32",
+																	Description: `Number of CPU sockets, 1 by default`,
+																},
+																"cores": {
+																	Type:        "integer",
+																	Format:      "int32",
+																	Description: `Number of cores per CPU socket, 1 by default`,
+																},
+																"memory": {
+																	Description:  `Memory of the VM`,
+																	XIntOrString: true,
+																},
+																"firmware": {
+																	Type:        "string",
+																	Description: `Firmware the guest boots with, bios by default`,
+																	Enum: []extv1.JSON{
+																		{
+																			Raw: []byte(`"bios"`),
+																		},
+																		{
+																			Raw: []byte(`"efi"`),
+																		},
+																	},
+																},
+																"secureBoot": {
+																	Type:        "boolean",
+																	Description: `SecureBoot enables secure boot for an efi firmware`,
+																},
+																"operatingSystem": {
+																	Type:        "string",
+																	Description: `OperatingSystem of the guest as named by the common templates, e.g. rhel8 or win2k19. The VM is created from the template of the operating system.`,
+																},
+															},
+															Required: []string{"memory"},
+														},
+														"sourceVM": {
+															Type:        "object",
+															Description: `PVCSourceVMSpec identifies the source VM of a PVC source in one of the source providers, like the ovirt and vmware sources`,
+															// the ovirt and vmware properties are the ones of the source, see withPVCSourceVMSchema
+															Properties: map[string]extv1.JSONSchemaProps{},
+														},
+													},
+													Required: []string{"disks"},
+												},
 											},
 										},
-										"startVm": {
-											Type:        "boolean",
-											Description: `If true imported virtual machine will be started`,
+										"sourceShutdown": {
+											Type:        "object",
+											Description: `SourceShutdownSpec defines how the source VM is stopped before its disks are imported`,
+											Properties: map[string]extv1.JSONSchemaProps{
+												"timeout": {
+													Type:        "string",
+													Description: `Timeout of the shut down, 5 minutes by default`,
+												},
+												"force": {
+													Type:        "boolean",
+													Description: `Force powers the source VM off when it isn't shut down before the timeout`,
+												},
+												"method": {
+													Type:        "string",
+													Description: `Method used to shut the source VM down, guest by default`,
+													Enum: []extv1.JSON{
+														{
+															Raw: []byte(`"guest"`),
+														},
+														{
+															Raw: []byte(`"acpi"`),
+														},
+														{
+															Raw: []byte(`"hard"`),
+														},
+													},
+												},
+											},
 										},
-										"targetVmName": {
-											Description: `Specifies the name of the imported virtual machine`,
+										"disks": {
+											Type:        "object",
+											Description: `Disks selects the disks of the source VM that are imported, all of them by default`,
+											Properties: map[string]extv1.JSONSchemaProps{
+												"include": {
+													Type: "array",
+													Description: `Include imports only the disks matching one of the rules, all the disks when it is empty`,
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:        "object",
+															Description: `DiskSelectorRule matches the disks of the source VM matching all its fields that are set`,
+															Properties: map[string]extv1.JSONSchemaProps{
+																"id": {
+																	Type:        "string",
+																	Description: `ID of the disk, the disk ID on oVirt or the vDiskID or DiskObjectId of the VirtualDisk on vSphere`,
+																},
+																"name": {
+																	Type:        "string",
+																	Description: `Name of the disk, the name or alias of the disk on oVirt or the label of the VirtualDisk on vSphere`,
+																},
+																"bootable": {
+																	Type:        "boolean",
+																	Description: `Bootable matches the disks the VM boots from when true, and the others when false`,
+																},
+																"minSize": {
+																	Description:  `MinSize matches the disks whose provisioned size is at least the quantity`,
+																	XIntOrString: true,
+																},
+																"maxSize": {
+																	Description:  `MaxSize matches the disks whose provisioned size is at most the quantity`,
+																	XIntOrString: true,
+																},
+															},
+														},
+													},
+												},
+												"exclude": {
+													Type: "array",
+													Description: `Exclude doesn't import the disks matching one of the rules, even when they are included`,
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:        "object",
+															Description: `DiskSelectorRule matches the disks of the source VM matching all its fields that are set`,
+															Properties: map[string]extv1.JSONSchemaProps{
+																"id": {
+																	Type:        "string",
+																	Description: `ID of the disk, the disk ID on oVirt or the vDiskID or DiskObjectId of the VirtualDisk on vSphere`,
+																},
+																"name": {
+																	Type:        "string",
+																	Description: `Name of the disk, the name or alias of the disk on oVirt or the label of the VirtualDisk on vSphere`,
+																},
+																"bootable": {
+																	Type:        "boolean",
+																	Description: `Bootable matches the disks the VM boots from when true, and the others when false`,
+																},
+																"minSize": {
+																	Description:  `MinSize matches the disks whose provisioned size is at least the quantity`,
+																	XIntOrString: true,
+																},
+																"maxSize": {
+																	Description:  `MaxSize matches the disks whose provisioned size is at most the quantity`,
+																	XIntOrString: true,
+																},
+															},
+														},
+													},
+												},
+											},
+										},
+										"guestCustomization": {
+											Type:        "object",
+											Description: `GuestCustomization references the config map holding the firstboot scripts, the files and the packages the guest is customized with when it is converted`,
+											Properties: map[string]extv1.JSONSchemaProps{
+												"configMap": {
+													Type: "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {
+															Type: "string",
+														},
+													},
+													Required: []string{"name"},
+												},
+											},
+											Required: []string{"configMap"},
+										},
+										"conversionPod": {
+											Type:        "object",
+											Description: `ConversionPod overrides the settings of the virt-v2v guest conversion pod of the controller configuration`,
+											Properties: map[string]extv1.JSONSchemaProps{
+												"image": {
+													Type:        "string",
+													Description: `Image of virt-v2v`,
+												},
+												"nodeSelector": {
+													Type:        "object",
+													Description: `NodeSelector is added to the kubevirt.io/schedulable node selector of the pod`,
+													AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+														Schema: &extv1.JSONSchemaProps{
+															Type: "string",
+														},
+													},
+												},
+												"tolerations": {
+													Type:        "array",
+													Description: `Tolerations of the pod`,
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type: "object",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"effect": {
+																	Type: "string",
+																},
+																"key": {
+																	Type: "string",
+																},
+																"operator": {
+																	Type: "string",
+																},
+																"tolerationSeconds": {
+																	Type:   "integer",
+																	Format: "int64",
+																},
+																"value": {
+																	Type: "string",
+																},
+															},
+														},
+													},
+												},
+												"resources": {
+													Type:        "object",
+													Description: `Resources of the virt-v2v container, the kvm device is always requested`,
+													Properties: map[string]extv1.JSONSchemaProps{
+														"limits": {
+															Type: "object",
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Schema: &extv1.JSONSchemaProps{
+																	XIntOrString: true,
+																},
+															},
+														},
+														"requests": {
+															Type: "object",
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Schema: &extv1.JSONSchemaProps{
+																	XIntOrString: true,
+																},
+															},
+														},
+													},
+												},
+												"priorityClassName": {
+													Type:        "string",
+													Description: `PriorityClassName of the pod`,
+												},
+												"serviceAccountName": {
+													Type:        "string",
+													Description: `ServiceAccountName of the pod`,
+												},
+											},
+										},
+										"startVm": {
+											Type:        "boolean",
+											Description: `If true imported virtual machine will be started`,
+										},
+										"targetVmName": {
+											Description: `Specifies the name of the imported virtual machine`,
 											Type:        "string",
 											MaxLength:   &maxTargetVMName,
 										},
 									},
-									Required: []string{"providerCredentialsSecret", "source"},
+									Required: []string{"source"},
 								},
 								"status": {
 									Type:        "object",
@@ -2567,15 +3183,260 @@ DiskMappings.Source.ID represents the DiskObjectId or vDiskID of the VirtualDisk
 															Description: `Name of the DataVolume that was created by virtual machine import`,
 															Type:        "string",
 														},
+														"sourceDiskID": {
+															Description: "Identifies the disk of the source VM the data volume is imported from.",
+															Type:        "string",
+														},
+														"sizeBytes": {
+															Description: "The size requested for the data volume.",
+															Type:        "integer",
+															Format:      "int64",
+														},
+														"sourceBytes": {
+															Description: "The size of the source disk, which the copy progress is measured against.",
+															Type:        "integer",
+															Format:      "int64",
+														},
+														"accessMode": {
+															Description: "The access mode of the PVC of the data volume.",
+															Type:        "string",
+														},
+														"volumeMode": {
+															Description: "The volume mode of the PVC of the data volume.",
+															Type:        "string",
+														},
+														"preallocated": {
+															Description: "Whether the whole space of the data volume is allocated up front.",
+															Type:        "boolean",
+														},
+														"estimatedBytes": {
+															Description: "The estimate of the bytes of storage the data volume consumes.",
+															Type:        "integer",
+															Format:      "int64",
+														},
+														"phase": {
+															Description: "The phase of the data volume.",
+															Type:        "string",
+														},
+														"progress": {
+															Description: "The percentage of the disk that is copied.",
+															Type:        "integer",
+														},
+														"bytesTransferred": {
+															Description: "The number of bytes of the source disk that are copied.",
+															Type:        "integer",
+															Format:      "int64",
+														},
+														"throughput": {
+															Description: "The average number of bytes copied per second since the copy started.",
+															Type:        "integer",
+															Format:      "int64",
+														},
+														"startedAt": {
+															Description: "The time when the copy of the disk started.",
+															Type:        "string",
+															Format:      "date-time",
+														},
+														"completedAt": {
+															Description: "The time when the copy of the disk completed.",
+															Type:        "string",
+															Format:      "date-time",
+														},
 													},
 													Required: []string{"name"},
 												},
 											},
 										},
+										"estimatedCompletionTime": {
+											Description: "The time when the copy of the disks is expected to complete at their current throughput.",
+											Type:        "string",
+											Format:      "date-time",
+										},
+										"guestInspection": {
+											Description: "The operating system, the drivers and the applications virt-v2v found in the guest.",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"type": {
+													Type: "string",
+												},
+												"distro": {
+													Type: "string",
+												},
+												"productName": {
+													Type: "string",
+												},
+												"majorVersion": {
+													Type: "integer",
+												},
+												"minorVersion": {
+													Type: "integer",
+												},
+												"osinfo": {
+													Description: "The libosinfo short ID of the operating system.",
+													Type:        "string",
+												},
+												"firmware": {
+													Type: "string",
+												},
+												"drivers": {
+													Description: "The virtio drivers installed in the guest.",
+													Type:        "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type: "string",
+														},
+													},
+												},
+												"applications": {
+													Type: "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type: "string",
+														},
+													},
+												},
+											},
+										},
+										"guestConversion": {
+											Description: "Whether the guest is converted, decided once before the target VM is created.",
+											Type:        "boolean",
+										},
+										"guestCustomization": {
+											Description: "The customization applied to the guest.",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"phase": {
+													Type: "string",
+												},
+												"scripts": {
+													Type: "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type: "string",
+														},
+													},
+												},
+												"files": {
+													Type: "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type: "string",
+														},
+													},
+												},
+												"packages": {
+													Type: "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type: "string",
+														},
+													},
+												},
+												"message": {
+													Type: "string",
+												},
+											},
+										},
+										"conversionLog": {
+											Description: "The config map holding the tail of the log of the guest conversion.",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"name": {
+													Type: "string",
+												},
+											},
+										},
+										"phase": {
+											Description: "The stage of the import process the import is in.",
+											Type:        "string",
+											Enum: []extv1.JSON{
+												{Raw: []byte(`"Pending"`)},
+												{Raw: []byte(`"Validating"`)},
+												{Raw: []byte(`"StoppingSource"`)},
+												{Raw: []byte(`"Creating"`)},
+												{Raw: []byte(`"WarmCopying"`)},
+												{Raw: []byte(`"CopyingDisks"`)},
+												{Raw: []byte(`"Converting"`)},
+												{Raw: []byte(`"Starting"`)},
+												{Raw: []byte(`"Succeeded"`)},
+												{Raw: []byte(`"Failed"`)},
+											},
+										},
+										"phaseTransitions": {
+											Description: "Records when the import entered and left each of the phases it went through.",
+											Type:        "array",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Type: "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"phase": {
+															Type: "string",
+														},
+														"startedAt": {
+															Description: "The time when the import entered the phase.",
+															Type:        "string",
+															Format:      "date-time",
+														},
+														"completedAt": {
+															Description: "The time when the import left the phase.",
+															Type:        "string",
+															Format:      "date-time",
+														},
+													},
+													Required: []string{"phase", "startedAt"},
+												},
+											},
+										},
+										"progress": {
+											Description: "The percentage of the import process that is completed.",
+											Type:        "integer",
+										},
+										"sourceSnapshot": {
+											Description: "The ID of the snapshot of the source VM the disks are imported from in a snapshot import.",
+											Type:        "string",
+										},
+										"sourceShutdown": {
+											Description: "Records the shut down of the source VM.",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"requestedAt": {
+													Type:        "string",
+													Format:      "date-time",
+													Description: "The time when the shut down was requested.",
+												},
+												"forcedAt": {
+													Type:        "string",
+													Format:      "date-time",
+													Description: "The time when the source VM was powered off after the shut down timed out.",
+												},
+												"method": {
+													Type:        "string",
+													Description: "The method of the last shut down request.",
+												},
+											},
+										},
 										"targetVmName": {
 											Description: "The name of the virtual machine created by the import process",
 											Type:        "string",
 										},
+										"virtualMachineImports": {
+											Description: `VirtualMachineImports created for the VMs matched by the VM selector`,
+											Type:        "array",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Type: "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {
+															Type: "string",
+														},
+														"namespace": {
+															Type: "string",
+														},
+													},
+													Required: []string{"name"},
+												},
+											},
+										},
 										"warmImport": {
 											Description: "Details about the status of a warm import.",
 											Type:        "object",
@@ -2585,6 +3446,11 @@ DiskMappings.Source.ID represents the DiskObjectId or vDiskID of the VirtualDisk
 													Format:      "date-time",
 													Description: "The time when the next warm import stage is scheduled.",
 												},
+												"stageStartTime": {
+													Type:        "string",
+													Format:      "date-time",
+													Description: "The time when the warm import stage being copied started.",
+												},
 												"successes": {
 													Type:        "integer",
 													Description: "The total number of successful stages.",
@@ -2621,7 +3487,22 @@ DiskMappings.Source.ID represents the DiskObjectId or vDiskID of the VirtualDisk
 				ShortNames: []string{"vmimports"},
 			},
 		},
+	})
+}
+
+// withPVCSourceVMSchema identifies the source VM of the PVC source of the v1beta1 imports like the oVirt and VMware
+// sources do
+func withPVCSourceVMSchema(crd *extv1.CustomResourceDefinition) *extv1.CustomResourceDefinition {
+	for _, version := range crd.Spec.Versions {
+		if version.Name != "v1beta1" {
+			continue
+		}
+		source := version.Schema.OpenAPIV3Schema.Properties["spec"].Properties["source"]
+		sourceVM := source.Properties["pvc"].Properties["sourceVM"]
+		sourceVM.Properties["ovirt"] = source.Properties["ovirt"]
+		sourceVM.Properties["vmware"] = source.Properties["vmware"]
 	}
+	return crd
 }
 
 // CreateResourceMapping creates the ResourceMapping CRD
@@ -2935,20 +3816,97 @@ func CreateResourceMapping() *extv1.CustomResourceDefinition {
 																"accessMode": {
 																	Type: "string",
 																},
+																"preallocation": {
+																	Type: "string",
+																	Enum: []extv1.JSON{
+																		{
+																			Raw: []byte(`"sparse"`),
+																		},
+																		{
+																			Raw: []byte(`"preallocated"`),
+																		},
+																		{
+																			Raw: []byte(`"inheritFromSource"`),
+																		},
+																	},
+																},
+															},
+															Required: []string{"source", "target"},
+														},
+													},
+												},
+												"diskMappings": {
+													Type: "array",
+													Description: `DiskMappings defines the mapping of disks to storage
+		classes DiskMappings.Source.ID represents the disk ID on ovirt (as opposed to disk-attachment ID) DiskMappings.Source.Name represents
+		the disk alias on ovirt DiskMappings is respected only when provided in context of a single VM import within VirtualMachineImport.`,
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:        "object",
+															Description: `NetworkResourceMappingItem defines the mapping of a single disk resource from the provider to kubevirt`,
+															Properties: map[string]extv1.JSONSchemaProps{
+																"source": {
+																	Description: `Source defines how to identify a resource on the provider, either by ID or by name`,
+																	Type:        "object",
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"id": {
+																			Type: "string",
+																		},
+																		"name": {
+																			Type: "string",
+																		},
+																	},
+																},
+																"target": {
+																	Description: `ObjectIdentifier defines how a resource should be identified on kubevirt`,
+																	Type:        "object",
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"name": {
+																			Type: "string",
+																		},
+																		"namespace": {
+																			Type: "string",
+																		},
+																	},
+																	Required: []string{"name"},
+																},
+																"type": {
+																	Type: "string",
+																},
+																"volumeMode": {
+																	Type: "string",
+																},
+																"accessMode": {
+																	Type: "string",
+																},
+																"preallocation": {
+																	Type: "string",
+																	Enum: []extv1.JSON{
+																		{
+																			Raw: []byte(`"sparse"`),
+																		},
+																		{
+																			Raw: []byte(`"preallocated"`),
+																		},
+																		{
+																			Raw: []byte(`"inheritFromSource"`),
+																		},
+																	},
+																},
 															},
 															Required: []string{"source", "target"},
 														},
 													},
 												},
-												"diskMappings": {
+												"cdromMappings": {
 													Type: "array",
-													Description: `DiskMappings defines the mapping of disks to storage
-		classes DiskMappings.Source.ID represents the disk ID on ovirt (as opposed to disk-attachment ID) DiskMappings.Source.Name represents
-		the disk alias on ovirt DiskMappings is respected only when provided in context of a single VM import within VirtualMachineImport.`,
+													Description: `CdromMappings defines the mapping of the ISO images inserted in the CD-ROMs of the VM
+		CdromMappings.Source.ID represents the ID of the ISO image on ovirt, i.e. the ID of its disk in a data domain or its file name in an ISO domain
+		CdromMappings.Source.Name represents the name of the ISO image on ovirt`,
 													Items: &extv1.JSONSchemaPropsOrArray{
 														Schema: &extv1.JSONSchemaProps{
 															Type:        "object",
-															Description: `NetworkResourceMappingItem defines the mapping of a single disk resource from the provider to kubevirt`,
+															Description: `CdromResourceMappingItem defines the mapping of the ISO image of a CD-ROM from the provider to kubevirt`,
 															Properties: map[string]extv1.JSONSchemaProps{
 																"source": {
 																	Description: `Source defines how to identify a resource on the provider, either by ID or by name`,
@@ -2963,7 +3921,7 @@ func CreateResourceMapping() *extv1.CustomResourceDefinition {
 																	},
 																},
 																"target": {
-																	Description: `ObjectIdentifier defines how a resource should be identified on kubevirt`,
+																	Description: `Target is the storage class the ISO image is imported to, or the PVC or DataSource holding the ISO image`,
 																	Type:        "object",
 																	Properties: map[string]extv1.JSONSchemaProps{
 																		"name": {
@@ -2976,13 +3934,19 @@ func CreateResourceMapping() *extv1.CustomResourceDefinition {
 																	Required: []string{"name"},
 																},
 																"type": {
-																	Type: "string",
-																},
-																"volumeMode": {
-																	Type: "string",
-																},
-																"accessMode": {
-																	Type: "string",
+																	Description: `Type defines what the target is, the ISO image is imported to a data volume of the target storage class when it isn't set`,
+																	Type:        "string",
+																	Enum: []extv1.JSON{
+																		{
+																			Raw: []byte(`"import"`),
+																		},
+																		{
+																			Raw: []byte(`"persistentVolumeClaim"`),
+																		},
+																		{
+																			Raw: []byte(`"dataSource"`),
+																		},
+																	},
 																},
 															},
 															Required: []string{"source", "target"},
@@ -3080,6 +4044,20 @@ NetworkMappings.Source.ID represents the macAddress field of the network adapter
 																"accessMode": {
 																	Type: "string",
 																},
+																"preallocation": {
+																	Type: "string",
+																	Enum: []extv1.JSON{
+																		{
+																			Raw: []byte(`"sparse"`),
+																		},
+																		{
+																			Raw: []byte(`"preallocated"`),
+																		},
+																		{
+																			Raw: []byte(`"inheritFromSource"`),
+																		},
+																	},
+																},
 															},
 															Required: []string{"source", "target"},
 														},
@@ -3129,6 +4107,74 @@ DiskMappings.Source.ID represents the DiskObjectId or vDiskID of the VirtualDisk
 																"accessMode": {
 																	Type: "string",
 																},
+																"preallocation": {
+																	Type: "string",
+																	Enum: []extv1.JSON{
+																		{
+																			Raw: []byte(`"sparse"`),
+																		},
+																		{
+																			Raw: []byte(`"preallocated"`),
+																		},
+																		{
+																			Raw: []byte(`"inheritFromSource"`),
+																		},
+																	},
+																},
+															},
+															Required: []string{"source", "target"},
+														},
+													},
+												},
+												"cdromMappings": {
+													Type: "array",
+													Description: `CdromMappings defines the mapping of the ISO images inserted in the CD-ROMs of the VM
+		CdromMappings.Source.Name represents the datastore path of the ISO image, e.g. "[datastore1] iso/image.iso", or the name of the CD-ROM in vCenter`,
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:        "object",
+															Description: `CdromResourceMappingItem defines the mapping of the ISO image of a CD-ROM from the provider to kubevirt`,
+															Properties: map[string]extv1.JSONSchemaProps{
+																"source": {
+																	Description: `Source defines how to identify a resource on the provider, either by ID or by name`,
+																	Type:        "object",
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"id": {
+																			Type: "string",
+																		},
+																		"name": {
+																			Type: "string",
+																		},
+																	},
+																},
+																"target": {
+																	Description: `Target is the storage class the ISO image is imported to, or the PVC or DataSource holding the ISO image`,
+																	Type:        "object",
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"name": {
+																			Type: "string",
+																		},
+																		"namespace": {
+																			Type: "string",
+																		},
+																	},
+																	Required: []string{"name"},
+																},
+																"type": {
+																	Description: `Type defines what the target is, the ISO image is imported to a data volume of the target storage class when it isn't set`,
+																	Type:        "string",
+																	Enum: []extv1.JSON{
+																		{
+																			Raw: []byte(`"import"`),
+																		},
+																		{
+																			Raw: []byte(`"persistentVolumeClaim"`),
+																		},
+																		{
+																			Raw: []byte(`"dataSource"`),
+																		},
+																	},
+																},
 															},
 															Required: []string{"source", "target"},
 														},
@@ -3160,6 +4206,248 @@ DiskMappings.Source.ID represents the DiskObjectId or vDiskID of the VirtualDisk
 	}
 }
 
+// CreateProvider creates the Provider CRD
+func CreateProvider() *extv1.CustomResourceDefinition {
+	objectIdentifier := func(description, resource string) extv1.JSONSchemaProps {
+		return extv1.JSONSchemaProps{
+			Type:        "object",
+			Description: description,
+			Properties: map[string]extv1.JSONSchemaProps{
+				"name": {
+					Description: "Name of the " + resource,
+					Type:        "string",
+				},
+				"namespace": {
+					Description: "Namespace of the " + resource + ", the namespace of the Provider by default",
+					Type:        "string",
+				},
+			},
+			Required: []string{"name"},
+		}
+	}
+	return &extv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apiextensions.k8s.io/v1",
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "providers.v2v.kubevirt.io",
+			Labels: map[string]string{
+				"operator.v2v.kubevirt.io": "",
+			},
+		},
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Group: "v2v.kubevirt.io",
+			Scope: "Namespaced",
+			Versions: []extv1.CustomResourceDefinitionVersion{
+				{
+					Name:    "v1beta1",
+					Served:  true,
+					Storage: true,
+					Subresources: &extv1.CustomResourceSubresources{
+						Status: &extv1.CustomResourceSubresourceStatus{},
+					},
+					AdditionalPrinterColumns: []extv1.CustomResourceColumnDefinition{
+						{
+							Name:     "Type",
+							Type:     "string",
+							JSONPath: ".spec.type",
+						},
+						{
+							Name:     "URL",
+							Type:     "string",
+							JSONPath: ".spec.url",
+						},
+						{
+							Name:     "Ready",
+							Type:     "string",
+							JSONPath: `.status.conditions[?(@.type=="Ready")].status`,
+						},
+						{
+							Name:     "Version",
+							Type:     "string",
+							JSONPath: ".status.version",
+						},
+					},
+					Schema: &extv1.CustomResourceValidation{
+						OpenAPIV3Schema: &extv1.JSONSchemaProps{
+							Description: "Provider is the Schema for the Providers API",
+							Type:        "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								"apiVersion": {
+									Type: "string",
+									Description: `APIVersion defines the versioned schema of this representation
+		of an object. Servers should convert recognized schemas to the latest
+		internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources`,
+								},
+								"kind": {
+									Type: "string",
+									Description: `Kind is a string value representing the REST resource this
+		object represents. Servers may infer this from the endpoint the client
+		submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds`,
+								},
+								"metadata": {
+									Type: "object",
+								},
+								"spec": {
+									Type:        "object",
+									Description: "ProviderSpec defines the desired state of Provider",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"type": {
+											Type:        "string",
+											Description: "Type of the source provider",
+											Enum: []extv1.JSON{
+												{
+													Raw: []byte(`"ovirt"`),
+												},
+												{
+													Raw: []byte(`"vmware"`),
+												},
+											},
+										},
+										"url": {
+											Type:        "string",
+											Description: "URL of the source provider API",
+										},
+										"secretRef": objectIdentifier("SecretRef identifies the secret holding the username and password of the source provider", "secret"),
+										"tls": {
+											Type:        "object",
+											Description: "TLS defines how the certificate of the source provider is verified",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"caCert": {
+													Type:        "string",
+													Description: "CACert holds the PEM encoded CA certificates the certificate of the source provider is verified with",
+												},
+												"caBundleRef": {
+													Type:        "object",
+													Description: "CABundleRef identifies the config map or the secret holding the PEM encoded CA certificates the certificate of the source provider is verified with",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"kind": {
+															Type:        "string",
+															Description: "Kind of the resource holding the CA bundle",
+															Enum: []extv1.JSON{
+																{Raw: []byte(`"ConfigMap"`)},
+																{Raw: []byte(`"Secret"`)},
+															},
+														},
+														"name": {
+															Type:        "string",
+															Description: "Name of the resource holding the CA bundle",
+														},
+														"namespace": {
+															Type:        "string",
+															Description: "Namespace of the resource holding the CA bundle, the namespace of the Provider by default",
+														},
+														"key": {
+															Type:        "string",
+															Description: "Key of the CA bundle in the resource, ca.crt by default",
+														},
+													},
+													Required: []string{"kind", "name"},
+												},
+												"thumbprint": {
+													Type:        "string",
+													Description: "Thumbprint pins the vCenter or ESXi host certificate to its SHA-1 or SHA-256 fingerprint",
+												},
+												"insecureSkipVerify": {
+													Type:        "boolean",
+													Description: "InsecureSkipVerify disables the verification of the certificate of the source provider",
+												},
+											},
+										},
+										"transferNetwork": objectIdentifier("TransferNetwork identifies the network attachment definition the disks are transferred over", "network attachment definition"),
+									},
+									Required: []string{"type", "url", "secretRef"},
+								},
+								"status": {
+									Type:        "object",
+									Description: "ProviderStatus defines the observed state of Provider",
+									Properties: map[string]extv1.JSONSchemaProps{
+										"conditions": {
+											Description: "A list of current conditions of the Provider resource",
+											Type:        "array",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Type: "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"lastHeartbeatTime": {
+															Description: "The last time we got an update on a given condition",
+															Type:        "string",
+															Format:      "date-time",
+														},
+														"lastTransitionTime": {
+															Description: "The last time the condition transit from one status to another",
+															Type:        "string",
+															Format:      "date-time",
+														},
+														"message": {
+															Description: "A human-readable message indicating details about last transition",
+															Type:        "string",
+														},
+														"reason": {
+															Description: "A brief CamelCase string that describes why the source provider is in current condition status",
+															Type:        "string",
+														},
+														"status": {
+															Description: "Status of the condition, one of True, False, Unknown",
+															Type:        "string",
+														},
+														"type": {
+															Description: "Type of source provider condition",
+															Type:        "string",
+														},
+													},
+													Required: []string{"status", "type"},
+												},
+											},
+										},
+										"version": {
+											Description: "Version of the source provider",
+											Type:        "string",
+										},
+										"inventory": {
+											Description: "Inventory counts the resources of the source provider",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"datacenters": {
+													Type: "integer",
+												},
+												"clusters": {
+													Type: "integer",
+												},
+												"hosts": {
+													Type: "integer",
+												},
+												"vms": {
+													Type: "integer",
+												},
+											},
+										},
+										"observedGeneration": {
+											Description: "ObservedGeneration is the generation of the spec the status was computed for",
+											Type:        "integer",
+											Format:      "int64",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Names: extv1.CustomResourceDefinitionNames{
+				Kind:     "Provider",
+				ListKind: "ProviderList",
+				Plural:   "providers",
+				Singular: "provider",
+				Categories: []string{
+					"all",
+				},
+			},
+		},
+	}
+}
+
 func createOperatorDeployment(operatorVersion, namespace, deployClusterResources, operatorImage, controllerImage, virtV2vImage, pullPolicy string) *appsv1.Deployment {
 	deployment := CreateOperatorDeployment(operatorName, namespace, "name", operatorName, serviceAccountName, int32(1))
 	container := CreateContainer(operatorName, operatorImage, pullPolicy)
@@ -3243,6 +4531,89 @@ func CreateMetricsService(namespace string) *v1.Service {
 	return service
 }
 
+// CreateWebhookService creates a Service resource for the validating webhooks of the controller
+func CreateWebhookService(namespace string) *v1.Service {
+	service := resourceBuilder.CreateService(webhook.ServiceName, "v2v.kubevirt.io", ControllerName, nil)
+	service.Spec.Ports = []v1.ServicePort{
+		{Port: 443, Name: "webhook", Protocol: v1.ProtocolTCP, TargetPort: intstr.IntOrString{Type: intstr.Int, IntVal: webhook.Port}},
+	}
+	service.SetNamespace(namespace)
+	return service
+}
+
+// CreateValidatingWebhookConfiguration registers the validating webhooks of the controller, verified with the CA
+// bundle of the serving certificate provisioned by the operator. Creations are rejected while the webhooks can't be
+// reached, but updates are let through: they also remove the finalizers of imports being deleted, which must not be
+// blocked while the controller is down, and the controller still reports an invalid import in its Valid condition.
+func CreateValidatingWebhookConfiguration(namespace string, caBundle []byte) *admissionregistrationv1.ValidatingWebhookConfiguration {
+	sideEffects := admissionregistrationv1.SideEffectClassNone
+	newWebhook := func(name, resource, path string, operation admissionregistrationv1.OperationType, failurePolicy admissionregistrationv1.FailurePolicyType) admissionregistrationv1.ValidatingWebhook {
+		webhookPath := path
+		return admissionregistrationv1.ValidatingWebhook{
+			Name: name,
+			ClientConfig: admissionregistrationv1.WebhookClientConfig{
+				Service: &admissionregistrationv1.ServiceReference{
+					Name:      webhook.ServiceName,
+					Namespace: namespace,
+					Path:      &webhookPath,
+				},
+				CABundle: caBundle,
+			},
+			Rules: []admissionregistrationv1.RuleWithOperations{
+				{
+					Operations: []admissionregistrationv1.OperationType{operation},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{"v2v.kubevirt.io"},
+						APIVersions: []string{"v1beta1"},
+						Resources:   []string{resource},
+					},
+				},
+			},
+			FailurePolicy:           &failurePolicy,
+			SideEffects:             &sideEffects,
+			AdmissionReviewVersions: []string{"v1beta1"},
+		}
+	}
+
+	return &admissionregistrationv1.ValidatingWebhookConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "admissionregistration.k8s.io/v1",
+			Kind:       "ValidatingWebhookConfiguration",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   webhook.ValidatingWebhookConfigurationName,
+			Labels: resourceBuilder.WithCommonLabels(nil),
+		},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{
+			newWebhook("virtualmachineimport-validator.v2v.kubevirt.io", "virtualmachineimports", webhook.VirtualMachineImportPath, admissionregistrationv1.Create, admissionregistrationv1.Fail),
+			newWebhook("virtualmachineimport-update-validator.v2v.kubevirt.io", "virtualmachineimports", webhook.VirtualMachineImportPath, admissionregistrationv1.Update, admissionregistrationv1.Ignore),
+			newWebhook("resourcemapping-validator.v2v.kubevirt.io", "resourcemappings", webhook.ResourceMappingPath, admissionregistrationv1.Create, admissionregistrationv1.Fail),
+			newWebhook("resourcemapping-update-validator.v2v.kubevirt.io", "resourcemappings", webhook.ResourceMappingPath, admissionregistrationv1.Update, admissionregistrationv1.Ignore),
+		},
+	}
+}
+
+// WithConversionWebhook converts the versions of the custom resource definition with the webhook of the controller,
+// verified with the CA bundle of the serving certificate provisioned by the operator
+func WithConversionWebhook(crd *extv1.CustomResourceDefinition, namespace string, caBundle []byte) *extv1.CustomResourceDefinition {
+	path := webhook.ConversionPath
+	crd.Spec.Conversion = &extv1.CustomResourceConversion{
+		Strategy: extv1.WebhookConverter,
+		Webhook: &extv1.WebhookConversion{
+			ClientConfig: &extv1.WebhookClientConfig{
+				Service: &extv1.ServiceReference{
+					Name:      webhook.ServiceName,
+					Namespace: namespace,
+					Path:      &path,
+				},
+				CABundle: caBundle,
+			},
+			ConversionReviewVersions: []string{"v1beta1"},
+		},
+	}
+	return crd
+}
+
 // CreateServiceMonitor create a service monitor for vm-operator metrics
 func CreateServiceMonitor(monitoringNamespace string, svcNamespace string) *monitoringv1.ServiceMonitor {
 	labels := map[string]string{"name": operatorName}
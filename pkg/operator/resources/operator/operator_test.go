@@ -5,6 +5,7 @@ import (
 
 	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
 	vmioperator "github.com/kubevirt/vm-import-operator/pkg/operator/resources/operator"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 
 	"github.com/RHsyseng/operator-utils/pkg/validation"
@@ -32,6 +33,10 @@ var crdTypeMap = map[string]crdToCreator{
 		&v2vv1.ResourceMapping{},
 		vmioperator.CreateResourceMapping,
 	},
+	"provider-crd": {
+		&v2vv1.Provider{},
+		vmioperator.CreateProvider,
+	},
 	"vmimportconfig-crd": {
 		&v2vv1.VMImportConfig{},
 		vmioperator.CreateVMImportConfig,
@@ -44,9 +49,10 @@ var _ = Describe("Operator resource test", func() {
 			schema := getSchema(crdCreatorObj.creator)
 			missingEntries := schema.GetMissingEntries(crdCreatorObj.resource)
 			for _, missing := range missingEntries {
-				if strings.HasPrefix(missing.Path, "/status") || strings.HasPrefix(missing.Path, "/spec/finalizeDate") {
+				if strings.HasPrefix(missing.Path, "/status") || strings.HasPrefix(missing.Path, "/spec/finalizeDate") || strings.HasPrefix(missing.Path, "/spec/sourceShutdown/timeout") || strings.HasPrefix(missing.Path, "/spec/source/pvc/hardware/memory") ||
+					(strings.HasPrefix(missing.Path, "/spec/disks/") && (strings.Contains(missing.Path, "/minSize") || strings.Contains(missing.Path, "/maxSize"))) {
 					// Not using subresources, so status is not expected to appear in CRD.
-					// GetMissingEntries doesn't handle dates properly, so skip the finalizeDate field.
+					// GetMissingEntries doesn't handle dates, durations and quantities properly, so skip the finalizeDate, timeout, memory and disk size fields.
 				} else {
 					msg := "Discrepancy between CRD and Struct Missing or incorrect schema validation at [%v], expected type [%v] in CRD file [%v]"
 					Fail(fmt.Sprintf(msg, missing.Path, missing.Type, crdFileName))
@@ -126,6 +132,52 @@ var _ = Describe("Operator resource test", func() {
 		err = schema.Validate(input)
 		Expect(err).To(HaveOccurred())
 	})
+
+	It("Test webhook configuration", func() {
+		configuration := vmioperator.CreateValidatingWebhookConfiguration("kubevirt-hyperconverged", []byte("bundle"))
+		service := vmioperator.CreateWebhookService("kubevirt-hyperconverged")
+
+		Expect(configuration.Webhooks).To(HaveLen(4))
+		for _, webhook := range configuration.Webhooks {
+			Expect(webhook.ClientConfig.Service.Name).To(Equal(service.Name))
+			Expect(webhook.ClientConfig.Service.Namespace).To(Equal(service.Namespace))
+			Expect(webhook.ClientConfig.CABundle).To(Equal([]byte("bundle")))
+			Expect(webhook.Rules).To(HaveLen(1))
+			Expect(webhook.Rules[0].Operations).To(HaveLen(1))
+			// updates remove the finalizers of the imports, they must not be blocked while the controller is down
+			if webhook.Rules[0].Operations[0] == admissionregistrationv1.Update {
+				Expect(*webhook.FailurePolicy).To(Equal(admissionregistrationv1.Ignore))
+			} else {
+				Expect(*webhook.FailurePolicy).To(Equal(admissionregistrationv1.Fail))
+			}
+		}
+		Expect(service.Spec.Selector).To(HaveKeyWithValue("v2v.kubevirt.io", vmioperator.ControllerName))
+	})
+
+	It("Test controller role", func() {
+		role := vmioperator.CreateControllerRole()
+
+		crdRules := 0
+		for _, rule := range role.Rules {
+			if len(rule.APIGroups) == 1 && rule.APIGroups[0] == "apiextensions.k8s.io" {
+				crdRules++
+				Expect(rule.ResourceNames).To(ConsistOf("virtualmachineimports.v2v.kubevirt.io", "resourcemappings.v2v.kubevirt.io"))
+				Expect(rule.Verbs).ToNot(ContainElement("list"))
+			}
+		}
+		Expect(crdRules).To(Equal(2))
+	})
+
+	It("Test conversion webhook", func() {
+		crd := vmioperator.WithConversionWebhook(vmioperator.CreateVMImport(), "kubevirt-hyperconverged", []byte("bundle"))
+		service := vmioperator.CreateWebhookService("kubevirt-hyperconverged")
+
+		Expect(crd.Spec.Conversion.Strategy).To(Equal(extv1.WebhookConverter))
+		Expect(crd.Spec.Conversion.Webhook.ClientConfig.Service.Name).To(Equal(service.Name))
+		Expect(crd.Spec.Conversion.Webhook.ClientConfig.Service.Namespace).To(Equal(service.Namespace))
+		Expect(crd.Spec.Conversion.Webhook.ClientConfig.CABundle).To(Equal([]byte("bundle")))
+		Expect(crd.Spec.Versions).To(HaveLen(2))
+	})
 })
 
 func getSchema(crdCreator createCrd) validation.Schema {
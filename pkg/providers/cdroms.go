@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+)
+
+// cdromBus is the bus of the CD-ROMs of the imported VMs, which every guest can boot from
+const cdromBus = "sata"
+
+// CdromMappingTypeOf returns the type of the CD-ROM mapping, importing the ISO image when the type isn't set
+func CdromMappingTypeOf(mapping *v2vv1.CdromResourceMappingItem) v2vv1.CdromMappingType {
+	if mapping == nil || mapping.Type == nil {
+		return v2vv1.ImportCdromMapping
+	}
+	return *mapping.Type
+}
+
+// CdromClaimName returns the name of the persistent volume claim backing the CD-ROM directly, i.e. the PVC the mapping
+// targets when it is in the namespace of the import. A PVC of another namespace is cloned.
+func CdromClaimName(mapping *v2vv1.CdromResourceMappingItem, namespace string) (string, bool) {
+	if CdromMappingTypeOf(mapping) != v2vv1.PersistentVolumeClaimCdromMapping {
+		return "", false
+	}
+	if sourceNamespace := cdromSourceNamespace(mapping, namespace); sourceNamespace != namespace {
+		return "", false
+	}
+	return mapping.Target.Name, true
+}
+
+// CdromCloneDataVolume returns the data volume cloning the ISO image of a CD-ROM mapped to a DataSource or to a PVC
+// of another namespace. The data volume is sized after the cloned PVC when it is created, and the controller only
+// clones the sources of the namespace of the import.
+func CdromCloneDataVolume(name string, namespace string, mapping *v2vv1.CdromResourceMappingItem) (cdiv1.DataVolume, bool) {
+	if CdromMappingTypeOf(mapping) == v2vv1.ImportCdromMapping {
+		return cdiv1.DataVolume{}, false
+	}
+	sourceNamespace := cdromSourceNamespace(mapping, namespace)
+	dv := cdiv1.DataVolume{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: cdiv1.SchemeGroupVersion.String(),
+			Kind:       "DataVolume",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: cdiv1.DataVolumeSpec{
+			PVC: &corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{
+					corev1.ReadWriteOnce,
+				},
+			},
+		},
+	}
+	switch CdromMappingTypeOf(mapping) {
+	case v2vv1.DataSourceCdromMapping:
+		dv.Annotations = map[string]string{
+			utils.AnnDataSource: fmt.Sprintf("%s/%s", sourceNamespace, mapping.Target.Name),
+		}
+	case v2vv1.PersistentVolumeClaimCdromMapping:
+		if _, direct := CdromClaimName(mapping, namespace); direct {
+			return dv, false
+		}
+		dv.Spec.Source.PVC = &cdiv1.DataVolumeSourcePVC{
+			Namespace: sourceNamespace,
+			Name:      mapping.Target.Name,
+		}
+	default:
+		return dv, false
+	}
+	return dv, true
+}
+
+func cdromSourceNamespace(mapping *v2vv1.CdromResourceMappingItem, namespace string) string {
+	if mapping.Target.Namespace != nil && *mapping.Target.Namespace != "" {
+		return *mapping.Target.Namespace
+	}
+	return namespace
+}
+
+// AddCdrom attaches the volume to the VM as a read-only CD-ROM
+func AddCdrom(vmSpec *kubevirtv1.VirtualMachine, name string, source kubevirtv1.VolumeSource) {
+	readOnly := true
+	volume := kubevirtv1.Volume{
+		Name:         name,
+		VolumeSource: source,
+	}
+	disk := kubevirtv1.Disk{
+		Name: name,
+		DiskDevice: kubevirtv1.DiskDevice{
+			CDRom: &kubevirtv1.CDRomTarget{
+				Bus:      cdromBus,
+				ReadOnly: &readOnly,
+			},
+		},
+	}
+	vmSpec.Spec.Template.Spec.Volumes = append(vmSpec.Spec.Template.Spec.Volumes, volume)
+	vmSpec.Spec.Template.Spec.Domain.Devices.Disks = append(vmSpec.Spec.Template.Spec.Domain.Devices.Disks, disk)
+}
+
+// WithUnmappedCdromsWarning adds the warning about the ISO images of the CD-ROMs that aren't imported to the Valid
+// condition of the validation results, unless the validation failed
+func WithUnmappedCdromsWarning(validationResults []v2vv1.VirtualMachineImportCondition, unmappedCdroms []string) []v2vv1.VirtualMachineImportCondition {
+	if len(unmappedCdroms) == 0 {
+		return validationResults
+	}
+	warning := fmt.Sprintf("ISO images that can't be imported, the CD-ROMs are left out unless they are mapped to a PVC or a DataSource: %s", strings.Join(unmappedCdroms, ", "))
+	return WithValidationWarning(validationResults, warning)
+}
@@ -0,0 +1,87 @@
+package provider_test
+
+import (
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/conditions"
+	provider "github.com/kubevirt/vm-import-operator/pkg/providers"
+	"github.com/kubevirt/vm-import-operator/pkg/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+)
+
+var _ = Describe("CD-ROMs", func() {
+	cdromMapping := func(mappingType v2vv1.CdromMappingType, name string, namespace *string) *v2vv1.CdromResourceMappingItem {
+		return &v2vv1.CdromResourceMappingItem{
+			Source: v2vv1.Source{Name: stringPtr("fedora.iso")},
+			Target: v2vv1.ObjectIdentifier{Name: name, Namespace: namespace},
+			Type:   &mappingType,
+		}
+	}
+
+	It("should import the ISO image by default", func() {
+		Expect(provider.CdromMappingTypeOf(nil)).To(Equal(v2vv1.ImportCdromMapping))
+		Expect(provider.CdromMappingTypeOf(&v2vv1.CdromResourceMappingItem{})).To(Equal(v2vv1.ImportCdromMapping))
+	})
+
+	It("should attach a PVC of the namespace of the import directly", func() {
+		mapping := cdromMapping(v2vv1.PersistentVolumeClaimCdromMapping, "fedora-iso", stringPtr("vms"))
+
+		claimName, found := provider.CdromClaimName(mapping, "vms")
+		_, clone := provider.CdromCloneDataVolume("dv", "vms", mapping)
+
+		Expect(found).To(BeTrue())
+		Expect(claimName).To(Equal("fedora-iso"))
+		Expect(clone).To(BeFalse())
+	})
+
+	It("should clone a PVC of another namespace", func() {
+		mapping := cdromMapping(v2vv1.PersistentVolumeClaimCdromMapping, "fedora-iso", stringPtr("images"))
+
+		_, found := provider.CdromClaimName(mapping, "vms")
+		dv, clone := provider.CdromCloneDataVolume("dv", "vms", mapping)
+
+		Expect(found).To(BeFalse())
+		Expect(clone).To(BeTrue())
+		Expect(dv.Namespace).To(Equal("vms"))
+		Expect(dv.Spec.Source.PVC).To(Equal(&cdiv1.DataVolumeSourcePVC{Namespace: "images", Name: "fedora-iso"}))
+	})
+
+	It("should clone a DataSource of the namespace of the import", func() {
+		mapping := cdromMapping(v2vv1.DataSourceCdromMapping, "fedora", nil)
+
+		dv, clone := provider.CdromCloneDataVolume("dv", "vms", mapping)
+
+		Expect(clone).To(BeTrue())
+		Expect(dv.Annotations[utils.AnnDataSource]).To(Equal("vms/fedora"))
+	})
+
+	It("should attach a read-only CD-ROM", func() {
+		vmSpec := &kubevirtv1.VirtualMachine{Spec: kubevirtv1.VirtualMachineSpec{Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{}}}
+
+		provider.AddCdrom(vmSpec, "cdrom-1", kubevirtv1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "fedora-iso"},
+		})
+
+		Expect(vmSpec.Spec.Template.Spec.Volumes).To(HaveLen(1))
+		Expect(vmSpec.Spec.Template.Spec.Volumes[0].Name).To(Equal("cdrom-1"))
+		disks := vmSpec.Spec.Template.Spec.Domain.Devices.Disks
+		Expect(disks).To(HaveLen(1))
+		Expect(disks[0].Name).To(Equal("cdrom-1"))
+		Expect(disks[0].CDRom.Bus).To(Equal("sata"))
+		Expect(*disks[0].CDRom.ReadOnly).To(BeTrue())
+	})
+
+	It("should warn about the ISO images that aren't imported", func() {
+		validationResults := []v2vv1.VirtualMachineImportCondition{
+			conditions.NewCondition(v2vv1.Valid, string(v2vv1.ValidationCompleted), "Validation completed successfully", corev1.ConditionTrue),
+		}
+
+		validationResults = provider.WithUnmappedCdromsWarning(validationResults, []string{"fedora.iso"})
+
+		Expect(*validationResults[0].Reason).To(Equal(string(v2vv1.ValidationReportedWarnings)))
+		Expect(*validationResults[0].Message).To(ContainSubstring("fedora.iso"))
+	})
+})
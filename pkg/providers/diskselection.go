@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/conditions"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// SourceDisk describes a disk of the source VM for the disk selection of the import
+type SourceDisk struct {
+	ID string
+	// Names of the disk, e.g. both its name and alias
+	Names     []string
+	Bootable  bool
+	SizeBytes int64
+}
+
+// DiskSelected tells whether the disk is imported according to the disk selection, a nil one selecting all the disks
+func DiskSelected(selection *v2vv1.DiskSelectionSpec, disk SourceDisk) bool {
+	if selection == nil {
+		return true
+	}
+	if len(selection.Include) > 0 && !matchesAnyRule(selection.Include, disk) {
+		return false
+	}
+	return !matchesAnyRule(selection.Exclude, disk)
+}
+
+func matchesAnyRule(rules []v2vv1.DiskSelectorRule, disk SourceDisk) bool {
+	for _, rule := range rules {
+		if matchesRule(rule, disk) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesRule tells whether the disk matches all the fields of the rule that are set
+func matchesRule(rule v2vv1.DiskSelectorRule, disk SourceDisk) bool {
+	if rule.ID != nil && *rule.ID != disk.ID {
+		return false
+	}
+	if rule.Name != nil && !hasName(disk, *rule.Name) {
+		return false
+	}
+	if rule.Bootable != nil && *rule.Bootable != disk.Bootable {
+		return false
+	}
+	if rule.MinSize != nil && disk.SizeBytes < rule.MinSize.Value() {
+		return false
+	}
+	if rule.MaxSize != nil && disk.SizeBytes > rule.MaxSize.Value() {
+		return false
+	}
+	return true
+}
+
+func hasName(disk SourceDisk, name string) bool {
+	for _, diskName := range disk.Names {
+		if diskName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// WithExcludedDisksWarning adds the warning about the disks excluded from the import to its Valid condition, unless
+// the validation failed
+func WithExcludedDisksWarning(validationResults []v2vv1.VirtualMachineImportCondition, excludedDisks []string) []v2vv1.VirtualMachineImportCondition {
+	if len(excludedDisks) == 0 {
+		return validationResults
+	}
+	return WithValidationWarning(validationResults, fmt.Sprintf("Disks excluded from the import: %s", strings.Join(excludedDisks, ", ")))
+}
+
+// WithValidationWarning adds the warning to the Valid condition of the validation results, unless the validation failed
+func WithValidationWarning(validationResults []v2vv1.VirtualMachineImportCondition, warning string) []v2vv1.VirtualMachineImportCondition {
+	for i, condition := range validationResults {
+		if condition.Type != v2vv1.Valid || condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		message := warning
+		if condition.Reason != nil && *condition.Reason == string(v2vv1.ValidationReportedWarnings) && condition.Message != nil {
+			message = *condition.Message + "; " + warning
+		}
+		validationResults[i] = conditions.NewCondition(v2vv1.Valid, string(v2vv1.ValidationReportedWarnings), message, corev1.ConditionTrue)
+	}
+	return validationResults
+}
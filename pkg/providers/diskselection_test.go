@@ -0,0 +1,89 @@
+package provider_test
+
+import (
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/conditions"
+	provider "github.com/kubevirt/vm-import-operator/pkg/providers"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var _ = Describe("Disk selection", func() {
+	var (
+		bootDisk = provider.SourceDisk{ID: "disk-1", Names: []string{"root", "vm_Disk1"}, Bootable: true, SizeBytes: 10 * 1024 * 1024 * 1024}
+		swapDisk = provider.SourceDisk{ID: "disk-2", Names: []string{"swap"}, SizeBytes: 2 * 1024 * 1024 * 1024}
+		dataDisk = provider.SourceDisk{ID: "disk-3", Names: []string{"backup"}, SizeBytes: 500 * 1024 * 1024 * 1024}
+	)
+
+	table.DescribeTable("should select the disks", func(selection *v2vv1.DiskSelectionSpec, expected []string) {
+		var selected []string
+		for _, disk := range []provider.SourceDisk{bootDisk, swapDisk, dataDisk} {
+			if provider.DiskSelected(selection, disk) {
+				selected = append(selected, disk.ID)
+			}
+		}
+
+		Expect(selected).To(Equal(expected))
+	},
+		table.Entry("all without a selection", nil, []string{"disk-1", "disk-2", "disk-3"}),
+		table.Entry("excluded by id", &v2vv1.DiskSelectionSpec{
+			Exclude: []v2vv1.DiskSelectorRule{{ID: stringPtr("disk-2")}},
+		}, []string{"disk-1", "disk-3"}),
+		table.Entry("excluded by alias", &v2vv1.DiskSelectionSpec{
+			Exclude: []v2vv1.DiskSelectorRule{{Name: stringPtr("vm_Disk1")}},
+		}, []string{"disk-2", "disk-3"}),
+		table.Entry("excluded by size", &v2vv1.DiskSelectionSpec{
+			Exclude: []v2vv1.DiskSelectorRule{{MinSize: quantityPtr("100Gi")}},
+		}, []string{"disk-1", "disk-2"}),
+		table.Entry("excluded unless bootable", &v2vv1.DiskSelectionSpec{
+			Exclude: []v2vv1.DiskSelectorRule{{Bootable: boolPtr(false), MaxSize: quantityPtr("4Gi")}},
+		}, []string{"disk-1", "disk-3"}),
+		table.Entry("included by bootable flag", &v2vv1.DiskSelectionSpec{
+			Include: []v2vv1.DiskSelectorRule{{Bootable: boolPtr(true)}},
+		}, []string{"disk-1"}),
+		table.Entry("included but excluded", &v2vv1.DiskSelectionSpec{
+			Include: []v2vv1.DiskSelectorRule{{Name: stringPtr("root")}, {Name: stringPtr("swap")}},
+			Exclude: []v2vv1.DiskSelectorRule{{ID: stringPtr("disk-2")}},
+		}, []string{"disk-1"}),
+	)
+
+	It("should warn about the excluded disks", func() {
+		validationResults := []v2vv1.VirtualMachineImportCondition{
+			conditions.NewCondition(v2vv1.Valid, string(v2vv1.ValidationReportedWarnings), "VM has a sound card", corev1.ConditionTrue),
+			conditions.NewCondition(v2vv1.MappingRulesVerified, string(v2vv1.MappingRulesVerificationCompleted), "All mapping rules checks passed", corev1.ConditionTrue),
+		}
+
+		validationResults = provider.WithExcludedDisksWarning(validationResults, []string{"swap", "backup"})
+
+		Expect(*validationResults[0].Reason).To(Equal(string(v2vv1.ValidationReportedWarnings)))
+		Expect(*validationResults[0].Message).To(Equal("VM has a sound card; Disks excluded from the import: swap, backup"))
+		Expect(*validationResults[1].Message).To(Equal("All mapping rules checks passed"))
+	})
+
+	It("should keep the failed validation", func() {
+		validationResults := []v2vv1.VirtualMachineImportCondition{
+			conditions.NewCondition(v2vv1.Valid, string(v2vv1.ValidationFailed), "VM is running", corev1.ConditionFalse),
+		}
+
+		validationResults = provider.WithExcludedDisksWarning(validationResults, []string{"swap"})
+
+		Expect(*validationResults[0].Reason).To(Equal(string(v2vv1.ValidationFailed)))
+		Expect(*validationResults[0].Message).To(Equal("VM is running"))
+	})
+})
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+func quantityPtr(value string) *resource.Quantity {
+	quantity := resource.MustParse(value)
+	return &quantity
+}
@@ -0,0 +1,60 @@
+package ovirtclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	engineCAPath    = "/ovirt-engine/services/pki-resource"
+	engineCAQuery   = "resource=ca-certificate&format=X509-PEM-CA"
+	engineCATimeout = 30 * time.Second
+)
+
+// GetEngineCA downloads the CA certificate of the engine, which signs the certificates of the imageio daemons the
+// disks are transferred from. It is verified the same way as the engine API.
+func (client *richOvirtClient) GetEngineCA() (string, error) {
+	return fetchEngineCA(client.settings)
+}
+
+func fetchEngineCA(cs *ConnectionSettings) (string, error) {
+	apiURL, err := url.Parse(cs.URL)
+	if err != nil {
+		return "", err
+	}
+	caURL := url.URL{Scheme: apiURL.Scheme, Host: apiURL.Host, Path: engineCAPath, RawQuery: engineCAQuery}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: cs.InsecureSkipVerify}
+	if len(cs.CACert) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(cs.CACert) {
+			return "", fmt.Errorf("the CA certificate of the engine doesn't contain any PEM encoded certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	httpClient := &http.Client{
+		Timeout:   engineCATimeout,
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig},
+	}
+	response, err := httpClient.Get(caURL.String())
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download the CA certificate of the engine from %s: %s", caURL.String(), response.Status)
+	}
+	ca, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(ca) {
+		return "", fmt.Errorf("%s didn't return a PEM encoded certificate", caURL.String())
+	}
+	return string(ca), nil
+}
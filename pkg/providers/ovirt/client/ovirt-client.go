@@ -5,37 +5,40 @@ import (
 	"runtime/debug"
 	"time"
 
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/metrics"
 	ovirtsdk "github.com/ovirt/go-ovirt"
 )
 
-const (
-	// Number of minutes to wait for VM to be stopped
-	vmStopTimeout = 5
-	// Vm poll interval in seconds
-	vmPollInterval = 5
-)
-
 // ConnectionSettings wrap information required to make oVirt API connection
 type ConnectionSettings struct {
 	URL      string
 	Username string
 	Password string
-	CACert   []byte
+	// CACert holds the PEM encoded CA certificates of the engine. The system CA certificates are used when empty.
+	CACert []byte
+	// InsecureSkipVerify disables the verification of the engine certificate
+	InsecureSkipVerify bool
 }
 
 // RichOvirtClient is responsible for retrieving VM data from oVirt API
 type richOvirtClient struct {
 	connection *ovirtsdk.Connection
+	settings   *ConnectionSettings
 }
 
 // NewRichOvirtClient creates new, connected rich oVirt client. After it is no longer needed, call Close().
 func NewRichOvirtClient(cs *ConnectionSettings) (*richOvirtClient, error) {
-	con, err := connect(cs.URL, cs.Username, cs.Password, cs.CACert)
+	if cs.InsecureSkipVerify && len(cs.CACert) > 0 {
+		return nil, fmt.Errorf("insecureSkipVerify can't be combined with a CA certificate")
+	}
+	con, err := connect(cs.URL, cs.Username, cs.Password, cs.CACert, cs.InsecureSkipVerify)
 	if err != nil {
 		return nil, err
 	}
 	ovirtClient := richOvirtClient{
 		connection: con,
+		settings:   cs,
 	}
 	return &ovirtClient, nil
 }
@@ -47,6 +50,7 @@ func (client *richOvirtClient) Close() error {
 
 // GetVM retrieves oVirt VM data for given id or name and cluster. VM will have certain links followed and updated.
 func (client *richOvirtClient) GetVM(id *string, name *string, cluster *string, clusterID *string) (_ interface{}, e error) {
+	defer func(start time.Time) { observeRequest("GetVM", start, e) }(time.Now())
 	defer func() {
 		if err := recover(); err != nil {
 			e = fmt.Errorf("ovirt client panicked GetVM: %v", err)
@@ -81,6 +85,10 @@ func (client *richOvirtClient) GetVM(id *string, name *string, cluster *string,
 	if err != nil {
 		return nil, err
 	}
+	err = client.populateApplications(vm)
+	if err != nil {
+		return nil, err
+	}
 	err = client.populateQuota(vm)
 	if err != nil {
 		return nil, err
@@ -108,59 +116,125 @@ func (client *richOvirtClient) GetVM(id *string, name *string, cluster *string,
 	return vm, nil
 }
 
-// StopVM stop the VM and wait for the vm to be stopped
-func (client *richOvirtClient) StopVM(id string) (e error) {
+// ListVMs retrieves the oVirt VMs matching the search query. Unlike GetVM, no links of the VMs are followed
+// apart from their cluster.
+func (client *richOvirtClient) ListVMs(search string) (_ []*ovirtsdk.Vm, e error) {
+	defer func(start time.Time) { observeRequest("ListVMs", start, e) }(time.Now())
 	defer func() {
 		if err := recover(); err != nil {
-			e = fmt.Errorf("ovirt client panicked in StopVM: %v", err)
+			e = fmt.Errorf("ovirt client panicked in ListVMs: %v", err)
 			debug.PrintStack()
 		}
 	}()
-	vmService := client.connection.SystemService().VmsService().VmService(id)
+	response, err := client.connection.SystemService().VmsService().List().Search(search).Send()
+	if err != nil {
+		return nil, err
+	}
+	vms, ok := response.Vms()
+	if !ok {
+		return []*ovirtsdk.Vm{}, nil
+	}
+	for _, vm := range vms.Slice() {
+		err = client.populateCluster(vm)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return vms.Slice(), nil
+}
 
-	// Stop the VM gracefully:
-	_, err := vmService.Shutdown().Send()
+// GetDisk retrieves the disk with the given ID. It returns nil when there is no such disk, e.g. for the ID of an ISO
+// image in an ISO domain, which is its file name.
+func (client *richOvirtClient) GetDisk(id string) (_ *ovirtsdk.Disk, e error) {
+	defer func(start time.Time) { observeRequest("GetDisk", start, e) }(time.Now())
+	defer func() {
+		if err := recover(); err != nil {
+			e = fmt.Errorf("ovirt client panicked in GetDisk: %v", err)
+			debug.PrintStack()
+		}
+	}()
+	response, err := client.connection.SystemService().DisksService().DiskService(id).Get().Send()
 	if err != nil {
-		return err
+		if _, notFound := err.(*ovirtsdk.NotFoundError); notFound {
+			return nil, nil
+		}
+		return nil, err
 	}
+	disk, _ := response.Disk()
+	return disk, nil
+}
 
-	var vm *ovirtsdk.Vm
-	var vmAvailable bool
-	// Wait for VM to be stopped
-	c := make(chan bool, 1)
-	go func() {
-		for {
-			time.Sleep(vmPollInterval * time.Second)
-			vmResponse, _ := vmService.Get().Send()
-			vm, vmAvailable = vmResponse.Vm()
-			if !vmAvailable {
-				c <- false
-			}
-			if status, _ := vm.Status(); status == ovirtsdk.VMSTATUS_DOWN {
-				c <- true
-				break
-			}
+// GetVersion retrieves the version of the oVirt engine.
+func (client *richOvirtClient) GetVersion() (_ string, e error) {
+	defer func(start time.Time) { observeRequest("GetVersion", start, e) }(time.Now())
+	defer func() {
+		if err := recover(); err != nil {
+			e = fmt.Errorf("ovirt client panicked in GetVersion: %v", err)
+			debug.PrintStack()
 		}
 	}()
-	select {
-	case success := <-c:
-		if !success {
-			return fmt.Errorf("Failed to stop vm %s", id)
+	response, err := client.connection.SystemService().Get().Send()
+	if err != nil {
+		return "", err
+	}
+	version := response.MustApi().MustProductInfo().MustVersion().MustFullVersion()
+	return version, nil
+}
+
+// GetInventory counts the data centers, clusters, hosts and VMs of the oVirt engine.
+func (client *richOvirtClient) GetInventory() (_ *v2vv1.ProviderInventory, e error) {
+	defer func(start time.Time) { observeRequest("GetInventory", start, e) }(time.Now())
+	defer func() {
+		if err := recover(); err != nil {
+			e = fmt.Errorf("ovirt client panicked in GetInventory: %v", err)
+			debug.PrintStack()
 		}
-		return nil
-	case <-time.After(vmStopTimeout * time.Minute):
-		status := ovirtsdk.VMSTATUS_UNKNOWN
-		if vm != nil {
-			if vmStatus, ok := vm.Status(); ok {
-				status = vmStatus
-			}
+	}()
+	systemService := client.connection.SystemService()
+	response, err := systemService.Get().Send()
+	if err != nil {
+		return nil, err
+	}
+	summary := response.MustApi().MustSummary()
+	dataCenters, err := systemService.DataCentersService().List().Send()
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := systemService.ClustersService().List().Send()
+	if err != nil {
+		return nil, err
+	}
+	return &v2vv1.ProviderInventory{
+		Datacenters: len(dataCenters.MustDataCenters().Slice()),
+		Clusters:    len(clusters.MustClusters().Slice()),
+		Hosts:       int(summary.MustHosts().MustTotal()),
+		VMs:         int(summary.MustVms().MustTotal()),
+	}, nil
+}
+
+// StopVM requests the shut down of the VM with the given method and doesn't wait for it to be DOWN
+func (client *richOvirtClient) StopVM(id string, method v2vv1.SourceShutdownMethod) (e error) {
+	defer func(start time.Time) { observeRequest("StopVM", start, e) }(time.Now())
+	defer func() {
+		if err := recover(); err != nil {
+			e = fmt.Errorf("ovirt client panicked in StopVM: %v", err)
+			debug.PrintStack()
 		}
-		return fmt.Errorf("Failed to stop vm %s, current status is %s", id, status)
+	}()
+	vmService := client.connection.SystemService().VmsService().VmService(id)
+
+	if method == v2vv1.HardShutdown {
+		_, err := vmService.Stop().Send()
+		return err
 	}
+	// oVirt shuts the guest down through the guest agent when it's available and sends an ACPI event otherwise
+	_, err := vmService.Shutdown().Send()
+	return err
 }
 
 // StartVM requests VM start and doesn't wait for it to be UP
 func (client *richOvirtClient) StartVM(id string) (e error) {
+	defer func(start time.Time) { observeRequest("StartVM", start, e) }(time.Now())
 	defer func() {
 		if err := recover(); err != nil {
 			e = fmt.Errorf("ovirt client panicked in StartVM: %v", err)
@@ -185,8 +259,78 @@ func (client *richOvirtClient) StartVM(id string) (e error) {
 	return nil
 }
 
+// CreateVMSnapshot creates a snapshot of the disks of the VM, without its memory, and returns its ID without
+// waiting for it to be ready. A snapshot of the VM with the same description is reused instead, so that a retried
+// request doesn't create another snapshot.
+func (client *richOvirtClient) CreateVMSnapshot(vmID string, description string) (_ string, e error) {
+	defer func(start time.Time) { observeRequest("CreateVMSnapshot", start, e) }(time.Now())
+	defer func() {
+		if err := recover(); err != nil {
+			e = fmt.Errorf("ovirt client panicked in CreateVMSnapshot: %v", err)
+			debug.PrintStack()
+		}
+	}()
+	snapshotsService := client.connection.SystemService().VmsService().VmService(vmID).SnapshotsService()
+
+	response, err := snapshotsService.List().Send()
+	if err != nil {
+		return "", err
+	}
+	if snapshots, ok := response.Snapshots(); ok {
+		for _, existing := range snapshots.Slice() {
+			if existingDescription, _ := existing.Description(); existingDescription == description {
+				return existing.MustId(), nil
+			}
+		}
+	}
+	addResponse, err := snapshotsService.Add().
+		Snapshot(ovirtsdk.NewSnapshotBuilder().Description(description).PersistMemorystate(false).MustBuild()).
+		Send()
+	if err != nil {
+		return "", err
+	}
+	return addResponse.MustSnapshot().MustId(), nil
+}
+
+// GetVMSnapshot retrieves the snapshot of the VM, it returns nil if the snapshot doesn't exist.
+func (client *richOvirtClient) GetVMSnapshot(vmID string, snapshotID string) (_ *ovirtsdk.Snapshot, e error) {
+	defer func(start time.Time) { observeRequest("GetVMSnapshot", start, e) }(time.Now())
+	defer func() {
+		if err := recover(); err != nil {
+			e = fmt.Errorf("ovirt client panicked in GetVMSnapshot: %v", err)
+			debug.PrintStack()
+		}
+	}()
+	response, err := client.connection.SystemService().VmsService().VmService(vmID).SnapshotsService().SnapshotService(snapshotID).Get().Send()
+	if err != nil {
+		if _, notFound := err.(*ovirtsdk.NotFoundError); notFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	snapshot, _ := response.Snapshot()
+	return snapshot, nil
+}
+
+// RemoveVMSnapshot removes the snapshot of the VM, it is not an error if the snapshot doesn't exist anymore.
+func (client *richOvirtClient) RemoveVMSnapshot(vmID string, snapshotID string) (e error) {
+	defer func(start time.Time) { observeRequest("RemoveVMSnapshot", start, e) }(time.Now())
+	defer func() {
+		if err := recover(); err != nil {
+			e = fmt.Errorf("ovirt client panicked in RemoveVMSnapshot: %v", err)
+			debug.PrintStack()
+		}
+	}()
+	_, err := client.connection.SystemService().VmsService().VmService(vmID).SnapshotsService().SnapshotService(snapshotID).Remove().Send()
+	if _, notFound := err.(*ovirtsdk.NotFoundError); notFound {
+		return nil
+	}
+	return err
+}
+
 // TestConnection checks the connectivity to oVirt provider
-func (client *richOvirtClient) TestConnection() error {
+func (client *richOvirtClient) TestConnection() (e error) {
+	defer func(start time.Time) { observeRequest("TestConnection", start, e) }(time.Now())
 	return client.connection.Test()
 }
 
@@ -262,6 +406,17 @@ func (client *richOvirtClient) populateReportedDevices(vm *ovirtsdk.Vm) error {
 	return nil
 }
 
+func (client *richOvirtClient) populateApplications(vm *ovirtsdk.Vm) error {
+	if applications, ok := vm.Applications(); ok {
+		followed, err := client.connection.FollowLink(applications)
+		if err != nil {
+			return err
+		}
+		vm.SetApplications(followed.(*ovirtsdk.ApplicationSlice))
+	}
+	return nil
+}
+
 func (client *richOvirtClient) populateCdRoms(vm *ovirtsdk.Vm) error {
 	if cdroms, ok := vm.Cdroms(); ok {
 		followed, err := client.connection.FollowLink(cdroms)
@@ -423,12 +578,18 @@ func (client *richOvirtClient) populateCluster(vm *ovirtsdk.Vm) error {
 	return nil
 }
 
-func connect(apiURL string, username string, password string, caCrt []byte) (*ovirtsdk.Connection, error) {
+// observeRequest saves the duration and the result of an oVirt API request in the metrics
+func observeRequest(operation string, start time.Time, err error) {
+	metrics.ProviderMetrics.ObserveRequest(string(v2vv1.OvirtProviderType), operation, start, err)
+}
+
+func connect(apiURL string, username string, password string, caCrt []byte, insecure bool) (*ovirtsdk.Connection, error) {
 	connection, err := ovirtsdk.NewConnectionBuilder().
 		URL(apiURL).
 		Username(username).
 		Password(password).
 		CACert(caCrt).
+		Insecure(insecure).
 		Build()
 	return connection, err
 }
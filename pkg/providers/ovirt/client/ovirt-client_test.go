@@ -1,6 +1,8 @@
 package ovirtclient
 
 import (
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -26,8 +28,42 @@ var _ = Describe("oVirt client", func() {
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("panicked"))
 	})
+	It("should recover from VM listing panic", func() {
+		vms, err := client.ListVMs("tag=any")
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("panicked"))
+		Expect(vms).To(BeNil())
+	})
+	It("should recover from disk retrieval panic", func() {
+		disk, err := client.GetDisk("any")
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("panicked"))
+		Expect(disk).To(BeNil())
+	})
+	It("should recover from VM snapshot creation panic", func() {
+		snapshotID, err := client.CreateVMSnapshot("any", "description")
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("panicked"))
+		Expect(snapshotID).To(BeEmpty())
+	})
+	It("should recover from VM snapshot retrieval panic", func() {
+		snapshot, err := client.GetVMSnapshot("any", "any")
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("panicked"))
+		Expect(snapshot).To(BeNil())
+	})
+	It("should recover from VM snapshot removal panic", func() {
+		err := client.RemoveVMSnapshot("any", "any")
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("panicked"))
+	})
 	It("should recover from VM stopping panic", func() {
-		err := client.StopVM("any")
+		err := client.StopVM("any", v2vv1.GuestShutdown)
 
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("panicked"))
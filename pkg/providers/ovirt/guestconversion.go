@@ -0,0 +1,179 @@
+package ovirtprovider
+
+import (
+	"encoding/xml"
+	"strings"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/guestconversion"
+	"github.com/kubevirt/vm-import-operator/pkg/ownerreferences"
+	ovirtsdk "github.com/ovirt/go-ovirt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+)
+
+// foreignOrigins are the origins oVirt records for the VMs it imported from other hypervisors. Their guests are still
+// configured for the hypervisor they were imported from.
+var foreignOrigins = map[string]bool{
+	"vmware": true,
+	"xen":    true,
+}
+
+// guestConversionPolicy returns the guest conversion policy of the import, Auto when it isn't set
+func guestConversionPolicy(instance *v2vv1.VirtualMachineImport) v2vv1.GuestConversionPolicy {
+	if instance == nil || instance.Spec.Source.Ovirt == nil || instance.Spec.Source.Ovirt.GuestConversion == nil {
+		return v2vv1.GuestConversionAuto
+	}
+	return *instance.Spec.Source.Ovirt.GuestConversion
+}
+
+// needsGuestConversion detects whether the guest can't boot on KubeVirt without being converted, i.e. whether it was
+// imported to oVirt from another hypervisor or it's a Windows guest without virtio drivers
+func needsGuestConversion(vm *ovirtsdk.Vm) bool {
+	if origin, ok := vm.Origin(); ok && foreignOrigins[origin] {
+		return true
+	}
+	return isWindows(vm) && !hasVirtioDrivers(vm)
+}
+
+// isWindows returns whether the guest is Windows, as reported by the guest agent or, without it, as configured
+func isWindows(vm *ovirtsdk.Vm) bool {
+	if guestOS, ok := vm.GuestOperatingSystem(); ok {
+		if family, ok := guestOS.Family(); ok {
+			return strings.EqualFold(family, "windows")
+		}
+	}
+	if os, ok := vm.Os(); ok {
+		if osType, ok := os.Type(); ok {
+			return strings.HasPrefix(strings.ToLower(osType), "windows")
+		}
+	}
+	return false
+}
+
+// hasVirtioDrivers returns whether the guest has virtio drivers, either because one of its disks is on a virtio bus
+// or because the guest agent reports the drivers among the installed applications
+func hasVirtioDrivers(vm *ovirtsdk.Vm) bool {
+	if attachments, ok := vm.DiskAttachments(); ok {
+		for _, attachment := range attachments.Slice() {
+			iface, _ := attachment.Interface()
+			if iface == ovirtsdk.DISKINTERFACE_VIRTIO || iface == ovirtsdk.DISKINTERFACE_VIRTIO_SCSI {
+				return true
+			}
+		}
+	}
+	if applications, ok := vm.Applications(); ok {
+		for _, application := range applications.Slice() {
+			if name, ok := application.Name(); ok && strings.Contains(strings.ToLower(name), "virtio") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NeedsGuestConversion indicates whether a VM from this provider must undergo guest conversion. Once the decision is
+// recorded in the status of the import it is kept, so that it doesn't change with the source VM during the import.
+func (o *OvirtProvider) NeedsGuestConversion() (bool, error) {
+	if o.instance != nil && o.instance.Status.GuestConversion != nil {
+		return *o.instance.Status.GuestConversion, nil
+	}
+	switch guestConversionPolicy(o.instance) {
+	case v2vv1.GuestConversionAlways:
+		return true, nil
+	case v2vv1.GuestConversionNever:
+		return false, nil
+	}
+	// the guest is customized by the conversion pod
+	if o.instance != nil && o.instance.Spec.GuestCustomization != nil {
+		return true, nil
+	}
+	vm, err := o.getVM()
+	if err != nil {
+		return false, err
+	}
+	return needsGuestConversion(vm), nil
+}
+
+// GetGuestConversionPod returns the conversion pod of the import, if it was launched
+func (o *OvirtProvider) GetGuestConversionPod() (*corev1.Pod, error) {
+	return o.podsManager.FindFor(o.GetVmiNamespacedName())
+}
+
+// LaunchGuestConversionPod creates the virt-v2v pod converting the guest on the imported disks, unless it exists
+func (o *OvirtProvider) LaunchGuestConversionPod(vmSpec *kubevirtv1.VirtualMachine, dataVolumes map[string]cdiv1.DataVolume, customization *guestconversion.GuestCustomization) (*corev1.Pod, error) {
+	configMap, err := o.ensureLibvirtDomainIsPresent(vmSpec, dataVolumes)
+	if err != nil {
+		return nil, err
+	}
+	pod, err := o.podsManager.FindFor(o.GetVmiNamespacedName())
+	if err != nil {
+		return nil, err
+	}
+	if pod != nil {
+		return pod, nil
+	}
+
+	settings, err := guestconversion.MergeConversionPodSettings(o.conversionPod, o.instance.Spec.ConversionPod, o.conversionAllowList)
+	if err != nil {
+		return nil, err
+	}
+	pod, err = guestconversion.MakeGuestConversionPodSpec(vmSpec, dataVolumes, configMap, settings, customization)
+	if err != nil {
+		return nil, err
+	}
+	pod.OwnerReferences = []metav1.OwnerReference{
+		ownerreferences.NewVMImportControllerReference(o.vmiTypeMeta, o.vmiObjectMeta),
+	}
+	err = o.podsManager.CreateFor(pod, o.GetVmiNamespacedName())
+	if err != nil {
+		return nil, err
+	}
+	return pod, nil
+}
+
+// ensureLibvirtDomainIsPresent adds the libvirt domain of the VM to the config map of the import, which may already
+// hold the CA certificate of the oVirt engine
+func (o *OvirtProvider) ensureLibvirtDomainIsPresent(vmSpec *kubevirtv1.VirtualMachine, dataVolumes map[string]cdiv1.DataVolume) (*corev1.ConfigMap, error) {
+	configMap, err := o.configMapsManager.FindFor(o.GetVmiNamespacedName())
+	if err != nil {
+		return nil, err
+	}
+	if configMap != nil {
+		if _, found := configMap.BinaryData[guestconversion.LibvirtDomainKey]; found {
+			return configMap, nil
+		}
+	}
+
+	domXML, err := xml.Marshal(guestconversion.MakeLibvirtDomain(vmSpec, dataVolumes))
+	if err != nil {
+		return nil, err
+	}
+	if configMap == nil {
+		configMap = &corev1.ConfigMap{
+			BinaryData: map[string][]byte{
+				guestconversion.LibvirtDomainKey: domXML,
+			},
+		}
+		configMap.OwnerReferences = []metav1.OwnerReference{
+			ownerreferences.NewVMImportOwnerReference(o.vmiTypeMeta, o.vmiObjectMeta),
+		}
+		err = o.configMapsManager.CreateFor(configMap, o.GetVmiNamespacedName())
+		if err != nil {
+			return nil, err
+		}
+		return configMap, nil
+	}
+
+	if configMap.BinaryData == nil {
+		configMap.BinaryData = make(map[string][]byte)
+	}
+	configMap.BinaryData[guestconversion.LibvirtDomainKey] = domXML
+	err = o.configMapsManager.Update(configMap)
+	if err != nil {
+		return nil, err
+	}
+	return configMap, nil
+}
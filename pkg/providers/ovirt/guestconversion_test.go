@@ -0,0 +1,102 @@
+package ovirtprovider
+
+import (
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/onsi/ginkgo/extensions/table"
+	ovirtsdk "github.com/ovirt/go-ovirt"
+	corev1 "k8s.io/api/core/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Detecting guest conversion", func() {
+	table.DescribeTable("should detect whether the guest needs to be converted: ", func(vm *ovirtsdk.Vm, expected bool) {
+		Expect(needsGuestConversion(vm)).To(Equal(expected))
+	},
+		table.Entry("VM imported from VMware", newGuestVM("rhel_8x64", "", "vmware", ovirtsdk.DISKINTERFACE_VIRTIO), true),
+		table.Entry("VM imported from Xen", newGuestVM("rhel_6x64", "", "xen", ovirtsdk.DISKINTERFACE_VIRTIO), true),
+		table.Entry("Windows VM without virtio drivers", newGuestVM("windows_2016x64", "", "ovirt", ovirtsdk.DISKINTERFACE_IDE), true),
+		table.Entry("Windows VM reported by the guest agent", newGuestVM("other", "Windows", "ovirt", ovirtsdk.DISKINTERFACE_SATA), true),
+		table.Entry("Windows VM booting from virtio", newGuestVM("windows_2016x64", "", "ovirt", ovirtsdk.DISKINTERFACE_VIRTIO_SCSI), false),
+		table.Entry("Windows VM with the virtio drivers installed", newGuestVM("windows_2016x64", "", "ovirt", ovirtsdk.DISKINTERFACE_IDE, "Virtio-win driver installer"), false),
+		table.Entry("Linux VM on IDE", newGuestVM("rhel_8x64", "Linux", "ovirt", ovirtsdk.DISKINTERFACE_IDE), false),
+	)
+
+	table.DescribeTable("should follow the guest conversion policy: ", func(policy *v2vv1.GuestConversionPolicy, origin string, expected bool) {
+		provider := OvirtProvider{
+			vm: newGuestVM("rhel_8x64", "", origin, ovirtsdk.DISKINTERFACE_VIRTIO),
+			instance: &v2vv1.VirtualMachineImport{
+				Spec: v2vv1.VirtualMachineImportSpec{
+					Source: v2vv1.VirtualMachineImportSourceSpec{
+						Ovirt: &v2vv1.VirtualMachineImportOvirtSourceSpec{GuestConversion: policy},
+					},
+				},
+			},
+		}
+
+		convertGuest, err := provider.NeedsGuestConversion()
+
+		Expect(err).To(BeNil())
+		Expect(convertGuest).To(Equal(expected))
+	},
+		table.Entry("auto by default", nil, "vmware", true),
+		table.Entry("auto", guestConversionPolicyPtr(v2vv1.GuestConversionAuto), "ovirt", false),
+		table.Entry("always", guestConversionPolicyPtr(v2vv1.GuestConversionAlways), "ovirt", true),
+		table.Entry("never", guestConversionPolicyPtr(v2vv1.GuestConversionNever), "vmware", false),
+	)
+
+	It("should convert a guest that is customized: ", func() {
+		provider := OvirtProvider{
+			vm: newGuestVM("rhel_8x64", "", "ovirt", ovirtsdk.DISKINTERFACE_VIRTIO),
+			instance: &v2vv1.VirtualMachineImport{
+				Spec: v2vv1.VirtualMachineImportSpec{
+					GuestCustomization: &v2vv1.GuestCustomizationSpec{ConfigMap: corev1.LocalObjectReference{Name: "customization"}},
+				},
+			},
+		}
+
+		convertGuest, err := provider.NeedsGuestConversion()
+
+		Expect(err).To(BeNil())
+		Expect(convertGuest).To(BeTrue())
+	})
+
+	It("should keep the decision recorded in the status of the import: ", func() {
+		recorded := false
+		provider := OvirtProvider{
+			vm: newGuestVM("rhel_8x64", "", "vmware", ovirtsdk.DISKINTERFACE_VIRTIO),
+			instance: &v2vv1.VirtualMachineImport{
+				Status: v2vv1.VirtualMachineImportStatus{GuestConversion: &recorded},
+			},
+		}
+
+		convertGuest, err := provider.NeedsGuestConversion()
+
+		Expect(err).To(BeNil())
+		Expect(convertGuest).To(BeFalse())
+	})
+})
+
+func newGuestVM(osType string, guestFamily string, origin string, iface ovirtsdk.DiskInterface, applications ...string) *ovirtsdk.Vm {
+	os, _ := ovirtsdk.NewOperatingSystemBuilder().Type(osType).Build()
+	attachment, _ := ovirtsdk.NewDiskAttachmentBuilder().Interface(iface).Build()
+	builder := ovirtsdk.NewVmBuilder().
+		Os(os).
+		Origin(origin).
+		DiskAttachmentsOfAny(attachment)
+	if guestFamily != "" {
+		guestOS, _ := ovirtsdk.NewGuestOperatingSystemBuilder().Family(guestFamily).Build()
+		builder.GuestOperatingSystem(guestOS)
+	}
+	for _, name := range applications {
+		application, _ := ovirtsdk.NewApplicationBuilder().Name(name).Build()
+		builder.ApplicationsOfAny(application)
+	}
+	vm, _ := builder.Build()
+	return vm
+}
+
+func guestConversionPolicyPtr(policy v2vv1.GuestConversionPolicy) *v2vv1.GuestConversionPolicy {
+	return &policy
+}
@@ -11,7 +11,9 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	provider "github.com/kubevirt/vm-import-operator/pkg/providers"
 	outils "github.com/kubevirt/vm-import-operator/pkg/providers/ovirt/utils"
+	"github.com/kubevirt/vm-import-operator/pkg/storageprofiles"
 	"github.com/kubevirt/vm-import-operator/pkg/utils"
 	ovirtsdk "github.com/ovirt/go-ovirt"
 	corev1 "k8s.io/api/core/v1"
@@ -49,7 +51,8 @@ var (
 )
 
 // DiskInterfaceModelMapping defines mapping of disk interface models between oVirt and kubevirt domains
-var DiskInterfaceModelMapping = map[string]string{"sata": "sata", "virtio_scsi": "scsi", "virtio": "virtio"}
+// KubeVirt doesn't emulate IDE, so IDE disks are attached to the SATA bus.
+var DiskInterfaceModelMapping = map[string]string{"ide": "sata", "sata": "sata", "virtio_scsi": "scsi", "virtio": "virtio"}
 
 // BiosTypeMapping defines mapping of BIOS types between oVirt and kubevirt domains
 var BiosTypeMapping = map[string]*kubevirtv1.Bootloader{
@@ -83,6 +86,14 @@ type OvirtMapper struct {
 	creds     DataVolumeCredentials
 	namespace string
 	osFinder  oos.OSFinder
+	// guestConversion attaches all the disks to the virtio bus, since virt-v2v installs the virtio drivers
+	guestConversion bool
+	// storageProfiles recommend the access and volume modes of the PVCs that aren't set in the mappings
+	storageProfiles storageprofiles.Finder
+	// diskSelection selects the disks that are imported, all of them when it is nil
+	diskSelection *v2vv1.DiskSelectionSpec
+	// isoDisks are the data domain disks holding the ISO images of the CD-ROMs, keyed by the ID of the ISO image
+	isoDisks map[string]*ovirtsdk.Disk
 }
 
 // NewOvirtMapper create ovirt mapper object
@@ -96,6 +107,28 @@ func NewOvirtMapper(vm *ovirtsdk.Vm, mappings *v2vv1.OvirtMappings, creds DataVo
 	}
 }
 
+// EnableGuestConversion maps the disks for a guest converted with virt-v2v, which boots from the virtio bus
+func (o *OvirtMapper) EnableGuestConversion() {
+	o.guestConversion = true
+}
+
+// UseStorageProfiles makes the PVCs of the disks use the best access and volume modes supported by the StorageProfile
+// of their storage class, unless the mapping sets them
+func (o *OvirtMapper) UseStorageProfiles(storageProfiles storageprofiles.Finder) {
+	o.storageProfiles = storageProfiles
+}
+
+// SelectDisks makes the mapper import only the disks selected by the disk selection
+func (o *OvirtMapper) SelectDisks(selection *v2vv1.DiskSelectionSpec) {
+	o.diskSelection = selection
+}
+
+// UseISODisks makes the mapper import the ISO images of the CD-ROMs from the data domain disks holding them, keyed by
+// the ID of the ISO image. The images of an ISO domain can't be imported.
+func (o *OvirtMapper) UseISODisks(isoDisks map[string]*ovirtsdk.Disk) {
+	o.isoDisks = isoDisks
+}
+
 // CreateEmptyVM creates empty virtual machine definition
 func (o *OvirtMapper) CreateEmptyVM(vmName *string) *kubevirtv1.VirtualMachine {
 	return &kubevirtv1.VirtualMachine{
@@ -188,6 +221,9 @@ func (o *OvirtMapper) MapVM(targetVMName *string, vmSpec *kubevirtv1.VirtualMach
 	networkToType := o.mapNetworksToTypes(vmSpec.Spec.Template.Spec.Networks)
 	vmSpec.Spec.Template.Spec.Domain.Devices.Interfaces = o.mapNics(networkToType)
 
+	// Map the CD-ROMs backed by existing PVCs, the other ones are mapped with their data volumes
+	o.mapCdromClaims(vmSpec)
+
 	return vmSpec, nil
 }
 
@@ -208,12 +244,25 @@ func (o *OvirtMapper) MapDisk(vmSpec *kubevirtv1.VirtualMachine, dv cdiv1.DataVo
 	// Map disks
 	diskAttachments, _ := o.vm.DiskAttachments()
 	diskAttachment := getDiskAttachmentByID(dv.Name, diskAttachments, vmSpec.ObjectMeta.Name)
+	if diskAttachment == nil {
+		if o.getCdromByDataVolumeName(dv.Name, vmSpec.ObjectMeta.Name) != nil {
+			provider.AddCdrom(vmSpec, name, volume.VolumeSource)
+		}
+		return
+	}
+	if !provider.DiskSelected(o.diskSelection, SourceDiskOf(diskAttachment)) {
+		return
+	}
 	iface, _ := diskAttachment.Interface()
+	bus := DiskInterfaceModelMapping[string(iface)]
+	if o.guestConversion {
+		bus = "virtio"
+	}
 	disk := kubevirtv1.Disk{
 		Name: name,
 		DiskDevice: kubevirtv1.DiskDevice{
 			Disk: &kubevirtv1.DiskTarget{
-				Bus: DiskInterfaceModelMapping[string(iface)],
+				Bus: bus,
 			},
 		},
 	}
@@ -258,34 +307,38 @@ func (o *OvirtMapper) getAccessMode(diskAttachment *ovirtsdk.DiskAttachment, map
 // MapDataVolumes map the oVirt VM disks to the map of CDI DataVolumes specification, where
 // map key is the target-vm-name + id of the oVirt disk
 func (o *OvirtMapper) MapDataVolumes(targetVMName *string, filesystemOverhead cdiv1.FilesystemOverhead) (map[string]cdiv1.DataVolume, error) {
-	// TODO: stateless, boot_devices, floppy/cdrom
+	// TODO: stateless, boot_devices. The floppies aren't imported: KubeVirt has no floppy drive, the validation warns
+	// about them.
 	diskAttachments, _ := o.vm.DiskAttachments()
 	dvs := make(map[string]cdiv1.DataVolume, len(diskAttachments.Slice()))
 
 	for _, diskAttachment := range diskAttachments.Slice() {
+		if !provider.DiskSelected(o.diskSelection, SourceDiskOf(diskAttachment)) {
+			continue
+		}
 		diskAttachID, _ := diskAttachment.Id()
 		dvName := buildDataVolumeName(*targetVMName, diskAttachID)
 		disk, _ := diskAttachment.Disk()
 		diskID, _ := disk.Id()
 
 		mapping := o.getMapping(disk, o.mappings)
-		accessMode := o.getAccessMode(diskAttachment, mapping)
 		sdClass := o.getStorageClassForDisk(mapping)
-		volumeMode := o.getVolumeMode(mapping)
+		accessMode, volumeMode, err := o.applyStorageProfile(sdClass, mapping, o.getAccessMode(diskAttachment, mapping), o.getVolumeMode(mapping))
+		if err != nil {
+			return dvs, err
+		}
 
 		diskSize, _ := disk.ProvisionedSize()
-		overhead := utils.GetOverheadForStorageClass(filesystemOverhead, sdClass)
-
-		blockSize := int64(1048576)
-		alignedSize := utils.RoundUp(diskSize, blockSize)
-		sizeWithOverhead := int64(math.Ceil(float64(alignedSize) / (1 - overhead)))
-		diskSizeConverted, err := utils.FormatBytes(sizeWithOverhead)
+		quantity, err := dataVolumeSize(diskSize, sdClass, filesystemOverhead)
 		if err != nil {
 			return dvs, err
 		}
-		quantity, _ := resource.ParseQuantity(diskSizeConverted)
 
-		dvs[dvName] = cdiv1.DataVolume{
+		sparse, _ := disk.Sparse()
+		actualSize, _ := disk.ActualSize()
+		preallocate := utils.Preallocate(o.getPreallocation(mapping), sparse)
+
+		dv := cdiv1.DataVolume{
 			TypeMeta: metav1.TypeMeta{
 				APIVersion: cdiAPIVersion,
 				Kind:       dataVolumeKind,
@@ -317,10 +370,203 @@ func (o *OvirtMapper) MapDataVolumes(targetVMName *string, filesystemOverhead cd
 			},
 		}
 		if sdClass != nil {
-			dvs[dvName].Spec.PVC.StorageClassName = sdClass
+			dv.Spec.PVC.StorageClassName = sdClass
 		}
+		utils.SetPreallocation(&dv, preallocate, utils.EstimateConsumedBytes(quantity.Value(), actualSize, preallocate))
+		utils.SetSourceBytes(&dv, diskSize)
+		dvs[dvName] = dv
+	}
+
+	err := o.mapCdromDataVolumes(*targetVMName, filesystemOverhead, dvs)
+	return dvs, err
+}
+
+// dataVolumeSize returns the size of the data volume a disk of the given size is imported to
+func dataVolumeSize(diskSize int64, storageClass *string, filesystemOverhead cdiv1.FilesystemOverhead) (resource.Quantity, error) {
+	overhead := utils.GetOverheadForStorageClass(filesystemOverhead, storageClass)
+
+	blockSize := int64(1048576)
+	alignedSize := utils.RoundUp(diskSize, blockSize)
+	sizeWithOverhead := int64(math.Ceil(float64(alignedSize) / (1 - overhead)))
+	diskSizeConverted, err := utils.FormatBytes(sizeWithOverhead)
+	if err != nil {
+		return resource.Quantity{}, err
 	}
-	return dvs, nil
+	return resource.ParseQuantity(diskSizeConverted)
+}
+
+// mapCdromDataVolumes adds the data volumes the ISO images of the CD-ROMs are imported or cloned to. The images that
+// are neither in a data domain nor mapped to a PVC or a DataSource are left out.
+func (o *OvirtMapper) mapCdromDataVolumes(targetVMName string, filesystemOverhead cdiv1.FilesystemOverhead, dvs map[string]cdiv1.DataVolume) error {
+	for _, cdrom := range o.cdroms() {
+		image, ok := cdromImage(cdrom)
+		if !ok {
+			continue
+		}
+		cdromID, _ := cdrom.Id()
+		dvName := buildDataVolumeName(targetVMName, cdromID)
+		mapping := o.getCdromMapping(image)
+		if dv, clone := provider.CdromCloneDataVolume(dvName, o.namespace, mapping); clone {
+			dvs[dvName] = dv
+			continue
+		}
+		if provider.CdromMappingTypeOf(mapping) != v2vv1.ImportCdromMapping {
+			continue
+		}
+		imageID, _ := image.Id()
+		isoDisk, found := o.isoDisks[imageID]
+		if !found {
+			continue
+		}
+
+		var storageClass *string
+		if mapping != nil && mapping.Target.Name != DefaultStorageClassTargetName {
+			storageClass = &mapping.Target.Name
+		}
+		accessMode, volumeMode, err := o.applyStorageProfile(storageClass, nil, corev1.ReadWriteOnce, &DefaultVolumeMode)
+		if err != nil {
+			return err
+		}
+		isoSize, _ := isoDisk.ProvisionedSize()
+		quantity, err := dataVolumeSize(isoSize, storageClass, filesystemOverhead)
+		if err != nil {
+			return err
+		}
+
+		dv := cdiv1.DataVolume{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: cdiAPIVersion,
+				Kind:       dataVolumeKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      dvName,
+				Namespace: o.namespace,
+			},
+			Spec: cdiv1.DataVolumeSpec{
+				Source: cdiv1.DataVolumeSource{
+					Imageio: &cdiv1.DataVolumeSourceImageIO{
+						URL:           o.creds.URL,
+						DiskID:        imageID,
+						SecretRef:     o.creds.SecretName,
+						CertConfigMap: o.creds.ConfigMapName,
+					},
+				},
+				PVC: &corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{
+						accessMode,
+					},
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: quantity,
+						},
+					},
+					VolumeMode:       volumeMode,
+					StorageClassName: storageClass,
+				},
+			},
+		}
+		utils.SetSourceBytes(&dv, isoSize)
+		dvs[dvName] = dv
+	}
+	return nil
+}
+
+// mapCdromClaims attaches the CD-ROMs mapped to a PVC of the namespace of the import to that PVC
+func (o *OvirtMapper) mapCdromClaims(vmSpec *kubevirtv1.VirtualMachine) {
+	for _, cdrom := range o.cdroms() {
+		image, ok := cdromImage(cdrom)
+		if !ok {
+			continue
+		}
+		claimName, found := provider.CdromClaimName(o.getCdromMapping(image), o.namespace)
+		if !found {
+			continue
+		}
+		cdromID, _ := cdrom.Id()
+		name := utils.EnsureLabelValueLength(fmt.Sprintf("cdrom-%v", cdromID))
+		provider.AddCdrom(vmSpec, name, kubevirtv1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: claimName,
+				ReadOnly:  true,
+			},
+		})
+	}
+}
+
+// UnmappedCdroms returns the ISO images of the CD-ROMs of the VM that can't be imported, because they aren't in one of
+// the data domain disks isoDisks, and aren't mapped to a PVC or a DataSource either
+func UnmappedCdroms(vm *ovirtsdk.Vm, mappings *v2vv1.OvirtMappings, isoDisks map[string]*ovirtsdk.Disk) []string {
+	var unmapped []string
+	for _, cdrom := range vmCdroms(vm) {
+		image, ok := cdromImage(cdrom)
+		if !ok || provider.CdromMappingTypeOf(cdromMapping(mappings, image)) != v2vv1.ImportCdromMapping {
+			continue
+		}
+		imageID, _ := image.Id()
+		if _, found := isoDisks[imageID]; !found {
+			name, _ := image.Name()
+			if name == "" {
+				name = imageID
+			}
+			unmapped = append(unmapped, name)
+		}
+	}
+	return unmapped
+}
+
+func (o *OvirtMapper) cdroms() []*ovirtsdk.Cdrom {
+	return vmCdroms(o.vm)
+}
+
+func vmCdroms(vm *ovirtsdk.Vm) []*ovirtsdk.Cdrom {
+	if cdroms, ok := vm.Cdroms(); ok {
+		return cdroms.Slice()
+	}
+	return nil
+}
+
+func (o *OvirtMapper) getCdromMapping(image *ovirtsdk.File) *v2vv1.CdromResourceMappingItem {
+	return cdromMapping(o.mappings, image)
+}
+
+// cdromMapping returns the mapping of the ISO image by its ID or name. The ID of an image in an ISO domain is its
+// file name, so the name of the mapping matches it too.
+func cdromMapping(mappings *v2vv1.OvirtMappings, image *ovirtsdk.File) *v2vv1.CdromResourceMappingItem {
+	if mappings == nil || mappings.CdromMappings == nil {
+		return nil
+	}
+	id, _ := image.Id()
+	name, _ := image.Name()
+	for _, mapping := range *mappings.CdromMappings {
+		if mapping.Source.ID != nil && *mapping.Source.ID == id {
+			return &mapping
+		}
+		if mapping.Source.Name != nil && (*mapping.Source.Name == id || *mapping.Source.Name == name) {
+			return &mapping
+		}
+	}
+	return nil
+}
+
+func (o *OvirtMapper) getCdromByDataVolumeName(dvName string, targetVMName string) *ovirtsdk.Cdrom {
+	for _, cdrom := range o.cdroms() {
+		if cdromID, ok := cdrom.Id(); ok && buildDataVolumeName(targetVMName, cdromID) == dvName {
+			return cdrom
+		}
+	}
+	return nil
+}
+
+// cdromImage returns the ISO image inserted in the CD-ROM, if any
+func cdromImage(cdrom *ovirtsdk.Cdrom) (*ovirtsdk.File, bool) {
+	file, ok := cdrom.File()
+	if !ok {
+		return nil, false
+	}
+	if id, ok := file.Id(); !ok || id == "" {
+		return nil, false
+	}
+	return file, true
 }
 
 func (o *OvirtMapper) mapNics(networkToType map[string]string) []kubevirtv1.Interface {
@@ -500,6 +746,21 @@ func (o *OvirtMapper) getMapping(disk *ovirtsdk.Disk, mappings *v2vv1.OvirtMappi
 	return nil
 }
 
+// applyStorageProfile replaces the access and volume modes of the PVC of a disk that aren't set in the mapping with
+// the best ones supported by the StorageProfile of its storage class, if any
+func (o *OvirtMapper) applyStorageProfile(storageClass *string, mapping *v2vv1.StorageResourceMappingItem, accessMode corev1.PersistentVolumeAccessMode, volumeMode *corev1.PersistentVolumeMode) (corev1.PersistentVolumeAccessMode, *corev1.PersistentVolumeMode, error) {
+	var mappedAccessMode *corev1.PersistentVolumeAccessMode
+	var mappedVolumeMode *corev1.PersistentVolumeMode
+	if mapping != nil {
+		mappedAccessMode, mappedVolumeMode = mapping.AccessMode, mapping.VolumeMode
+	}
+	profileAccessMode, profileVolumeMode, found, err := storageprofiles.Recommend(o.storageProfiles, storageClass, mappedAccessMode, mappedVolumeMode)
+	if err != nil || !found {
+		return accessMode, volumeMode, err
+	}
+	return profileAccessMode, &profileVolumeMode, nil
+}
+
 func (o *OvirtMapper) getVolumeMode(mapping *v2vv1.StorageResourceMappingItem) *corev1.PersistentVolumeMode {
 	if mapping != nil {
 		return mapping.VolumeMode
@@ -508,6 +769,13 @@ func (o *OvirtMapper) getVolumeMode(mapping *v2vv1.StorageResourceMappingItem) *
 	return &DefaultVolumeMode
 }
 
+func (o *OvirtMapper) getPreallocation(mapping *v2vv1.StorageResourceMappingItem) *v2vv1.PreallocationMode {
+	if mapping != nil {
+		return mapping.Preallocation
+	}
+	return nil
+}
+
 func (o *OvirtMapper) getStorageClassForDisk(mapping *v2vv1.StorageResourceMappingItem) *string {
 	if mapping != nil {
 		targetName := mapping.Target.Name
@@ -822,6 +1090,23 @@ func (o *OvirtMapper) mapTimeZone() *kubevirtv1.Clock {
 	return &clock
 }
 
+// SourceDiskOf describes the disk of the attachment for the disk selection
+func SourceDiskOf(diskAttachment *ovirtsdk.DiskAttachment) provider.SourceDisk {
+	sourceDisk := provider.SourceDisk{}
+	sourceDisk.Bootable, _ = diskAttachment.Bootable()
+	if disk, ok := diskAttachment.Disk(); ok {
+		sourceDisk.ID, _ = disk.Id()
+		sourceDisk.SizeBytes, _ = disk.ProvisionedSize()
+		if name, ok := disk.Name(); ok {
+			sourceDisk.Names = append(sourceDisk.Names, name)
+		}
+		if alias, ok := disk.Alias(); ok {
+			sourceDisk.Names = append(sourceDisk.Names, alias)
+		}
+	}
+	return sourceDisk
+}
+
 func getDiskAttachmentByID(id string, diskAttachments *ovirtsdk.DiskAttachmentSlice, targetVMName string) *ovirtsdk.DiskAttachment {
 	for _, diskAttachment := range diskAttachments.Slice() {
 		if diskID, ok := diskAttachment.Id(); ok && buildDataVolumeName(targetVMName, diskID) == id {
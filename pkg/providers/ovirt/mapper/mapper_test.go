@@ -2,6 +2,7 @@ package mapper_test
 
 import (
 	"fmt"
+	"strconv"
 
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
 
@@ -11,6 +12,7 @@ import (
 
 	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
 	"github.com/kubevirt/vm-import-operator/pkg/providers/ovirt/mapper"
+	"github.com/kubevirt/vm-import-operator/pkg/storageprofiles"
 	"github.com/kubevirt/vm-import-operator/pkg/utils"
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
@@ -378,6 +380,293 @@ var _ = Describe("Test pvc accessmodes", func() {
 	)
 })
 
+var _ = Describe("Test pvc modes recommended by the storage profile", func() {
+	var (
+		credentials = mapper.DataVolumeCredentials{
+			URL:           "any-url",
+			SecretName:    "secret-name",
+			ConfigMapName: "config-map",
+		}
+		block      = corev1.PersistentVolumeBlock
+		filesystem = corev1.PersistentVolumeFilesystem
+		profiles   = &mockStorageProfiles{sets: []storageprofiles.ClaimPropertySet{
+			{AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, VolumeMode: &filesystem},
+			{AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}, VolumeMode: &block},
+		}}
+	)
+
+	It("should prefer readwritemany block volumes", func() {
+		vm := createVMGeneric(ovirtsdk.VMAFFINITY_PINNED, false, ovirtsdk.BIOSTYPE_Q35_SEA_BIOS, ovirtsdk.DISKINTERFACE_VIRTIO)
+		mappings := createMappings()
+		mapper := mapper.NewOvirtMapper(vm, &mappings, credentials, "the-namespace", &osFinder)
+		mapper.UseStorageProfiles(profiles)
+
+		dvs, err := mapper.MapDataVolumes(&targetVMName, filesystemOverhead)
+
+		Expect(err).To(BeNil())
+		dv := dvs[expectedDVName]
+		Expect(dv.Spec.PVC.AccessModes).To(ConsistOf(corev1.ReadWriteMany))
+		Expect(*dv.Spec.PVC.VolumeMode).To(Equal(block))
+	})
+
+	It("should keep the modes of the mapping", func() {
+		vm := createVMGeneric(ovirtsdk.VMAFFINITY_PINNED, false, ovirtsdk.BIOSTYPE_Q35_SEA_BIOS, ovirtsdk.DISKINTERFACE_VIRTIO)
+		mappings := createMappings()
+		accessMode := corev1.ReadWriteOnce
+		(*mappings.StorageMappings)[0].AccessMode = &accessMode
+		(*mappings.StorageMappings)[0].VolumeMode = &filesystem
+		mapper := mapper.NewOvirtMapper(vm, &mappings, credentials, "the-namespace", &osFinder)
+		mapper.UseStorageProfiles(profiles)
+
+		dvs, err := mapper.MapDataVolumes(&targetVMName, filesystemOverhead)
+
+		Expect(err).To(BeNil())
+		dv := dvs[expectedDVName]
+		Expect(dv.Spec.PVC.AccessModes).To(ConsistOf(corev1.ReadWriteOnce))
+		Expect(*dv.Spec.PVC.VolumeMode).To(Equal(filesystem))
+	})
+})
+
+var _ = Describe("Test preallocation of the disks", func() {
+	var (
+		credentials = mapper.DataVolumeCredentials{
+			URL:           "any-url",
+			SecretName:    "secret-name",
+			ConfigMapName: "config-map",
+		}
+		vm *ovirtsdk.Vm
+	)
+
+	BeforeEach(func() {
+		vm = createVMGeneric(ovirtsdk.VMAFFINITY_PINNED, false, ovirtsdk.BIOSTYPE_Q35_SEA_BIOS, ovirtsdk.DISKINTERFACE_VIRTIO)
+		disk := vm.MustDiskAttachments().Slice()[0].MustDisk()
+		disk.SetSparse(true)
+		disk.SetActualSize(1024)
+	})
+
+	table.DescribeTable("should preallocate the disk according to the mapping", func(mode *v2vv1.PreallocationMode, expected string) {
+		mappings := createMappings()
+		(*mappings.StorageMappings)[0].Preallocation = mode
+		mapper := mapper.NewOvirtMapper(vm, &mappings, credentials, "the-namespace", &osFinder)
+
+		dvs, err := mapper.MapDataVolumes(&targetVMName, filesystemOverhead)
+
+		Expect(err).To(BeNil())
+		preallocation, found := dvs[expectedDVName].Annotations[utils.AnnPreallocation]
+		Expect(found).To(Equal(expected != ""))
+		Expect(preallocation).To(Equal(expected))
+	},
+		table.Entry("left to CDI", nil, ""),
+		table.Entry("sparse", preallocationMode(v2vv1.SparsePreallocation), "false"),
+		table.Entry("preallocated", preallocationMode(v2vv1.FullPreallocation), "true"),
+		table.Entry("inherited from the sparse disk", preallocationMode(v2vv1.InheritPreallocation), "false"),
+	)
+
+	It("should estimate the bytes allocated to the sparse disk", func() {
+		mappings := createMappings()
+		mapper := mapper.NewOvirtMapper(vm, &mappings, credentials, "the-namespace", &osFinder)
+
+		dvs, err := mapper.MapDataVolumes(&targetVMName, filesystemOverhead)
+
+		Expect(err).To(BeNil())
+		Expect(dvs[expectedDVName].Annotations[utils.AnnEstimatedBytes]).To(Equal("1024"))
+	})
+
+	It("should record the size of the source disk", func() {
+		mappings := createMappings()
+		mapper := mapper.NewOvirtMapper(vm, &mappings, credentials, "the-namespace", &osFinder)
+
+		dvs, err := mapper.MapDataVolumes(&targetVMName, filesystemOverhead)
+
+		Expect(err).To(BeNil())
+		Expect(dvs[expectedDVName].Annotations[utils.AnnSourceBytes]).To(Equal(strconv.FormatInt(memoryGI, 10)))
+	})
+
+	It("should estimate the whole size of the preallocated disk", func() {
+		mappings := createMappings()
+		mode := v2vv1.FullPreallocation
+		(*mappings.StorageMappings)[0].Preallocation = &mode
+		mapper := mapper.NewOvirtMapper(vm, &mappings, credentials, "the-namespace", &osFinder)
+
+		dvs, err := mapper.MapDataVolumes(&targetVMName, filesystemOverhead)
+
+		Expect(err).To(BeNil())
+		dv := dvs[expectedDVName]
+		size := dv.Spec.PVC.Resources.Requests[corev1.ResourceStorage]
+		Expect(dv.Annotations[utils.AnnEstimatedBytes]).To(Equal(strconv.FormatInt(size.Value(), 10)))
+	})
+})
+
+var _ = Describe("Test selection of the disks", func() {
+	var (
+		credentials = mapper.DataVolumeCredentials{
+			URL:           "any-url",
+			SecretName:    "secret-name",
+			ConfigMapName: "config-map",
+		}
+		alias = "mydisk"
+	)
+
+	BeforeEach(func() {
+		findOs = func(vm *ovirtsdk.Vm) (string, error) {
+			return "linux", nil
+		}
+	})
+
+	It("should not map the excluded disk", func() {
+		vm := createVMGeneric(ovirtsdk.VMAFFINITY_PINNED, false, ovirtsdk.BIOSTYPE_Q35_SEA_BIOS, ovirtsdk.DISKINTERFACE_VIRTIO)
+		mappings := createMappings()
+		mapper := mapper.NewOvirtMapper(vm, &mappings, credentials, "the-namespace", &osFinder)
+		mapper.SelectDisks(&v2vv1.DiskSelectionSpec{
+			Exclude: []v2vv1.DiskSelectorRule{{Name: &alias}},
+		})
+
+		dvs, err := mapper.MapDataVolumes(&targetVMName, filesystemOverhead)
+
+		Expect(err).To(BeNil())
+		Expect(dvs).To(BeEmpty())
+	})
+
+	It("should not attach the excluded disk", func() {
+		vm := createVMGeneric(ovirtsdk.VMAFFINITY_PINNED, false, ovirtsdk.BIOSTYPE_Q35_SEA_BIOS, ovirtsdk.DISKINTERFACE_VIRTIO)
+		vm.MustDiskAttachments().Slice()[0].SetBootable(true)
+		mappings := createMappings()
+		mapper := mapper.NewOvirtMapper(vm, &mappings, credentials, "the-namespace", &osFinder)
+		dvs, err := mapper.MapDataVolumes(&targetVMName, filesystemOverhead)
+		Expect(err).To(BeNil())
+		vmSpec, err := mapper.MapVM(&targetVMName, &kubevirtv1.VirtualMachine{})
+		Expect(err).To(BeNil())
+		notBootable := false
+		mapper.SelectDisks(&v2vv1.DiskSelectionSpec{
+			Include: []v2vv1.DiskSelectorRule{{Bootable: &notBootable}},
+		})
+
+		mapper.MapDisk(vmSpec, dvs[expectedDVName])
+
+		Expect(vmSpec.Spec.Template.Spec.Volumes).To(BeEmpty())
+		Expect(vmSpec.Spec.Template.Spec.Domain.Devices.Disks).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Test mapping CD-ROMs", func() {
+	var (
+		credentials = mapper.DataVolumeCredentials{
+			URL:           "any-url",
+			SecretName:    "secret-name",
+			ConfigMapName: "config-map",
+		}
+		vm       *ovirtsdk.Vm
+		mappings v2vv1.OvirtMappings
+	)
+
+	BeforeEach(func() {
+		findOs = func(vm *ovirtsdk.Vm) (string, error) {
+			return "linux", nil
+		}
+		vm = createVM()
+		cdroms := &ovirtsdk.CdromSlice{}
+		cdroms.SetSlice([]*ovirtsdk.Cdrom{
+			ovirtsdk.NewCdromBuilder().
+				Id("cdrom-ID").
+				File(ovirtsdk.NewFileBuilder().Id("iso-ID").Name("fedora.iso").MustBuild()).
+				MustBuild(),
+		})
+		vm.SetCdroms(cdroms)
+		mappings = createMappings()
+	})
+
+	It("should import the ISO image of a data domain", func() {
+		isoDisk := ovirtsdk.NewDiskBuilder().Id("iso-ID").ProvisionedSize(memoryGI).MustBuild()
+		mapper := mapper.NewOvirtMapper(vm, &mappings, credentials, "the-namespace", &osFinder)
+		mapper.UseISODisks(map[string]*ovirtsdk.Disk{"iso-ID": isoDisk})
+
+		dvs, err := mapper.MapDataVolumes(&targetVMName, filesystemOverhead)
+
+		Expect(err).To(BeNil())
+		Expect(dvs).To(HaveLen(2))
+		var isoDV cdiv1.DataVolume
+		for name, dv := range dvs {
+			if name != expectedDVName {
+				isoDV = dv
+			}
+		}
+		Expect(isoDV.Spec.Source.Imageio.DiskID).To(Equal("iso-ID"))
+		Expect(isoDV.Spec.PVC.AccessModes).To(ConsistOf(corev1.ReadWriteOnce))
+		size := isoDV.Spec.PVC.Resources.Requests[corev1.ResourceStorage]
+		Expect(size.Value()).To(BeEquivalentTo(memoryGI))
+
+		vmSpec, err := mapper.MapVM(&targetVMName, &kubevirtv1.VirtualMachine{})
+		Expect(err).To(BeNil())
+		mapper.MapDisk(vmSpec, isoDV)
+
+		Expect(vmSpec.Spec.Template.Spec.Volumes).To(HaveLen(1))
+		Expect(vmSpec.Spec.Template.Spec.Volumes[0].DataVolume.Name).To(Equal(isoDV.Name))
+		Expect(vmSpec.Spec.Template.Spec.Domain.Devices.Disks).To(HaveLen(1))
+		Expect(vmSpec.Spec.Template.Spec.Domain.Devices.Disks[0].CDRom).ToNot(BeNil())
+		Expect(vmSpec.Spec.Template.Spec.Domain.Devices.Disks[0].CDRom.Bus).To(Equal("sata"))
+	})
+
+	It("should leave out the ISO image of an ISO domain", func() {
+		mapper := mapper.NewOvirtMapper(vm, &mappings, credentials, "the-namespace", &osFinder)
+
+		dvs, err := mapper.MapDataVolumes(&targetVMName, filesystemOverhead)
+
+		Expect(err).To(BeNil())
+		Expect(dvs).To(HaveLen(1))
+		Expect(dvs).To(HaveKey(expectedDVName))
+	})
+
+	It("should report the ISO image of an ISO domain as unmapped", func() {
+		Expect(mapper.UnmappedCdroms(vm, &mappings, nil)).To(ConsistOf("fedora.iso"))
+	})
+
+	It("should attach the CD-ROM to the PVC it is mapped to", func() {
+		isoName := "fedora.iso"
+		pvcType := v2vv1.PersistentVolumeClaimCdromMapping
+		mappings.CdromMappings = &[]v2vv1.CdromResourceMappingItem{
+			{Source: v2vv1.Source{Name: &isoName}, Target: v2vv1.ObjectIdentifier{Name: "fedora-iso"}, Type: &pvcType},
+		}
+		vmMapper := mapper.NewOvirtMapper(vm, &mappings, credentials, "the-namespace", &osFinder)
+
+		dvs, err := vmMapper.MapDataVolumes(&targetVMName, filesystemOverhead)
+		Expect(err).To(BeNil())
+		Expect(dvs).To(HaveLen(1))
+		vmSpec, err := vmMapper.MapVM(&targetVMName, &kubevirtv1.VirtualMachine{})
+
+		Expect(err).To(BeNil())
+		Expect(vmSpec.Spec.Template.Spec.Volumes).To(HaveLen(1))
+		Expect(vmSpec.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("fedora-iso"))
+		Expect(vmSpec.Spec.Template.Spec.Domain.Devices.Disks).To(HaveLen(1))
+		Expect(vmSpec.Spec.Template.Spec.Domain.Devices.Disks[0].CDRom).ToNot(BeNil())
+		Expect(mapper.UnmappedCdroms(vm, &mappings, nil)).To(BeEmpty())
+	})
+
+	It("should clone the DataSource the CD-ROM is mapped to", func() {
+		isoID := "iso-ID"
+		imagesNamespace := "images"
+		dataSourceType := v2vv1.DataSourceCdromMapping
+		mappings.CdromMappings = &[]v2vv1.CdromResourceMappingItem{
+			{Source: v2vv1.Source{ID: &isoID}, Target: v2vv1.ObjectIdentifier{Name: "fedora", Namespace: &imagesNamespace}, Type: &dataSourceType},
+		}
+		mapper := mapper.NewOvirtMapper(vm, &mappings, credentials, "the-namespace", &osFinder)
+
+		dvs, err := mapper.MapDataVolumes(&targetVMName, filesystemOverhead)
+
+		Expect(err).To(BeNil())
+		Expect(dvs).To(HaveLen(2))
+		for name, dv := range dvs {
+			if name != expectedDVName {
+				Expect(dv.Annotations[utils.AnnDataSource]).To(Equal("images/fedora"))
+				Expect(dv.Namespace).To(Equal("the-namespace"))
+			}
+		}
+	})
+})
+
+func preallocationMode(mode v2vv1.PreallocationMode) *v2vv1.PreallocationMode {
+	return &mode
+}
+
 var _ = Describe("Test mapping disks", func() {
 	var (
 		vm                 *ovirtsdk.Vm
@@ -619,7 +908,28 @@ var _ = Describe("Test mapping disks", func() {
 		table.Entry("virtio to virtio", ovirtsdk.DISKINTERFACE_VIRTIO),
 		table.Entry("sata to sata", ovirtsdk.DISKINTERFACE_SATA),
 		table.Entry("virtio_scsi to scsi", ovirtsdk.DISKINTERFACE_VIRTIO_SCSI),
+		table.Entry("ide to sata", ovirtsdk.DISKINTERFACE_IDE),
 	)
+
+	It("should map the disks of a converted guest to the virtio bus: ", func() {
+		vm := createVMGeneric(ovirtsdk.VMAFFINITY_MIGRATABLE, false, ovirtsdk.BIOSTYPE_Q35_SEA_BIOS, ovirtsdk.DISKINTERFACE_IDE)
+		vmSpec := &kubevirtv1.VirtualMachine{
+			ObjectMeta: v1.ObjectMeta{
+				Name: targetVMName,
+			},
+		}
+		vmSpec.Spec.Template = &kubevirtv1.VirtualMachineInstanceTemplateSpec{}
+		mappings := v2vv1.OvirtMappings{
+			StorageMappings: &[]v2vv1.StorageResourceMappingItem{},
+		}
+
+		mapper_ := mapper.NewOvirtMapper(vm, &mappings, mapper.DataVolumeCredentials{}, "", &osFinder)
+		mapper_.EnableGuestConversion()
+		dvs, _ := mapper_.MapDataVolumes(&targetVMName, filesystemOverhead)
+		mapper_.MapDisk(vmSpec, dvs[expectedDVName])
+
+		Expect(vmSpec.Spec.Template.Spec.Domain.Devices.Disks[0].Disk.Bus).To(Equal("virtio"))
+	})
 })
 
 func createVM() *ovirtsdk.Vm {
@@ -768,3 +1078,11 @@ type mockOsFinder struct{}
 func (o *mockOsFinder) FindOperatingSystem(vm *ovirtsdk.Vm) (string, error) {
 	return findOs(vm)
 }
+
+type mockStorageProfiles struct {
+	sets []storageprofiles.ClaimPropertySet
+}
+
+func (m *mockStorageProfiles) ClaimPropertySets(_ *string) ([]storageprofiles.ClaimPropertySet, error) {
+	return m.sets, nil
+}
@@ -14,6 +14,7 @@ func MergeMappings(externalMappingSpec *v2vv1.ResourceMappingSpec, vmiMapping *v
 
 	networkMappings := mappings.MergeNetworkMappings(primaryMappings.NetworkMappings, secondaryMappings.NetworkMappings)
 	storageMappings := mappings.MergeStorageMappings(primaryMappings.StorageMappings, secondaryMappings.StorageMappings)
+	cdromMappings := mappings.MergeCdromMappings(primaryMappings.CdromMappings, secondaryMappings.CdromMappings)
 
 	// diskMappings are expected to be provided only for a specific VM Import CR
 	diskMappings := primaryMappings.DiskMappings
@@ -22,6 +23,7 @@ func MergeMappings(externalMappingSpec *v2vv1.ResourceMappingSpec, vmiMapping *v
 		NetworkMappings: networkMappings,
 		StorageMappings: storageMappings,
 		DiskMappings:    diskMappings,
+		CdromMappings:   cdromMappings,
 	}
 	return &ovirtMappings
 }
@@ -392,6 +392,22 @@ var _ = Describe("Mappings merging ", func() {
 		Expect(*result.NetworkMappings).To(ConsistOf(i(&id1, &name1, &type1), i(&id3, &name3, &type1)))
 		Expect(*result.StorageMappings).To(ConsistOf(si(&id2, &name2, &type1), si(&id4, &name4, &type2)))
 	})
+	It("Should merge and override CD-ROM mappings when both present", func() {
+		mapping := v2vv1.OvirtMappings{
+			CdromMappings: &[]v2vv1.CdromResourceMappingItem{ci(&id1, &name1, "iso-pvc"), ci(&id3, &name3, "other-pvc")},
+		}
+		externalMapping := v2vv1.OvirtMappings{
+			CdromMappings: &[]v2vv1.CdromResourceMappingItem{ci(&id1, &name1, "library-pvc"), ci(&id4, &name4, "library-pvc")},
+		}
+
+		spec := v2vv1.ResourceMappingSpec{
+			OvirtMappings: &externalMapping,
+		}
+		result := mappings.MergeMappings(&spec, &mapping)
+
+		Expect(result).To(Not(BeNil()))
+		Expect(*result.CdromMappings).To(ConsistOf(ci(&id1, &name1, "iso-pvc"), ci(&id3, &name3, "other-pvc"), ci(&id4, &name4, "library-pvc")))
+	})
 })
 
 func i(id *string, name *string, tp *string) v2vv1.NetworkResourceMappingItem {
@@ -412,3 +428,15 @@ func si(id *string, name *string, volumeMode *string) v2vv1.StorageResourceMappi
 		},
 	}
 }
+
+func ci(id *string, name *string, target string) v2vv1.CdromResourceMappingItem {
+	return v2vv1.CdromResourceMappingItem{
+		Source: v2vv1.Source{
+			ID:   id,
+			Name: name,
+		},
+		Target: v2vv1.ObjectIdentifier{
+			Name: target,
+		},
+	}
+}
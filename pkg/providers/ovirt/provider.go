@@ -13,6 +13,7 @@ import (
 	kvConfig "github.com/kubevirt/vm-import-operator/pkg/config/kubevirt"
 
 	"github.com/kubevirt/vm-import-operator/pkg/ownerreferences"
+	"github.com/kubevirt/vm-import-operator/pkg/pods"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -24,6 +25,7 @@ import (
 	pclient "github.com/kubevirt/vm-import-operator/pkg/client"
 	"github.com/kubevirt/vm-import-operator/pkg/configmaps"
 	"github.com/kubevirt/vm-import-operator/pkg/datavolumes"
+	"github.com/kubevirt/vm-import-operator/pkg/guestconversion"
 	provider "github.com/kubevirt/vm-import-operator/pkg/providers"
 	"github.com/kubevirt/vm-import-operator/pkg/providers/ovirt/mapper"
 	"github.com/kubevirt/vm-import-operator/pkg/providers/ovirt/mappings"
@@ -31,6 +33,7 @@ import (
 	"github.com/kubevirt/vm-import-operator/pkg/providers/ovirt/validation"
 	"github.com/kubevirt/vm-import-operator/pkg/providers/ovirt/validation/validators"
 	"github.com/kubevirt/vm-import-operator/pkg/secrets"
+	"github.com/kubevirt/vm-import-operator/pkg/storageprofiles"
 	templates "github.com/kubevirt/vm-import-operator/pkg/templates"
 	"github.com/kubevirt/vm-import-operator/pkg/utils"
 	"github.com/kubevirt/vm-import-operator/pkg/virtualmachines"
@@ -43,6 +46,7 @@ import (
 	kubevirtv1 "kubevirt.io/client-go/api/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	rclient "sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 const (
@@ -51,18 +55,21 @@ const (
 	keyAccessKey   = "accessKeyId"
 	keySecretKey   = "secretKey"
 	diskNameFormat = "disk-%v"
+
+	snapshotImportSnapshotDescription = "VM Import Operator snapshot import"
 )
 
 var (
 	labels = map[string]string{
 		"origin": ovirtLabel,
 	}
+	log = logf.Log.WithName("ovirt-provider")
 )
 
 // OvirtProvider is Ovirt implementation of the Provider interface to support importing VM from ovirt
 type OvirtProvider struct {
 	ovirtSecretDataMap    map[string]string
-	ovirtClient           pclient.VMClient
+	ovirtClient           pclient.OvirtClient
 	validator             validation.VirtualMachineImportValidator
 	vm                    *ovirtsdk.Vm
 	vmiObjectMeta         metav1.ObjectMeta
@@ -73,10 +80,14 @@ type OvirtProvider struct {
 	templateHandler       *templates.TemplateHandler
 	secretsManager        provider.SecretsManager
 	configMapsManager     provider.ConfigMapsManager
+	podsManager           provider.PodsManager
 	datavolumesManager    provider.DataVolumesManager
 	virtualMachineManager provider.VirtualMachineManager
 	factory               pclient.Factory
 	instance              *v2vv1.VirtualMachineImport
+	conversionPod         v2vv1.ConversionPodSpec
+	conversionAllowList   ctrlConfig.ConversionPodAllowList
+	storageProfiles       storageprofiles.Finder
 }
 
 // NewOvirtProvider creates new OvirtProvider configured with dependencies
@@ -84,6 +95,7 @@ func NewOvirtProvider(vmiObjectMeta metav1.ObjectMeta, vmiTypeMeta metav1.TypeMe
 	validator := validators.NewValidatorWrapper(client, kvConfigProvider)
 	secretsManager := secrets.NewManager(client)
 	configMapsManager := configmaps.NewManager(client)
+	podsManager := pods.NewManager(client)
 	datavolumesManager := datavolumes.NewManager(client)
 	virtualMachineManager := virtualmachines.NewManager(client)
 	templateProvider := templates.NewTemplateProvider(tempClient)
@@ -97,9 +109,13 @@ func NewOvirtProvider(vmiObjectMeta metav1.ObjectMeta, vmiTypeMeta metav1.TypeMe
 		templateHandler:       templates.NewTemplateHandler(templateProvider),
 		secretsManager:        &secretsManager,
 		configMapsManager:     &configMapsManager,
+		podsManager:           &podsManager,
 		datavolumesManager:    &datavolumesManager,
 		virtualMachineManager: &virtualMachineManager,
 		factory:               factory,
+		conversionPod:         ctrlConfig.ConversionPod(),
+		conversionAllowList:   ctrlConfig.ConversionPodAllowList(),
+		storageProfiles:       storageprofiles.NewStorageProfiles(client),
 	}
 }
 
@@ -145,11 +161,13 @@ func (o *OvirtProvider) Init(secret *corev1.Secret, instance *v2vv1.VirtualMachi
 	if len(o.ovirtSecretDataMap["password"]) == 0 {
 		return fmt.Errorf("oVirt secret password cannot be empty")
 	}
-	if _, ok := o.ovirtSecretDataMap["caCert"]; !ok {
-		return fmt.Errorf("oVirt secret must contain caCert attribute")
-	}
-	if len(o.ovirtSecretDataMap["caCert"]) == 0 {
-		return fmt.Errorf("oVirt secret caCert cannot be empty")
+	if insecure, ok := o.ovirtSecretDataMap["insecureSkipVerify"]; ok {
+		if insecure != "true" && insecure != "false" {
+			return fmt.Errorf("oVirt secret insecureSkipVerify must be either true or false")
+		}
+		if insecure == "true" && len(o.ovirtSecretDataMap["caCert"]) > 0 {
+			return fmt.Errorf("oVirt secret insecureSkipVerify cannot be combined with caCert")
+		}
 	}
 	o.instance = instance
 	return nil
@@ -168,7 +186,7 @@ func (o *OvirtProvider) TestConnection() error {
 	return nil
 }
 
-func (o *OvirtProvider) getClient() (pclient.VMClient, error) {
+func (o *OvirtProvider) getClient() (pclient.OvirtClient, error) {
 	if o.ovirtClient == nil {
 		client, err := o.factory.NewOvirtClient(o.ovirtSecretDataMap)
 		if err != nil {
@@ -199,10 +217,10 @@ func (o *OvirtProvider) GetVMName() (string, error) {
 	return vmName, nil
 }
 
-// Close the connection to ovirt provider
+// Close releases the connection to ovirt provider
 func (o *OvirtProvider) Close() {
 	if o.ovirtClient != nil {
-		o.ovirtClient.Close()
+		_ = o.factory.Release(o.ovirtClient)
 	}
 }
 
@@ -229,6 +247,29 @@ func (o *OvirtProvider) LoadVM(sourceSpec v2vv1.VirtualMachineImportSourceSpec)
 	return nil
 }
 
+// SelectVMs lists the source VMs matching the oVirt search query
+func (o *OvirtProvider) SelectVMs(search string) ([]provider.SelectedVM, error) {
+	client, err := o.getClient()
+	if err != nil {
+		return nil, err
+	}
+	vms, err := client.ListVMs(search)
+	if err != nil {
+		return nil, err
+	}
+	selected := make([]provider.SelectedVM, 0, len(vms))
+	for _, vm := range vms {
+		selectedVM := provider.SelectedVM{}
+		selectedVM.ID, _ = vm.Id()
+		selectedVM.Name, _ = vm.Name()
+		if cluster, ok := vm.Cluster(); ok {
+			selectedVM.Cluster, _ = cluster.Name()
+		}
+		selected = append(selected, selectedVM)
+	}
+	return selected, nil
+}
+
 // PrepareResourceMapping merges external resource mapping and resource mapping provided in the virtual machine import spec
 func (o *OvirtProvider) PrepareResourceMapping(externalResourceMapping *v2vv1.ResourceMappingSpec, vmiSpec v2vv1.VirtualMachineImportSourceSpec) {
 	o.resourceMapping = mappings.MergeMappings(externalResourceMapping, vmiSpec.Ovirt.Mappings)
@@ -242,6 +283,11 @@ func (o *OvirtProvider) ValidateDiskStatus(dv cdiv1.DataVolume) (bool, error) {
 		return false, err
 	}
 
+	// The ISO images of the CD-ROMs are imported from their disks, or cloned, as they are:
+	if dv.Spec.Source.Imageio == nil || isCdromImage(o.vm, dv.Spec.Source.Imageio.DiskID) {
+		return true, nil
+	}
+
 	// Find the disk by ID and validate the status:
 	if diskAttachments, ok := o.vm.DiskAttachments(); ok {
 		for _, da := range diskAttachments.Slice() {
@@ -268,11 +314,93 @@ func (o *OvirtProvider) Validate() ([]v2vv1.VirtualMachineImportCondition, error
 		return []v2vv1.VirtualMachineImportCondition{}, errors.New("VM has not been loaded")
 	}
 	vmiName := o.GetVmiNamespacedName()
-	return o.validator.Validate(vm, &vmiName, o.resourceMapping, o.templateFinder), nil
+	selectedVM, excludedDisks := selectDisks(vm, o.instance.Spec.Disks)
+	validationResults := o.validator.Validate(selectedVM, &vmiName, o.resourceMapping, o.templateFinder)
+	validationResults = provider.WithExcludedDisksWarning(validationResults, excludedDisks)
+	isoDisks, err := o.isoDisks(vm)
+	if err != nil {
+		return nil, err
+	}
+	return provider.WithUnmappedCdromsWarning(validationResults, mapper.UnmappedCdroms(vm, o.resourceMapping, isoDisks)), nil
 }
 
-// StopVM stop the source VM on ovirt
-func (o *OvirtProvider) StopVM(instance *v2vv1.VirtualMachineImport, rclient rclient.Client) error {
+func isCdromImage(vm *ovirtsdk.Vm, diskID string) bool {
+	cdroms, ok := vm.Cdroms()
+	if !ok {
+		return false
+	}
+	for _, cdrom := range cdroms.Slice() {
+		if file, ok := cdrom.File(); ok {
+			if id, ok := file.Id(); ok && id != "" && id == diskID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isoDisks returns the data domain disks holding the ISO images of the CD-ROMs of the VM, keyed by the ID of the
+// ISO image. The images of an ISO domain have no disk.
+func (o *OvirtProvider) isoDisks(vm *ovirtsdk.Vm) (map[string]*ovirtsdk.Disk, error) {
+	isoDisks := make(map[string]*ovirtsdk.Disk)
+	cdroms, ok := vm.Cdroms()
+	if !ok {
+		return isoDisks, nil
+	}
+	for _, cdrom := range cdroms.Slice() {
+		file, ok := cdrom.File()
+		if !ok {
+			continue
+		}
+		id, ok := file.Id()
+		if !ok || id == "" {
+			continue
+		}
+		client, err := o.getClient()
+		if err != nil {
+			return nil, err
+		}
+		disk, err := client.GetDisk(id)
+		if err != nil {
+			return nil, err
+		}
+		if disk != nil {
+			isoDisks[id] = disk
+		}
+	}
+	return isoDisks, nil
+}
+
+// selectDisks returns a copy of the VM with only the disk attachments selected for the import, so that the excluded
+// disks aren't validated, and the names of the excluded disks
+func selectDisks(vm *ovirtsdk.Vm, selection *v2vv1.DiskSelectionSpec) (*ovirtsdk.Vm, []string) {
+	attachments, ok := vm.DiskAttachments()
+	if selection == nil || !ok {
+		return vm, nil
+	}
+	var selected []*ovirtsdk.DiskAttachment
+	var excluded []string
+	for _, attachment := range attachments.Slice() {
+		sourceDisk := mapper.SourceDiskOf(attachment)
+		if provider.DiskSelected(selection, sourceDisk) {
+			selected = append(selected, attachment)
+			continue
+		}
+		name := sourceDisk.ID
+		if len(sourceDisk.Names) > 0 {
+			name = sourceDisk.Names[len(sourceDisk.Names)-1]
+		}
+		excluded = append(excluded, name)
+	}
+	selectedAttachments := &ovirtsdk.DiskAttachmentSlice{}
+	selectedAttachments.SetSlice(selected)
+	selectedVM := *vm
+	selectedVM.SetDiskAttachments(selectedAttachments)
+	return &selectedVM, excluded
+}
+
+// StopVM requests the shut down of the source VM on ovirt with the given method, without waiting for it to be down
+func (o *OvirtProvider) StopVM(instance *v2vv1.VirtualMachineImport, rclient rclient.Client, method v2vv1.SourceShutdownMethod) error {
 	vm, err := o.getVM()
 	if err != nil {
 		return err
@@ -286,7 +414,7 @@ func (o *OvirtProvider) StopVM(instance *v2vv1.VirtualMachineImport, rclient rcl
 	if err != nil {
 		return err
 	}
-	err = client.StopVM(vmID)
+	err = client.StopVM(vmID, method)
 	if err != nil {
 		return err
 	}
@@ -340,7 +468,22 @@ func (o *OvirtProvider) CreateMapper() (provider.Mapper, error) {
 	if err != nil {
 		return nil, err
 	}
-	return mapper.NewOvirtMapper(vm, o.resourceMapping, credentials, o.vmiObjectMeta.Namespace, o.osFinder), nil
+	ovirtMapper := mapper.NewOvirtMapper(vm, o.resourceMapping, credentials, o.vmiObjectMeta.Namespace, o.osFinder)
+	ovirtMapper.UseStorageProfiles(o.storageProfiles)
+	ovirtMapper.SelectDisks(o.instance.Spec.Disks)
+	isoDisks, err := o.isoDisks(vm)
+	if err != nil {
+		return nil, err
+	}
+	ovirtMapper.UseISODisks(isoDisks)
+	convertGuest, err := o.NeedsGuestConversion()
+	if err != nil {
+		return nil, err
+	}
+	if convertGuest {
+		ovirtMapper.EnableGuestConversion()
+	}
+	return ovirtMapper, nil
 }
 
 // StartVM starts the source VM
@@ -382,6 +525,23 @@ func (o *OvirtProvider) CleanUp(failure bool, cr *v2vv1.VirtualMachineImport, cl
 		errs = append(errs, err)
 	}
 
+	// remove the snapshot that was created for snapshot import
+	if cr.Status.SourceSnapshot != nil {
+		err = o.RemoveVMSnapshot(*cr.Status.SourceSnapshot, false)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// keep the conversion pod around if it failed or the annotation was set
+	_, found := cr.Annotations[guestconversion.AnnRetainConversionPod]
+	if !(failure || found) {
+		err = o.podsManager.DeleteFor(vmiName)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	if failure {
 		err = o.datavolumesManager.DeleteFor(vmiName)
 		if err != nil {
@@ -402,34 +562,63 @@ func (o *OvirtProvider) CleanUp(failure bool, cr *v2vv1.VirtualMachineImport, cl
 	return nil
 }
 
-// NeedsGuestConversion indicates whether a VM from this provider must undergo guest conversion.
-func (o *OvirtProvider) NeedsGuestConversion() bool {
+// SupportsWarmMigration returns whether this provider supports warm migrations.
+func (o *OvirtProvider) SupportsWarmMigration() bool {
 	return false
 }
 
-// GetGuestConversionJob is not implemented.
-func (o *OvirtProvider) GetGuestConversionPod() (*corev1.Pod, error) {
-	return nil, nil
-}
-
-// LaunchGuestConversionJob is not implemented.
-func (o *OvirtProvider) LaunchGuestConversionPod(_ *kubevirtv1.VirtualMachine, _ map[string]cdiv1.DataVolume) (*corev1.Pod, error) {
-	return nil, nil
+// SupportsSnapshotImport returns whether this provider supports importing the disks from a snapshot of the running VM.
+func (o *OvirtProvider) SupportsSnapshotImport() bool {
+	return true
 }
 
-// SupportsWarmMigration returns whether this provider supports warm migrations.
-func (o *OvirtProvider) SupportsWarmMigration() bool {
-	return false
+// CreateVMSnapshot creates a snapshot of the disks of the VM for a snapshot import. The snapshot is described
+// after the import, so that it is found again if the import is retried before the snapshot is recorded.
+func (o *OvirtProvider) CreateVMSnapshot() (string, error) {
+	vm, err := o.getVM()
+	if err != nil {
+		return "", err
+	}
+	client, err := o.getClient()
+	if err != nil {
+		return "", err
+	}
+	description := fmt.Sprintf("%s %s", snapshotImportSnapshotDescription, o.vmiObjectMeta.UID)
+	return client.CreateVMSnapshot(vm.MustId(), description)
 }
 
-// CreateVMSnapshot is not implemented.
-func (o *OvirtProvider) CreateVMSnapshot() (string, error) {
-	return "", nil
+// VMSnapshotReady tells whether the snapshot of the VM is ready, the disks of the VM are locked until it is.
+func (o *OvirtProvider) VMSnapshotReady(snapshotID string) (bool, error) {
+	vm, err := o.getVM()
+	if err != nil {
+		return false, err
+	}
+	client, err := o.getClient()
+	if err != nil {
+		return false, err
+	}
+	snapshot, err := client.GetVMSnapshot(vm.MustId(), snapshotID)
+	if err != nil {
+		return false, err
+	}
+	if snapshot == nil {
+		return false, fmt.Errorf("snapshot %s of vm %s doesn't exist", snapshotID, vm.MustId())
+	}
+	status, _ := snapshot.SnapshotStatus()
+	return status == ovirtsdk.SNAPSHOTSTATUS_OK, nil
 }
 
-// RemoveVMSnapshot is not implemented.
-func (o *OvirtProvider) RemoveVMSnapshot(_ string, _ bool) error {
-	return nil
+// RemoveVMSnapshot removes the snapshot of the VM. oVirt snapshots have no children to remove.
+func (o *OvirtProvider) RemoveVMSnapshot(snapshotID string, _ bool) error {
+	vm, err := o.getVM()
+	if err != nil {
+		return err
+	}
+	client, err := o.getClient()
+	if err != nil {
+		return err
+	}
+	return client.RemoveVMSnapshot(vm.MustId(), snapshotID)
 }
 
 func (o *OvirtProvider) prepareDataVolumeCredentials() (mapper.DataVolumeCredentials, error) {
@@ -440,7 +629,10 @@ func (o *OvirtProvider) prepareDataVolumeCredentials() (mapper.DataVolumeCredent
 		return mapper.DataVolumeCredentials{}, err
 	}
 
-	caCert := o.ovirtSecretDataMap["caCert"]
+	caCert, err := o.engineCA()
+	if err != nil {
+		return mapper.DataVolumeCredentials{}, err
+	}
 	configMap, err := o.ensureConfigMapIsPresent(caCert)
 	if err != nil {
 		return mapper.DataVolumeCredentials{}, err
@@ -456,6 +648,19 @@ func (o *OvirtProvider) prepareDataVolumeCredentials() (mapper.DataVolumeCredent
 	}, nil
 }
 
+// engineCA returns the CA certificate the imageio daemons are verified with. CDI can't use the system CA
+// certificates, so the CA certificate of the engine is downloaded when the secret doesn't provide one.
+func (o *OvirtProvider) engineCA() (string, error) {
+	if caCert := o.ovirtSecretDataMap["caCert"]; len(caCert) > 0 {
+		return caCert, nil
+	}
+	client, err := o.getClient()
+	if err != nil {
+		return "", err
+	}
+	return client.GetEngineCA()
+}
+
 func (o *OvirtProvider) ensureSecretIsPresent(keyAccess string, keySecret string) (*corev1.Secret, error) {
 	secret, err := o.secretsManager.FindFor(o.GetVmiNamespacedName())
 	if err != nil {
@@ -466,6 +671,16 @@ func (o *OvirtProvider) ensureSecretIsPresent(keyAccess string, keySecret string
 		if err != nil {
 			return nil, err
 		}
+	} else if string(secret.Data[keyAccessKey]) != keyAccess || string(secret.Data[keySecretKey]) != keySecret {
+		// the credentials were rotated, the importer pods created from now on have to use the new ones
+		secret.Data = map[string][]byte{
+			keyAccessKey: []byte(keyAccess),
+			keySecretKey: []byte(keySecret),
+		}
+		err = o.secretsManager.Update(secret)
+		if err != nil {
+			return nil, err
+		}
 	}
 	return secret, nil
 }
@@ -497,6 +712,14 @@ func (o *OvirtProvider) ensureConfigMapIsPresent(caCert string) (*corev1.ConfigM
 		if err != nil {
 			return nil, err
 		}
+	} else if configMap.Data["ca.pem"] != caCert {
+		configMap.Data = map[string]string{
+			"ca.pem": caCert,
+		}
+		err = o.configMapsManager.Update(configMap)
+		if err != nil {
+			return nil, err
+		}
 	}
 	return configMap, nil
 }
@@ -49,6 +49,7 @@ var _ = Describe("Validating Disk Attachment", func() {
 		table.Entry("virtio", "virtio"),
 		table.Entry("sata", "sata"),
 		table.Entry("virtio_scsi", "virtio_scsi"),
+		table.Entry("ide", "ide"),
 	)
 	It("should flag disk attachment with logical name: ", func() {
 		attachment := newDiskAttachment()
@@ -105,7 +106,6 @@ var _ = Describe("Validating Disk", func() {
 		Expect(failures).To(HaveLen(1))
 		Expect(failures[0].ID).To(Equal(validators.DiskInterfaceID))
 	},
-		table.Entry("ide", "ide"),
 		table.Entry("spapr_vscsi", "spapr_vscsi"),
 
 		table.Entry("garbage", "123saas-#$#@"),
@@ -124,6 +124,7 @@ var _ = Describe("Validating Disk", func() {
 		table.Entry("virtio", "virtio"),
 		table.Entry("sata", "sata"),
 		table.Entry("virtio_scsi", "virtio_scsi"),
+		table.Entry("ide", "ide"),
 	)
 	It("should flag disk with logical name: ", func() {
 		disk := newDisk()
@@ -624,7 +624,7 @@ func isValidFloppies(vm *ovirtsdk.Vm) (ValidationFailure, bool) {
 	if floppies, ok := vm.Floppies(); ok && len(floppies.Slice()) > 0 {
 		return ValidationFailure{
 			ID:      VMFloppiesID,
-			Message: fmt.Sprintf("VM uses %d floppies", len(floppies.Slice())),
+			Message: fmt.Sprintf("VM uses %d floppies, they have no KubeVirt counterpart and aren't imported", len(floppies.Slice())),
 		}, false
 	}
 	return ValidationFailure{}, true
@@ -95,7 +95,7 @@ var checkToAction = map[validators.CheckID]action{
 	validators.VMQuotaID:                         log,
 	validators.VMWatchdogsID:                     block,
 	validators.VMCdromsID:                        log,
-	validators.VMFloppiesID:                      log,
+	validators.VMFloppiesID:                      warn,
 	validators.VMTimezoneID:                      block,
 	// Network mapping validation
 	validators.NetworkConfig:               block,
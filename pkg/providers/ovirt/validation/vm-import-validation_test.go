@@ -100,7 +100,6 @@ var _ = Describe("Validating VirtualMachineImport Admitter", func() {
 		table.Entry("Reported devices", validators.VMReportedDevicesID),
 		table.Entry("Quote", validators.VMQuotaID),
 		table.Entry("CD roms", validators.VMCdromsID),
-		table.Entry("Floppies", validators.VMFloppiesID),
 	)
 	table.DescribeTable("should accept VirtualMachineImport spec with VM warning for ", func(checkId validators.CheckID) {
 		message := "Some warning"
@@ -127,6 +126,7 @@ var _ = Describe("Validating VirtualMachineImport Admitter", func() {
 		table.Entry("NUMA tune mode", validators.VMNumaTuneModeID),
 		table.Entry("Sound card", validators.VMSoundcardEnabledID),
 		table.Entry("Tunnel migration", validators.VMTunnelMigrationID),
+		table.Entry("Floppies", validators.VMFloppiesID),
 	)
 	table.DescribeTable("should reject VirtualMachineImport spec for ", func(checkId validators.CheckID) {
 		message := "Blocked!"
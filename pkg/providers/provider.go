@@ -1,7 +1,10 @@
 package provider
 
 import (
+	"time"
+
 	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/guestconversion"
 	oapiv1 "github.com/openshift/api/template/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -17,6 +20,27 @@ const (
 	VMStatusUp VMStatus = "up"
 )
 
+// DefaultSourceShutdownTimeout is how long the source VM is given to shut down when the import doesn't specify it
+const DefaultSourceShutdownTimeout = 5 * time.Minute
+
+// SourceShutdownOf returns how the source VM of the import is shut down: the method, the timeout and whether
+// it is powered off after the timeout, with the defaults applied.
+func SourceShutdownOf(instance *v2vv1.VirtualMachineImport) (v2vv1.SourceShutdownMethod, time.Duration, bool) {
+	method := v2vv1.GuestShutdown
+	timeout := DefaultSourceShutdownTimeout
+	shutdown := instance.Spec.SourceShutdown
+	if shutdown == nil {
+		return method, timeout, false
+	}
+	if shutdown.Method != nil {
+		method = *shutdown.Method
+	}
+	if shutdown.Timeout != nil {
+		timeout = shutdown.Timeout.Duration
+	}
+	return method, timeout, shutdown.Force
+}
+
 // Provider defines the methods required by source providers for importing a VM
 type Provider interface {
 	Init(*corev1.Secret, *v2vv1.VirtualMachineImport) error
@@ -26,7 +50,7 @@ type Provider interface {
 	PrepareResourceMapping(*v2vv1.ResourceMappingSpec, v2vv1.VirtualMachineImportSourceSpec)
 	Validate() ([]v2vv1.VirtualMachineImportCondition, error)
 	ValidateDiskStatus(cdiv1.DataVolume) (bool, error)
-	StopVM(*v2vv1.VirtualMachineImport, rclient.Client) error
+	StopVM(*v2vv1.VirtualMachineImport, rclient.Client, v2vv1.SourceShutdownMethod) error
 	CreateMapper() (Mapper, error)
 	GetVMStatus() (VMStatus, error)
 	GetVMName() (string, error)
@@ -34,11 +58,13 @@ type Provider interface {
 	CleanUp(bool, *v2vv1.VirtualMachineImport, rclient.Client) error
 	FindTemplate() (*oapiv1.Template, error)
 	ProcessTemplate(*oapiv1.Template, *string, string) (*kubevirtv1.VirtualMachine, error)
-	NeedsGuestConversion() bool
+	NeedsGuestConversion() (bool, error)
 	GetGuestConversionPod() (*corev1.Pod, error)
-	LaunchGuestConversionPod(*kubevirtv1.VirtualMachine, map[string]cdiv1.DataVolume) (*corev1.Pod, error)
+	LaunchGuestConversionPod(*kubevirtv1.VirtualMachine, map[string]cdiv1.DataVolume, *guestconversion.GuestCustomization) (*corev1.Pod, error)
 	SupportsWarmMigration() bool
+	SupportsSnapshotImport() bool
 	CreateVMSnapshot() (string, error)
+	VMSnapshotReady(string) (bool, error)
 	RemoveVMSnapshot(string, bool) error
 }
 
@@ -59,6 +85,7 @@ type VMStatus string
 type SecretsManager interface {
 	FindFor(types.NamespacedName) (*corev1.Secret, error)
 	CreateFor(*corev1.Secret, types.NamespacedName) error
+	Update(*corev1.Secret) error
 	DeleteFor(types.NamespacedName) error
 }
 
@@ -66,6 +93,7 @@ type SecretsManager interface {
 type ConfigMapsManager interface {
 	FindFor(types.NamespacedName) (*corev1.ConfigMap, error)
 	CreateFor(*corev1.ConfigMap, types.NamespacedName) error
+	Update(*corev1.ConfigMap) error
 	DeleteFor(types.NamespacedName) error
 }
 
@@ -87,3 +115,28 @@ type PodsManager interface {
 	CreateFor(*corev1.Pod, types.NamespacedName) error
 	DeleteFor(types.NamespacedName) error
 }
+
+// AmbiguousSourceVMError is returned when the source VM identity matches more than one VM
+type AmbiguousSourceVMError struct {
+	Err error
+}
+
+func (e AmbiguousSourceVMError) Error() string {
+	return e.Err.Error()
+}
+
+func (e AmbiguousSourceVMError) Unwrap() error {
+	return e.Err
+}
+
+// VMSelector is implemented by providers which can select several source VMs at once
+type VMSelector interface {
+	SelectVMs(search string) ([]SelectedVM, error)
+}
+
+// SelectedVM identifies a source VM matched by a VMSelector
+type SelectedVM struct {
+	ID      string
+	Name    string
+	Cluster string
+}
@@ -0,0 +1,90 @@
+package pvcprovider
+
+import (
+	"encoding/xml"
+
+	"github.com/kubevirt/vm-import-operator/pkg/guestconversion"
+	"github.com/kubevirt/vm-import-operator/pkg/ownerreferences"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+)
+
+// NeedsGuestConversion always converts the guest, it is the only change made to the disks
+func (p *PVCProvider) NeedsGuestConversion() (bool, error) {
+	return true, nil
+}
+
+// GetGuestConversionPod returns the conversion pod of the import, if it was launched
+func (p *PVCProvider) GetGuestConversionPod() (*corev1.Pod, error) {
+	if p.sourceVMProvider != nil {
+		return p.sourceVMProvider.GetGuestConversionPod()
+	}
+	return p.podsManager.FindFor(p.getVmiNamespacedName())
+}
+
+// LaunchGuestConversionPod creates the virt-v2v pod converting the guest on the persistent volume claims, unless it
+// exists
+func (p *PVCProvider) LaunchGuestConversionPod(vmSpec *kubevirtv1.VirtualMachine, dataVolumes map[string]cdiv1.DataVolume, customization *guestconversion.GuestCustomization) (*corev1.Pod, error) {
+	if p.sourceVMProvider != nil {
+		return p.sourceVMProvider.LaunchGuestConversionPod(vmSpec, dataVolumes, customization)
+	}
+	configMap, err := p.ensureLibvirtDomainIsPresent(vmSpec, dataVolumes)
+	if err != nil {
+		return nil, err
+	}
+	pod, err := p.podsManager.FindFor(p.getVmiNamespacedName())
+	if err != nil {
+		return nil, err
+	}
+	if pod != nil {
+		return pod, nil
+	}
+
+	settings, err := guestconversion.MergeConversionPodSettings(p.conversionPod, p.instance.Spec.ConversionPod, p.conversionAllowList)
+	if err != nil {
+		return nil, err
+	}
+	pod, err = guestconversion.MakeGuestConversionPodSpec(vmSpec, dataVolumes, configMap, settings, customization)
+	if err != nil {
+		return nil, err
+	}
+	pod.OwnerReferences = []metav1.OwnerReference{
+		ownerreferences.NewVMImportControllerReference(p.vmiTypeMeta, p.vmiObjectMeta),
+	}
+	err = p.podsManager.CreateFor(pod, p.getVmiNamespacedName())
+	if err != nil {
+		return nil, err
+	}
+	return pod, nil
+}
+
+// ensureLibvirtDomainIsPresent creates the config map of the import holding the libvirt domain of the VM
+func (p *PVCProvider) ensureLibvirtDomainIsPresent(vmSpec *kubevirtv1.VirtualMachine, dataVolumes map[string]cdiv1.DataVolume) (*corev1.ConfigMap, error) {
+	configMap, err := p.configMapsManager.FindFor(p.getVmiNamespacedName())
+	if err != nil {
+		return nil, err
+	}
+	if configMap != nil {
+		return configMap, nil
+	}
+
+	domXML, err := xml.Marshal(guestconversion.MakeLibvirtDomain(vmSpec, dataVolumes))
+	if err != nil {
+		return nil, err
+	}
+	configMap = &corev1.ConfigMap{
+		BinaryData: map[string][]byte{
+			guestconversion.LibvirtDomainKey: domXML,
+		},
+	}
+	configMap.OwnerReferences = []metav1.OwnerReference{
+		ownerreferences.NewVMImportOwnerReference(p.vmiTypeMeta, p.vmiObjectMeta),
+	}
+	err = p.configMapsManager.CreateFor(configMap, p.getVmiNamespacedName())
+	if err != nil {
+		return nil, err
+	}
+	return configMap, nil
+}
@@ -0,0 +1,207 @@
+package pvcprovider
+
+import (
+	"fmt"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	provider "github.com/kubevirt/vm-import-operator/pkg/providers"
+	"github.com/kubevirt/vm-import-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+)
+
+const (
+	busTypeVirtio = "virtio"
+)
+
+// PVCMapper maps a VM onto the persistent volume claims holding its disks. The rest of the VM is mapped by the mapper
+// of the source VM or from the hardware described by the import.
+type PVCMapper struct {
+	instance       *v2vv1.VirtualMachineImport
+	claims         map[string]*corev1.PersistentVolumeClaim
+	sourceVMMapper provider.Mapper
+}
+
+// NewPVCMapper creates a new PVCMapper. The source VM mapper is nil when the import describes the hardware of the VM.
+func NewPVCMapper(instance *v2vv1.VirtualMachineImport, claims map[string]*corev1.PersistentVolumeClaim, sourceVMMapper provider.Mapper) *PVCMapper {
+	return &PVCMapper{
+		instance:       instance,
+		claims:         claims,
+		sourceVMMapper: sourceVMMapper,
+	}
+}
+
+// CreateEmptyVM creates an empty VM
+func (p *PVCMapper) CreateEmptyVM(vmName *string) *kubevirtv1.VirtualMachine {
+	if p.sourceVMMapper != nil {
+		return p.sourceVMMapper.CreateEmptyVM(vmName)
+	}
+	return &kubevirtv1.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				"app": *vmName,
+			},
+		},
+		Spec: kubevirtv1.VirtualMachineSpec{
+			Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"kubevirt.io/domain":  *vmName,
+						"vm.kubevirt.io/name": *vmName,
+					},
+				},
+				Spec: kubevirtv1.VirtualMachineInstanceSpec{
+					Domain: kubevirtv1.DomainSpec{},
+				},
+			},
+		},
+	}
+}
+
+// ResolveVMName resolves the name of the VM from the source VM or, without it, from the name of the import
+func (p *PVCMapper) ResolveVMName(targetVMName *string) *string {
+	if p.sourceVMMapper != nil {
+		return p.sourceVMMapper.ResolveVMName(targetVMName)
+	}
+	name := p.instance.Name
+	if targetVMName != nil {
+		name = *targetVMName
+	}
+	// VM name is put in label values and has to be shorter than regular k8s name
+	name = utils.EnsureLabelValueLength(name)
+	return &name
+}
+
+// MapVM maps the source VM or the described hardware to the VM definition, and attaches the persistent volume claims
+// holding the disks to it. The VM isn't started until the guest is converted.
+func (p *PVCMapper) MapVM(targetVMName *string, vmSpec *kubevirtv1.VirtualMachine) (*kubevirtv1.VirtualMachine, error) {
+	var err error
+	if p.sourceVMMapper != nil {
+		vmSpec, err = p.sourceVMMapper.MapVM(targetVMName, vmSpec)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		p.mapHardware(targetVMName, vmSpec)
+	}
+
+	for i, disk := range p.instance.Spec.Source.PVC.Disks {
+		p.mapClaim(vmSpec, disk, i == 0)
+	}
+
+	running := false
+	vmSpec.Spec.Running = &running
+	return vmSpec, nil
+}
+
+// mapHardware maps the hardware described by the import
+func (p *PVCMapper) mapHardware(targetVMName *string, vmSpec *kubevirtv1.VirtualMachine) {
+	hardware := p.instance.Spec.Source.PVC.Hardware
+	if vmSpec.Spec.Template == nil {
+		vmSpec.Spec.Template = &kubevirtv1.VirtualMachineInstanceTemplateSpec{}
+	}
+	vmSpec.ObjectMeta.Namespace = p.instance.Namespace
+	if targetVMName != nil {
+		vmSpec.ObjectMeta.Name = *targetVMName
+	}
+
+	cpu := &kubevirtv1.CPU{Sockets: 1, Cores: 1}
+	if hardware.Sockets != nil {
+		cpu.Sockets = uint32(*hardware.Sockets)
+	}
+	if hardware.Cores != nil {
+		cpu.Cores = uint32(*hardware.Cores)
+	}
+	domain := &vmSpec.Spec.Template.Spec.Domain
+	domain.CPU = cpu
+
+	if domain.Resources.Requests == nil {
+		domain.Resources.Requests = corev1.ResourceList{}
+	}
+	domain.Resources.Requests[corev1.ResourceMemory] = hardware.Memory
+
+	firmware := &kubevirtv1.Firmware{
+		Bootloader: &kubevirtv1.Bootloader{BIOS: &kubevirtv1.BIOS{}},
+	}
+	if hardware.Firmware != nil && *hardware.Firmware == v2vv1.EFIFirmware {
+		secureBoot := hardware.SecureBoot
+		firmware.Bootloader = &kubevirtv1.Bootloader{EFI: &kubevirtv1.EFI{SecureBoot: &secureBoot}}
+		if secureBoot {
+			// Secure Boot requires SMM to be enabled
+			smmEnabled := true
+			if domain.Features == nil {
+				domain.Features = &kubevirtv1.Features{}
+			}
+			domain.Features.SMM = &kubevirtv1.FeatureState{Enabled: &smmEnabled}
+		}
+	}
+	domain.Firmware = firmware
+	domain.Devices.Disks = []kubevirtv1.Disk{}
+	vmSpec.Spec.Template.Spec.Volumes = []kubevirtv1.Volume{}
+}
+
+// mapClaim attaches a persistent volume claim holding a disk to the VM, the VM boots from the first one
+func (p *PVCMapper) mapClaim(vmSpec *kubevirtv1.VirtualMachine, pvcDisk v2vv1.PVCDisk, bootable bool) {
+	name := utils.EnsureLabelValueLength(fmt.Sprintf("pvc-%v", pvcDisk.ClaimName))
+	volume := kubevirtv1.Volume{
+		Name: name,
+		VolumeSource: kubevirtv1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: pvcDisk.ClaimName,
+			},
+		},
+	}
+
+	bus := busTypeVirtio
+	if pvcDisk.Bus != nil {
+		bus = *pvcDisk.Bus
+	}
+	disk := kubevirtv1.Disk{
+		Name: name,
+		DiskDevice: kubevirtv1.DiskDevice{
+			Disk: &kubevirtv1.DiskTarget{
+				Bus: bus,
+			},
+		},
+	}
+	if bootable {
+		bootOrder := uint(1)
+		disk.BootOrder = &bootOrder
+	}
+
+	vmSpec.Spec.Template.Spec.Volumes = append(vmSpec.Spec.Template.Spec.Volumes, volume)
+	vmSpec.Spec.Template.Spec.Domain.Devices.Disks = append(vmSpec.Spec.Template.Spec.Domain.Devices.Disks, disk)
+}
+
+// MapDataVolumes describes the persistent volume claims holding the disks as data volumes, keyed by the claim names,
+// for the guest conversion. The data volumes aren't created, the disks are already populated.
+func (p *PVCMapper) MapDataVolumes(_ *string, _ cdiv1.FilesystemOverhead) (map[string]cdiv1.DataVolume, error) {
+	dvs := make(map[string]cdiv1.DataVolume, len(p.instance.Spec.Source.PVC.Disks))
+	for _, disk := range p.instance.Spec.Source.PVC.Disks {
+		claim, found := p.claims[disk.ClaimName]
+		if !found {
+			return nil, fmt.Errorf("persistent volume claim %s not found", disk.ClaimName)
+		}
+		dvs[disk.ClaimName] = cdiv1.DataVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      claim.Name,
+				Namespace: claim.Namespace,
+			},
+			Spec: cdiv1.DataVolumeSpec{
+				PVC: claim.Spec.DeepCopy(),
+			},
+		}
+	}
+	return dvs, nil
+}
+
+// MapDisk does nothing, the persistent volume claims are attached to the VM when it is mapped
+func (p *PVCMapper) MapDisk(_ *kubevirtv1.VirtualMachine, _ cdiv1.DataVolume) {
+}
+
+// RunningState returns false, the VM is started after the guest is converted
+func (p *PVCMapper) RunningState() bool {
+	return false
+}
@@ -0,0 +1,82 @@
+package pvcprovider
+
+import (
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Mapping a VM onto persistent volume claims", func() {
+	var (
+		instance *v2vv1.VirtualMachineImport
+		claims   map[string]*corev1.PersistentVolumeClaim
+	)
+
+	BeforeEach(func() {
+		instance = newPVCImport()
+		instance.Spec.Source.PVC.Disks = append(instance.Spec.Source.PVC.Disks, v2vv1.PVCDisk{ClaimName: "disk-2"})
+		claims = map[string]*corev1.PersistentVolumeClaim{
+			"disk-1": newClaim("disk-1", corev1.PersistentVolumeFilesystem),
+			"disk-2": newClaim("disk-2", corev1.PersistentVolumeBlock),
+		}
+	})
+
+	It("should map the described hardware", func() {
+		sockets := int32(2)
+		firmware := v2vv1.EFIFirmware
+		instance.Spec.Source.PVC.Hardware.Sockets = &sockets
+		instance.Spec.Source.PVC.Hardware.Firmware = &firmware
+		instance.Spec.Source.PVC.Hardware.SecureBoot = true
+		mapper := NewPVCMapper(instance, claims, nil)
+		vmName := mapper.ResolveVMName(nil)
+
+		vm, err := mapper.MapVM(vmName, mapper.CreateEmptyVM(vmName))
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vm.Name).To(Equal(instance.Name))
+		Expect(vm.Namespace).To(Equal(instance.Namespace))
+		Expect(*vm.Spec.Running).To(BeFalse())
+		domain := vm.Spec.Template.Spec.Domain
+		Expect(domain.CPU.Sockets).To(BeEquivalentTo(2))
+		Expect(domain.CPU.Cores).To(BeEquivalentTo(1))
+		Expect(domain.Resources.Requests[corev1.ResourceMemory]).To(Equal(resource.MustParse("2Gi")))
+		Expect(*domain.Firmware.Bootloader.EFI.SecureBoot).To(BeTrue())
+		Expect(*domain.Features.SMM.Enabled).To(BeTrue())
+	})
+
+	It("should attach the claims and boot from the first one", func() {
+		bus := "sata"
+		instance.Spec.Source.PVC.Disks[1].Bus = &bus
+		mapper := NewPVCMapper(instance, claims, nil)
+		vmName := mapper.ResolveVMName(nil)
+
+		vm, err := mapper.MapVM(vmName, mapper.CreateEmptyVM(vmName))
+
+		Expect(err).ToNot(HaveOccurred())
+		volumes := vm.Spec.Template.Spec.Volumes
+		Expect(volumes).To(HaveLen(2))
+		Expect(volumes[0].Name).To(Equal("pvc-disk-1"))
+		Expect(volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("disk-1"))
+		disks := vm.Spec.Template.Spec.Domain.Devices.Disks
+		Expect(disks).To(HaveLen(2))
+		Expect(disks[0].Disk.Bus).To(Equal("virtio"))
+		Expect(*disks[0].BootOrder).To(BeEquivalentTo(1))
+		Expect(disks[1].Disk.Bus).To(Equal("sata"))
+		Expect(disks[1].BootOrder).To(BeNil())
+	})
+
+	It("should describe the claims as data volumes", func() {
+		mapper := NewPVCMapper(instance, claims, nil)
+
+		dvs, err := mapper.MapDataVolumes(nil, cdiv1.FilesystemOverhead{})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dvs).To(HaveLen(2))
+		Expect(*dvs["disk-1"].Spec.PVC.VolumeMode).To(Equal(corev1.PersistentVolumeFilesystem))
+		Expect(*dvs["disk-2"].Spec.PVC.VolumeMode).To(Equal(corev1.PersistentVolumeBlock))
+	})
+})
@@ -0,0 +1,336 @@
+package pvcprovider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/conditions"
+	ctrlConfig "github.com/kubevirt/vm-import-operator/pkg/config/controller"
+	"github.com/kubevirt/vm-import-operator/pkg/configmaps"
+	"github.com/kubevirt/vm-import-operator/pkg/guestconversion"
+	"github.com/kubevirt/vm-import-operator/pkg/pods"
+	provider "github.com/kubevirt/vm-import-operator/pkg/providers"
+	"github.com/kubevirt/vm-import-operator/pkg/templates"
+	"github.com/kubevirt/vm-import-operator/pkg/utils"
+	"github.com/kubevirt/vm-import-operator/pkg/virtualmachines"
+	templatev1 "github.com/openshift/api/template/v1"
+	tempclient "github.com/openshift/client-go/template/clientset/versioned/typed/template/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubevirtv1 "kubevirt.io/client-go/api/v1"
+	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
+	rclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	templateNamespace = "openshift"
+	serverWorkload    = "server"
+	mediumFlavor      = "medium"
+)
+
+// PVCProvider is the implementation of the Provider interface for the VMs whose disks are already in persistent volume
+// claims. The disks aren't imported: the guest is converted on the claims and the VM is created with them, from the
+// configuration of the source VM read by the provider of its type or from the hardware described by the import.
+type PVCProvider struct {
+	client                rclient.Client
+	vmiObjectMeta         metav1.ObjectMeta
+	vmiTypeMeta           metav1.TypeMeta
+	instance              *v2vv1.VirtualMachineImport
+	sourceVMProvider      provider.Provider
+	templateProvider      templates.TemplateProvider
+	templateHandler       *templates.TemplateHandler
+	configMapsManager     provider.ConfigMapsManager
+	podsManager           provider.PodsManager
+	virtualMachineManager provider.VirtualMachineManager
+	conversionPod         v2vv1.ConversionPodSpec
+	conversionAllowList   ctrlConfig.ConversionPodAllowList
+}
+
+// NewPVCProvider creates a new PVCProvider. The source VM provider reads the source VM of the import, it is nil when
+// the import describes the hardware of the VM instead.
+func NewPVCProvider(vmiObjectMeta metav1.ObjectMeta, vmiTypeMeta metav1.TypeMeta, client rclient.Client, tempClient *tempclient.TemplateV1Client, ctrlConfig ctrlConfig.ControllerConfig, sourceVMProvider provider.Provider) PVCProvider {
+	configMapsManager := configmaps.NewManager(client)
+	podsManager := pods.NewManager(client)
+	virtualMachineManager := virtualmachines.NewManager(client)
+	templateProvider := templates.NewTemplateProvider(tempClient)
+	return PVCProvider{
+		client:                client,
+		vmiObjectMeta:         vmiObjectMeta,
+		vmiTypeMeta:           vmiTypeMeta,
+		sourceVMProvider:      sourceVMProvider,
+		templateProvider:      templateProvider,
+		templateHandler:       templates.NewTemplateHandler(templateProvider),
+		configMapsManager:     &configMapsManager,
+		podsManager:           &podsManager,
+		virtualMachineManager: &virtualMachineManager,
+		conversionPod:         ctrlConfig.ConversionPod(),
+		conversionAllowList:   ctrlConfig.ConversionPodAllowList(),
+	}
+}
+
+// SourceVMSpec returns the source spec identifying the source VM of a PVC source the way the provider of its type
+// expects it
+func SourceVMSpec(sourceSpec v2vv1.VirtualMachineImportSourceSpec) v2vv1.VirtualMachineImportSourceSpec {
+	if sourceSpec.PVC == nil || sourceSpec.PVC.SourceVM == nil {
+		return v2vv1.VirtualMachineImportSourceSpec{}
+	}
+	return v2vv1.VirtualMachineImportSourceSpec{
+		Ovirt:  sourceSpec.PVC.SourceVM.Ovirt,
+		Vmware: sourceSpec.PVC.SourceVM.Vmware,
+	}
+}
+
+// sourceVMImport returns the import as seen by the provider of the source VM
+func sourceVMImport(instance *v2vv1.VirtualMachineImport) *v2vv1.VirtualMachineImport {
+	sourceVMImport := instance.DeepCopy()
+	sourceVMImport.Spec.Source = SourceVMSpec(instance.Spec.Source)
+	// the source VM isn't shut down, so the way it would be mustn't fail its validation
+	hard := v2vv1.HardShutdown
+	sourceVMImport.Spec.SourceShutdown = &v2vv1.SourceShutdownSpec{Method: &hard}
+	return sourceVMImport
+}
+
+// Init initializes the provider of the source VM, if any
+func (p *PVCProvider) Init(secret *corev1.Secret, instance *v2vv1.VirtualMachineImport) error {
+	p.instance = instance
+	if p.sourceVMProvider == nil {
+		return nil
+	}
+	return p.sourceVMProvider.Init(secret, sourceVMImport(instance))
+}
+
+// TestConnection tests the connection to the provider of the source VM, if any
+func (p *PVCProvider) TestConnection() error {
+	if p.sourceVMProvider == nil {
+		return nil
+	}
+	return p.sourceVMProvider.TestConnection()
+}
+
+// Close releases the connection to the provider of the source VM, if any
+func (p *PVCProvider) Close() {
+	if p.sourceVMProvider != nil {
+		p.sourceVMProvider.Close()
+	}
+}
+
+// LoadVM loads the source VM, if any
+func (p *PVCProvider) LoadVM(sourceSpec v2vv1.VirtualMachineImportSourceSpec) error {
+	if p.sourceVMProvider == nil {
+		return nil
+	}
+	return p.sourceVMProvider.LoadVM(SourceVMSpec(sourceSpec))
+}
+
+// PrepareResourceMapping merges the external resource mapping with the mappings of the source VM, if any
+func (p *PVCProvider) PrepareResourceMapping(externalResourceMapping *v2vv1.ResourceMappingSpec, sourceSpec v2vv1.VirtualMachineImportSourceSpec) {
+	if p.sourceVMProvider != nil {
+		p.sourceVMProvider.PrepareResourceMapping(externalResourceMapping, SourceVMSpec(sourceSpec))
+	}
+}
+
+// Validate checks that the persistent volume claims holding the disks exist, and validates the source VM, if any
+func (p *PVCProvider) Validate() ([]v2vv1.VirtualMachineImportCondition, error) {
+	validCondition := conditions.NewCondition(v2vv1.Valid, string(v2vv1.ValidationCompleted), "Validation completed successfully", corev1.ConditionTrue)
+	mappingCondition := conditions.NewCondition(v2vv1.MappingRulesVerified, string(v2vv1.MappingRulesVerificationCompleted), "All mapping rules checks passed", corev1.ConditionTrue)
+	validationConditions := []v2vv1.VirtualMachineImportCondition{validCondition, mappingCondition}
+	if p.sourceVMProvider != nil {
+		var err error
+		validationConditions, err = p.sourceVMProvider.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, missing, err := p.findClaims()
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		message := fmt.Sprintf("Persistent volume claims not found: %s", strings.Join(missing, ", "))
+		notFoundCondition := conditions.NewCondition(v2vv1.Valid, string(v2vv1.PersistentVolumeClaimNotFound), message, corev1.ConditionFalse)
+		for i := range validationConditions {
+			if validationConditions[i].Type == v2vv1.Valid {
+				validationConditions[i] = notFoundCondition
+			}
+		}
+	}
+	return validationConditions, nil
+}
+
+// findClaims returns the persistent volume claims holding the disks of the VM and the names of those not found
+func (p *PVCProvider) findClaims() (map[string]*corev1.PersistentVolumeClaim, []string, error) {
+	claims := make(map[string]*corev1.PersistentVolumeClaim)
+	var missing []string
+	for _, disk := range p.instance.Spec.Source.PVC.Disks {
+		claim := &corev1.PersistentVolumeClaim{}
+		err := p.client.Get(context.TODO(), types.NamespacedName{Name: disk.ClaimName, Namespace: p.instance.Namespace}, claim)
+		if k8serrors.IsNotFound(err) {
+			missing = append(missing, disk.ClaimName)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		claims[disk.ClaimName] = claim
+	}
+	return claims, missing, nil
+}
+
+// ValidateDiskStatus accepts the disks, they aren't imported
+func (p *PVCProvider) ValidateDiskStatus(_ cdiv1.DataVolume) (bool, error) {
+	return true, nil
+}
+
+// StopVM doesn't stop the source VM, its disks were copied already
+func (p *PVCProvider) StopVM(_ *v2vv1.VirtualMachineImport, _ rclient.Client, _ v2vv1.SourceShutdownMethod) error {
+	return nil
+}
+
+// GetVMStatus reports the source VM as down, it is left as it is
+func (p *PVCProvider) GetVMStatus() (provider.VMStatus, error) {
+	return provider.VMStatusDown, nil
+}
+
+// StartVM doesn't start the source VM, it is left as it is
+func (p *PVCProvider) StartVM() error {
+	return nil
+}
+
+// GetVMName returns the name of the source VM or, without it, the name of the import
+func (p *PVCProvider) GetVMName() (string, error) {
+	if p.sourceVMProvider != nil {
+		return p.sourceVMProvider.GetVMName()
+	}
+	return p.vmiObjectMeta.Name, nil
+}
+
+// CreateMapper creates the mapper of the VM on the persistent volume claims
+func (p *PVCProvider) CreateMapper() (provider.Mapper, error) {
+	claims, missing, err := p.findClaims()
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("persistent volume claims not found: %s", strings.Join(missing, ", "))
+	}
+	var sourceVMMapper provider.Mapper
+	if p.sourceVMProvider != nil {
+		sourceVMMapper, err = p.sourceVMProvider.CreateMapper()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return NewPVCMapper(p.instance, claims, sourceVMMapper), nil
+}
+
+// FindTemplate finds the template of the source VM or of the operating system of the described hardware
+func (p *PVCProvider) FindTemplate() (*templatev1.Template, error) {
+	if p.sourceVMProvider != nil {
+		return p.sourceVMProvider.FindTemplate()
+	}
+	os := p.instance.Spec.Source.PVC.Hardware.OperatingSystem
+	if os == nil {
+		return nil, fmt.Errorf("the operating system of the VM isn't described")
+	}
+	tmpls, err := p.templateProvider.Find(&templateNamespace, os, &serverWorkload, &mediumFlavor)
+	if err != nil {
+		return nil, err
+	}
+	if len(tmpls.Items) == 0 {
+		return nil, fmt.Errorf("template not found for %s OS", *os)
+	}
+	// Take the newest which matches label selector
+	sort.Slice(tmpls.Items, func(i, j int) bool {
+		return tmpls.Items[j].CreationTimestamp.Before(&tmpls.Items[i].CreationTimestamp)
+	})
+	return &tmpls.Items[0], nil
+}
+
+// ProcessTemplate processes the template with the provider of the source VM or labels the VM with the operating
+// system of the described hardware
+func (p *PVCProvider) ProcessTemplate(template *templatev1.Template, vmName *string, namespace string) (*kubevirtv1.VirtualMachine, error) {
+	if p.sourceVMProvider != nil {
+		return p.sourceVMProvider.ProcessTemplate(template, vmName, namespace)
+	}
+	vm, err := p.templateHandler.ProcessTemplate(template, vmName, namespace)
+	if err != nil {
+		return nil, err
+	}
+	os := p.instance.Spec.Source.PVC.Hardware.OperatingSystem
+	key := fmt.Sprintf(templates.TemplateNameOsAnnotation, *os)
+	utils.UpdateLabels(vm, templates.OSLabelBuilder(os, &serverWorkload, &mediumFlavor))
+	utils.UpdateAnnotations(vm, map[string]string{key: template.GetAnnotations()[key]})
+	return vm, nil
+}
+
+// CleanUp removes the transient resources created for the import. The persistent volume claims are kept, even when
+// the import fails, since they weren't created by the import.
+func (p *PVCProvider) CleanUp(failure bool, cr *v2vv1.VirtualMachineImport, client rclient.Client) error {
+	if p.sourceVMProvider != nil {
+		return p.sourceVMProvider.CleanUp(failure, cr, client)
+	}
+	var errs []error
+	vmiName := p.getVmiNamespacedName()
+	err := p.configMapsManager.DeleteFor(vmiName)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	// keep the conversion pod around if it failed or the annotation was set
+	_, found := cr.Annotations[guestconversion.AnnRetainConversionPod]
+	if !(failure || found) {
+		err = p.podsManager.DeleteFor(vmiName)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if failure {
+		err = p.virtualMachineManager.DeleteFor(vmiName)
+		// ignore not found errors, since the VM being deleted
+		// might be the cause of the failed import.
+		if err != nil && !k8serrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return utils.FoldCleanUpErrors(errs, vmiName)
+	}
+	return nil
+}
+
+// SupportsWarmMigration returns false, the disks aren't imported
+func (p *PVCProvider) SupportsWarmMigration() bool {
+	return false
+}
+
+// SupportsSnapshotImport returns false, the disks aren't imported
+func (p *PVCProvider) SupportsSnapshotImport() bool {
+	return false
+}
+
+// CreateVMSnapshot is not implemented.
+func (p *PVCProvider) CreateVMSnapshot() (string, error) {
+	return "", nil
+}
+
+// VMSnapshotReady is not implemented.
+func (p *PVCProvider) VMSnapshotReady(_ string) (bool, error) {
+	return true, nil
+}
+
+// RemoveVMSnapshot is not implemented.
+func (p *PVCProvider) RemoveVMSnapshot(_ string, _ bool) error {
+	return nil
+}
+
+func (p *PVCProvider) getVmiNamespacedName() types.NamespacedName {
+	return types.NamespacedName{Name: p.vmiObjectMeta.Name, Namespace: p.vmiObjectMeta.Namespace}
+}
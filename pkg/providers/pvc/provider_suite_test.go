@@ -0,0 +1,13 @@
+package pvcprovider
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestPVCProvider(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "PVC provider Suite")
+}
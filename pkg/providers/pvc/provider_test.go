@@ -0,0 +1,130 @@
+package pvcprovider
+
+import (
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/conditions"
+	providers "github.com/kubevirt/vm-import-operator/pkg/providers"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Validating the persistent volume claims", func() {
+	var (
+		provider PVCProvider
+		instance *v2vv1.VirtualMachineImport
+	)
+
+	BeforeEach(func() {
+		instance = newPVCImport()
+		claim := newClaim("disk-1", corev1.PersistentVolumeFilesystem)
+		provider = PVCProvider{
+			client:        fake.NewFakeClientWithScheme(scheme.Scheme, claim),
+			vmiObjectMeta: instance.ObjectMeta,
+		}
+		Expect(provider.Init(nil, instance)).To(Succeed())
+	})
+
+	It("should accept existing claims", func() {
+		validationConditions, err := provider.Validate()
+
+		Expect(err).ToNot(HaveOccurred())
+		valid := conditions.FindConditionOfType(validationConditions, v2vv1.Valid)
+		Expect(valid.Status).To(Equal(corev1.ConditionTrue))
+		mapping := conditions.FindConditionOfType(validationConditions, v2vv1.MappingRulesVerified)
+		Expect(mapping.Status).To(Equal(corev1.ConditionTrue))
+	})
+
+	It("should reject missing claims", func() {
+		instance.Spec.Source.PVC.Disks = append(instance.Spec.Source.PVC.Disks, v2vv1.PVCDisk{ClaimName: "disk-2"})
+
+		validationConditions, err := provider.Validate()
+
+		Expect(err).ToNot(HaveOccurred())
+		valid := conditions.FindConditionOfType(validationConditions, v2vv1.Valid)
+		Expect(valid.Status).To(Equal(corev1.ConditionFalse))
+		Expect(*valid.Reason).To(Equal(string(v2vv1.PersistentVolumeClaimNotFound)))
+		Expect(*valid.Message).To(ContainSubstring("disk-2"))
+	})
+
+	It("should fail to create the mapper with missing claims", func() {
+		instance.Spec.Source.PVC.Disks = []v2vv1.PVCDisk{{ClaimName: "disk-2"}}
+
+		_, err := provider.CreateMapper()
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should name the VM after the import without a source VM", func() {
+		name, err := provider.GetVMName()
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(name).To(Equal(instance.Name))
+	})
+
+	It("should convert the guest of a source VM already down", func() {
+		status, err := provider.GetVMStatus()
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(providers.VMStatusDown))
+		convertGuest, err := provider.NeedsGuestConversion()
+		Expect(err).To(BeNil())
+		Expect(convertGuest).To(BeTrue())
+		Expect(provider.SupportsWarmMigration()).To(BeFalse())
+	})
+
+	It("should fail to find a template without the operating system", func() {
+		_, err := provider.FindTemplate()
+
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Reading the source VM of the persistent volume claims", func() {
+	It("should identify the source VM to its provider", func() {
+		vmName := "myvm"
+		sourceVM := &v2vv1.VirtualMachineImportOvirtSourceSpec{
+			VM: v2vv1.VirtualMachineImportOvirtSourceVMSpec{Name: &vmName},
+		}
+		instance := newPVCImport()
+		instance.Spec.Source.PVC.Hardware = nil
+		instance.Spec.Source.PVC.SourceVM = &v2vv1.PVCSourceVMSpec{Ovirt: sourceVM}
+
+		sourceVMImport := sourceVMImport(instance)
+
+		Expect(sourceVMImport.Spec.Source.Ovirt).To(Equal(sourceVM))
+		Expect(sourceVMImport.Spec.Source.PVC).To(BeNil())
+		Expect(*sourceVMImport.Spec.SourceShutdown.Method).To(Equal(v2vv1.HardShutdown))
+		Expect(instance.Spec.Source.PVC).ToNot(BeNil())
+	})
+})
+
+func newPVCImport() *v2vv1.VirtualMachineImport {
+	return &v2vv1.VirtualMachineImport{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+		Spec: v2vv1.VirtualMachineImportSpec{
+			Source: v2vv1.VirtualMachineImportSourceSpec{
+				PVC: &v2vv1.VirtualMachineImportPVCSourceSpec{
+					Disks: []v2vv1.PVCDisk{{ClaimName: "disk-1"}},
+					Hardware: &v2vv1.VirtualMachineHardwareSpec{
+						Memory: resource.MustParse("2Gi"),
+					},
+				},
+			},
+		},
+	}
+}
+
+func newClaim(name string, volumeMode corev1.PersistentVolumeMode) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			VolumeMode: &volumeMode,
+		},
+	}
+}
@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ProviderSecretUsernameKey is the key of the username in the secret of a Provider resource
+	ProviderSecretUsernameKey = "username"
+	// ProviderSecretPasswordKey is the key of the password in the secret of a Provider resource
+	ProviderSecretPasswordKey = "password"
+)
+
+// ConnectionDetailsOf returns the connection details of the Provider resource, with the username and the password
+// taken from its secret and the CA bundle it references. The details have the same attributes as the
+// providerCredentialsSecret of an import.
+func ConnectionDetailsOf(sourceProvider *v2vv1.Provider, secret *corev1.Secret, caBundle string) (map[string]string, error) {
+	username := string(secret.Data[ProviderSecretUsernameKey])
+	if len(username) == 0 {
+		return nil, fmt.Errorf("provider secret %s/%s must contain a %s", secret.Namespace, secret.Name, ProviderSecretUsernameKey)
+	}
+	password := string(secret.Data[ProviderSecretPasswordKey])
+	if len(password) == 0 {
+		return nil, fmt.Errorf("provider secret %s/%s must contain a %s", secret.Namespace, secret.Name, ProviderSecretPasswordKey)
+	}
+
+	details := map[string]string{
+		"apiUrl":   sourceProvider.Spec.URL,
+		"username": username,
+		"password": password,
+	}
+	if tls := sourceProvider.Spec.TLS; tls != nil {
+		err := validateTLS(sourceProvider)
+		if err != nil {
+			return nil, err
+		}
+		if tls.CACert != nil {
+			details["caCert"] = *tls.CACert
+		}
+		if tls.CABundleRef != nil {
+			details["caCert"] = caBundle
+		}
+		if tls.Thumbprint != nil {
+			details["thumbprint"] = *tls.Thumbprint
+		}
+		if tls.InsecureSkipVerify {
+			details["insecureSkipVerify"] = "true"
+		}
+	}
+	return details, nil
+}
+
+// InvalidTLSError is returned when the TLS settings of a Provider resource contradict each other or reference a
+// CA bundle that can't be read
+type InvalidTLSError struct {
+	message string
+}
+
+func (e InvalidTLSError) Error() string {
+	return e.message
+}
+
+func validateTLS(sourceProvider *v2vv1.Provider) error {
+	tls := sourceProvider.Spec.TLS
+	options := 0
+	for _, set := range []bool{tls.CACert != nil, tls.CABundleRef != nil, tls.Thumbprint != nil, tls.InsecureSkipVerify} {
+		if set {
+			options++
+		}
+	}
+	if options > 1 {
+		return InvalidTLSError{"only one of caCert, caBundleRef, thumbprint and insecureSkipVerify may be set"}
+	}
+	if tls.Thumbprint != nil && sourceProvider.Spec.Type != v2vv1.VmwareProviderType {
+		return InvalidTLSError{fmt.Sprintf("thumbprint pinning isn't supported by %s providers", sourceProvider.Spec.Type)}
+	}
+	return nil
+}
+
+// CABundleOf reads the CA bundle referenced by the TLS settings of the Provider resource, if any
+func CABundleOf(c client.Client, sourceProvider *v2vv1.Provider) (string, error) {
+	if sourceProvider.Spec.TLS == nil || sourceProvider.Spec.TLS.CABundleRef == nil {
+		return "", nil
+	}
+	ref := sourceProvider.Spec.TLS.CABundleRef
+	name := types.NamespacedName{Name: ref.Name, Namespace: sourceProvider.Namespace}
+	if ref.Namespace != nil {
+		name.Namespace = *ref.Namespace
+	}
+	key := ref.Key
+	if key == "" {
+		key = v2vv1.DefaultCABundleKey
+	}
+
+	var caBundle string
+	switch ref.Kind {
+	case v2vv1.ConfigMapCABundle:
+		configMap := &corev1.ConfigMap{}
+		err := c.Get(context.TODO(), name, configMap)
+		if err != nil {
+			return "", err
+		}
+		caBundle = configMap.Data[key]
+	case v2vv1.SecretCABundle:
+		secret := &corev1.Secret{}
+		err := c.Get(context.TODO(), name, secret)
+		if err != nil {
+			return "", err
+		}
+		caBundle = string(secret.Data[key])
+	default:
+		return "", InvalidTLSError{fmt.Sprintf("CA bundle kind must be either %s or %s", v2vv1.ConfigMapCABundle, v2vv1.SecretCABundle)}
+	}
+	if caBundle == "" {
+		return "", InvalidTLSError{fmt.Sprintf("%s %s doesn't contain a CA bundle under the %s key", ref.Kind, name, key)}
+	}
+	return caBundle, nil
+}
+
+// InsecureSkipVerify returns whether the certificate of the source provider isn't verified
+func InsecureSkipVerify(details map[string]string) bool {
+	return details["insecureSkipVerify"] == "true"
+}
+
+// SkipsVerification returns whether the credentials secret of an import, of the given source type, disables the
+// verification of the certificate of the source provider
+func SkipsVerification(credentials *corev1.Secret, sourceType v2vv1.ProviderType) bool {
+	details := make(map[string]string)
+	err := yaml.Unmarshal(credentials.Data[string(sourceType)], &details)
+	if err != nil {
+		return false
+	}
+	return InsecureSkipVerify(details)
+}
+
+// CredentialsSecretOf builds the equivalent of the providerCredentialsSecret of an import from the Provider resource
+// and its secret, so that the source providers can be initialized from either.
+func CredentialsSecretOf(sourceProvider *v2vv1.Provider, secret *corev1.Secret, caBundle string) (*corev1.Secret, error) {
+	details, err := ConnectionDetailsOf(sourceProvider, secret, caBundle)
+	if err != nil {
+		return nil, err
+	}
+	data, err := yaml.Marshal(details)
+	if err != nil {
+		return nil, err
+	}
+	credentials := &corev1.Secret{
+		Data: map[string][]byte{
+			// the source providers read their connection details from the key named after their type
+			string(sourceProvider.Spec.Type): data,
+		},
+	}
+	credentials.Name = secret.Name
+	credentials.Namespace = secret.Namespace
+	return credentials, nil
+}
+
+// SourceTypeOf returns the type of the source provider the import is from
+func SourceTypeOf(instance *v2vv1.VirtualMachineImport) (v2vv1.ProviderType, bool) {
+	switch {
+	case instance.Spec.Source.Ovirt != nil:
+		return v2vv1.OvirtProviderType, true
+	case instance.Spec.Source.Vmware != nil:
+		return v2vv1.VmwareProviderType, true
+	case instance.Spec.Source.PVC != nil && instance.Spec.Source.PVC.SourceVM != nil:
+		// the disks are already in persistent volume claims, the source VM is only read
+		sourceVM := instance.Spec.Source.PVC.SourceVM
+		if sourceVM.Ovirt != nil {
+			return v2vv1.OvirtProviderType, true
+		}
+		if sourceVM.Vmware != nil {
+			return v2vv1.VmwareProviderType, true
+		}
+	}
+	return "", false
+}
@@ -5,12 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"path"
 	"strings"
 	"time"
 
+	"github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/metrics"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/view"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/soap"
@@ -18,10 +22,6 @@ import (
 )
 
 const (
-	// Number of minutes to wait for VM to be stopped
-	shutdownTimeout = 5 * time.Minute
-	// Vm poll interval in seconds
-	pollInterval = 5 * time.Second
 	// timeout value in seconds for vmware api requests
 	timeout = 30 * time.Second
 )
@@ -31,10 +31,11 @@ type RichVmwareClient struct {
 	client         *vim25.Client
 	user           *url.Userinfo
 	sessionManager *session.Manager
+	peer           *peerCertificate
 }
 
 // NewRichVMWareClient creates a new, connected rich VMWare client.
-func NewRichVMWareClient(apiUrl, username, password string, thumbprint string) (*RichVmwareClient, error) {
+func NewRichVMWareClient(apiUrl, username, password string, tlsOptions TLSOptions) (*RichVmwareClient, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
@@ -46,8 +47,14 @@ func NewRichVMWareClient(apiUrl, username, password string, thumbprint string) (
 		u.User = url.UserPassword(username, password)
 	}
 
-	soapClient := soap.NewClient(u, false)
-	soapClient.SetThumbprint(u.Host, thumbprint)
+	// the certificate is verified by the TLS configuration below rather than by the SOAP client
+	peer := &peerCertificate{}
+	tlsConfig, err := newTLSConfig(u.Host, tlsOptions, peer)
+	if err != nil {
+		return nil, err
+	}
+	soapClient := soap.NewClient(u, true)
+	soapClient.DefaultTransport().TLSClientConfig = tlsConfig
 	vimClient, err := vim25.NewClient(ctx, soapClient)
 	if err != nil {
 		return nil, err
@@ -63,19 +70,101 @@ func NewRichVMWareClient(apiUrl, username, password string, thumbprint string) (
 		client:         vimClient,
 		user:           u.User,
 		sessionManager: sessionManager,
+		peer:           peer,
 	}
 	return &vmwareClient, nil
 }
 
+// Thumbprint returns the SHA-1 thumbprint of the certificate presented by the vCenter or ESXi host, or an empty
+// string if the connection isn't secured by TLS
+func (r RichVmwareClient) Thumbprint() string {
+	return r.peer.get()
+}
+
+// VMLookup defines how to identify a VM, and optionally the part of the inventory it should be looked up in.
+type VMLookup struct {
+	ID           *string
+	InstanceUUID bool
+	MoRef        *string
+	Name         *string
+
+	Datacenter   *string
+	Cluster      *string
+	Folder       *string
+	ResourcePool *string
+}
+
+func (l VMLookup) scoped() bool {
+	return l.Datacenter != nil || l.Cluster != nil || l.Folder != nil || l.ResourcePool != nil
+}
+
+func (l VMLookup) String() string {
+	switch {
+	case l.MoRef != nil:
+		return *l.MoRef
+	case l.ID != nil:
+		return *l.ID
+	case l.Name != nil:
+		return *l.Name
+	}
+	return ""
+}
+
+// AmbiguousVMError is returned when a VM lookup matches more than one VM.
+type AmbiguousVMError struct {
+	Lookup  string
+	Matches []string
+}
+
+func (e *AmbiguousVMError) Error() string {
+	return fmt.Sprintf("vm '%s' matches more than one VM: %s", e.Lookup, strings.Join(e.Matches, ", "))
+}
+
 // GetVM retrieves a VM from a vCenter or ESXI host by UUID or by name/inventory path.
 func (r RichVmwareClient) GetVM(id *string, name *string, _ *string, _ *string) (interface{}, error) {
-	if id != nil {
-		return r.getVMByUUID(*id)
+	if id == nil && name == nil {
+		return nil, errors.New("not found")
+	}
+	return r.FindVM(VMLookup{ID: id, Name: name})
+}
+
+// FindVM retrieves a VM from a vCenter or ESXi host by MoRef, UUID or name, optionally restricting
+// the lookup to a datacenter, cluster, folder or resource pool. An AmbiguousVMError is returned
+// if more than one VM matches.
+func (r RichVmwareClient) FindVM(lookup VMLookup) (_ *object.VirtualMachine, e error) {
+	defer func(start time.Time) { observeRequest("FindVM", start, e) }(time.Now())
+	if lookup.MoRef == nil && lookup.ID == nil && lookup.Name == nil {
+		return nil, errors.New("not found")
+	}
+	if !lookup.scoped() {
+		switch {
+		case lookup.MoRef != nil:
+			return r.getExistingVMByMoRef(*lookup.MoRef)
+		case lookup.ID == nil && strings.Contains(*lookup.Name, "/"):
+			return r.getVMByInventoryPath(*lookup.Name)
+		}
 	}
-	if name != nil {
-		return r.getVMByInventoryPath(*name)
+	if lookup.MoRef == nil && lookup.ID != nil {
+		return r.searchVMByUUID(lookup)
 	}
-	return nil, errors.New("not found")
+	return r.searchVM(lookup)
+}
+
+func (r RichVmwareClient) getExistingVMByMoRef(moRef string) (*object.VirtualMachine, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	vm := r.getVMByMoRef(moRef)
+	var vmProperties mo.VirtualMachine
+	err := vm.Properties(ctx, vm.Reference(), []string{"name"}, &vmProperties)
+	if err != nil {
+		if soap.IsSoapFault(err) {
+			if _, ok := soap.ToSoapFault(err).VimFault().(types.ManagedObjectNotFound); ok {
+				return nil, fmt.Errorf("vm '%s' not found", moRef)
+			}
+		}
+		return nil, err
+	}
+	return vm, nil
 }
 
 func (r RichVmwareClient) getVMByMoRef(moRef string) *object.VirtualMachine {
@@ -86,38 +175,211 @@ func (r RichVmwareClient) getVMByMoRef(moRef string) *object.VirtualMachine {
 	return object.NewVirtualMachine(r.client, ref)
 }
 
-// getVMByUUID gets a VM by its UUID
-func (r RichVmwareClient) getVMByUUID(id string) (*object.VirtualMachine, error) {
+// getVMByInventoryPath gets a VM by its complete inventory path or by name alone.
+func (r RichVmwareClient) getVMByInventoryPath(vmPath string) (*object.VirtualMachine, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
-	searchIndex := object.NewSearchIndex(r.client)
-	instanceUUID := false
-	vmRef, err := searchIndex.FindByUuid(ctx, nil, id, true, &instanceUUID)
+	finder := find.NewFinder(r.client)
+
+	vm, err := finder.VirtualMachine(ctx, vmPath)
 	if err != nil {
 		return nil, err
 	}
-	if vmRef == nil {
-		return nil, fmt.Errorf("vm '%s' not found", id)
-	}
-	vm := object.NewVirtualMachine(r.client, vmRef.Reference())
 	return vm, nil
 }
 
-// getVMByInventoryPath gets a VM by its complete inventory path or by name alone.
-func (r RichVmwareClient) getVMByInventoryPath(vmPath string) (*object.VirtualMachine, error) {
+// searchVM finds the VMs matching the lookup within each of its scopes, and returns
+// the single VM that is present in all of them.
+func (r RichVmwareClient) searchVM(lookup VMLookup) (*object.VirtualMachine, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
-	finder := find.NewFinder(r.client)
 
-	vm, err := finder.VirtualMachine(ctx, vmPath)
+	scopes, err := r.lookupScopes(ctx, lookup)
 	if err != nil {
 		return nil, err
 	}
-	return vm, nil
+
+	var candidates []mo.VirtualMachine
+	for i, scope := range scopes {
+		vms, err := r.listVMs(ctx, scope)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			for _, vm := range vms {
+				if matchesVM(lookup, vm) {
+					candidates = append(candidates, vm)
+				}
+			}
+			continue
+		}
+		inScope := make(map[string]bool, len(vms))
+		for _, vm := range vms {
+			inScope[vm.Self.Value] = true
+		}
+		filtered := candidates[:0]
+		for _, vm := range candidates {
+			if inScope[vm.Self.Value] {
+				filtered = append(filtered, vm)
+			}
+		}
+		candidates = filtered
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("vm '%s' not found", lookup)
+	case 1:
+		return object.NewVirtualMachine(r.client, candidates[0].Self), nil
+	}
+	matches := make([]string, 0, len(candidates))
+	for _, vm := range candidates {
+		matches = append(matches, vm.Self.Value)
+	}
+	return nil, &AmbiguousVMError{Lookup: lookup.String(), Matches: matches}
+}
+
+// searchVMByUUID finds the VMs with the BIOS or instance UUID through the search index of the datacenter the lookup
+// is restricted to, or of the whole inventory, and returns the single one of them that is within the other scopes of
+// the lookup. BIOS UUIDs aren't unique, cloned VMs can share them.
+func (r RichVmwareClient) searchVMByUUID(lookup VMLookup) (*object.VirtualMachine, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var datacenter *object.Datacenter
+	var scopes []types.ManagedObjectReference
+	if lookup.scoped() {
+		var err error
+		scopes, err = r.lookupScopes(ctx, lookup)
+		if err != nil {
+			return nil, err
+		}
+		if lookup.Datacenter != nil {
+			datacenter = object.NewDatacenter(r.client, scopes[0])
+			scopes = scopes[1:]
+		}
+	}
+
+	instanceUUID := lookup.InstanceUUID
+	refs, err := object.NewSearchIndex(r.client).FindAllByUuid(ctx, datacenter, *lookup.ID, true, &instanceUUID)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]types.ManagedObjectReference, 0, len(refs))
+	for _, ref := range refs {
+		candidates = append(candidates, ref.Reference())
+	}
+	for _, scope := range scopes {
+		vms, err := r.listVMs(ctx, scope)
+		if err != nil {
+			return nil, err
+		}
+		filtered := candidates[:0]
+		for _, ref := range candidates {
+			if containsVM(vms, ref) {
+				filtered = append(filtered, ref)
+			}
+		}
+		candidates = filtered
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("vm '%s' not found", lookup)
+	case 1:
+		return object.NewVirtualMachine(r.client, candidates[0]), nil
+	}
+	matches := make([]string, 0, len(candidates))
+	for _, ref := range candidates {
+		matches = append(matches, ref.Value)
+	}
+	return nil, &AmbiguousVMError{Lookup: lookup.String(), Matches: matches}
+}
+
+func containsVM(vms []mo.VirtualMachine, ref types.ManagedObjectReference) bool {
+	for _, vm := range vms {
+		if vm.Self == ref {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupScopes resolves the inventory containers the lookup is restricted to,
+// defaulting to the root folder if the lookup isn't scoped.
+func (r RichVmwareClient) lookupScopes(ctx context.Context, lookup VMLookup) ([]types.ManagedObjectReference, error) {
+	finder := find.NewFinder(r.client, true)
+	scopes := make([]types.ManagedObjectReference, 0)
+	if lookup.Datacenter != nil {
+		datacenter, err := finder.Datacenter(ctx, *lookup.Datacenter)
+		if err != nil {
+			return nil, err
+		}
+		finder.SetDatacenter(datacenter)
+		scopes = append(scopes, datacenter.Reference())
+	} else if datacenter, err := finder.DefaultDatacenter(ctx); err == nil {
+		// relative paths can only be resolved without a datacenter if there is just one
+		finder.SetDatacenter(datacenter)
+	}
+	if lookup.Cluster != nil {
+		cluster, err := finder.ClusterComputeResource(ctx, *lookup.Cluster)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, cluster.Reference())
+	}
+	if lookup.Folder != nil {
+		folder, err := finder.Folder(ctx, *lookup.Folder)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, folder.Reference())
+	}
+	if lookup.ResourcePool != nil {
+		pool, err := finder.ResourcePool(ctx, *lookup.ResourcePool)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, pool.Reference())
+	}
+	if len(scopes) == 0 {
+		scopes = append(scopes, r.client.ServiceContent.RootFolder)
+	}
+	return scopes, nil
+}
+
+// listVMs retrieves the identifying properties of all the VMs within the container.
+func (r RichVmwareClient) listVMs(ctx context.Context, container types.ManagedObjectReference) ([]mo.VirtualMachine, error) {
+	manager := view.NewManager(r.client)
+	containerView, err := manager.CreateContainerView(ctx, container, []string{"VirtualMachine"}, true)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = containerView.Destroy(ctx)
+	}()
+
+	var vms []mo.VirtualMachine
+	err = containerView.Retrieve(ctx, []string{"VirtualMachine"}, []string{"name"}, &vms)
+	if err != nil {
+		return nil, err
+	}
+	return vms, nil
+}
+
+func matchesVM(lookup VMLookup, vm mo.VirtualMachine) bool {
+	switch {
+	case lookup.MoRef != nil:
+		return vm.Self.Value == *lookup.MoRef
+	case lookup.Name != nil:
+		return vm.Name == *lookup.Name || path.Base(*lookup.Name) == vm.Name
+	}
+	return false
 }
 
 // CreateVMSnapshot creates a snapshot of the VM.
-func (r RichVmwareClient) CreateVMSnapshot(moRef string, name string, desc string, memory bool, quiesce bool) (*types.ManagedObjectReference, error) {
+func (r RichVmwareClient) CreateVMSnapshot(moRef string, name string, desc string, memory bool, quiesce bool) (_ *types.ManagedObjectReference, e error) {
+	defer func(start time.Time) { observeRequest("CreateVMSnapshot", start, e) }(time.Now())
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
@@ -134,7 +396,8 @@ func (r RichVmwareClient) CreateVMSnapshot(moRef string, name string, desc strin
 	return &snapshotRef, nil
 }
 
-func (r RichVmwareClient) FindVMSnapshot(moRef string, snapshot string) (*types.ManagedObjectReference, error) {
+func (r RichVmwareClient) FindVMSnapshot(moRef string, snapshot string) (_ *types.ManagedObjectReference, e error) {
+	defer func(start time.Time) { observeRequest("FindVMSnapshot", start, e) }(time.Now())
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 	vm := r.getVMByMoRef(moRef)
@@ -149,7 +412,8 @@ func (r RichVmwareClient) FindVMSnapshot(moRef string, snapshot string) (*types.
 	return snapshotRef, nil
 }
 
-func (r RichVmwareClient) RemoveVMSnapshot(moRef string, snapshot string, removeChildren bool, consolidate *bool) error {
+func (r RichVmwareClient) RemoveVMSnapshot(moRef string, snapshot string, removeChildren bool, consolidate *bool) (e error) {
+	defer func(start time.Time) { observeRequest("RemoveVMSnapshot", start, e) }(time.Now())
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 	vm := r.getVMByMoRef(moRef)
@@ -161,7 +425,8 @@ func (r RichVmwareClient) RemoveVMSnapshot(moRef string, snapshot string, remove
 }
 
 // GetVMProperties retrieves the Properties struct for the VM.
-func (r RichVmwareClient) GetVMProperties(vm *object.VirtualMachine) (*mo.VirtualMachine, error) {
+func (r RichVmwareClient) GetVMProperties(vm *object.VirtualMachine) (_ *mo.VirtualMachine, e error) {
+	defer func(start time.Time) { observeRequest("GetVMProperties", start, e) }(time.Now())
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 	vmProperties := &mo.VirtualMachine{}
@@ -173,17 +438,26 @@ func (r RichVmwareClient) GetVMProperties(vm *object.VirtualMachine) (*mo.Virtua
 }
 
 // GetVMHostProperties retrieves the Properties struct for the HostSystem the VM is on.
-func (r RichVmwareClient) GetVMHostProperties(vm *object.VirtualMachine) (*mo.HostSystem, error) {
+func (r RichVmwareClient) GetVMHostProperties(vm *object.VirtualMachine) (_ *mo.HostSystem, e error) {
+	defer func(start time.Time) { observeRequest("GetVMHostProperties", start, e) }(time.Now())
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	hostSystem, err := vm.HostSystem(ctx)
 	if err != nil {
-		return nil, err
+		if r.IsVCenter() {
+			return nil, err
+		}
+		// a standalone ESXi host only manages itself, so fall back to it
+		// when the VM's runtime host can't be resolved.
+		hostSystem, err = find.NewFinder(r.client).DefaultHostSystem(ctx)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	hostProperties := &mo.HostSystem{}
-	err = hostSystem.Properties(context.TODO(), hostSystem.Reference(), nil, hostProperties)
+	err = hostSystem.Properties(ctx, hostSystem.Reference(), nil, hostProperties)
 	if err != nil {
 		return nil, err
 	}
@@ -191,8 +465,59 @@ func (r RichVmwareClient) GetVMHostProperties(vm *object.VirtualMachine) (*mo.Ho
 	return hostProperties, nil
 }
 
+// GetDatastoreFile returns the HTTP URL the file of the datastore is downloaded from and the size of the file. The
+// name of the file is a datastore path, e.g. "[datastore1] iso/image.iso".
+func (r RichVmwareClient) GetDatastoreFile(datastoreMoRef string, fileName string) (_ string, _ int64, e error) {
+	defer func(start time.Time) { observeRequest("GetDatastoreFile", start, e) }(time.Now())
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var datastorePath object.DatastorePath
+	if !datastorePath.FromString(fileName) {
+		return "", 0, fmt.Errorf("%s is not a datastore path", fileName)
+	}
+	datastoreRef := types.ManagedObjectReference{Type: "Datastore", Value: datastoreMoRef}
+	ancestors, err := mo.Ancestors(ctx, r.client, r.client.ServiceContent.PropertyCollector, datastoreRef)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(ancestors) < 2 {
+		return "", 0, fmt.Errorf("datastore %s is not in a datacenter", datastoreMoRef)
+	}
+
+	// the datacenter path is the inventory path of the datacenter below the root folder
+	var datacenterPath []string
+	for _, ancestor := range ancestors[1:] {
+		datacenterPath = append(datacenterPath, ancestor.Name)
+		if ancestor.Self.Type == "Datacenter" {
+			break
+		}
+	}
+	datastore := object.NewDatastore(r.client, datastoreRef)
+	datastore.SetInventoryPath(path.Join("/", path.Join(datacenterPath...), "datastore", ancestors[len(ancestors)-1].Name))
+	datastore.DatacenterPath = path.Join(datacenterPath...)
+
+	fileInfo, err := datastore.Stat(ctx, datastorePath.Path)
+	if err != nil {
+		return "", 0, err
+	}
+	return datastore.NewURL(datastorePath.Path).String(), fileInfo.GetFileInfo().FileSize, nil
+}
+
+// observeRequest saves the duration and the result of a VMware API request in the metrics
+func observeRequest(operation string, start time.Time, err error) {
+	metrics.ProviderMetrics.ObserveRequest(string(v1beta1.VmwareProviderType), operation, start, err)
+}
+
+// IsVCenter returns true if the client is connected to a vCenter, or false if it is
+// connected directly to a standalone ESXi host.
+func (r RichVmwareClient) IsVCenter() bool {
+	return r.client.IsVC()
+}
+
 // StartVM requests VM start and doesn't wait for it to complete.
-func (r RichVmwareClient) StartVM(moRef string) error {
+func (r RichVmwareClient) StartVM(moRef string) (e error) {
+	defer func(start time.Time) { observeRequest("StartVM", start, e) }(time.Now())
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
@@ -210,38 +535,34 @@ func (r RichVmwareClient) StartVM(moRef string) error {
 	return nil
 }
 
-// StopVM stops the VM and waits for the vm to be stopped.
-func (r RichVmwareClient) StopVM(moRef string) error {
-	err := r.shutdownGuest(moRef)
-	if err != nil {
-		return err
+// StopVM requests the shut down of the VM with the given method and doesn't wait for it to be powered off.
+func (r RichVmwareClient) StopVM(moRef string, method v1beta1.SourceShutdownMethod) (e error) {
+	defer func(start time.Time) { observeRequest("StopVM", start, e) }(time.Now())
+	switch method {
+	case v1beta1.HardShutdown:
+		return r.powerOff(moRef)
+	case v1beta1.ACPIShutdown:
+		return fmt.Errorf("ACPI shutdown of vm %s is not supported by VMware", moRef)
 	}
+	return r.shutdownGuest(moRef)
+}
 
-	// wait for the VM to shut down
-	c := make(chan bool, 1)
-	go func() {
-		for {
-			time.Sleep(pollInterval)
-			powerState, err := r.powerState(moRef)
-			if err != nil {
-				c <- false
-			}
-			if powerState == types.VirtualMachinePowerStatePoweredOff {
-				c <- true
-				break
-			}
-		}
-	}()
+func (r RichVmwareClient) powerOff(moRef string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-	select {
-	case success := <-c:
-		if !success {
-			return fmt.Errorf("failed to gracefully shutdown vm %s", moRef)
-		}
+	vm := r.getVMByMoRef(moRef)
+
+	powerState, err := vm.PowerState(ctx)
+	if err != nil {
+		return err
+	}
+	if powerState == types.VirtualMachinePowerStatePoweredOff {
 		return nil
-	case <-time.After(shutdownTimeout):
-		return fmt.Errorf("timed out trying to gracefully shutdown vm %s", moRef)
 	}
+
+	_, err = vm.PowerOff(ctx)
+	return err
 }
 
 func (r RichVmwareClient) shutdownGuest(moRef string) error {
@@ -272,11 +593,65 @@ func (r RichVmwareClient) powerState(moRef string) (types.VirtualMachinePowerSta
 }
 
 // TestConnection checks the connectivity to the vCenter or ESXi host.
-func (r RichVmwareClient) TestConnection() error {
+func (r RichVmwareClient) TestConnection() (e error) {
+	defer func(start time.Time) { observeRequest("TestConnection", start, e) }(time.Now())
 	_, err := r.client.Get(r.client.URL().String())
 	return err
 }
 
+// GetVersion retrieves the version of the vCenter or ESXi host.
+func (r RichVmwareClient) GetVersion() (string, error) {
+	about := r.client.ServiceContent.About
+	return fmt.Sprintf("%s build-%s", about.Version, about.Build), nil
+}
+
+// GetInventory counts the datacenters, clusters, hosts and VMs of the vCenter or ESXi host.
+func (r RichVmwareClient) GetInventory() (_ *v1beta1.ProviderInventory, e error) {
+	defer func(start time.Time) { observeRequest("GetInventory", start, e) }(time.Now())
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	kinds := []string{"Datacenter", "ClusterComputeResource", "HostSystem", "VirtualMachine"}
+	manager := view.NewManager(r.client)
+	containerView, err := manager.CreateContainerView(ctx, r.client.ServiceContent.RootFolder, kinds, true)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = containerView.Destroy(ctx)
+	}()
+
+	counts := make(map[string]int)
+	for _, kind := range kinds {
+		refs, err := containerView.Find(ctx, []string{kind}, nil)
+		if err != nil {
+			return nil, err
+		}
+		counts[kind] = len(refs)
+	}
+	return &v1beta1.ProviderInventory{
+		Datacenters: counts["Datacenter"],
+		Clusters:    counts["ClusterComputeResource"],
+		Hosts:       counts["HostSystem"],
+		VMs:         counts["VirtualMachine"],
+	}, nil
+}
+
+// KeepAlive keeps the session alive, logging in again if it has expired.
+func (r RichVmwareClient) KeepAlive() error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	userSession, err := r.sessionManager.UserSession(ctx)
+	if err != nil {
+		return err
+	}
+	if userSession != nil {
+		return nil
+	}
+	return r.sessionManager.Login(ctx, r.user)
+}
+
 // Close logs out and shuts down idle connections.
 func (r RichVmwareClient) Close() error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
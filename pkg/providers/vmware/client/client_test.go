@@ -1,18 +1,22 @@
 package client_test
 
 import (
+	"github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
 	"github.com/kubevirt/vm-import-operator/pkg/providers/vmware/client"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
 )
 
 var _ = Describe("Test VMware rich client", func() {
 
 	It("should fail to create a client if the scheme is invalid", func() {
-		_, err := client.NewRichVMWareClient("invalidUrl", "username", "password", "")
+		_, err := client.NewRichVMWareClient("invalidUrl", "username", "password", client.TLSOptions{})
 		Expect(err).ToNot(BeNil())
 	})
 
@@ -25,7 +29,7 @@ var _ = Describe("Test VMware rich client", func() {
 		password, _ := server.URL.User.Password()
 		server.Close()
 		model.Remove()
-		_, err := client.NewRichVMWareClient(unreachableApiUrl, username, password, "")
+		_, err := client.NewRichVMWareClient(unreachableApiUrl, username, password, client.TLSOptions{})
 		Expect(err).ToNot(BeNil())
 	})
 
@@ -43,6 +47,24 @@ var _ = Describe("Test VMware rich client", func() {
 		Entry("ESXi", simulator.ESX()),
 	)
 
+	It("should log in again when keeping an expired session alive", func() {
+		model := simulator.VPX()
+		_ = model.Create()
+		server := model.Service.NewServer()
+		defer model.Remove()
+		defer server.Close()
+		richClient, err := createRichClient(server)
+		Expect(err).To(BeNil())
+		Expect(richClient.Close()).To(Succeed())
+
+		err = richClient.KeepAlive()
+		Expect(err).To(BeNil())
+
+		_, uuid := getVMIdentifiers()
+		_, err = richClient.GetVM(&uuid, nil, nil, nil)
+		Expect(err).To(BeNil())
+	})
+
 	DescribeTable("should retrieve a VM by ID", func(model *simulator.Model) {
 		_ = model.Create()
 		server := model.Service.NewServer()
@@ -117,6 +139,173 @@ var _ = Describe("Test VMware rich client", func() {
 		Entry("ESXi", simulator.ESX()),
 	)
 
+	DescribeTable("should retrieve a VM by MoRef", func(model *simulator.Model) {
+		_ = model.Create()
+		server := model.Service.NewServer()
+		defer model.Remove()
+		defer server.Close()
+		richClient, err := createRichClient(server)
+		Expect(err).To(BeNil())
+		vmRef, _ := getVMIdentifiers()
+
+		vm, err := richClient.FindVM(client.VMLookup{MoRef: &vmRef})
+
+		Expect(err).To(BeNil())
+		Expect(vm.Reference().Value).To(Equal(vmRef))
+	},
+		Entry("vCenter", simulator.VPX()),
+		Entry("ESXi", simulator.ESX()),
+	)
+
+	DescribeTable("should return an error when a VM is not found by MoRef", func(model *simulator.Model) {
+		_ = model.Create()
+		server := model.Service.NewServer()
+		defer model.Remove()
+		defer server.Close()
+		richClient, err := createRichClient(server)
+		Expect(err).To(BeNil())
+		vmRef := "vm-invalid"
+
+		_, err = richClient.FindVM(client.VMLookup{MoRef: &vmRef})
+
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(Equal("vm 'vm-invalid' not found"))
+	},
+		Entry("vCenter", simulator.VPX()),
+		Entry("ESXi", simulator.ESX()),
+	)
+
+	It("should retrieve a VM by instance UUID", func() {
+		model := simulator.VPX()
+		_ = model.Create()
+		server := model.Service.NewServer()
+		defer model.Remove()
+		defer server.Close()
+		richClient, err := createRichClient(server)
+		Expect(err).To(BeNil())
+		vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+		instanceUUID := vm.Config.InstanceUuid
+
+		retrievedVm, err := richClient.FindVM(client.VMLookup{ID: &instanceUUID, InstanceUUID: true})
+
+		Expect(err).To(BeNil())
+		Expect(retrievedVm.Reference()).To(Equal(vm.Reference()))
+
+		_, err = richClient.FindVM(client.VMLookup{ID: &instanceUUID})
+		Expect(err).ToNot(BeNil())
+	})
+
+	Describe("scoped lookups", func() {
+		var model *simulator.Model
+		var server *simulator.Server
+		var richClient *client.RichVmwareClient
+		var clusterVm *simulator.VirtualMachine
+		var hostVm *simulator.VirtualMachine
+
+		BeforeEach(func() {
+			model = simulator.VPX()
+			_ = model.Create()
+			server = model.Service.NewServer()
+			var err error
+			richClient, err = createRichClient(server)
+			Expect(err).To(BeNil())
+
+			clusterVm = simulator.Map.Get(getVMRefByName("DC0_C0_RP0_VM0")).(*simulator.VirtualMachine)
+			hostVm = simulator.Map.Get(getVMRefByName("DC0_H0_VM0")).(*simulator.VirtualMachine)
+			// give both VMs the same name
+			clusterVm.Name = hostVm.Name
+		})
+
+		AfterEach(func() {
+			server.Close()
+			model.Remove()
+		})
+
+		It("should report an ambiguous lookup by name", func() {
+			_, err := richClient.FindVM(client.VMLookup{Name: &hostVm.Name})
+
+			Expect(err).ToNot(BeNil())
+			ambiguous, ok := err.(*client.AmbiguousVMError)
+			Expect(ok).To(BeTrue())
+			Expect(ambiguous.Matches).To(ConsistOf(clusterVm.Reference().Value, hostVm.Reference().Value))
+		})
+
+		It("should retrieve a VM by name within a cluster", func() {
+			cluster := "DC0_C0"
+			vm, err := richClient.FindVM(client.VMLookup{Name: &hostVm.Name, Cluster: &cluster})
+
+			Expect(err).To(BeNil())
+			Expect(vm.Reference()).To(Equal(clusterVm.Reference()))
+		})
+
+		It("should retrieve a VM by name within a resource pool", func() {
+			datacenter := "DC0"
+			pool := "DC0_H0/Resources"
+			vm, err := richClient.FindVM(client.VMLookup{Name: &hostVm.Name, Datacenter: &datacenter, ResourcePool: &pool})
+
+			Expect(err).To(BeNil())
+			Expect(vm.Reference()).To(Equal(hostVm.Reference()))
+		})
+
+		It("should still report an ambiguous lookup within a folder containing both VMs", func() {
+			folder := "/DC0/vm"
+			_, err := richClient.FindVM(client.VMLookup{Name: &hostVm.Name, Folder: &folder})
+
+			Expect(err).ToNot(BeNil())
+			_, ok := err.(*client.AmbiguousVMError)
+			Expect(ok).To(BeTrue())
+		})
+
+		It("should not find a VM outside of the scope", func() {
+			cluster := "DC0_C0"
+			name := "DC0_H0_VM1"
+			_, err := richClient.FindVM(client.VMLookup{Name: &name, Cluster: &cluster})
+
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("vm 'DC0_H0_VM1' not found"))
+		})
+
+		It("should retrieve a VM by UUID within a datacenter and a cluster", func() {
+			datacenter := "DC0"
+			cluster := "DC0_C0"
+			uuid := clusterVm.Config.Uuid
+			vm, err := richClient.FindVM(client.VMLookup{ID: &uuid, Datacenter: &datacenter, Cluster: &cluster})
+
+			Expect(err).To(BeNil())
+			Expect(vm.Reference()).To(Equal(clusterVm.Reference()))
+		})
+
+		It("should not find a VM by UUID outside of the scope", func() {
+			cluster := "DC0_C0"
+			uuid := hostVm.Config.Uuid
+			_, err := richClient.FindVM(client.VMLookup{ID: &uuid, Cluster: &cluster})
+
+			Expect(err).ToNot(BeNil())
+			Expect(err.Error()).To(Equal("vm '" + uuid + "' not found"))
+		})
+
+		It("should report an ambiguous lookup by a BIOS UUID shared by two VMs", func() {
+			hostVm.Config.Uuid = clusterVm.Config.Uuid
+			uuid := clusterVm.Config.Uuid
+			_, err := richClient.FindVM(client.VMLookup{ID: &uuid})
+
+			Expect(err).ToNot(BeNil())
+			ambiguous, ok := err.(*client.AmbiguousVMError)
+			Expect(ok).To(BeTrue())
+			Expect(ambiguous.Matches).To(ConsistOf(clusterVm.Reference().Value, hostVm.Reference().Value))
+		})
+
+		It("should retrieve the VM within the cluster among the VMs sharing its BIOS UUID", func() {
+			hostVm.Config.Uuid = clusterVm.Config.Uuid
+			cluster := "DC0_C0"
+			uuid := clusterVm.Config.Uuid
+			vm, err := richClient.FindVM(client.VMLookup{ID: &uuid, Cluster: &cluster})
+
+			Expect(err).To(BeNil())
+			Expect(vm.Reference()).To(Equal(clusterVm.Reference()))
+		})
+	})
+
 	DescribeTable("should create a snapshot for a VM", func(model *simulator.Model) {
 		_ = model.Create()
 		server := model.Service.NewServer()
@@ -145,7 +334,7 @@ var _ = Describe("Test VMware rich client", func() {
 		Expect(err).To(BeNil())
 		moRef, _ := getVMIdentifiers()
 
-		err = richClient.StopVM(moRef)
+		err = richClient.StopVM(moRef, v1beta1.GuestShutdown)
 		Expect(err).To(BeNil())
 
 		err = richClient.StartVM(moRef)
@@ -155,6 +344,37 @@ var _ = Describe("Test VMware rich client", func() {
 		Entry("ESXi", simulator.ESX()),
 	)
 
+	It("should power off a VM with a hard shutdown", func() {
+		model := simulator.VPX()
+		_ = model.Create()
+		server := model.Service.NewServer()
+		defer model.Remove()
+		defer server.Close()
+		richClient, err := createRichClient(server)
+		Expect(err).To(BeNil())
+		moRef, _ := getVMIdentifiers()
+
+		err = richClient.StopVM(moRef, v1beta1.HardShutdown)
+		Expect(err).To(BeNil())
+
+		vm := simulator.Map.Get(types.ManagedObjectReference{Type: "VirtualMachine", Value: moRef}).(*simulator.VirtualMachine)
+		Expect(vm.Runtime.PowerState).To(Equal(types.VirtualMachinePowerStatePoweredOff))
+	})
+
+	It("should not support an ACPI shutdown", func() {
+		model := simulator.VPX()
+		_ = model.Create()
+		server := model.Service.NewServer()
+		defer model.Remove()
+		defer server.Close()
+		richClient, err := createRichClient(server)
+		Expect(err).To(BeNil())
+		moRef, _ := getVMIdentifiers()
+
+		err = richClient.StopVM(moRef, v1beta1.ACPIShutdown)
+		Expect(err).ToNot(BeNil())
+	})
+
 	DescribeTable("should not throw an error when trying to power off an VM that's already off", func(model *simulator.Model) {
 		_ = model.Create()
 		server := model.Service.NewServer()
@@ -164,10 +384,10 @@ var _ = Describe("Test VMware rich client", func() {
 		Expect(err).To(BeNil())
 		moRef, _ := getVMIdentifiers()
 
-		err = richClient.StopVM(moRef)
+		err = richClient.StopVM(moRef, v1beta1.GuestShutdown)
 		Expect(err).To(BeNil())
 
-		err = richClient.StopVM(moRef)
+		err = richClient.StopVM(moRef, v1beta1.GuestShutdown)
 		Expect(err).To(BeNil())
 	},
 		Entry("vCenter", simulator.VPX()),
@@ -192,15 +412,117 @@ var _ = Describe("Test VMware rich client", func() {
 		Entry("vCenter", simulator.VPX()),
 		Entry("ESXi", simulator.ESX()),
 	)
+
+	DescribeTable("should report whether it is connected to a vCenter", func(model *simulator.Model, isVCenter bool) {
+		_ = model.Create()
+		server := model.Service.NewServer()
+		defer model.Remove()
+		defer server.Close()
+		richClient, err := createRichClient(server)
+		Expect(err).To(BeNil())
+
+		Expect(richClient.IsVCenter()).To(Equal(isVCenter))
+	},
+		Entry("vCenter", simulator.VPX(), true),
+		Entry("ESXi", simulator.ESX(), false),
+	)
+
+	DescribeTable("should retrieve the properties of the host a VM is running on", func(model *simulator.Model) {
+		_ = model.Create()
+		server := model.Service.NewServer()
+		defer model.Remove()
+		defer server.Close()
+		richClient, err := createRichClient(server)
+		Expect(err).To(BeNil())
+		_, uuid := getVMIdentifiers()
+		rawVm, err := richClient.GetVM(&uuid, nil, nil, nil)
+		Expect(err).To(BeNil())
+
+		hostProperties, err := richClient.GetVMHostProperties(rawVm.(*object.VirtualMachine))
+		Expect(err).To(BeNil())
+		Expect(hostProperties).ToNot(BeNil())
+		Expect(hostProperties.Summary.Config.Name).ToNot(BeEmpty())
+	},
+		Entry("vCenter", simulator.VPX()),
+		Entry("ESXi", simulator.ESX()),
+	)
+
+	DescribeTable("should retrieve the URL of a datastore file", func(model *simulator.Model, datacenterPath string) {
+		_ = model.Create()
+		server := model.Service.NewServer()
+		defer model.Remove()
+		defer server.Close()
+		richClient, err := createRichClient(server)
+		Expect(err).To(BeNil())
+		vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+		var vmPath object.DatastorePath
+		Expect(vmPath.FromString(vm.Config.Files.VmPathName)).To(BeTrue())
+
+		fileURL, _, err := richClient.GetDatastoreFile(vm.Datastore[0].Value, vm.Config.Files.VmPathName)
+
+		Expect(err).To(BeNil())
+		Expect(fileURL).To(HavePrefix(server.URL.Scheme + "://" + server.URL.Host + "/folder/" + vmPath.Path + "?"))
+		Expect(fileURL).To(ContainSubstring("dcPath=" + datacenterPath))
+		Expect(fileURL).To(ContainSubstring("dsName=" + vmPath.Datastore))
+	},
+		Entry("vCenter", simulator.VPX(), "DC0"),
+		Entry("ESXi", simulator.ESX(), "ha-datacenter"),
+	)
+
+	It("should fail to retrieve a datastore file that doesn't exist", func() {
+		model := simulator.VPX()
+		_ = model.Create()
+		server := model.Service.NewServer()
+		defer model.Remove()
+		defer server.Close()
+		richClient, err := createRichClient(server)
+		Expect(err).To(BeNil())
+		vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+
+		_, _, err = richClient.GetDatastoreFile(vm.Datastore[0].Value, "[LocalDS_0] iso/missing.iso")
+
+		Expect(err).ToNot(BeNil())
+	})
 })
 
 func createRichClient(server *simulator.Server) (*client.RichVmwareClient, error) {
+	simulator.Map.Put(&searchIndex{simulator.Map.SearchIndex()})
 	username := server.URL.User.Username()
 	password, _ := server.URL.User.Password()
-	return client.NewRichVMWareClient(server.URL.String(), username, password, "")
+	return client.NewRichVMWareClient(server.URL.String(), username, password, client.TLSOptions{})
 }
 
 func getVMIdentifiers() (string, string) {
 	vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
 	return vm.Reference().Value, vm.Config.Uuid
 }
+
+func getVMRefByName(name string) types.ManagedObjectReference {
+	for _, obj := range simulator.Map.All("VirtualMachine") {
+		if obj.Entity().Name == name {
+			return obj.Reference()
+		}
+	}
+	return types.ManagedObjectReference{}
+}
+
+// searchIndex adds FindAllByUuid, which vcsim doesn't implement, to the search index of the simulator. The datacenter
+// of the request is ignored.
+type searchIndex struct {
+	*simulator.SearchIndex
+}
+
+func (s *searchIndex) FindAllByUuid(req *types.FindAllByUuid) soap.HasFault {
+	body := &methods.FindAllByUuidBody{Res: new(types.FindAllByUuidResponse)}
+	for _, obj := range simulator.Map.All("VirtualMachine") {
+		vm := obj.(*simulator.VirtualMachine)
+		uuid := vm.Config.Uuid
+		if req.InstanceUuid != nil && *req.InstanceUuid {
+			uuid = vm.Config.InstanceUuid
+		}
+		if uuid == req.Uuid {
+			body.Res.Returnval = append(body.Res.Returnval, vm.Reference())
+		}
+	}
+	return body
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// TLSOptions defines how the certificate of the vCenter or ESXi host is verified. The system CA certificates
+// are used when none of the options is set.
+type TLSOptions struct {
+	// Thumbprint pins the certificate to its SHA-1 or SHA-256 fingerprint, in colon-separated hexadecimal octets
+	Thumbprint string
+	// CACert holds the PEM encoded CA certificates the certificate is verified with
+	CACert string
+	// InsecureSkipVerify disables the verification of the certificate
+	InsecureSkipVerify bool
+}
+
+// ThumbprintMismatchError is returned when the certificate of the vCenter or ESXi host doesn't have the pinned
+// fingerprint
+type ThumbprintMismatchError struct {
+	Host       string
+	Expected   string
+	Thumbprint string
+}
+
+func (e ThumbprintMismatchError) Error() string {
+	return fmt.Sprintf("the certificate of %s has the thumbprint %s, expected %s", e.Host, e.Thumbprint, e.Expected)
+}
+
+// peerCertificate records the certificate presented by the vCenter or ESXi host
+type peerCertificate struct {
+	lock       sync.Mutex
+	thumbprint string
+}
+
+func (p *peerCertificate) set(thumbprint string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.thumbprint = thumbprint
+}
+
+func (p *peerCertificate) get() string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.thumbprint
+}
+
+// newTLSConfig builds the TLS configuration of the connection to host. The SHA-1 thumbprint of the certificate
+// presented by the host is recorded in peer, because VDDK only accepts SHA-1 thumbprints.
+func newTLSConfig(host string, options TLSOptions, peer *peerCertificate) (*tls.Config, error) {
+	config := &tls.Config{}
+	var expected func([]byte) string
+	switch {
+	case options.InsecureSkipVerify:
+		if options.Thumbprint != "" || options.CACert != "" {
+			return nil, fmt.Errorf("insecureSkipVerify can't be combined with a thumbprint or a CA certificate")
+		}
+		config.InsecureSkipVerify = true
+	case options.Thumbprint != "":
+		if options.CACert != "" {
+			return nil, fmt.Errorf("a thumbprint can't be combined with a CA certificate")
+		}
+		hash, err := thumbprintHash(options.Thumbprint)
+		if err != nil {
+			return nil, err
+		}
+		expected = hash
+		// the certificate is verified by its thumbprint instead of its chain
+		config.InsecureSkipVerify = true
+	case options.CACert != "":
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(options.CACert)) {
+			return nil, fmt.Errorf("the CA certificate of %s doesn't contain any PEM encoded certificate", host)
+		}
+		config.RootCAs = pool
+	}
+
+	config.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return fmt.Errorf("%s didn't present a certificate", host)
+		}
+		if expected != nil {
+			thumbprint := expected(rawCerts[0])
+			if !strings.EqualFold(thumbprint, options.Thumbprint) {
+				return ThumbprintMismatchError{Host: host, Expected: options.Thumbprint, Thumbprint: thumbprint}
+			}
+		}
+		peer.set(ThumbprintSHA1(rawCerts[0]))
+		return nil
+	}
+	return config, nil
+}
+
+// IsSHA1Thumbprint returns whether the thumbprint is a SHA-1 fingerprint, as opposed to a SHA-256 one
+func IsSHA1Thumbprint(thumbprint string) bool {
+	return len(strings.Split(thumbprint, ":")) == sha1.Size
+}
+
+// ThumbprintSHA1 returns the SHA-1 fingerprint of the DER encoded certificate, in the format VDDK expects
+func ThumbprintSHA1(cert []byte) string {
+	sum := sha1.Sum(cert)
+	return formatThumbprint(sum[:])
+}
+
+// ThumbprintSHA256 returns the SHA-256 fingerprint of the DER encoded certificate
+func ThumbprintSHA256(cert []byte) string {
+	sum := sha256.Sum256(cert)
+	return formatThumbprint(sum[:])
+}
+
+func thumbprintHash(thumbprint string) (func([]byte) string, error) {
+	switch len(strings.Split(thumbprint, ":")) {
+	case sha1.Size:
+		return ThumbprintSHA1, nil
+	case sha256.Size:
+		return ThumbprintSHA256, nil
+	}
+	return nil, fmt.Errorf("thumbprint %s is neither a SHA-1 nor a SHA-256 fingerprint in colon-separated hexadecimal octets", thumbprint)
+}
+
+func formatThumbprint(sum []byte) string {
+	octets := make([]string, len(sum))
+	for i, b := range sum {
+		octets[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(octets, ":")
+}
@@ -0,0 +1,89 @@
+package client_test
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"errors"
+
+	"github.com/kubevirt/vm-import-operator/pkg/providers/vmware/client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/simulator"
+)
+
+var _ = Describe("TLS verification of the vCenter or ESXi host", func() {
+	var (
+		model  *simulator.Model
+		server *simulator.Server
+		cert   []byte
+	)
+
+	BeforeEach(func() {
+		model = simulator.VPX()
+		Expect(model.Create()).To(Succeed())
+		model.Service.TLS = new(tls.Config)
+		server = model.Service.NewServer()
+		cert = server.Certificate().Raw
+	})
+
+	AfterEach(func() {
+		server.Close()
+		model.Remove()
+	})
+
+	connect := func(options client.TLSOptions) (*client.RichVmwareClient, error) {
+		username := server.URL.User.Username()
+		password, _ := server.URL.User.Password()
+		return client.NewRichVMWareClient(server.URL.String(), username, password, options)
+	}
+
+	DescribeTable("should connect", func(options func() client.TLSOptions) {
+		richClient, err := connect(options())
+
+		Expect(err).To(BeNil())
+		Expect(richClient.Thumbprint()).To(Equal(client.ThumbprintSHA1(cert)))
+	},
+		Entry("with a SHA-1 thumbprint", func() client.TLSOptions {
+			return client.TLSOptions{Thumbprint: client.ThumbprintSHA1(cert)}
+		}),
+		Entry("with a SHA-256 thumbprint", func() client.TLSOptions {
+			return client.TLSOptions{Thumbprint: client.ThumbprintSHA256(cert)}
+		}),
+		Entry("with a CA certificate", func() client.TLSOptions {
+			return client.TLSOptions{CACert: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}))}
+		}),
+		Entry("without verification", func() client.TLSOptions {
+			return client.TLSOptions{InsecureSkipVerify: true}
+		}),
+	)
+
+	It("should reject a certificate with another thumbprint", func() {
+		thumbprint := "21:EA:74:11:59:89:5E:20:D5:D9:A2:39:5C:6A:2D:36:38:B2:52:2B:21:EA:74:11:59:89:5E:20:D5:D9:A2:39"
+
+		_, err := connect(client.TLSOptions{Thumbprint: thumbprint})
+
+		var mismatch client.ThumbprintMismatchError
+		Expect(errors.As(err, &mismatch)).To(BeTrue())
+		Expect(mismatch.Thumbprint).To(Equal(client.ThumbprintSHA256(cert)))
+	})
+
+	It("should reject a certificate that isn't trusted by the system", func() {
+		_, err := connect(client.TLSOptions{})
+
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("should reject a thumbprint that is neither SHA-1 nor SHA-256", func() {
+		_, err := connect(client.TLSOptions{Thumbprint: "21:EA:74"})
+
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("neither a SHA-1 nor a SHA-256 fingerprint"))
+	})
+
+	It("should not skip the verification when a thumbprint is pinned", func() {
+		_, err := connect(client.TLSOptions{Thumbprint: client.ThumbprintSHA1(cert), InsecureSkipVerify: true})
+
+		Expect(err).ToNot(BeNil())
+	})
+})
@@ -9,7 +9,9 @@ import (
 	"strings"
 
 	v1beta1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	provider "github.com/kubevirt/vm-import-operator/pkg/providers"
 	vos "github.com/kubevirt/vm-import-operator/pkg/providers/vmware/os"
+	"github.com/kubevirt/vm-import-operator/pkg/storageprofiles"
 	"github.com/kubevirt/vm-import-operator/pkg/utils"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/mo"
@@ -76,6 +78,38 @@ type Disk struct {
 	ID              string
 	Name            string
 	Key             int32
+	Thin            bool
+	// AllocatedBytes is the size of the disk on the datastore, 0 when it isn't known
+	AllocatedBytes int64
+	// Bootable tells whether the VM boots from the disk
+	Bootable bool
+}
+
+// SourceDisk describes the disk for the disk selection of the import
+func (d Disk) SourceDisk() provider.SourceDisk {
+	return provider.SourceDisk{
+		ID:        d.ID,
+		Names:     []string{d.Name},
+		Bootable:  d.Bootable,
+		SizeBytes: d.Capacity,
+	}
+}
+
+// Cdrom is an abstraction of a VMWare VirtualCdrom with an ISO image inserted
+type Cdrom struct {
+	// FileName is the datastore path of the ISO image
+	FileName       string
+	DatastoreMoRef string
+	DatastoreName  string
+	Name           string
+	Key            int32
+}
+
+// ISOFile is the ISO image of a CD-ROM on its datastore
+type ISOFile struct {
+	// URL is the URL the ISO image is downloaded from
+	URL  string
+	Size int64
 }
 
 // Nic is an abstraction of a VMWare VirtualEthernetCard
@@ -117,6 +151,8 @@ func BuildDisks(vmProperties *mo.VirtualMachine) []Disk {
 			disk := Disk{
 				BackingFileName: backingFileName,
 				Capacity:        getDiskCapacityInBytes(virtualDisk),
+				AllocatedBytes:  getDiskAllocatedBytes(vmProperties, virtualDisk.Key),
+				Thin:            isThinProvisioned(virtualDisk),
 				DatastoreMoRef:  datastoreMoRef,
 				DatastoreName:   datastoreName,
 				ID:              diskId,
@@ -130,9 +166,61 @@ func BuildDisks(vmProperties *mo.VirtualMachine) []Disk {
 
 	}
 
+	bootDisk := getBootDiskKey(vmProperties, disks)
+	for i := range disks {
+		disks[i].Bootable = disks[i].Key == bootDisk
+	}
+
 	return disks
 }
 
+// BuildCdroms retrieves each of the VM's VirtualCdroms with an ISO image inserted
+// and pulls out the values that are needed for import
+func BuildCdroms(vmProperties *mo.VirtualMachine) []Cdrom {
+	cdroms := make([]Cdrom, 0)
+	for _, device := range vmProperties.Config.Hardware.Device {
+		cdrom, ok := device.(*types.VirtualCdrom)
+		if !ok {
+			continue
+		}
+		backing, ok := cdrom.Backing.(*types.VirtualCdromIsoBackingInfo)
+		if !ok || backing.FileName == "" {
+			continue
+		}
+		var datastoreMoRef string
+		if backing.Datastore != nil {
+			datastoreMoRef = backing.Datastore.Value
+		}
+		var name string
+		if cdrom.DeviceInfo != nil {
+			name = cdrom.DeviceInfo.GetDescription().Label
+		}
+		cdroms = append(cdroms, Cdrom{
+			FileName:       backing.FileName,
+			DatastoreMoRef: datastoreMoRef,
+			DatastoreName:  getDatastoreNameFromBacking(backing.FileName),
+			Name:           name,
+			Key:            cdrom.Key,
+		})
+	}
+	return cdroms
+}
+
+// getBootDiskKey returns the key of the disk the VM boots from: the first disk in its boot order, or else its first disk
+func getBootDiskKey(vmProperties *mo.VirtualMachine, disks []Disk) int32 {
+	if bootOptions := vmProperties.Config.BootOptions; bootOptions != nil {
+		for _, bootable := range bootOptions.BootOrder {
+			if bootableDisk, ok := bootable.(*types.VirtualMachineBootOptionsBootableDiskDevice); ok {
+				return bootableDisk.DeviceKey
+			}
+		}
+	}
+	if len(disks) > 0 {
+		return disks[0].Key
+	}
+	return -1
+}
+
 // BuildNics retrieves each of the VM's VirtualEthernetCards
 // and pulls out the values that are needed for import
 func BuildNics(vmProperties *mo.VirtualMachine) []Nic {
@@ -201,6 +289,12 @@ type VmwareMapper struct {
 	osFinder       vos.OSFinder
 	vm             *object.VirtualMachine
 	vmProperties   *mo.VirtualMachine
+	// storageProfiles recommend the access and volume modes of the PVCs that aren't set in the mappings
+	storageProfiles storageprofiles.Finder
+	// diskSelection selects the disks that are imported, all of them when it is nil
+	diskSelection *v1beta1.DiskSelectionSpec
+	// isoFiles are the ISO images of the CD-ROMs that are imported, keyed by their datastore path
+	isoFiles map[string]ISOFile
 }
 
 // NewVmwareMapper creates a new VmwareMapper struct
@@ -217,6 +311,23 @@ func NewVmwareMapper(vm *object.VirtualMachine, vmProperties *mo.VirtualMachine,
 	}
 }
 
+// UseStorageProfiles makes the PVCs of the disks use the best access and volume modes supported by the StorageProfile
+// of their storage class, unless the mapping sets them
+func (r *VmwareMapper) UseStorageProfiles(storageProfiles storageprofiles.Finder) {
+	r.storageProfiles = storageProfiles
+}
+
+// SelectDisks makes the mapper import only the disks selected by the disk selection
+func (r *VmwareMapper) SelectDisks(selection *v1beta1.DiskSelectionSpec) {
+	r.diskSelection = selection
+}
+
+// UseISOFiles makes the mapper import the ISO images of the CD-ROMs from their datastores, keyed by their datastore
+// path
+func (r *VmwareMapper) UseISOFiles(isoFiles map[string]ISOFile) {
+	r.isoFiles = isoFiles
+}
+
 // buildNics retrieves each of the VM's VirtualEthernetCards
 // and pulls out the values that are needed for import
 func (r *VmwareMapper) buildNics() {
@@ -299,6 +410,28 @@ func (r *VmwareMapper) getVolumeModeForDisk(mapping *v1beta1.StorageResourceMapp
 	return &defaultVolumeMode
 }
 
+func (r *VmwareMapper) getPreallocationForDisk(mapping *v1beta1.StorageResourceMappingItem) *v1beta1.PreallocationMode {
+	if mapping != nil {
+		return mapping.Preallocation
+	}
+	return nil
+}
+
+// applyStorageProfile returns the access and volume modes of the PVC of a disk. Those that aren't set in the mapping
+// are the best ones supported by the StorageProfile of its storage class, if any.
+func (r *VmwareMapper) applyStorageProfile(storageClass *string, mapping *v1beta1.StorageResourceMappingItem) (corev1.PersistentVolumeAccessMode, *corev1.PersistentVolumeMode, error) {
+	var mappedAccessMode *corev1.PersistentVolumeAccessMode
+	var mappedVolumeMode *corev1.PersistentVolumeMode
+	if mapping != nil {
+		mappedAccessMode, mappedVolumeMode = mapping.AccessMode, mapping.VolumeMode
+	}
+	profileAccessMode, profileVolumeMode, found, err := storageprofiles.Recommend(r.storageProfiles, storageClass, mappedAccessMode, mappedVolumeMode)
+	if err != nil || !found {
+		return r.getAccessModeForDisk(mapping), r.getVolumeModeForDisk(mapping), err
+	}
+	return profileAccessMode, &profileVolumeMode, nil
+}
+
 // MapDataVolumes maps the VMware disks to CDI DataVolumes
 func (r *VmwareMapper) MapDataVolumes(_ *string, filesystemOverhead cdiv1.FilesystemOverhead) (map[string]cdiv1.DataVolume, error) {
 	err := r.buildDisks()
@@ -309,11 +442,18 @@ func (r *VmwareMapper) MapDataVolumes(_ *string, filesystemOverhead cdiv1.Filesy
 	dvs := make(map[string]cdiv1.DataVolume)
 
 	for _, disk := range *r.disks {
-		dvName := fmt.Sprintf("%s-%d", r.instanceUID, disk.Key)
+		if !provider.DiskSelected(r.diskSelection, disk.SourceDisk()) {
+			continue
+		}
+		dvName := r.dataVolumeName(disk)
 
 		mapping := r.getMappingForDisk(disk)
 
 		storageClass := r.getStorageClassForDisk(mapping)
+		accessMode, volumeMode, err := r.applyStorageProfile(storageClass, mapping)
+		if err != nil {
+			return nil, err
+		}
 
 		overhead := utils.GetOverheadForStorageClass(filesystemOverhead, storageClass)
 
@@ -325,7 +465,9 @@ func (r *VmwareMapper) MapDataVolumes(_ *string, filesystemOverhead cdiv1.Filesy
 			return nil, err
 		}
 
-		dvs[dvName] = cdiv1.DataVolume{
+		preallocate := utils.Preallocate(r.getPreallocationForDisk(mapping), disk.Thin)
+
+		dv := cdiv1.DataVolume{
 			TypeMeta: metav1.TypeMeta{
 				APIVersion: cdiAPIVersion,
 				Kind:       dataVolumeKind,
@@ -346,9 +488,9 @@ func (r *VmwareMapper) MapDataVolumes(_ *string, filesystemOverhead cdiv1.Filesy
 				},
 				PVC: &corev1.PersistentVolumeClaimSpec{
 					AccessModes: []corev1.PersistentVolumeAccessMode{
-						r.getAccessModeForDisk(mapping),
+						accessMode,
 					},
-					VolumeMode: r.getVolumeModeForDisk(mapping),
+					VolumeMode: volumeMode,
 					Resources: corev1.ResourceRequirements{
 						Requests: corev1.ResourceList{
 							corev1.ResourceStorage: capacityAsQuantity,
@@ -358,14 +500,168 @@ func (r *VmwareMapper) MapDataVolumes(_ *string, filesystemOverhead cdiv1.Filesy
 				},
 			},
 		}
+		utils.SetPreallocation(&dv, preallocate, utils.EstimateConsumedBytes(capacityAsQuantity.Value(), disk.AllocatedBytes, preallocate))
+		utils.SetSourceBytes(&dv, disk.Capacity)
+		dvs[dvName] = dv
 	}
-	return dvs, nil
+
+	err = r.mapCdromDataVolumes(filesystemOverhead, dvs)
+	return dvs, err
+}
+
+// mapCdromDataVolumes adds the data volumes the ISO images of the CD-ROMs are imported or cloned to. The images that
+// can't be downloaded from their datastore and aren't mapped to a PVC or a DataSource are left out.
+func (r *VmwareMapper) mapCdromDataVolumes(filesystemOverhead cdiv1.FilesystemOverhead, dvs map[string]cdiv1.DataVolume) error {
+	for _, cdrom := range BuildCdroms(r.vmProperties) {
+		dvName := r.cdromDataVolumeName(cdrom)
+		mapping := r.getMappingForCdrom(cdrom)
+		if dv, clone := provider.CdromCloneDataVolume(dvName, r.namespace, mapping); clone {
+			dvs[dvName] = dv
+			continue
+		}
+		if provider.CdromMappingTypeOf(mapping) != v1beta1.ImportCdromMapping {
+			continue
+		}
+		isoFile, found := r.isoFiles[cdrom.FileName]
+		if !found {
+			continue
+		}
+
+		var storageClass *string
+		if mapping != nil && mapping.Target.Name != defaultStorageClassTargetName {
+			storageClass = &mapping.Target.Name
+		}
+		accessMode, volumeMode, err := r.applyStorageProfile(storageClass, nil)
+		if err != nil {
+			return err
+		}
+		overhead := utils.GetOverheadForStorageClass(filesystemOverhead, storageClass)
+		sizeWithOverhead := int64(math.Ceil(float64(utils.RoundUp(isoFile.Size, 1048576)) / (1 - overhead)))
+		size, err := bytesToQuantity(sizeWithOverhead)
+		if err != nil {
+			return err
+		}
+
+		dv := cdiv1.DataVolume{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: cdiAPIVersion,
+				Kind:       dataVolumeKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      dvName,
+				Namespace: r.namespace,
+			},
+			Spec: cdiv1.DataVolumeSpec{
+				Source: cdiv1.DataVolumeSource{
+					// the datastore file is downloaded with the credentials of the import, which CDI reads from
+					// the same keys of the secret as for VDDK
+					HTTP: &cdiv1.DataVolumeSourceHTTP{
+						URL:       isoFile.URL,
+						SecretRef: r.credentials.SecretName,
+					},
+				},
+				PVC: &corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{
+						accessMode,
+					},
+					VolumeMode: volumeMode,
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: size,
+						},
+					},
+					StorageClassName: storageClass,
+				},
+			},
+		}
+		utils.SetSourceBytes(&dv, isoFile.Size)
+		dvs[dvName] = dv
+	}
+	return nil
+}
+
+// mapCdromClaims attaches the CD-ROMs mapped to a PVC of the namespace of the import to that PVC
+func (r *VmwareMapper) mapCdromClaims(vmSpec *kubevirtv1.VirtualMachine) {
+	for _, cdrom := range BuildCdroms(r.vmProperties) {
+		claimName, found := provider.CdromClaimName(r.getMappingForCdrom(cdrom), r.namespace)
+		if !found {
+			continue
+		}
+		name := utils.EnsureLabelValueLength(fmt.Sprintf("cdrom-%d", cdrom.Key))
+		provider.AddCdrom(vmSpec, name, kubevirtv1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: claimName,
+				ReadOnly:  true,
+			},
+		})
+	}
+}
+
+// UnmappedCdroms returns the ISO images of the CD-ROMs of the VM that can't be imported, because they aren't among the
+// datastore files isoFiles, and aren't mapped to a PVC or a DataSource either
+func UnmappedCdroms(vmProperties *mo.VirtualMachine, mappings *v1beta1.VmwareMappings, isoFiles map[string]ISOFile) []string {
+	var unmapped []string
+	for _, cdrom := range BuildCdroms(vmProperties) {
+		if provider.CdromMappingTypeOf(cdromMapping(mappings, cdrom)) != v1beta1.ImportCdromMapping {
+			continue
+		}
+		if _, found := isoFiles[cdrom.FileName]; !found {
+			unmapped = append(unmapped, cdrom.FileName)
+		}
+	}
+	return unmapped
+}
+
+func (r *VmwareMapper) getMappingForCdrom(cdrom Cdrom) *v1beta1.CdromResourceMappingItem {
+	return cdromMapping(r.mappings, cdrom)
+}
+
+// cdromMapping returns the mapping of the CD-ROM by the datastore path of its ISO image or by its label
+func cdromMapping(mappings *v1beta1.VmwareMappings, cdrom Cdrom) *v1beta1.CdromResourceMappingItem {
+	if mappings == nil || mappings.CdromMappings == nil {
+		return nil
+	}
+	for _, mapping := range *mappings.CdromMappings {
+		if mapping.Source.ID != nil && *mapping.Source.ID == cdrom.FileName {
+			return &mapping
+		}
+		if mapping.Source.Name != nil && (*mapping.Source.Name == cdrom.FileName || *mapping.Source.Name == cdrom.Name) {
+			return &mapping
+		}
+	}
+	return nil
+}
+
+// cdromDataVolumeName returns the name of the data volume the ISO image of the CD-ROM is imported to
+func (r *VmwareMapper) cdromDataVolumeName(cdrom Cdrom) string {
+	return fmt.Sprintf("%s-%d", r.instanceUID, cdrom.Key)
+}
+
+func (r *VmwareMapper) isCdromDataVolume(dv cdiv1.DataVolume) bool {
+	for _, cdrom := range BuildCdroms(r.vmProperties) {
+		if r.cdromDataVolumeName(cdrom) == dv.Name {
+			return true
+		}
+	}
+	return false
 }
 
 // MapDisk maps a disk from the VMware VM to the Kubevirt VM.
 func (r *VmwareMapper) MapDisk(vmSpec *kubevirtv1.VirtualMachine, dv cdiv1.DataVolume) {
+	if !r.dataVolumeSelected(dv) {
+		return
+	}
 	name := fmt.Sprintf("dv-%v", dv.Name)
 	name = utils.EnsureLabelValueLength(name)
+	if r.isCdromDataVolume(dv) {
+		provider.AddCdrom(vmSpec, name, kubevirtv1.VolumeSource{
+			DataVolume: &kubevirtv1.DataVolumeSource{
+				Name: dv.Name,
+			},
+		})
+		sortDisks(vmSpec.Spec.Template.Spec.Domain.Devices.Disks)
+		return
+	}
 	volume := kubevirtv1.Volume{
 		Name: name,
 		VolumeSource: kubevirtv1.VolumeSource{
@@ -387,14 +683,41 @@ func (r *VmwareMapper) MapDisk(vmSpec *kubevirtv1.VirtualMachine, dv cdiv1.DataV
 	vmSpec.Spec.Template.Spec.Volumes = append(vmSpec.Spec.Template.Spec.Volumes, volume)
 	disks := append(vmSpec.Spec.Template.Spec.Domain.Devices.Disks, kubevirtDisk)
 
-	// Since the import controller is iterating over a map of DVs,
-	// MapDisk gets called for each DV in a nondeterministic order which results
-	// in the disks being in an arbitrary order. This sort ensure the disks are
-	// attached in the same order as the devices on the source VM.
+	sortDisks(disks)
+	vmSpec.Spec.Template.Spec.Domain.Devices.Disks = disks
+}
+
+// sortDisks puts the disks before the CD-ROMs, so that the VM keeps booting from its disks, and sorts both by name.
+// Since the import controller is iterating over a map of DVs,
+// MapDisk gets called for each DV in a nondeterministic order which results
+// in the disks being in an arbitrary order. This sort ensure the disks are
+// attached in the same order as the devices on the source VM.
+func sortDisks(disks []kubevirtv1.Disk) {
 	sort.Slice(disks, func(i, j int) bool {
+		iCdrom, jCdrom := disks[i].CDRom != nil, disks[j].CDRom != nil
+		if iCdrom != jCdrom {
+			return jCdrom
+		}
 		return disks[i].Name < disks[j].Name
 	})
-	vmSpec.Spec.Template.Spec.Domain.Devices.Disks = disks
+}
+
+// dataVolumeName returns the name of the data volume the disk is imported to
+func (r *VmwareMapper) dataVolumeName(disk Disk) string {
+	return fmt.Sprintf("%s-%d", r.instanceUID, disk.Key)
+}
+
+// dataVolumeSelected tells whether the disk imported to the data volume is selected
+func (r *VmwareMapper) dataVolumeSelected(dv cdiv1.DataVolume) bool {
+	if r.diskSelection == nil || r.buildDisks() != nil {
+		return true
+	}
+	for _, disk := range *r.disks {
+		if r.dataVolumeName(disk) == dv.Name {
+			return provider.DiskSelected(r.diskSelection, disk.SourceDisk())
+		}
+	}
+	return true
 }
 
 // ResolveVMName resolves the target VM name
@@ -521,6 +844,9 @@ func (r *VmwareMapper) MapVM(targetVmName *string, vmSpec *kubevirtv1.VirtualMac
 	os, _ := r.osFinder.FindOperatingSystem(r.vmProperties)
 	vmSpec.Spec.Template.Spec.Domain.Devices.Inputs = r.mapInputDevice(os)
 	vmSpec.Spec.Template.Spec.Domain.Devices.Disks = []kubevirtv1.Disk{}
+
+	// Map the CD-ROMs backed by existing PVCs, the other ones are mapped with their data volumes
+	r.mapCdromClaims(vmSpec)
 	return vmSpec, nil
 }
 
@@ -708,6 +1034,42 @@ func getDiskCapacityInBytes(disk *types.VirtualDisk) int64 {
 	return capacityInBytes
 }
 
+// isThinProvisioned tells whether the space of the disk is allocated on the datastore as it is written
+func isThinProvisioned(disk *types.VirtualDisk) bool {
+	switch backing := disk.Backing.(type) {
+	case *types.VirtualDiskFlatVer2BackingInfo:
+		return backing.ThinProvisioned != nil && *backing.ThinProvisioned
+	case *types.VirtualDiskSeSparseBackingInfo, *types.VirtualDiskSparseVer2BackingInfo:
+		return true
+	}
+	return false
+}
+
+// getDiskAllocatedBytes sums up the sizes of the extent files of the disk, including those of its snapshots
+func getDiskAllocatedBytes(vmProperties *mo.VirtualMachine, diskKey int32) int64 {
+	if vmProperties.LayoutEx == nil {
+		return 0
+	}
+	fileSizes := make(map[int32]int64)
+	for _, file := range vmProperties.LayoutEx.File {
+		if file.Type == string(types.VirtualMachineFileLayoutExFileTypeDiskExtent) {
+			fileSizes[file.Key] = file.Size
+		}
+	}
+	var allocated int64
+	for _, diskLayout := range vmProperties.LayoutEx.Disk {
+		if diskLayout.Key != diskKey {
+			continue
+		}
+		for _, chain := range diskLayout.Chain {
+			for _, fileKey := range chain.FileKey {
+				allocated += fileSizes[fileKey]
+			}
+		}
+	}
+	return allocated
+}
+
 func getDatastoreNameFromBacking(backingFile string) string {
 	var datastoreName string
 	datastoreNamePattern := regexp.MustCompile(`^\[(.+)\]`)
@@ -6,6 +6,7 @@ import (
 	"github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
 	"github.com/kubevirt/vm-import-operator/pkg/providers/vmware/mapper"
 	"github.com/kubevirt/vm-import-operator/pkg/providers/vmware/os"
+	"github.com/kubevirt/vm-import-operator/pkg/storageprofiles"
 	"github.com/kubevirt/vm-import-operator/pkg/utils"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -16,6 +17,7 @@ import (
 	"github.com/vmware/govmomi/vim25/types"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubevirtv1 "kubevirt.io/client-go/api/v1"
 	cdiv1 "kubevirt.io/containerized-data-importer/pkg/apis/core/v1alpha1"
 )
@@ -363,8 +365,229 @@ var _ = Describe("Test mapping disks", func() {
 		storageResource = dvs[expectedDiskName2].Spec.PVC.Resources.Requests[v1.ResourceStorage]
 		Expect(storageResource.Value()).To(BeEquivalentTo(1073741824))
 	})
+
+	It("should map datavolumes with the modes recommended by the storage profile", func() {
+		mappings := createMinimalMapping()
+		mappings.DiskMappings = &[]v1beta1.StorageResourceMappingItem{
+			{
+				Source: v1beta1.Source{
+					Name: &diskName1,
+				},
+				Target: v1beta1.ObjectIdentifier{
+					Name: storageClass,
+				},
+				AccessMode: &accessModeRWO,
+			},
+		}
+		mapper := mapper.NewVmwareMapper(vm, vmProperties, hostProperties, credentials, mappings, instanceUID, "", osFinder)
+		mapper.UseStorageProfiles(&mockStorageProfiles{sets: []storageprofiles.ClaimPropertySet{
+			{AccessModes: []v1.PersistentVolumeAccessMode{accessModeRWO}, VolumeMode: &volumeModeFilesystem},
+			{AccessModes: []v1.PersistentVolumeAccessMode{accessModeRWO, accessModeRWM}, VolumeMode: &volumeModeBlock},
+		}})
+		dvs, err := mapper.MapDataVolumes(&targetVMName, filesystemOverhead)
+		Expect(err).To(BeNil())
+
+		// the mapped access mode is kept
+		Expect(dvs[expectedDiskName1].Spec.PVC.AccessModes[0]).To(Equal(accessModeRWO))
+		Expect(dvs[expectedDiskName1].Spec.PVC.VolumeMode).To(Equal(&volumeModeBlock))
+
+		Expect(dvs[expectedDiskName2].Spec.PVC.AccessModes[0]).To(Equal(accessModeRWM))
+		Expect(dvs[expectedDiskName2].Spec.PVC.VolumeMode).To(Equal(&volumeModeBlock))
+	})
+
+	It("should preallocate the datavolumes of thick disks when inheriting from the source", func() {
+		thin, thick := true, false
+		var keys []int32
+		for _, device := range vmProperties.Config.Hardware.Device {
+			if disk, ok := device.(*types.VirtualDisk); ok {
+				backing := &types.VirtualDiskFlatVer2BackingInfo{
+					VirtualDeviceFileBackingInfo: *disk.Backing.(types.BaseVirtualDeviceFileBackingInfo).GetVirtualDeviceFileBackingInfo(),
+					ThinProvisioned:              &thick,
+				}
+				if len(keys) == 0 {
+					backing.ThinProvisioned = &thin
+				}
+				disk.Backing = backing
+				keys = append(keys, disk.Key)
+			}
+		}
+		vmProperties.LayoutEx = &types.VirtualMachineFileLayoutEx{
+			File: []types.VirtualMachineFileLayoutExFileInfo{
+				{Key: 1, Type: string(types.VirtualMachineFileLayoutExFileTypeDiskDescriptor), Size: 512},
+				{Key: 2, Type: string(types.VirtualMachineFileLayoutExFileTypeDiskExtent), Size: 1048576},
+			},
+			Disk: []types.VirtualMachineFileLayoutExDiskLayout{
+				{Key: keys[0], Chain: []types.VirtualMachineFileLayoutExDiskUnit{{FileKey: []int32{1, 2}}}},
+			},
+		}
+		mode := v1beta1.InheritPreallocation
+		mappings := createMinimalMapping()
+		mappings.DiskMappings = &[]v1beta1.StorageResourceMappingItem{
+			{Source: v1beta1.Source{Name: &diskName1}, Preallocation: &mode},
+			{Source: v1beta1.Source{Name: &diskName2}, Preallocation: &mode},
+		}
+		mapper := mapper.NewVmwareMapper(vm, vmProperties, hostProperties, credentials, mappings, instanceUID, "", osFinder)
+		dvs, err := mapper.MapDataVolumes(&targetVMName, filesystemOverhead)
+		Expect(err).To(BeNil())
+
+		// the thin disk is sparse and consumes the size of its extent
+		Expect(dvs[expectedDiskName1].Annotations[utils.AnnPreallocation]).To(Equal("false"))
+		Expect(dvs[expectedDiskName1].Annotations[utils.AnnEstimatedBytes]).To(Equal("1048576"))
+
+		// the thick disk is preallocated and consumes its whole size
+		Expect(dvs[expectedDiskName2].Annotations[utils.AnnPreallocation]).To(Equal("true"))
+		Expect(dvs[expectedDiskName2].Annotations[utils.AnnEstimatedBytes]).To(Equal("1073741824"))
+	})
+
+	It("should not map the excluded disks", func() {
+		mappings := createMinimalMapping()
+		notBootable := false
+		mapper := mapper.NewVmwareMapper(vm, vmProperties, hostProperties, credentials, mappings, instanceUID, "", osFinder)
+		mapper.SelectDisks(&v1beta1.DiskSelectionSpec{
+			Exclude: []v1beta1.DiskSelectorRule{{Bootable: &notBootable}},
+		})
+		dvs, err := mapper.MapDataVolumes(&targetVMName, filesystemOverhead)
+		Expect(err).To(BeNil())
+
+		Expect(dvs).To(HaveLen(1))
+		Expect(dvs).To(HaveKey(expectedDiskName1))
+
+		vmSpec := &kubevirtv1.VirtualMachine{Spec: kubevirtv1.VirtualMachineSpec{Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{}}}
+		mapper.MapDisk(vmSpec, cdiv1.DataVolume{ObjectMeta: metav1.ObjectMeta{Name: expectedDiskName2}})
+		Expect(vmSpec.Spec.Template.Spec.Volumes).To(BeEmpty())
+	})
+
+	It("should mark the disk the VM boots from", func() {
+		disks := mapper.BuildDisks(vmProperties)
+
+		Expect(disks).To(HaveLen(expectedNumDisks))
+		Expect(disks[0].Bootable).To(BeTrue())
+		Expect(disks[1].Bootable).To(BeFalse())
+	})
+})
+
+var _ = Describe("Test mapping CD-ROMs", func() {
+	var (
+		vm                 *object.VirtualMachine
+		vmProperties       *mo.VirtualMachine
+		hostProperties     *mo.HostSystem
+		credentials        *mapper.DataVolumeCredentials
+		isoFileName        = "[datastore1] iso/fedora.iso"
+		expectedCdromName  = "d39a8d6c-ea37-5c91-8979-334e7e07cab6-3002"
+		filesystemOverhead = cdiv1.FilesystemOverhead{
+			Global: "0.0",
+		}
+	)
+
+	BeforeEach(func() {
+		model := simulator.VPX()
+		err := model.Load("../../../../tests/vmware/vcsim")
+		Expect(err).To(BeNil())
+		server := model.Service.NewServer()
+		client, _ := govmomi.NewClient(context.TODO(), server.URL, false)
+		findOs = func() (string, error) {
+			return "linux", nil
+		}
+		vm, vmProperties, hostProperties = prepareVsphereObjects(client)
+		credentials = prepareCredentials(server)
+		credentials.SecretName = "vmware-credentials"
+		vmProperties.Config.Hardware.Device = append(vmProperties.Config.Hardware.Device, &types.VirtualCdrom{
+			VirtualDevice: types.VirtualDevice{
+				Key:        3002,
+				DeviceInfo: &types.Description{Label: "CD/DVD drive 1"},
+				Backing: &types.VirtualCdromIsoBackingInfo{
+					VirtualDeviceFileBackingInfo: types.VirtualDeviceFileBackingInfo{
+						FileName:  isoFileName,
+						Datastore: &types.ManagedObjectReference{Type: "Datastore", Value: "datastore-1"},
+					},
+				},
+			},
+		})
+	})
+
+	It("should build the CD-ROMs with an ISO image", func() {
+		cdroms := mapper.BuildCdroms(vmProperties)
+
+		Expect(cdroms).To(ConsistOf(mapper.Cdrom{
+			FileName:       isoFileName,
+			DatastoreMoRef: "datastore-1",
+			DatastoreName:  "datastore1",
+			Name:           "CD/DVD drive 1",
+			Key:            3002,
+		}))
+	})
+
+	It("should import the ISO image from its datastore", func() {
+		vmMapper := mapper.NewVmwareMapper(vm, vmProperties, hostProperties, credentials, createMinimalMapping(), instanceUID, "", osFinder)
+		vmMapper.UseISOFiles(map[string]mapper.ISOFile{
+			isoFileName: {URL: "https://vcenter/folder/iso/fedora.iso?dcPath=DC0&dsName=datastore1", Size: 1073741824},
+		})
+
+		dvs, err := vmMapper.MapDataVolumes(&targetVMName, filesystemOverhead)
+
+		Expect(err).To(BeNil())
+		Expect(dvs).To(HaveLen(expectedNumDisks + 1))
+		Expect(dvs).To(HaveKey(expectedCdromName))
+		isoDV := dvs[expectedCdromName]
+		Expect(isoDV.Spec.Source.HTTP.URL).To(Equal("https://vcenter/folder/iso/fedora.iso?dcPath=DC0&dsName=datastore1"))
+		Expect(isoDV.Spec.Source.HTTP.SecretRef).To(Equal("vmware-credentials"))
+		size := isoDV.Spec.PVC.Resources.Requests[v1.ResourceStorage]
+		Expect(size.Value()).To(BeEquivalentTo(1073741824))
+
+		vmSpec, err := vmMapper.MapVM(&targetVMName, &kubevirtv1.VirtualMachine{})
+		Expect(err).To(BeNil())
+		vmMapper.MapDisk(vmSpec, isoDV)
+		vmMapper.MapDisk(vmSpec, dvs[expectedDiskName1])
+
+		disks := vmSpec.Spec.Template.Spec.Domain.Devices.Disks
+		Expect(disks).To(HaveLen(2))
+		Expect(disks[0].Disk).ToNot(BeNil())
+		Expect(disks[1].CDRom).ToNot(BeNil())
+		Expect(disks[1].Name).To(Equal("dv-" + expectedCdromName))
+	})
+
+	It("should leave out the ISO image that isn't found on its datastore", func() {
+		mappings := createMinimalMapping()
+		vmMapper := mapper.NewVmwareMapper(vm, vmProperties, hostProperties, credentials, mappings, instanceUID, "", osFinder)
+
+		dvs, err := vmMapper.MapDataVolumes(&targetVMName, filesystemOverhead)
+
+		Expect(err).To(BeNil())
+		Expect(dvs).To(HaveLen(expectedNumDisks))
+		Expect(mapper.UnmappedCdroms(vmProperties, mappings, nil)).To(ConsistOf(isoFileName))
+	})
+
+	It("should attach the CD-ROM to the PVC it is mapped to by its label", func() {
+		mappings := createMinimalMapping()
+		label := "CD/DVD drive 1"
+		pvcType := v1beta1.PersistentVolumeClaimCdromMapping
+		mappings.CdromMappings = &[]v1beta1.CdromResourceMappingItem{
+			{Source: v1beta1.Source{Name: &label}, Target: v1beta1.ObjectIdentifier{Name: "fedora-iso"}, Type: &pvcType},
+		}
+		vmMapper := mapper.NewVmwareMapper(vm, vmProperties, hostProperties, credentials, mappings, instanceUID, "", osFinder)
+
+		dvs, err := vmMapper.MapDataVolumes(&targetVMName, filesystemOverhead)
+		Expect(err).To(BeNil())
+		Expect(dvs).To(HaveLen(expectedNumDisks))
+		vmSpec, err := vmMapper.MapVM(&targetVMName, &kubevirtv1.VirtualMachine{})
+
+		Expect(err).To(BeNil())
+		Expect(vmSpec.Spec.Template.Spec.Volumes).To(HaveLen(1))
+		Expect(vmSpec.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("fedora-iso"))
+		Expect(vmSpec.Spec.Template.Spec.Domain.Devices.Disks).To(HaveLen(1))
+		Expect(vmSpec.Spec.Template.Spec.Domain.Devices.Disks[0].CDRom).ToNot(BeNil())
+		Expect(mapper.UnmappedCdroms(vmProperties, mappings, nil)).To(BeEmpty())
+	})
 })
 
+type mockStorageProfiles struct {
+	sets []storageprofiles.ClaimPropertySet
+}
+
+func (m *mockStorageProfiles) ClaimPropertySets(_ *string) ([]storageprofiles.ClaimPropertySet, error) {
+	return m.sets, nil
+}
+
 func createMinimalMapping() *v1beta1.VmwareMappings {
 	return &v1beta1.VmwareMappings{
 		NetworkMappings: &[]v1beta1.NetworkResourceMappingItem{},
@@ -2,6 +2,7 @@ package vmware
 
 import (
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -38,6 +39,7 @@ import (
 	vos "github.com/kubevirt/vm-import-operator/pkg/providers/vmware/os"
 	vtemplates "github.com/kubevirt/vm-import-operator/pkg/providers/vmware/templates"
 	"github.com/kubevirt/vm-import-operator/pkg/secrets"
+	"github.com/kubevirt/vm-import-operator/pkg/storageprofiles"
 	"github.com/kubevirt/vm-import-operator/pkg/templates"
 	"github.com/kubevirt/vm-import-operator/pkg/utils"
 	"github.com/kubevirt/vm-import-operator/pkg/virtualmachines"
@@ -52,10 +54,12 @@ const (
 	thumbprintKey   = "thumbprint"
 	vmwareSecretKey = "vmware"
 
-	warmMigrationSnapshotName        = "warm-migration-stage"
-	warmMigrationSnapshotDescription = "VM Import Operator warm migration stage"
+	insecureSkipVerifyKey = "insecureSkipVerify"
 
-	annRetainConversionPod = "vmimport.v2v.kubevirt.io/retain-conversion-pod"
+	warmMigrationSnapshotName         = "warm-migration-stage"
+	warmMigrationSnapshotDescription  = "VM Import Operator warm migration stage"
+	snapshotImportSnapshotName        = "snapshot-import"
+	snapshotImportSnapshotDescription = "VM Import Operator snapshot import"
 )
 
 // VmwareProvider is VMware implementation of the Provider interface to support importing VMs from VMware
@@ -77,6 +81,9 @@ type VmwareProvider struct {
 	vmiTypeMeta           metav1.TypeMeta
 	vmwareClient          *vclient.RichVmwareClient
 	vmwareSecretDataMap   map[string]string
+	conversionPod         v1beta1.ConversionPodSpec
+	conversionAllowList   ctrlConfig.ConversionPodAllowList
+	storageProfiles       storageprofiles.Finder
 }
 
 // NewVmwareProvider creates a new VmwareProvider
@@ -100,6 +107,9 @@ func NewVmwareProvider(vmiObjectMeta metav1.ObjectMeta, vmiTypeMeta metav1.TypeM
 		osFinder:              &osFinder,
 		templateHandler:       templates.NewTemplateHandler(templateProvider),
 		templateFinder:        vtemplates.NewTemplateFinder(templateProvider, osFinder),
+		conversionPod:         ctrlConfig.ConversionPod(),
+		conversionAllowList:   ctrlConfig.ConversionPodAllowList(),
+		storageProfiles:       storageprofiles.NewStorageProfiles(client),
 	}
 }
 
@@ -128,6 +138,9 @@ func (r *VmwareProvider) Init(secret *corev1.Secret, instance *v1beta1.VirtualMa
 	if len(r.vmwareSecretDataMap["password"]) == 0 {
 		return fmt.Errorf("vmware secret password cannot be empty")
 	}
+	if insecure, ok := r.vmwareSecretDataMap[insecureSkipVerifyKey]; ok && insecure != "true" && insecure != "false" {
+		return fmt.Errorf("vmware secret insecureSkipVerify must be either true or false")
+	}
 	r.instance = instance
 	return nil
 }
@@ -154,7 +167,15 @@ func (r *VmwareProvider) CreateMapper() (provider.Mapper, error) {
 	if err != nil {
 		return nil, err
 	}
-	return mapper.NewVmwareMapper(vm, vmProperties, hostProperties, credentials, r.resourceMapping, string(r.vmiObjectMeta.UID), r.vmiObjectMeta.Namespace, r.osFinder), nil
+	vmwareMapper := mapper.NewVmwareMapper(vm, vmProperties, hostProperties, credentials, r.resourceMapping, string(r.vmiObjectMeta.UID), r.vmiObjectMeta.Namespace, r.osFinder)
+	vmwareMapper.UseStorageProfiles(r.storageProfiles)
+	vmwareMapper.SelectDisks(r.instance.Spec.Disks)
+	isoFiles, err := r.isoFiles(vmProperties)
+	if err != nil {
+		return nil, err
+	}
+	vmwareMapper.UseISOFiles(isoFiles)
+	return vmwareMapper, nil
 }
 
 // FindTemplate attempts to find best match for a template based on the source VM
@@ -196,11 +217,25 @@ func (r *VmwareProvider) LoadVM(sourceSpec v1beta1.VirtualMachineImportSourceSpe
 	if err != nil {
 		return err
 	}
-	vm, err := vmwareClient.GetVM(sourceSpec.Vmware.VM.ID, sourceSpec.Vmware.VM.Name, nil, nil)
-	if err != nil {
+	vmSpec := sourceSpec.Vmware.VM
+	vm, err := vmwareClient.FindVM(vclient.VMLookup{
+		ID:           vmSpec.ID,
+		InstanceUUID: vmSpec.UUIDType != nil && *vmSpec.UUIDType == v1beta1.InstanceUUID,
+		MoRef:        vmSpec.MoRef,
+		Name:         vmSpec.Name,
+		Datacenter:   vmSpec.Datacenter,
+		Cluster:      vmSpec.Cluster,
+		Folder:       vmSpec.Folder,
+		ResourcePool: vmSpec.ResourcePool,
+	})
+	if err != nil {
+		var ambiguous *vclient.AmbiguousVMError
+		if errors.As(err, &ambiguous) {
+			return provider.AmbiguousSourceVMError{Err: err}
+		}
 		return err
 	}
-	r.vm = vm.(*object.VirtualMachine)
+	r.vm = vm
 
 	return nil
 }
@@ -247,8 +282,8 @@ func (r *VmwareProvider) StartVM() error {
 	return vmwareClient.StartVM(vm.Reference().Value)
 }
 
-// StopVM powers off the source VM.
-func (r *VmwareProvider) StopVM(instance *v1beta1.VirtualMachineImport, client client.Client) error {
+// StopVM requests the shut down of the source VM with the given method, without waiting for it to be powered off.
+func (r *VmwareProvider) StopVM(instance *v1beta1.VirtualMachineImport, client client.Client, method v1beta1.SourceShutdownMethod) error {
 	vmwareClient, err := r.getClient()
 	if err != nil {
 		return err
@@ -263,7 +298,7 @@ func (r *VmwareProvider) StopVM(instance *v1beta1.VirtualMachineImport, client c
 	}
 
 	if vmProperties.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOff {
-		err = vmwareClient.StopVM(vm.Reference().Value)
+		err = vmwareClient.StopVM(vm.Reference().Value, method)
 		if err != nil {
 			return err
 		}
@@ -277,14 +312,25 @@ func (r *VmwareProvider) StopVM(instance *v1beta1.VirtualMachineImport, client c
 	return nil
 }
 
-// CreateVMSnapshot creates a snapshot to use in a warm migration.
+// CreateVMSnapshot creates a snapshot to use in a warm migration or a snapshot import.
+// The guest file system of a warm migration is always quiesced, the one of a snapshot import only if
+// the VMware Tools are running.
 func (r *VmwareProvider) CreateVMSnapshot() (string, error) {
 	vm, err := r.getVM()
 	if err != nil {
 		return "", err
 	}
 
-	snapshotRef, err := r.vmwareClient.CreateVMSnapshot(vm.Reference().Value, warmMigrationSnapshotName, warmMigrationSnapshotDescription, false, true)
+	name, description, quiesce := warmMigrationSnapshotName, warmMigrationSnapshotDescription, true
+	if r.instance.Spec.Snapshot {
+		vmProperties, err := r.getVmProperties()
+		if err != nil {
+			return "", err
+		}
+		name, description = snapshotImportSnapshotName, snapshotImportSnapshotDescription
+		quiesce = vmProperties.Guest != nil && vmProperties.Guest.ToolsRunningStatus == string(types.VirtualMachineToolsRunningStatusGuestToolsRunning)
+	}
+	snapshotRef, err := r.vmwareClient.CreateVMSnapshot(vm.Reference().Value, name, description, false, quiesce)
 	if err != nil {
 		return "", err
 	}
@@ -295,6 +341,16 @@ func (r *VmwareProvider) SupportsWarmMigration() bool {
 	return true
 }
 
+// SupportsSnapshotImport returns whether this provider supports importing the disks from a snapshot of the running VM.
+func (r *VmwareProvider) SupportsSnapshotImport() bool {
+	return true
+}
+
+// VMSnapshotReady always returns true, the snapshot is ready once CreateVMSnapshot returns.
+func (r *VmwareProvider) VMSnapshotReady(_ string) (bool, error) {
+	return true, nil
+}
+
 func (r *VmwareProvider) RemoveVMSnapshot(snapshotID string, removeChildren bool) error {
 	vm, err := r.getVM()
 	if err != nil {
@@ -346,9 +402,16 @@ func (r *VmwareProvider) CleanUp(failure bool, cr *v1beta1.VirtualMachineImport,
 			errs = append(errs, err)
 		}
 	}
+	// remove the snapshot that was created for snapshot import
+	if cr.Status.SourceSnapshot != nil {
+		err = r.vmwareClient.RemoveVMSnapshot(vm.Reference().Value, *cr.Status.SourceSnapshot, false, nil)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
 
 	// keep the conversion pod around if it failed or the annotation was set
-	_, found := cr.Annotations[annRetainConversionPod]
+	_, found := cr.Annotations[guestconversion.AnnRetainConversionPod]
 	if !(failure || found) {
 		err = r.podsManager.DeleteFor(vmiName)
 		if err != nil {
@@ -394,19 +457,33 @@ func (r *VmwareProvider) Validate() ([]v1beta1.VirtualMachineImportCondition, er
 
 	validCondition := conditions.NewCondition(v1beta1.Valid, string(v1beta1.ValidationCompleted), "Validation completed successfully", corev1.ConditionTrue)
 	validationFailures := make([]string, 0)
+	validationWarnings := make([]string, 0)
 	mappingCondition := conditions.NewCondition(v1beta1.MappingRulesVerified, string(v1beta1.MappingRulesVerificationCompleted), "All mapping rules checks passed", corev1.ConditionTrue)
 	mappingFailures := make([]string, 0)
 
-	if !r.validateToolsStatus(vmProperties) {
+	shutdownMethod, _, forceShutdown := provider.SourceShutdownOf(r.instance)
+	if !r.instance.Spec.Snapshot && shutdownMethod == v1beta1.GuestShutdown && !forceShutdown && !r.validateToolsStatus(vmProperties) {
 		validationFailures = append(validationFailures, "VM must be powered off, or up to date VMWare Tools must be installed and running to allow the guest to be shutdown gracefully")
 	}
+	if shutdownMethod == v1beta1.ACPIShutdown {
+		validationFailures = append(validationFailures, "ACPI shutdown is not supported by VMware, the source VM can be shut down with the guest or hard method")
+	}
 	if r.instance.Spec.Warm {
 		if !r.validateChangeTrackingEnabled(vmProperties) {
 			validationFailures = append(validationFailures, "Changed Block Tracking must be enabled to allow warm import")
 		}
 	}
+	if !r.vmwareClient.IsVCenter() {
+		warnings, err := r.validateStandaloneHost(vmProperties)
+		if err != nil {
+			return nil, err
+		}
+		validationWarnings = append(validationWarnings, warnings...)
+	}
 	if len(validationFailures) > 0 {
 		validCondition = conditions.NewCondition(v1beta1.Valid, string(v1beta1.ValidationFailed), strings.Join(validationFailures, "; "), corev1.ConditionFalse)
+	} else if len(validationWarnings) > 0 {
+		validCondition = conditions.NewCondition(v1beta1.Valid, string(v1beta1.ValidationReportedWarnings), strings.Join(validationWarnings, "; "), corev1.ConditionTrue)
 	}
 
 	nics := mapper.BuildNics(vmProperties)
@@ -420,7 +497,52 @@ func (r *VmwareProvider) Validate() ([]v1beta1.VirtualMachineImportCondition, er
 		mappingCondition = conditions.NewCondition(v1beta1.MappingRulesVerified, string(v1beta1.MappingRulesVerificationFailed), strings.Join(mappingFailures, "; "), corev1.ConditionFalse)
 	}
 
-	return []v1beta1.VirtualMachineImportCondition{validCondition, mappingCondition}, nil
+	validationResults := []v1beta1.VirtualMachineImportCondition{validCondition, mappingCondition}
+	validationResults = provider.WithExcludedDisksWarning(validationResults, r.excludedDisks(vmProperties))
+	isoFiles, err := r.isoFiles(vmProperties)
+	if err != nil {
+		return nil, err
+	}
+	return provider.WithUnmappedCdromsWarning(validationResults, mapper.UnmappedCdroms(vmProperties, r.resourceMapping, isoFiles)), nil
+}
+
+// isoFiles returns the ISO images of the CD-ROMs of the VM that are imported from their datastores, keyed by their
+// datastore path. The images that aren't found on their datastores are left out.
+func (r *VmwareProvider) isoFiles(vmProperties *mo.VirtualMachine) (map[string]mapper.ISOFile, error) {
+	isoFiles := make(map[string]mapper.ISOFile)
+	for _, cdrom := range mapper.BuildCdroms(vmProperties) {
+		if cdrom.DatastoreMoRef == "" {
+			continue
+		}
+		vmwareClient, err := r.getClient()
+		if err != nil {
+			return nil, err
+		}
+		fileURL, size, err := vmwareClient.GetDatastoreFile(cdrom.DatastoreMoRef, cdrom.FileName)
+		switch err.(type) {
+		case nil:
+			isoFiles[cdrom.FileName] = mapper.ISOFile{URL: fileURL, Size: size}
+		case object.DatastoreNoSuchFileError, object.DatastoreNoSuchDirectoryError:
+			continue
+		default:
+			return nil, err
+		}
+	}
+	return isoFiles, nil
+}
+
+// excludedDisks returns the names of the disks that aren't selected for the import
+func (r *VmwareProvider) excludedDisks(vmProperties *mo.VirtualMachine) []string {
+	if r.instance.Spec.Disks == nil {
+		return nil
+	}
+	var excluded []string
+	for _, disk := range mapper.BuildDisks(vmProperties) {
+		if !provider.DiskSelected(r.instance.Spec.Disks, disk.SourceDisk()) {
+			excluded = append(excluded, disk.Name)
+		}
+	}
+	return excluded
 }
 
 func (r *VmwareProvider) validateToolsStatus(vmProperties *mo.VirtualMachine) bool {
@@ -431,6 +553,30 @@ func (r *VmwareProvider) validateChangeTrackingEnabled(vmProperties *mo.VirtualM
 	return vmProperties.Config.ChangeTrackingEnabled != nil && *vmProperties.Config.ChangeTrackingEnabled
 }
 
+// validateStandaloneHost reports the vCenter-only features that are unavailable
+// when importing directly from an ESXi host.
+func (r *VmwareProvider) validateStandaloneHost(vmProperties *mo.VirtualMachine) ([]string, error) {
+	warnings := make([]string, 0)
+	for _, nic := range mapper.BuildNics(vmProperties) {
+		if nic.DVPortGroup != "" {
+			warnings = append(warnings, fmt.Sprintf("NIC %s is attached to a distributed port group which is managed by vCenter", nic.Name))
+		}
+	}
+
+	vm, err := r.getVM()
+	if err != nil {
+		return nil, err
+	}
+	hostProperties, err := r.vmwareClient.GetVMHostProperties(vm)
+	if err != nil {
+		return nil, err
+	}
+	if hostProperties.Summary.ManagementServerIp != "" {
+		warnings = append(warnings, fmt.Sprintf("ESXi host is managed by vCenter %s, power operations and snapshots should be performed through vCenter instead", hostProperties.Summary.ManagementServerIp))
+	}
+	return warnings, nil
+}
+
 func (r *VmwareProvider) validateNetworksMapped(nics []mapper.Nic) []string {
 	var unmapped []string
 	if r.resourceMapping.NetworkMappings == nil {
@@ -484,7 +630,7 @@ func (r *VmwareProvider) validateNoDuplicatePodTargets(nics []mapper.Nic) []stri
 // Close logs out the client and shuts down idle connections.
 func (r *VmwareProvider) Close() {
 	if r.vmwareClient != nil {
-		_ = r.vmwareClient.Close()
+		_ = r.factory.Release(r.vmwareClient)
 	}
 }
 
@@ -541,16 +687,34 @@ func (r *VmwareProvider) prepareDataVolumeCredentials() (*mapper.DataVolumeCrede
 	if err != nil {
 		return &mapper.DataVolumeCredentials{}, err
 	}
+	thumbprint, err := r.vddkThumbprint()
+	if err != nil {
+		return &mapper.DataVolumeCredentials{}, err
+	}
 
 	return &mapper.DataVolumeCredentials{
 		URL:        r.vmwareSecretDataMap[apiUrlKey],
-		Thumbprint: r.vmwareSecretDataMap[thumbprintKey],
+		Thumbprint: thumbprint,
 		Username:   username,
 		Password:   password,
 		SecretName: secret.Name,
 	}, nil
 }
 
+// vddkThumbprint returns the SHA-1 thumbprint VDDK pins the certificate of the vCenter or ESXi host to. Unless the
+// secret pins it to its SHA-1 thumbprint already, it is the thumbprint of the certificate that was verified when
+// connecting to the host.
+func (r *VmwareProvider) vddkThumbprint() (string, error) {
+	if thumbprint := r.vmwareSecretDataMap[thumbprintKey]; vclient.IsSHA1Thumbprint(thumbprint) {
+		return thumbprint, nil
+	}
+	vmwareClient, err := r.getClient()
+	if err != nil {
+		return "", err
+	}
+	return vmwareClient.Thumbprint(), nil
+}
+
 func (r *VmwareProvider) ensureSecretIsPresent(keyAccess, keySecret string) (*corev1.Secret, error) {
 	vmiName := r.getNamespacedName()
 	secret, err := r.secretsManager.FindFor(vmiName)
@@ -562,6 +726,16 @@ func (r *VmwareProvider) ensureSecretIsPresent(keyAccess, keySecret string) (*co
 		if err != nil {
 			return nil, err
 		}
+	} else if string(secret.Data[keyAccessKey]) != keyAccess || string(secret.Data[keySecretKey]) != keySecret {
+		// the credentials were rotated, the importer pods created from now on have to use the new ones
+		secret.Data = map[string][]byte{
+			keyAccessKey: []byte(keyAccess),
+			keySecretKey: []byte(keySecret),
+		}
+		err = r.secretsManager.Update(secret)
+		if err != nil {
+			return nil, err
+		}
 	}
 	return secret, nil
 }
@@ -584,8 +758,8 @@ func (r *VmwareProvider) createSecret(username, password string) (*corev1.Secret
 	return &newSecret, nil
 }
 
-func (r *VmwareProvider) NeedsGuestConversion() bool {
-	return true
+func (r *VmwareProvider) NeedsGuestConversion() (bool, error) {
+	return true, nil
 }
 
 func (r *VmwareProvider) GetGuestConversionPod() (*corev1.Pod, error) {
@@ -597,12 +771,12 @@ func (r *VmwareProvider) GetGuestConversionPod() (*corev1.Pod, error) {
 	return pod, nil
 }
 
-func (r *VmwareProvider) LaunchGuestConversionPod(vmSpec *v1.VirtualMachine, dataVolumes map[string]cdiv1.DataVolume) (*corev1.Pod, error) {
+func (r *VmwareProvider) LaunchGuestConversionPod(vmSpec *v1.VirtualMachine, dataVolumes map[string]cdiv1.DataVolume, customization *guestconversion.GuestCustomization) (*corev1.Pod, error) {
 	configMap, err := r.ensureConfigMapIsPresent(vmSpec, dataVolumes)
 	if err != nil {
 		return nil, err
 	}
-	return r.ensureGuestConversionPodIsPresent(vmSpec, dataVolumes, configMap)
+	return r.ensureGuestConversionPodIsPresent(vmSpec, dataVolumes, configMap, customization)
 }
 
 func (r *VmwareProvider) ensureConfigMapIsPresent(vmSpec *v1.VirtualMachine, dataVolumes map[string]cdiv1.DataVolume) (*corev1.ConfigMap, error) {
@@ -629,7 +803,7 @@ func (r *VmwareProvider) createConfigMap(vmSpec *v1.VirtualMachine, dataVolumes
 	}
 	newConfigMap := &corev1.ConfigMap{
 		BinaryData: map[string][]byte{
-			"input.xml": domXML,
+			guestconversion.LibvirtDomainKey: domXML,
 		},
 	}
 	newConfigMap.OwnerReferences = []metav1.OwnerReference{
@@ -642,14 +816,14 @@ func (r *VmwareProvider) createConfigMap(vmSpec *v1.VirtualMachine, dataVolumes
 	return newConfigMap, nil
 }
 
-func (r *VmwareProvider) ensureGuestConversionPodIsPresent(vmSpec *v1.VirtualMachine, dataVolumes map[string]cdiv1.DataVolume, libvirtConfigMap *corev1.ConfigMap) (*corev1.Pod, error) {
+func (r *VmwareProvider) ensureGuestConversionPodIsPresent(vmSpec *v1.VirtualMachine, dataVolumes map[string]cdiv1.DataVolume, libvirtConfigMap *corev1.ConfigMap, customization *guestconversion.GuestCustomization) (*corev1.Pod, error) {
 	vmiName := r.getNamespacedName()
 	pod, err := r.podsManager.FindFor(vmiName)
 	if err != nil {
 		return nil, err
 	}
 	if pod == nil {
-		pod, err = r.createGuestConversionPod(vmSpec, dataVolumes, libvirtConfigMap)
+		pod, err = r.createGuestConversionPod(vmSpec, dataVolumes, libvirtConfigMap, customization)
 		if err != nil {
 			return nil, err
 		}
@@ -657,13 +831,20 @@ func (r *VmwareProvider) ensureGuestConversionPodIsPresent(vmSpec *v1.VirtualMac
 	return pod, nil
 }
 
-func (r *VmwareProvider) createGuestConversionPod(vmSpec *v1.VirtualMachine, dataVolumes map[string]cdiv1.DataVolume, libvirtConfigMap *corev1.ConfigMap) (*corev1.Pod, error) {
+func (r *VmwareProvider) createGuestConversionPod(vmSpec *v1.VirtualMachine, dataVolumes map[string]cdiv1.DataVolume, libvirtConfigMap *corev1.ConfigMap, customization *guestconversion.GuestCustomization) (*corev1.Pod, error) {
 	vmiName := r.getNamespacedName()
-	pod := guestconversion.MakeGuestConversionPodSpec(vmSpec, dataVolumes, libvirtConfigMap)
+	settings, err := guestconversion.MergeConversionPodSettings(r.conversionPod, r.instance.Spec.ConversionPod, r.conversionAllowList)
+	if err != nil {
+		return nil, err
+	}
+	pod, err := guestconversion.MakeGuestConversionPodSpec(vmSpec, dataVolumes, libvirtConfigMap, settings, customization)
+	if err != nil {
+		return nil, err
+	}
 	pod.OwnerReferences = []metav1.OwnerReference{
 		ownerreferences.NewVMImportControllerReference(r.vmiTypeMeta, r.vmiObjectMeta),
 	}
-	err := r.podsManager.CreateFor(pod, vmiName)
+	err = r.podsManager.CreateFor(pod, vmiName)
 	if err != nil {
 		return nil, err
 	}
@@ -18,11 +18,14 @@ import (
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25/methods"
 	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kubevirtv1 "kubevirt.io/client-go/api/v1"
@@ -34,12 +37,16 @@ var (
 )
 
 func makeProvider() (*simulator.Model, *simulator.Server, *VmwareProvider) {
-	model := simulator.VPX()
+	return makeProviderFor(simulator.VPX())
+}
+
+func makeProviderFor(model *simulator.Model) (*simulator.Model, *simulator.Server, *VmwareProvider) {
 	_ = model.Create()
 	server := model.Service.NewServer()
+	simulator.Map.Put(&searchIndex{simulator.Map.SearchIndex()})
 	username := server.URL.User.Username()
 	password, _ := server.URL.User.Password()
-	vmwareClient, err := vclient.NewRichVMWareClient(server.URL.String(), username, password, "")
+	vmwareClient, err := vclient.NewRichVMWareClient(server.URL.String(), username, password, vclient.TLSOptions{})
 	Expect(err).To(BeNil())
 	provider := &VmwareProvider{
 		vmwareClient: vmwareClient,
@@ -231,6 +238,72 @@ var _ = Describe("Validation", func() {
 		Expect(*conditions[0].Message).ToNot(ContainSubstring("Changed Block Tracking must be enabled to allow warm import"))
 	})
 
+	It("should report a validation warning if disks are excluded from the import", func() {
+		vm := getSimulatorVM()
+		_, uuid, _ := getSimulatorVMIdentifiers(vm)
+
+		vm.Runtime.PowerState = types.VirtualMachinePowerStatePoweredOff
+		bootable := true
+		provider.instance.Spec.Disks = &v1beta1.DiskSelectionSpec{
+			Exclude: []v1beta1.DiskSelectorRule{{Bootable: &bootable}},
+		}
+		provider.instance.Spec.Source = v1beta1.VirtualMachineImportSourceSpec{
+			Vmware: &v1beta1.VirtualMachineImportVmwareSourceSpec{
+				VM: v1beta1.VirtualMachineImportVmwareSourceVMSpec{
+					ID:   &uuid,
+					Name: nil,
+				},
+			},
+		}
+
+		conditions, err := provider.Validate()
+		Expect(err).To(BeNil())
+
+		// valid condition, then mapping condition
+		Expect(len(conditions)).To(Equal(2))
+		Expect(conditions[0].Type).To(Equal(v1beta1.Valid))
+		Expect(*conditions[0].Reason).To(Equal(string(v1beta1.ValidationReportedWarnings)))
+		Expect(conditions[0].Status).To(Equal(v1.ConditionTrue))
+		Expect(*conditions[0].Message).To(ContainSubstring("Disks excluded from the import"))
+	})
+
+	It("should report a validation warning if the ISO image of a CD-ROM isn't found on its datastore", func() {
+		vm := getSimulatorVM()
+		_, uuid, _ := getSimulatorVMIdentifiers(vm)
+
+		vm.Runtime.PowerState = types.VirtualMachinePowerStatePoweredOff
+		datastore := vm.Datastore[0]
+		vm.Config.Hardware.Device = append(vm.Config.Hardware.Device, &types.VirtualCdrom{
+			VirtualDevice: types.VirtualDevice{
+				Key: 3002,
+				Backing: &types.VirtualCdromIsoBackingInfo{
+					VirtualDeviceFileBackingInfo: types.VirtualDeviceFileBackingInfo{
+						FileName:  "[LocalDS_0] iso/missing.iso",
+						Datastore: &datastore,
+					},
+				},
+			},
+		})
+		provider.instance.Spec.Source = v1beta1.VirtualMachineImportSourceSpec{
+			Vmware: &v1beta1.VirtualMachineImportVmwareSourceSpec{
+				VM: v1beta1.VirtualMachineImportVmwareSourceVMSpec{
+					ID:   &uuid,
+					Name: nil,
+				},
+			},
+		}
+
+		conditions, err := provider.Validate()
+		Expect(err).To(BeNil())
+
+		// valid condition, then mapping condition
+		Expect(len(conditions)).To(Equal(2))
+		Expect(conditions[0].Type).To(Equal(v1beta1.Valid))
+		Expect(*conditions[0].Reason).To(Equal(string(v1beta1.ValidationReportedWarnings)))
+		Expect(conditions[0].Status).To(Equal(v1.ConditionTrue))
+		Expect(*conditions[0].Message).To(ContainSubstring("[LocalDS_0] iso/missing.iso"))
+	})
+
 	It("should throw a validation failure the VM is powered on but the VMware tools aren't installed", func() {
 		vm := getSimulatorVM()
 		_, uuid, _ := getSimulatorVMIdentifiers(vm)
@@ -282,6 +355,134 @@ var _ = Describe("Validation", func() {
 		Expect(conditions[0].Status).To(Equal(v1.ConditionTrue))
 		Expect(*conditions[0].Message).ToNot(ContainSubstring("VM must be powered off, or up to date VMWare Tools must be installed and running to allow the guest to be shutdown gracefully"))
 	})
+	It("should not require the VMware tools for a hard shutdown", func() {
+		vm := getSimulatorVM()
+		_, uuid, _ := getSimulatorVMIdentifiers(vm)
+
+		vm.Runtime.PowerState = types.VirtualMachinePowerStatePoweredOn
+		vm.Guest.ToolsStatus = types.VirtualMachineToolsStatusToolsNotInstalled
+		method := v1beta1.HardShutdown
+		provider.instance.Spec.SourceShutdown = &v1beta1.SourceShutdownSpec{Method: &method}
+		provider.instance.Spec.Source = v1beta1.VirtualMachineImportSourceSpec{
+			Vmware: &v1beta1.VirtualMachineImportVmwareSourceSpec{
+				VM: v1beta1.VirtualMachineImportVmwareSourceVMSpec{
+					ID: &uuid,
+				},
+			},
+		}
+
+		conditions, err := provider.Validate()
+		Expect(err).To(BeNil())
+
+		Expect(conditions[0].Type).To(Equal(v1beta1.Valid))
+		Expect(*conditions[0].Reason).To(Equal(string(v1beta1.ValidationCompleted)))
+	})
+
+	It("should throw a validation failure if an ACPI shutdown was requested", func() {
+		vm := getSimulatorVM()
+		_, uuid, _ := getSimulatorVMIdentifiers(vm)
+
+		vm.Runtime.PowerState = types.VirtualMachinePowerStatePoweredOn
+		vm.Guest.ToolsStatus = types.VirtualMachineToolsStatusToolsOk
+		method := v1beta1.ACPIShutdown
+		provider.instance.Spec.SourceShutdown = &v1beta1.SourceShutdownSpec{Method: &method}
+		provider.instance.Spec.Source = v1beta1.VirtualMachineImportSourceSpec{
+			Vmware: &v1beta1.VirtualMachineImportVmwareSourceSpec{
+				VM: v1beta1.VirtualMachineImportVmwareSourceVMSpec{
+					ID: &uuid,
+				},
+			},
+		}
+
+		conditions, err := provider.Validate()
+		Expect(err).To(BeNil())
+
+		Expect(conditions[0].Type).To(Equal(v1beta1.Valid))
+		Expect(*conditions[0].Reason).To(Equal(string(v1beta1.ValidationFailed)))
+		Expect(*conditions[0].Message).To(ContainSubstring("ACPI shutdown is not supported by VMware"))
+	})
+})
+
+var _ = Describe("Validation against a standalone ESXi host", func() {
+	var provider *VmwareProvider
+	var model *simulator.Model
+	var server *simulator.Server
+	var vm *simulator.VirtualMachine
+
+	BeforeEach(func() {
+		model, server, provider = makeProviderFor(simulator.ESX())
+		vm = getSimulatorVM()
+		_, uuid, _ := getSimulatorVMIdentifiers(vm)
+		vm.Runtime.PowerState = types.VirtualMachinePowerStatePoweredOff
+		provider.instance.Spec.Source = v1beta1.VirtualMachineImportSourceSpec{
+			Vmware: &v1beta1.VirtualMachineImportVmwareSourceSpec{
+				VM: v1beta1.VirtualMachineImportVmwareSourceVMSpec{
+					ID:   &uuid,
+					Name: nil,
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+		model.Remove()
+	})
+
+	It("should not report a validation warning if CBT is disabled for a cold import", func() {
+		cbtEnabled := false
+		vm.Config.ChangeTrackingEnabled = &cbtEnabled
+
+		conditions, err := provider.Validate()
+		Expect(err).To(BeNil())
+
+		// valid condition, then mapping condition
+		Expect(len(conditions)).To(Equal(2))
+		Expect(conditions[0].Type).To(Equal(v1beta1.Valid))
+		Expect(*conditions[0].Reason).To(Equal(string(v1beta1.ValidationCompleted)))
+		Expect(conditions[0].Status).To(Equal(v1.ConditionTrue))
+	})
+
+	It("should report a validation warning if the host is managed by vCenter", func() {
+		host := simulator.Map.Any("HostSystem").(*simulator.HostSystem)
+		host.Summary.ManagementServerIp = "10.0.0.1"
+		cbtEnabled := true
+		vm.Config.ChangeTrackingEnabled = &cbtEnabled
+
+		conditions, err := provider.Validate()
+		Expect(err).To(BeNil())
+
+		Expect(conditions[0].Type).To(Equal(v1beta1.Valid))
+		Expect(*conditions[0].Reason).To(Equal(string(v1beta1.ValidationReportedWarnings)))
+		Expect(conditions[0].Status).To(Equal(v1.ConditionTrue))
+		Expect(*conditions[0].Message).To(ContainSubstring("ESXi host is managed by vCenter 10.0.0.1"))
+	})
+
+	It("should complete validation without warnings if CBT is enabled", func() {
+		cbtEnabled := true
+		vm.Config.ChangeTrackingEnabled = &cbtEnabled
+
+		conditions, err := provider.Validate()
+		Expect(err).To(BeNil())
+
+		Expect(conditions[0].Type).To(Equal(v1beta1.Valid))
+		Expect(*conditions[0].Reason).To(Equal(string(v1beta1.ValidationCompleted)))
+		Expect(conditions[0].Status).To(Equal(v1.ConditionTrue))
+	})
+
+	It("should still fail validation if a warm import was requested but CBT is disabled", func() {
+		cbtEnabled := false
+		vm.Config.ChangeTrackingEnabled = &cbtEnabled
+		provider.instance.Spec.Warm = true
+
+		conditions, err := provider.Validate()
+		Expect(err).To(BeNil())
+
+		Expect(conditions[0].Type).To(Equal(v1beta1.Valid))
+		Expect(*conditions[0].Reason).To(Equal(string(v1beta1.ValidationFailed)))
+		Expect(conditions[0].Status).To(Equal(v1.ConditionFalse))
+		Expect(*conditions[0].Message).To(ContainSubstring("Changed Block Tracking must be enabled to allow warm import"))
+	})
 })
 
 var _ = Describe("Initialization", func() {
@@ -494,6 +695,7 @@ var _ = Describe("CreateVMSnapshot", func() {
 		Expect(err).To(BeNil())
 		Expect(provider.vm).ToNot(BeNil())
 		Expect(snapshotRef[0:9]).To(Equal("snapshot-"))
+		Expect(vm.Snapshot.RootSnapshotList[0].Quiesced).To(BeTrue())
 	})
 
 	It("Should create a snapshot of a VM that is identified by Name", func() {
@@ -514,6 +716,40 @@ var _ = Describe("CreateVMSnapshot", func() {
 		Expect(provider.vm).ToNot(BeNil())
 		Expect(snapshotRef[0:9]).To(Equal("snapshot-"))
 	})
+	It("Should create a named snapshot for a snapshot import", func() {
+		vm := getSimulatorVM()
+		_, uuid, _ := getSimulatorVMIdentifiers(vm)
+		provider.instance.Spec.Snapshot = true
+		provider.instance.Spec.Source = v1beta1.VirtualMachineImportSourceSpec{
+			Vmware: &v1beta1.VirtualMachineImportVmwareSourceSpec{
+				VM: v1beta1.VirtualMachineImportVmwareSourceVMSpec{
+					ID: &uuid,
+				},
+			},
+		}
+
+		_, err := provider.CreateVMSnapshot()
+		Expect(err).To(BeNil())
+		Expect(vm.Snapshot).ToNot(BeNil())
+		Expect(vm.Snapshot.RootSnapshotList[0].Name).To(Equal(snapshotImportSnapshotName))
+	})
+	It("Should not quiesce the snapshot of a snapshot import if the VMware Tools aren't running", func() {
+		vm := getSimulatorVM()
+		vm.Guest.ToolsRunningStatus = string(types.VirtualMachineToolsRunningStatusGuestToolsNotRunning)
+		_, uuid, _ := getSimulatorVMIdentifiers(vm)
+		provider.instance.Spec.Snapshot = true
+		provider.instance.Spec.Source = v1beta1.VirtualMachineImportSourceSpec{
+			Vmware: &v1beta1.VirtualMachineImportVmwareSourceSpec{
+				VM: v1beta1.VirtualMachineImportVmwareSourceVMSpec{
+					ID: &uuid,
+				},
+			},
+		}
+
+		_, err := provider.CreateVMSnapshot()
+		Expect(err).To(BeNil())
+		Expect(vm.Snapshot.RootSnapshotList[0].Quiesced).To(BeFalse())
+	})
 })
 
 var _ = Describe("GetVMStatus", func() {
@@ -617,9 +853,10 @@ var _ = Describe("StartVM and StopVM", func() {
 		model = simulator.VPX()
 		_ = model.Load("../../../tests/vmware/vcsim")
 		server = model.Service.NewServer()
+		simulator.Map.Put(&searchIndex{simulator.Map.SearchIndex()})
 		username := server.URL.User.Username()
 		password, _ := server.URL.User.Password()
-		vmwareClient, err := vclient.NewRichVMWareClient(server.URL.String(), username, password, "")
+		vmwareClient, err := vclient.NewRichVMWareClient(server.URL.String(), username, password, vclient.TLSOptions{})
 		Expect(err).To(BeNil())
 		provider = VmwareProvider{
 			vmwareClient: vmwareClient,
@@ -669,7 +906,7 @@ var _ = Describe("StartVM and StopVM", func() {
 		Expect(err).To(BeNil())
 		Expect(powerState).To(Equal(providers.VMStatusUp))
 
-		err = provider.StopVM(provider.instance, mockClient)
+		err = provider.StopVM(provider.instance, mockClient, v1beta1.GuestShutdown)
 		Expect(err).To(BeNil())
 
 		provider.vmProperties = nil
@@ -684,7 +921,7 @@ var _ = Describe("StartVM and StopVM", func() {
 		Expect(err).To(BeNil())
 		Expect(powerState).To(Equal(providers.VMStatusDown))
 
-		err = provider.StopVM(provider.instance, mockClient)
+		err = provider.StopVM(provider.instance, mockClient, v1beta1.GuestShutdown)
 		Expect(err).To(BeNil())
 
 		provider.vmProperties = nil
@@ -733,7 +970,7 @@ var _ = table.DescribeTable("Processing a template", func(workloadLabel string)
 
 	username := server.URL.User.Username()
 	password, _ := server.URL.User.Password()
-	richClient, _ := vclient.NewRichVMWareClient(server.URL.String(), username, password, "")
+	richClient, _ := vclient.NewRichVMWareClient(server.URL.String(), username, password, vclient.TLSOptions{})
 	provider.vmwareClient = richClient
 	provider.vm = object.NewVirtualMachine(client.Client, simVm.Reference())
 	templateProvider := &mockTemplateProvider{}
@@ -883,3 +1120,109 @@ func (c *mockClient) List(ctx context.Context, objectList runtime.Object, opts .
 func (c *mockClient) Status() client.StatusWriter {
 	return c
 }
+
+var _ = Describe("Data volume credentials", func() {
+	var (
+		provider *VmwareProvider
+		secrets  *fakeSecretsManager
+	)
+
+	BeforeEach(func() {
+		secrets = &fakeSecretsManager{}
+		provider = &VmwareProvider{
+			vmwareSecretDataMap: map[string]string{
+				apiUrlKey:     "https://my.vsphere.example/sdk",
+				usernameKey:   "user",
+				passwordKey:   "pass",
+				thumbprintKey: "21:EA:74:11:59:89:5E:20:D5:D9:A2:39:5C:6A:2D:36:38:B2:52:2B",
+			},
+			secretsManager: secrets,
+			vmiObjectMeta:  metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		}
+	})
+
+	It("should create the secret of the importer pods", func() {
+		credentials, err := provider.prepareDataVolumeCredentials()
+
+		Expect(err).To(BeNil())
+		Expect(secrets.secret).ToNot(BeNil())
+		Expect(string(secrets.secret.Data[keyAccessKey])).To(Equal("user"))
+		Expect(string(secrets.secret.Data[keySecretKey])).To(Equal("pass"))
+		Expect(credentials.Password).To(Equal("pass"))
+	})
+
+	It("should update the secret of the importer pods when the password was rotated", func() {
+		_, err := provider.prepareDataVolumeCredentials()
+		Expect(err).To(BeNil())
+		provider.vmwareSecretDataMap[passwordKey] = "rotated"
+
+		_, err = provider.prepareDataVolumeCredentials()
+
+		Expect(err).To(BeNil())
+		Expect(secrets.created).To(Equal(1))
+		Expect(secrets.updated).To(Equal(1))
+		Expect(string(secrets.secret.Data[keySecretKey])).To(Equal("rotated"))
+	})
+
+	It("should not update the secret of the importer pods when the credentials didn't change", func() {
+		_, err := provider.prepareDataVolumeCredentials()
+		Expect(err).To(BeNil())
+
+		_, err = provider.prepareDataVolumeCredentials()
+
+		Expect(err).To(BeNil())
+		Expect(secrets.updated).To(Equal(0))
+	})
+})
+
+type fakeSecretsManager struct {
+	secret  *v1.Secret
+	created int
+	updated int
+}
+
+func (m *fakeSecretsManager) FindFor(k8stypes.NamespacedName) (*v1.Secret, error) {
+	if m.secret == nil {
+		return nil, nil
+	}
+	return m.secret.DeepCopy(), nil
+}
+
+func (m *fakeSecretsManager) CreateFor(secret *v1.Secret, vmiName k8stypes.NamespacedName) error {
+	secret.Name = vmiName.Name
+	m.secret = secret.DeepCopy()
+	m.created++
+	return nil
+}
+
+func (m *fakeSecretsManager) Update(secret *v1.Secret) error {
+	m.secret = secret.DeepCopy()
+	m.updated++
+	return nil
+}
+
+func (m *fakeSecretsManager) DeleteFor(k8stypes.NamespacedName) error {
+	m.secret = nil
+	return nil
+}
+
+// searchIndex adds FindAllByUuid, which vcsim doesn't implement, to the search index of the simulator. The datacenter
+// of the request is ignored.
+type searchIndex struct {
+	*simulator.SearchIndex
+}
+
+func (s *searchIndex) FindAllByUuid(req *types.FindAllByUuid) soap.HasFault {
+	body := &methods.FindAllByUuidBody{Res: new(types.FindAllByUuidResponse)}
+	for _, obj := range simulator.Map.All("VirtualMachine") {
+		vm := obj.(*simulator.VirtualMachine)
+		uuid := vm.Config.Uuid
+		if req.InstanceUuid != nil && *req.InstanceUuid {
+			uuid = vm.Config.InstanceUuid
+		}
+		if uuid == req.Uuid {
+			body.Res.Returnval = append(body.Res.Returnval, vm.Reference())
+		}
+	}
+	return body
+}
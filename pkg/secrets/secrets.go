@@ -63,6 +63,11 @@ func (m *Manager) CreateFor(secret *corev1.Secret, vmiCrName types.NamespacedNam
 	return m.client.Create(context.TODO(), secret)
 }
 
+// Update updates given secret, e.g. after the credentials it was derived from have changed.
+func (m *Manager) Update(secret *corev1.Secret) error {
+	return m.client.Update(context.TODO(), secret)
+}
+
 // DeleteFor removes secret associated with vmiCrName.
 func (m *Manager) DeleteFor(vmiCrName types.NamespacedName) error {
 	secret, err := m.FindFor(vmiCrName)
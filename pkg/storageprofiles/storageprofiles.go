@@ -0,0 +1,168 @@
+package storageprofiles
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// annDefaultStorageClass marks the default storage class of the cluster
+	annDefaultStorageClass = "storageclass.kubernetes.io/is-default-class"
+	// annBetaDefaultStorageClass marks the default storage class of the cluster on older clusters
+	annBetaDefaultStorageClass = "storageclass.beta.kubernetes.io/is-default-class"
+)
+
+// storageProfileKind is the kind of the CDI StorageProfile resources. CDI names them after their storage class.
+var storageProfileKind = schema.GroupVersionKind{Group: "cdi.kubevirt.io", Version: "v1beta1", Kind: "StorageProfile"}
+
+// ClaimPropertySet is a combination of access modes and a volume mode that CDI reports a storage class supports
+type ClaimPropertySet struct {
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+	VolumeMode  *corev1.PersistentVolumeMode        `json:"volumeMode,omitempty"`
+}
+
+// Finder finds the claim property sets of storage classes
+type Finder interface {
+	ClaimPropertySets(storageClass *string) ([]ClaimPropertySet, error)
+}
+
+// StorageProfiles finds the claim property sets of storage classes in their CDI StorageProfile
+type StorageProfiles struct {
+	client client.Client
+	cache  map[string][]ClaimPropertySet
+}
+
+// NewStorageProfiles creates new StorageProfiles
+func NewStorageProfiles(client client.Client) *StorageProfiles {
+	return &StorageProfiles{
+		client: client,
+		cache:  make(map[string][]ClaimPropertySet),
+	}
+}
+
+// ClaimPropertySets returns the claim property sets of the storage class, the default one when it is nil. There are
+// none when the storage class has no StorageProfile, e.g. when CDI doesn't provide them.
+func (p *StorageProfiles) ClaimPropertySets(storageClass *string) ([]ClaimPropertySet, error) {
+	var name string
+	if storageClass != nil && *storageClass != "" {
+		name = *storageClass
+	} else {
+		defaultClass, err := p.defaultStorageClass()
+		if err != nil || defaultClass == "" {
+			return nil, err
+		}
+		name = defaultClass
+	}
+	if sets, found := p.cache[name]; found {
+		return sets, nil
+	}
+
+	profile := &unstructured.Unstructured{}
+	profile.SetGroupVersionKind(storageProfileKind)
+	err := p.client.Get(context.TODO(), types.NamespacedName{Name: name}, profile)
+	if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		p.cache[name] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sets []ClaimPropertySet
+	rawSets, found, err := unstructured.NestedSlice(profile.Object, "status", "claimPropertySets")
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		for _, rawSet := range rawSets {
+			rawSetMap, ok := rawSet.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			set := ClaimPropertySet{}
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(rawSetMap, &set)
+			if err != nil {
+				return nil, err
+			}
+			sets = append(sets, set)
+		}
+	}
+	p.cache[name] = sets
+	return sets, nil
+}
+
+// defaultStorageClass returns the name of the default storage class, if any
+func (p *StorageProfiles) defaultStorageClass() (string, error) {
+	storageClasses := &storagev1.StorageClassList{}
+	err := p.client.List(context.TODO(), storageClasses)
+	if err != nil {
+		return "", err
+	}
+	for _, storageClass := range storageClasses.Items {
+		if storageClass.Annotations[annDefaultStorageClass] == "true" || storageClass.Annotations[annBetaDefaultStorageClass] == "true" {
+			return storageClass.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// Recommend returns the best access and volume modes of a claim of the storage class among those supported by its
+// StorageProfile, keeping the modes already set. ReadWriteMany, which allows the live migration of the VM, is
+// preferred, then the Block volume mode. It returns false when the storage class supports none with the modes already
+// set, or has no StorageProfile, or when no finder is given.
+func Recommend(finder Finder, storageClass *string, accessMode *corev1.PersistentVolumeAccessMode, volumeMode *corev1.PersistentVolumeMode) (corev1.PersistentVolumeAccessMode, corev1.PersistentVolumeMode, bool, error) {
+	if finder == nil || (accessMode != nil && volumeMode != nil) {
+		return "", "", false, nil
+	}
+	sets, err := finder.ClaimPropertySets(storageClass)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	var bestAccessMode corev1.PersistentVolumeAccessMode
+	var bestVolumeMode corev1.PersistentVolumeMode
+	bestScore := -1
+	for _, set := range sets {
+		setVolumeMode := corev1.PersistentVolumeFilesystem
+		if set.VolumeMode != nil {
+			setVolumeMode = *set.VolumeMode
+		}
+		if volumeMode != nil && *volumeMode != setVolumeMode {
+			continue
+		}
+		for _, setAccessMode := range set.AccessModes {
+			if accessMode != nil && *accessMode != setAccessMode {
+				continue
+			}
+			score := modesScore(setAccessMode, setVolumeMode)
+			if score > bestScore {
+				bestAccessMode, bestVolumeMode, bestScore = setAccessMode, setVolumeMode, score
+			}
+		}
+	}
+	return bestAccessMode, bestVolumeMode, bestScore >= 0, nil
+}
+
+// modesScore ranks the access and volume modes of a claim, the higher the better
+func modesScore(accessMode corev1.PersistentVolumeAccessMode, volumeMode corev1.PersistentVolumeMode) int {
+	score := 0
+	switch accessMode {
+	case corev1.ReadWriteMany:
+		score += 4
+	case corev1.ReadWriteOnce:
+		score += 2
+	}
+	if volumeMode == corev1.PersistentVolumeBlock {
+		score++
+	}
+	return score
+}
@@ -0,0 +1,13 @@
+package storageprofiles_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestStorageProfiles(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Storage profiles Suite")
+}
@@ -0,0 +1,144 @@
+package storageprofiles_test
+
+import (
+	"github.com/kubevirt/vm-import-operator/pkg/storageprofiles"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var (
+	rwo        = corev1.ReadWriteOnce
+	rwx        = corev1.ReadWriteMany
+	block      = corev1.PersistentVolumeBlock
+	filesystem = corev1.PersistentVolumeFilesystem
+)
+
+var _ = Describe("Finding the claim property sets", func() {
+	It("should read the StorageProfile of the storage class", func() {
+		client := fake.NewFakeClientWithScheme(scheme.Scheme, newStorageProfile("ceph-rbd"))
+		storageClass := "ceph-rbd"
+
+		sets, err := storageprofiles.NewStorageProfiles(client).ClaimPropertySets(&storageClass)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sets).To(ConsistOf(
+			storageprofiles.ClaimPropertySet{AccessModes: []corev1.PersistentVolumeAccessMode{rwx}, VolumeMode: &block},
+			storageprofiles.ClaimPropertySet{AccessModes: []corev1.PersistentVolumeAccessMode{rwo}, VolumeMode: &filesystem},
+		))
+	})
+
+	It("should read the StorageProfile of the default storage class", func() {
+		defaultClass := &storagev1.StorageClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "ceph-rbd",
+				Annotations: map[string]string{"storageclass.kubernetes.io/is-default-class": "true"},
+			},
+		}
+		client := fake.NewFakeClientWithScheme(scheme.Scheme, newStorageProfile("ceph-rbd"), defaultClass)
+
+		sets, err := storageprofiles.NewStorageProfiles(client).ClaimPropertySets(nil)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sets).To(HaveLen(2))
+	})
+
+	It("should find none without a StorageProfile", func() {
+		client := fake.NewFakeClientWithScheme(scheme.Scheme)
+		storageClass := "local"
+
+		sets, err := storageprofiles.NewStorageProfiles(client).ClaimPropertySets(&storageClass)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sets).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Recommending the claim modes", func() {
+	finder := &mockFinder{sets: []storageprofiles.ClaimPropertySet{
+		{AccessModes: []corev1.PersistentVolumeAccessMode{rwo}, VolumeMode: &filesystem},
+		{AccessModes: []corev1.PersistentVolumeAccessMode{rwo, rwx}, VolumeMode: &block},
+		{AccessModes: []corev1.PersistentVolumeAccessMode{rwx}, VolumeMode: &filesystem},
+	}}
+
+	It("should prefer ReadWriteMany and Block", func() {
+		accessMode, volumeMode, found, err := storageprofiles.Recommend(finder, nil, nil, nil)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(accessMode).To(Equal(rwx))
+		Expect(volumeMode).To(Equal(block))
+	})
+
+	It("should keep the access mode already set", func() {
+		accessMode, volumeMode, found, err := storageprofiles.Recommend(finder, nil, &rwo, nil)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(accessMode).To(Equal(rwo))
+		Expect(volumeMode).To(Equal(block))
+	})
+
+	It("should keep the volume mode already set", func() {
+		accessMode, volumeMode, found, err := storageprofiles.Recommend(finder, nil, nil, &filesystem)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(accessMode).To(Equal(rwx))
+		Expect(volumeMode).To(Equal(filesystem))
+	})
+
+	It("should recommend nothing when the modes already set aren't supported", func() {
+		rox := corev1.ReadOnlyMany
+
+		_, _, found, err := storageprofiles.Recommend(finder, nil, &rox, nil)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("should recommend nothing without a finder", func() {
+		_, _, found, err := storageprofiles.Recommend(nil, nil, nil, nil)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+})
+
+type mockFinder struct {
+	sets []storageprofiles.ClaimPropertySet
+}
+
+func (m *mockFinder) ClaimPropertySets(_ *string) ([]storageprofiles.ClaimPropertySet, error) {
+	return m.sets, nil
+}
+
+func newStorageProfile(name string) *unstructured.Unstructured {
+	profile := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cdi.kubevirt.io/v1beta1",
+		"kind":       "StorageProfile",
+		"metadata": map[string]interface{}{
+			"name": name,
+		},
+		"status": map[string]interface{}{
+			"storageClass": name,
+			"claimPropertySets": []interface{}{
+				map[string]interface{}{
+					"accessModes": []interface{}{"ReadWriteMany"},
+					"volumeMode":  "Block",
+				},
+				map[string]interface{}{
+					"accessModes": []interface{}{"ReadWriteOnce"},
+					"volumeMode":  "Filesystem",
+				},
+			},
+		},
+	}}
+	return profile
+}
@@ -10,8 +10,8 @@ const (
 	// templateNameLabel defines a label of the template name which was used to created the VM
 	templateNameLabel = "vm.kubevirt.io/template"
 
-	// templateNamespace defines a label of the template namespace which was used to create the VM
-	templateNamespace = "vm.kubevirt.io/template.namespace"
+	// TemplateNamespaceLabel defines a label of the template namespace which was used to create the VM
+	TemplateNamespaceLabel = "vm.kubevirt.io/template.namespace"
 
 	// vmNameLabel defines a label of virtual machine name which was used to create the VM
 	vmNameLabel = "vm.kubevirt.io/name"
@@ -68,7 +68,7 @@ func addLabels(vm *kubevirtv1.VirtualMachine, template *templatev1.Template) {
 		vm.ObjectMeta.SetLabels(labels)
 	}
 	labels[templateNameLabel] = template.GetObjectMeta().GetName()
-	labels[templateNamespace] = template.GetObjectMeta().GetNamespace()
+	labels[TemplateNamespaceLabel] = template.GetObjectMeta().GetNamespace()
 	tempLabels := vm.Spec.Template.ObjectMeta.GetLabels()
 	tempLabels[vmNameLabel] = vm.GetName()
 }
@@ -30,6 +30,18 @@ const (
 
 	// Finalaizer for handling cancelled import
 	CancelledImportFinalizer = "vmimport.v2v.kubevirt.io/cancelled-import"
+
+	// AnnPreallocation records on a data volume whether its space is allocated up front
+	AnnPreallocation = "vmimport.v2v.kubevirt.io/preallocation"
+
+	// AnnEstimatedBytes records on a data volume the estimate of the bytes of storage it consumes
+	AnnEstimatedBytes = "vmimport.v2v.kubevirt.io/estimated-bytes"
+
+	// AnnSourceBytes records on a data volume the size of the source disk it copies
+	AnnSourceBytes = "vmimport.v2v.kubevirt.io/source-bytes"
+
+	// AnnDataSource records on a data volume the namespace/name of the CDI DataSource whose PVC it clones
+	AnnDataSource = "vmimport.v2v.kubevirt.io/data-source"
 )
 
 var (
@@ -89,6 +101,21 @@ func IndexStorageItemByIDAndName(mapping *[]v2vv1.StorageResourceMappingItem) (m
 	return
 }
 
+// IndexCdromItemByIDAndName indexes mapping array by ID and by Name
+func IndexCdromItemByIDAndName(mapping *[]v2vv1.CdromResourceMappingItem) (mapByID map[string]v2vv1.CdromResourceMappingItem, mapByName map[string]v2vv1.CdromResourceMappingItem) {
+	mapByID = make(map[string]v2vv1.CdromResourceMappingItem)
+	mapByName = make(map[string]v2vv1.CdromResourceMappingItem)
+	for _, item := range *mapping {
+		if item.Source.ID != nil {
+			mapByID[*item.Source.ID] = item
+		}
+		if item.Source.Name != nil {
+			mapByName[*item.Source.Name] = item
+		}
+	}
+	return
+}
+
 // IndexNetworkByIDAndName indexes mapping array by ID and by Name
 func IndexNetworkByIDAndName(mapping *[]v2vv1.NetworkResourceMappingItem) (mapByID map[string]v2vv1.NetworkResourceMappingItem, mapByName map[string]v2vv1.NetworkResourceMappingItem) {
 	mapByID = make(map[string]v2vv1.NetworkResourceMappingItem)
@@ -386,3 +413,55 @@ func RoundUp(number, multiple int64) int64 {
 	partitions := math.Ceil(float64(number) / float64(multiple))
 	return int64(partitions) * multiple
 }
+
+// Preallocate tells whether to allocate the space of a disk up front according to the preallocation mode, following
+// the provisioning of the source disk for inheritFromSource. It returns nil without a mode, leaving it to CDI.
+func Preallocate(mode *v2vv1.PreallocationMode, sourceSparse bool) *bool {
+	if mode == nil {
+		return nil
+	}
+	var preallocate bool
+	switch *mode {
+	case v2vv1.FullPreallocation:
+		preallocate = true
+	case v2vv1.InheritPreallocation:
+		preallocate = !sourceSparse
+	default:
+		preallocate = false
+	}
+	return &preallocate
+}
+
+// EstimateConsumedBytes estimates the bytes of storage consumed by a data volume of the size: all of them when it is
+// preallocated, else the bytes allocated to the source disk, or the whole size when those are unknown.
+func EstimateConsumedBytes(size int64, sourceAllocated int64, preallocate *bool) int64 {
+	if (preallocate != nil && *preallocate) || sourceAllocated <= 0 || sourceAllocated > size {
+		return size
+	}
+	return sourceAllocated
+}
+
+// SetPreallocation records on the data volume whether its space is allocated up front, when it is decided, and the
+// estimate of the bytes of storage it consumes
+func SetPreallocation(dv *cdiv1.DataVolume, preallocate *bool, estimatedBytes int64) {
+	annotations := dv.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if preallocate != nil {
+		annotations[AnnPreallocation] = strconv.FormatBool(*preallocate)
+	}
+	annotations[AnnEstimatedBytes] = strconv.FormatInt(estimatedBytes, 10)
+	dv.SetAnnotations(annotations)
+}
+
+// SetSourceBytes records on the data volume the size of the source disk it copies, which its copy progress is
+// measured against
+func SetSourceBytes(dv *cdiv1.DataVolume, sourceBytes int64) {
+	annotations := dv.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AnnSourceBytes] = strconv.FormatInt(sourceBytes, 10)
+	dv.SetAnnotations(annotations)
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/alecthomas/units"
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
 	"github.com/kubevirt/vm-import-operator/pkg/utils"
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
@@ -184,6 +185,40 @@ var _ = Describe("Label generation", func() {
 	)
 })
 
+var _ = Describe("Preallocation", func() {
+	table.DescribeTable("should decide the preallocation of the disk", func(mode *v2vv1.PreallocationMode, sourceSparse bool, expected *bool) {
+		preallocate := utils.Preallocate(mode, sourceSparse)
+
+		Expect(preallocate).To(Equal(expected))
+	},
+		table.Entry("left to CDI without a mode", nil, false, nil),
+		table.Entry("sparse", preallocationMode(v2vv1.SparsePreallocation), false, boolPtr(false)),
+		table.Entry("preallocated", preallocationMode(v2vv1.FullPreallocation), true, boolPtr(true)),
+		table.Entry("inherited from a sparse source", preallocationMode(v2vv1.InheritPreallocation), true, boolPtr(false)),
+		table.Entry("inherited from a thick source", preallocationMode(v2vv1.InheritPreallocation), false, boolPtr(true)),
+	)
+
+	table.DescribeTable("should estimate the bytes consumed by the disk", func(sourceAllocated int64, preallocate *bool, expected int64) {
+		estimate := utils.EstimateConsumedBytes(1000, sourceAllocated, preallocate)
+
+		Expect(estimate).To(Equal(expected))
+	},
+		table.Entry("sparse", int64(300), boolPtr(false), int64(300)),
+		table.Entry("left to CDI", int64(300), nil, int64(300)),
+		table.Entry("preallocated", int64(300), boolPtr(true), int64(1000)),
+		table.Entry("unknown source allocation", int64(0), boolPtr(false), int64(1000)),
+		table.Entry("source allocation beyond the size", int64(2000), boolPtr(false), int64(1000)),
+	)
+})
+
+func preallocationMode(mode v2vv1.PreallocationMode) *v2vv1.PreallocationMode {
+	return &mode
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
+
 func createStringOfLength(n int) string {
 	return strings.Repeat("x", n)
 }
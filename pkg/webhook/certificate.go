@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/cert"
+)
+
+// CertificateRenewalPeriod is how long before its expiry the serving certificate is renewed
+const CertificateRenewalPeriod = 30 * 24 * time.Hour
+
+// GenerateCertificate generates a self-signed serving certificate for the webhook service in the given namespace. The
+// certificate is followed by the self-signed CA certificate it is signed with, so that it is also the CA bundle the API
+// server verifies it with.
+func GenerateCertificate(namespace string) ([]byte, []byte, error) {
+	host := fmt.Sprintf("%s.%s.svc", ServiceName, namespace)
+	alternateDNS := []string{
+		ServiceName,
+		fmt.Sprintf("%s.%s", ServiceName, namespace),
+		fmt.Sprintf("%s.cluster.local", host),
+	}
+	return cert.GenerateSelfSignedCertKey(host, nil, alternateDNS)
+}
+
+// NeedsNewCertificate returns whether the certificate secret holds no valid serving certificate, or one expiring
+// within the renewal period
+func NeedsNewCertificate(secret *corev1.Secret, now time.Time) bool {
+	certPEM := secret.Data[corev1.TLSCertKey]
+	_, err := tls.X509KeyPair(certPEM, secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return true
+	}
+	certificates, err := cert.ParseCertsPEM(certPEM)
+	if err != nil {
+		return true
+	}
+	for _, certificate := range certificates {
+		if now.Add(CertificateRenewalPeriod).After(certificate.NotAfter) {
+			return true
+		}
+	}
+	return false
+}
+
+// CABundleOf returns the CA bundle the serving certificate of the certificate secret is verified with
+func CABundleOf(secret *corev1.Secret) []byte {
+	return secret.Data[corev1.TLSCertKey]
+}
@@ -0,0 +1,114 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ResourceMappingValidator rejects ResourceMapping resources mapping the same source resource more than once
+type ResourceMappingValidator struct {
+	decoder *admission.Decoder
+}
+
+// Handle validates the created or updated ResourceMapping
+func (v *ResourceMappingValidator) Handle(_ context.Context, req admission.Request) admission.Response {
+	mapping := &v2vv1.ResourceMapping{}
+	err := v.decoder.Decode(req, mapping)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	errs := validateResourceMappingSpec(&mapping.Spec, field.NewPath("spec"))
+	if len(errs) > 0 {
+		return admission.Denied(errs.ToAggregate().Error())
+	}
+	return admission.Allowed("")
+}
+
+// InjectDecoder injects the decoder of the admission requests
+func (v *ResourceMappingValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+func validateResourceMappingSpec(spec *v2vv1.ResourceMappingSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if spec.OvirtMappings != nil {
+		errs = append(errs, validateOvirtMappings(spec.OvirtMappings, path.Child("ovirt"))...)
+	}
+	if spec.VmwareMappings != nil {
+		errs = append(errs, validateVmwareMappings(spec.VmwareMappings, path.Child("vmware"))...)
+	}
+	return errs
+}
+
+func validateOvirtMappings(mappings *v2vv1.OvirtMappings, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if mappings.NetworkMappings != nil {
+		errs = append(errs, duplicateSources(networkSources(*mappings.NetworkMappings), path.Child("networkMappings"))...)
+	}
+	if mappings.StorageMappings != nil {
+		errs = append(errs, duplicateSources(storageSources(*mappings.StorageMappings), path.Child("storageMappings"))...)
+	}
+	if mappings.DiskMappings != nil {
+		errs = append(errs, duplicateSources(storageSources(*mappings.DiskMappings), path.Child("diskMappings"))...)
+	}
+	return errs
+}
+
+func validateVmwareMappings(mappings *v2vv1.VmwareMappings, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if mappings.NetworkMappings != nil {
+		errs = append(errs, duplicateSources(networkSources(*mappings.NetworkMappings), path.Child("networkMappings"))...)
+	}
+	if mappings.StorageMappings != nil {
+		errs = append(errs, duplicateSources(storageSources(*mappings.StorageMappings), path.Child("storageMappings"))...)
+	}
+	if mappings.DiskMappings != nil {
+		errs = append(errs, duplicateSources(storageSources(*mappings.DiskMappings), path.Child("diskMappings"))...)
+	}
+	return errs
+}
+
+func networkSources(items []v2vv1.NetworkResourceMappingItem) []v2vv1.Source {
+	sources := make([]v2vv1.Source, len(items))
+	for i, item := range items {
+		sources[i] = item.Source
+	}
+	return sources
+}
+
+func storageSources(items []v2vv1.StorageResourceMappingItem) []v2vv1.Source {
+	sources := make([]v2vv1.Source, len(items))
+	for i, item := range items {
+		sources[i] = item.Source
+	}
+	return sources
+}
+
+// duplicateSources reports the entries whose source ID or name was already mapped by a previous entry, since only
+// one of the targets would be used
+func duplicateSources(sources []v2vv1.Source, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	ids := make(map[string]bool)
+	names := make(map[string]bool)
+	for i, source := range sources {
+		if source.ID != nil {
+			if ids[*source.ID] {
+				errs = append(errs, field.Duplicate(path.Index(i).Child("source", "id"), *source.ID))
+			}
+			ids[*source.ID] = true
+		}
+		if source.Name != nil {
+			if names[*source.Name] {
+				errs = append(errs, field.Duplicate(path.Index(i).Child("source", "name"), *source.Name))
+			}
+			names[*source.Name] = true
+		}
+	}
+	return errs
+}
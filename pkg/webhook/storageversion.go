@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MigrationRetryInterval is how often the migration of the stored resources is retried until it succeeds
+const MigrationRetryInterval = 30 * time.Second
+
+// storageVersionMigrator rewrites the resources still stored in a former version of the converted custom resource
+// definitions, so that the former versions can be removed from their stored versions and eventually stop being served.
+// The resources are read from the API server rather than from the cache, which only holds the watched namespace when
+// the controller is scoped to one, since every resource of the cluster must have been rewritten.
+type storageVersionMigrator struct {
+	client   client.Client
+	reader   client.Reader
+	interval time.Duration
+}
+
+// Start migrates the stored resources, retrying until it succeeds or the stop channel is closed.
+// It implements the controller-runtime manager.Runnable interface.
+func (m *storageVersionMigrator) Start(stop <-chan struct{}) error {
+	err := wait.PollImmediateUntil(m.interval, func() (bool, error) {
+		err := m.migrate()
+		if err != nil {
+			// the conversion webhook may not be reachable yet
+			log.Info("Failed to migrate the stored resources to the storage version, retrying", "Error", err.Error())
+			return false, nil
+		}
+		return true, nil
+	}, stop)
+	if err == wait.ErrWaitTimeout {
+		return nil
+	}
+	return err
+}
+
+func (m *storageVersionMigrator) migrate() error {
+	for _, name := range ConvertedCRDs {
+		err := m.migrateCRD(name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (m *storageVersionMigrator) migrateCRD(name string) error {
+	crd := &extv1.CustomResourceDefinition{}
+	err := m.reader.Get(context.TODO(), types.NamespacedName{Name: name}, crd)
+	if err != nil {
+		return err
+	}
+	storageVersion := storageVersionOf(crd)
+	if len(crd.Status.StoredVersions) == 1 && crd.Status.StoredVersions[0] == storageVersion {
+		return nil
+	}
+
+	list, err := newList(name)
+	if err != nil {
+		return err
+	}
+	err = m.reader.List(context.TODO(), list)
+	if err != nil {
+		return err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	// updating a resource stores it in the storage version, even when nothing changed
+	for _, item := range items {
+		err = m.client.Update(context.TODO(), item)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Info("Migrated the stored resources to the storage version", "CRD", name, "Version", storageVersion, "Count", len(items))
+	crd.Status.StoredVersions = []string{storageVersion}
+	return m.client.Status().Update(context.TODO(), crd)
+}
+
+func storageVersionOf(crd *extv1.CustomResourceDefinition) string {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name
+		}
+	}
+	return ""
+}
+
+func newList(crdName string) (runtime.Object, error) {
+	switch crdName {
+	case "virtualmachineimports.v2v.kubevirt.io":
+		return &v2vv1.VirtualMachineImportList{}, nil
+	case "resourcemappings.v2v.kubevirt.io":
+		return &v2vv1.ResourceMappingList{}, nil
+	}
+	return nil, fmt.Errorf("resources of %s can't be migrated", crdName)
+}
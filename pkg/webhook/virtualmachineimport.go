@@ -0,0 +1,256 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/conditions"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// VirtualMachineImportValidator rejects structurally invalid VirtualMachineImport resources, which would otherwise
+// only be reported in their Valid condition, and changes to the source and the mapping of an import in progress
+type VirtualMachineImportValidator struct {
+	decoder *admission.Decoder
+}
+
+// Handle validates the created or updated VirtualMachineImport
+func (v *VirtualMachineImportValidator) Handle(_ context.Context, req admission.Request) admission.Response {
+	instance := &v2vv1.VirtualMachineImport{}
+	err := v.decoder.Decode(req, instance)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	var errs field.ErrorList
+	if req.Operation == admissionv1beta1.Update {
+		old := &v2vv1.VirtualMachineImport{}
+		err = v.decoder.DecodeRaw(req.OldObject, old)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		// imports created before the webhook was deployed may be invalid, they must still accept finalizers
+		// and annotations
+		if reflect.DeepEqual(old.Spec, instance.Spec) {
+			return admission.Allowed("")
+		}
+		errs = append(errs, validateVirtualMachineImportUpdate(old, instance)...)
+	}
+	errs = append(errs, validateVirtualMachineImportSpec(&instance.Spec, field.NewPath("spec"))...)
+
+	if len(errs) > 0 {
+		return admission.Denied(errs.ToAggregate().Error())
+	}
+	return admission.Allowed("")
+}
+
+// InjectDecoder injects the decoder of the admission requests
+func (v *VirtualMachineImportValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+func validateVirtualMachineImportSpec(spec *v2vv1.VirtualMachineImportSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	sourcePath := path.Child("source")
+	switch sourceCount(spec.Source) {
+	case 0:
+		errs = append(errs, field.Required(sourcePath, "one of ovirt, vmware and pvc must be set"))
+	case 1:
+		errs = append(errs, validateSource(spec, path)...)
+	default:
+		errs = append(errs, field.Invalid(sourcePath, strings.Join(sourceNames(spec.Source), ", "), "only one of ovirt, vmware and pvc may be set"))
+	}
+
+	if spec.TargetVMName != nil {
+		for _, msg := range k8svalidation.IsDNS1123Label(*spec.TargetVMName) {
+			errs = append(errs, field.Invalid(path.Child("targetVmName"), *spec.TargetVMName, msg))
+		}
+	}
+
+	if spec.Disks != nil {
+		if spec.Source.PVC != nil {
+			errs = append(errs, field.Forbidden(path.Child("disks"), "the disks already in persistent volume claims are listed in the pvc source"))
+		}
+		errs = append(errs, validateDiskSelectorRules(spec.Disks.Include, path.Child("disks", "include"))...)
+		errs = append(errs, validateDiskSelectorRules(spec.Disks.Exclude, path.Child("disks", "exclude"))...)
+	}
+	return errs
+}
+
+// validateDiskSelectorRules checks that each rule sets what the disks are matched on, with a size range that can match
+func validateDiskSelectorRules(rules []v2vv1.DiskSelectorRule, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for i, rule := range rules {
+		rulePath := path.Index(i)
+		if rule.ID == nil && rule.Name == nil && rule.Bootable == nil && rule.MinSize == nil && rule.MaxSize == nil {
+			errs = append(errs, field.Required(rulePath, "one of id, name, bootable, minSize and maxSize must be set"))
+		}
+		if rule.MinSize != nil && rule.MaxSize != nil && rule.MinSize.Cmp(*rule.MaxSize) > 0 {
+			errs = append(errs, field.Invalid(rulePath.Child("minSize"), rule.MinSize.String(), "must not be greater than maxSize"))
+		}
+	}
+	return errs
+}
+
+// validateSource validates the only source set in the spec
+func validateSource(spec *v2vv1.VirtualMachineImportSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	sourcePath := path.Child("source")
+	switch {
+	case spec.Source.Ovirt != nil:
+		if spec.Source.Ovirt.VMSelector == nil {
+			errs = append(errs, validateOvirtVM(&spec.Source.Ovirt.VM, sourcePath.Child("ovirt", "vm"))...)
+		}
+		if spec.Source.Ovirt.Mappings != nil {
+			errs = append(errs, validateOvirtMappings(spec.Source.Ovirt.Mappings, sourcePath.Child("ovirt", "mappings"))...)
+		}
+		if spec.Warm {
+			errs = append(errs, field.Forbidden(path.Child("warm"), "warm import isn't supported by ovirt providers"))
+		}
+		if spec.GuestCustomization != nil && spec.Source.Ovirt.GuestConversion != nil && *spec.Source.Ovirt.GuestConversion == v2vv1.GuestConversionNever {
+			errs = append(errs, field.Forbidden(path.Child("guestCustomization"), "the guest is customized by virt-v2v, which the Never guest conversion policy doesn't run"))
+		}
+	case spec.Source.Vmware != nil:
+		errs = append(errs, validateVmwareVM(&spec.Source.Vmware.VM, sourcePath.Child("vmware", "vm"))...)
+		if spec.Source.Vmware.Mappings != nil {
+			errs = append(errs, validateVmwareMappings(spec.Source.Vmware.Mappings, sourcePath.Child("vmware", "mappings"))...)
+		}
+	case spec.Source.PVC != nil:
+		errs = append(errs, validatePVCSource(spec.Source.PVC, sourcePath.Child("pvc"))...)
+		if spec.Warm {
+			errs = append(errs, field.Forbidden(path.Child("warm"), "warm import isn't supported for disks already in persistent volume claims"))
+		}
+	}
+	return errs
+}
+
+// validatePVCSource checks that the disks already in persistent volume claims are attached to either the source VM
+// they were copied from or the described hardware
+func validatePVCSource(source *v2vv1.VirtualMachineImportPVCSourceSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if len(source.Disks) == 0 {
+		errs = append(errs, field.Required(path.Child("disks"), "at least one persistent volume claim must be set"))
+	}
+	for i, disk := range source.Disks {
+		if disk.ClaimName == "" {
+			errs = append(errs, field.Required(path.Child("disks").Index(i).Child("claimName"), ""))
+		}
+	}
+
+	switch {
+	case source.Hardware != nil && source.SourceVM != nil:
+		errs = append(errs, field.Invalid(path, "hardware, sourceVM", "only one of hardware and sourceVM may be set"))
+	case source.Hardware == nil && source.SourceVM == nil:
+		errs = append(errs, field.Required(path, "either hardware or sourceVM must be set"))
+	case source.Hardware != nil:
+		if source.Hardware.Memory.Sign() <= 0 {
+			errs = append(errs, field.Invalid(path.Child("hardware", "memory"), source.Hardware.Memory.String(), "must be greater than zero"))
+		}
+		if source.Hardware.Sockets != nil && *source.Hardware.Sockets <= 0 {
+			errs = append(errs, field.Invalid(path.Child("hardware", "sockets"), *source.Hardware.Sockets, "must be greater than zero"))
+		}
+		if source.Hardware.Cores != nil && *source.Hardware.Cores <= 0 {
+			errs = append(errs, field.Invalid(path.Child("hardware", "cores"), *source.Hardware.Cores, "must be greater than zero"))
+		}
+	case source.SourceVM != nil:
+		sourceVMPath := path.Child("sourceVM")
+		switch {
+		case source.SourceVM.Ovirt != nil && source.SourceVM.Vmware != nil:
+			errs = append(errs, field.Invalid(sourceVMPath, "ovirt, vmware", "only one of ovirt and vmware may be set"))
+		case source.SourceVM.Ovirt == nil && source.SourceVM.Vmware == nil:
+			errs = append(errs, field.Required(sourceVMPath, "either ovirt or vmware must be set"))
+		case source.SourceVM.Ovirt != nil:
+			if source.SourceVM.Ovirt.VMSelector != nil {
+				errs = append(errs, field.Forbidden(sourceVMPath.Child("ovirt", "vmSelector"), "the source VM must be identified"))
+			} else {
+				errs = append(errs, validateOvirtVM(&source.SourceVM.Ovirt.VM, sourceVMPath.Child("ovirt", "vm"))...)
+			}
+			if source.SourceVM.Ovirt.Mappings != nil {
+				errs = append(errs, validateOvirtMappings(source.SourceVM.Ovirt.Mappings, sourceVMPath.Child("ovirt", "mappings"))...)
+			}
+		case source.SourceVM.Vmware != nil:
+			errs = append(errs, validateVmwareVM(&source.SourceVM.Vmware.VM, sourceVMPath.Child("vmware", "vm"))...)
+			if source.SourceVM.Vmware.Mappings != nil {
+				errs = append(errs, validateVmwareMappings(source.SourceVM.Vmware.Mappings, sourceVMPath.Child("vmware", "mappings"))...)
+			}
+		}
+	}
+	return errs
+}
+
+// validateOvirtVM checks that the oVirt VM is identified, either by its ID or by its name and cluster since VM names
+// are only unique within a cluster
+func validateOvirtVM(vm *v2vv1.VirtualMachineImportOvirtSourceVMSpec, path *field.Path) field.ErrorList {
+	if vm.ID != nil {
+		return nil
+	}
+	if vm.Name == nil {
+		return field.ErrorList{field.Required(path, "either id or name and cluster must be set")}
+	}
+	if vm.Cluster == nil || (vm.Cluster.ID == nil && vm.Cluster.Name == nil) {
+		return field.ErrorList{field.Required(path.Child("cluster"), "the cluster must be set when the VM is identified by its name")}
+	}
+	return nil
+}
+
+// validateVmwareVM checks that the VMware VM is identified by its UUID, its managed object reference or its name
+func validateVmwareVM(vm *v2vv1.VirtualMachineImportVmwareSourceVMSpec, path *field.Path) field.ErrorList {
+	if vm.ID == nil && vm.MoRef == nil && vm.Name == nil {
+		return field.ErrorList{field.Required(path, "one of id, moRef and name must be set")}
+	}
+	return nil
+}
+
+// sourceCount counts the sources set in the source spec
+func sourceCount(source v2vv1.VirtualMachineImportSourceSpec) int {
+	return len(sourceNames(source))
+}
+
+// sourceNames returns the names of the sources set in the source spec
+func sourceNames(source v2vv1.VirtualMachineImportSourceSpec) []string {
+	var names []string
+	if source.Ovirt != nil {
+		names = append(names, "ovirt")
+	}
+	if source.Vmware != nil {
+		names = append(names, "vmware")
+	}
+	if source.PVC != nil {
+		names = append(names, "pvc")
+	}
+	return names
+}
+
+// validateVirtualMachineImportUpdate forbids changing what is imported once the import has started, since the
+// disks and the target VM already created from them wouldn't match anymore
+func validateVirtualMachineImportUpdate(old *v2vv1.VirtualMachineImport, instance *v2vv1.VirtualMachineImport) field.ErrorList {
+	if conditions.FindConditionOfType(old.Status.Conditions, v2vv1.Processing) == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	path := field.NewPath("spec")
+	if !reflect.DeepEqual(old.Spec.Source, instance.Spec.Source) {
+		errs = append(errs, field.Forbidden(path.Child("source"), immutableMessage(old)))
+	}
+	if !reflect.DeepEqual(old.Spec.ResourceMapping, instance.Spec.ResourceMapping) {
+		errs = append(errs, field.Forbidden(path.Child("resourceMapping"), immutableMessage(old)))
+	}
+	if !reflect.DeepEqual(old.Spec.Disks, instance.Spec.Disks) {
+		errs = append(errs, field.Forbidden(path.Child("disks"), immutableMessage(old)))
+	}
+	return errs
+}
+
+func immutableMessage(instance *v2vv1.VirtualMachineImport) string {
+	return fmt.Sprintf("can't be changed once the import of %s/%s has started", instance.Namespace, instance.Name)
+}
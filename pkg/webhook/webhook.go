@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+const (
+	// Port is the port the webhook server of the controller listens on
+	Port = 9443
+	// CertDir is the directory the serving certificate of the webhook server is mounted at
+	CertDir = "/tmp/k8s-webhook-server/serving-certs"
+	// CertificateSecretName is the name of the secret the operator keeps the serving certificate of the webhook server
+	// in, so that it doesn't change when the controller restarts
+	CertificateSecretName = "vm-import-webhook-cert"
+	// ServiceName is the name of the service exposing the webhook server of the controller
+	ServiceName = "vm-import-webhook"
+	// ValidatingWebhookConfigurationName is the name of the configuration registering the validating webhooks
+	ValidatingWebhookConfigurationName = "vm-import-validator"
+
+	// VirtualMachineImportPath is the path VirtualMachineImport resources are validated at
+	VirtualMachineImportPath = "/validate-v2v-kubevirt-io-v1beta1-virtualmachineimport"
+	// ResourceMappingPath is the path ResourceMapping resources are validated at
+	ResourceMappingPath = "/validate-v2v-kubevirt-io-v1beta1-resourcemapping"
+	// ConversionPath is the path VirtualMachineImport and ResourceMapping resources are converted between v1alpha1
+	// and v1beta1 at
+	ConversionPath = "/convert"
+)
+
+var log = logf.Log.WithName("webhook")
+
+// ConvertedCRDs are the custom resource definitions whose versions are converted by the webhook server
+var ConvertedCRDs = []string{
+	"virtualmachineimports.v2v.kubevirt.io",
+	"resourcemappings.v2v.kubevirt.io",
+}
+
+// AddToManager registers the validating and conversion webhooks with the webhook server of the Manager and migrates
+// the stored resources to the storage version. The serving certificate and the CA bundles of the webhooks are
+// provisioned by the operator. The scheme of the Manager must hold both v1alpha1 and v1beta1.
+func AddToManager(mgr manager.Manager) error {
+	server := mgr.GetWebhookServer()
+	server.Register(VirtualMachineImportPath, &webhook.Admission{Handler: &VirtualMachineImportValidator{}})
+	server.Register(ResourceMappingPath, &webhook.Admission{Handler: &ResourceMappingValidator{}})
+	server.Register(ConversionPath, &conversion.Webhook{})
+
+	return mgr.Add(&storageVersionMigrator{client: mgr.GetClient(), reader: mgr.GetAPIReader(), interval: MigrationRetryInterval})
+}
@@ -0,0 +1,506 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	v2vv1 "github.com/kubevirt/vm-import-operator/pkg/apis/v2v/v1beta1"
+	"github.com/kubevirt/vm-import-operator/pkg/conditions"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/cert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("Validating VirtualMachineImport", func() {
+	var (
+		validator *VirtualMachineImportValidator
+		instance  *v2vv1.VirtualMachineImport
+	)
+
+	BeforeEach(func() {
+		validator = &VirtualMachineImportValidator{}
+		Expect(validator.InjectDecoder(newDecoder())).To(Succeed())
+		vmName := "myvm"
+		clusterName := "mycluster"
+		instance = &v2vv1.VirtualMachineImport{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v2vv1.SchemeGroupVersion.String(), Kind: "VirtualMachineImport"},
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+			Spec: v2vv1.VirtualMachineImportSpec{
+				ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{Name: "test"},
+				ResourceMapping:           &v2vv1.ObjectIdentifier{Name: "mapping"},
+				Source: v2vv1.VirtualMachineImportSourceSpec{
+					Ovirt: &v2vv1.VirtualMachineImportOvirtSourceSpec{
+						VM: v2vv1.VirtualMachineImportOvirtSourceVMSpec{
+							Name:    &vmName,
+							Cluster: &v2vv1.VirtualMachineImportOvirtSourceVMClusterSpec{Name: &clusterName},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("should allow a valid import: ", func() {
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeTrue())
+	})
+
+	It("should reject an import from both providers: ", func() {
+		instance.Spec.Source.Vmware = &v2vv1.VirtualMachineImportVmwareSourceSpec{}
+
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("spec.source"))
+	})
+
+	It("should reject an import from neither provider: ", func() {
+		instance.Spec.Source.Ovirt = nil
+
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("one of ovirt, vmware and pvc must be set"))
+	})
+
+	It("should reject an invalid target VM name: ", func() {
+		name := "My_VM"
+		instance.Spec.TargetVMName = &name
+
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("spec.targetVmName"))
+	})
+
+	It("should reject a warm import from oVirt: ", func() {
+		instance.Spec.Warm = true
+
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("spec.warm"))
+	})
+
+	It("should reject a guest customization of an oVirt VM that is never converted: ", func() {
+		never := v2vv1.GuestConversionNever
+		instance.Spec.Source.Ovirt.GuestConversion = &never
+		instance.Spec.GuestCustomization = &v2vv1.GuestCustomizationSpec{ConfigMap: corev1.LocalObjectReference{Name: "customization"}}
+
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("spec.guestCustomization"))
+	})
+
+	It("should allow a guest customization of an oVirt VM converted automatically: ", func() {
+		instance.Spec.GuestCustomization = &v2vv1.GuestCustomizationSpec{ConfigMap: corev1.LocalObjectReference{Name: "customization"}}
+
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeTrue())
+	})
+
+	It("should allow a warm import from VMware: ", func() {
+		instance.Spec.Warm = true
+		instance.Spec.Source.Vmware = &v2vv1.VirtualMachineImportVmwareSourceSpec{
+			VM: v2vv1.VirtualMachineImportVmwareSourceVMSpec{Name: instance.Spec.Source.Ovirt.VM.Name},
+		}
+		instance.Spec.Source.Ovirt = nil
+
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeTrue())
+	})
+
+	It("should reject an import of an oVirt VM identified by its name only: ", func() {
+		instance.Spec.Source.Ovirt.VM.Cluster = nil
+
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("spec.source.ovirt.vm.cluster"))
+	})
+
+	It("should allow an import of an oVirt VM identified by its ID: ", func() {
+		vmID := "123"
+		instance.Spec.Source.Ovirt.VM = v2vv1.VirtualMachineImportOvirtSourceVMSpec{ID: &vmID}
+
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeTrue())
+	})
+
+	It("should reject an import of an unidentified VMware VM: ", func() {
+		instance.Spec.Source.Ovirt = nil
+		instance.Spec.Source.Vmware = &v2vv1.VirtualMachineImportVmwareSourceSpec{}
+
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("spec.source.vmware.vm"))
+	})
+
+	It("should allow an import of disks already in persistent volume claims: ", func() {
+		instance.Spec.Source.Ovirt = nil
+		instance.Spec.Source.PVC = &v2vv1.VirtualMachineImportPVCSourceSpec{
+			Disks:    []v2vv1.PVCDisk{{ClaimName: "disk-1"}},
+			Hardware: &v2vv1.VirtualMachineHardwareSpec{Memory: resource.MustParse("1Gi")},
+		}
+
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeTrue())
+	})
+
+	It("should reject an import of persistent volume claims without disks: ", func() {
+		instance.Spec.Source.Ovirt = nil
+		instance.Spec.Source.PVC = &v2vv1.VirtualMachineImportPVCSourceSpec{
+			Hardware: &v2vv1.VirtualMachineHardwareSpec{Memory: resource.MustParse("1Gi")},
+		}
+
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("spec.source.pvc.disks"))
+	})
+
+	It("should reject an import of persistent volume claims with both the hardware and the source VM: ", func() {
+		instance.Spec.Source.PVC = &v2vv1.VirtualMachineImportPVCSourceSpec{
+			Disks:    []v2vv1.PVCDisk{{ClaimName: "disk-1"}},
+			Hardware: &v2vv1.VirtualMachineHardwareSpec{Memory: resource.MustParse("1Gi")},
+			SourceVM: &v2vv1.PVCSourceVMSpec{Ovirt: instance.Spec.Source.Ovirt},
+		}
+		instance.Spec.Source.Ovirt = nil
+
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("only one of hardware and sourceVM may be set"))
+	})
+
+	It("should reject a warm import of persistent volume claims: ", func() {
+		instance.Spec.Warm = true
+		instance.Spec.Source.PVC = &v2vv1.VirtualMachineImportPVCSourceSpec{
+			Disks:    []v2vv1.PVCDisk{{ClaimName: "disk-1"}},
+			SourceVM: &v2vv1.PVCSourceVMSpec{Ovirt: instance.Spec.Source.Ovirt},
+		}
+		instance.Spec.Source.Ovirt = nil
+
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("spec.warm"))
+	})
+
+	It("should allow a selection of the disks: ", func() {
+		swap := "swap"
+		instance.Spec.Disks = &v2vv1.DiskSelectionSpec{
+			Exclude: []v2vv1.DiskSelectorRule{{Name: &swap}},
+		}
+
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeTrue())
+	})
+
+	It("should reject an empty disk selector rule: ", func() {
+		instance.Spec.Disks = &v2vv1.DiskSelectionSpec{
+			Exclude: []v2vv1.DiskSelectorRule{{}},
+		}
+
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("spec.disks.exclude[0]"))
+	})
+
+	It("should reject a disk selector rule with a min size greater than its max size: ", func() {
+		minSize, maxSize := resource.MustParse("100Gi"), resource.MustParse("10Gi")
+		instance.Spec.Disks = &v2vv1.DiskSelectionSpec{
+			Include: []v2vv1.DiskSelectorRule{{MinSize: &minSize, MaxSize: &maxSize}},
+		}
+
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("spec.disks.include[0].minSize"))
+	})
+
+	It("should reject a selection of the disks already in persistent volume claims: ", func() {
+		bootable := true
+		instance.Spec.Source.Ovirt = nil
+		instance.Spec.Source.PVC = &v2vv1.VirtualMachineImportPVCSourceSpec{
+			Disks:    []v2vv1.PVCDisk{{ClaimName: "disk-1"}},
+			Hardware: &v2vv1.VirtualMachineHardwareSpec{Memory: resource.MustParse("1Gi")},
+		}
+		instance.Spec.Disks = &v2vv1.DiskSelectionSpec{
+			Include: []v2vv1.DiskSelectorRule{{Bootable: &bootable}},
+		}
+
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("spec.disks"))
+	})
+
+	It("should reject duplicate sources in the inline mappings: ", func() {
+		id := "123"
+		instance.Spec.Source.Ovirt.Mappings = &v2vv1.OvirtMappings{
+			StorageMappings: &[]v2vv1.StorageResourceMappingItem{
+				{Source: v2vv1.Source{ID: &id}, Target: v2vv1.ObjectIdentifier{Name: "fast"}},
+				{Source: v2vv1.Source{ID: &id}, Target: v2vv1.ObjectIdentifier{Name: "slow"}},
+			},
+		}
+
+		response := validator.Handle(context.TODO(), createRequest(instance))
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("spec.source.ovirt.mappings.storageMappings[1].source.id"))
+	})
+
+	It("should allow changing the source before the import has started: ", func() {
+		updated := instance.DeepCopy()
+		vmID := "123"
+		updated.Spec.Source.Ovirt.VM = v2vv1.VirtualMachineImportOvirtSourceVMSpec{ID: &vmID}
+
+		response := validator.Handle(context.TODO(), updateRequest(instance, updated))
+
+		Expect(response.Allowed).To(BeTrue())
+	})
+
+	It("should reject changing the source once the import has started: ", func() {
+		conditions.UpsertCondition(instance, conditions.NewProcessingCondition(string(v2vv1.CopyingDisks), "Copying", corev1.ConditionTrue))
+		updated := instance.DeepCopy()
+		vmID := "123"
+		updated.Spec.Source.Ovirt.VM = v2vv1.VirtualMachineImportOvirtSourceVMSpec{ID: &vmID}
+
+		response := validator.Handle(context.TODO(), updateRequest(instance, updated))
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("spec.source"))
+	})
+
+	It("should reject changing the resource mapping once the import has started: ", func() {
+		conditions.UpsertCondition(instance, conditions.NewProcessingCondition(string(v2vv1.CopyingDisks), "Copying", corev1.ConditionTrue))
+		updated := instance.DeepCopy()
+		updated.Spec.ResourceMapping = &v2vv1.ObjectIdentifier{Name: "other"}
+
+		response := validator.Handle(context.TODO(), updateRequest(instance, updated))
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("spec.resourceMapping"))
+	})
+
+	It("should reject changing the disk selection once the import has started: ", func() {
+		conditions.UpsertCondition(instance, conditions.NewProcessingCondition(string(v2vv1.CopyingDisks), "Copying", corev1.ConditionTrue))
+		updated := instance.DeepCopy()
+		swap := "swap"
+		updated.Spec.Disks = &v2vv1.DiskSelectionSpec{
+			Exclude: []v2vv1.DiskSelectorRule{{Name: &swap}},
+		}
+
+		response := validator.Handle(context.TODO(), updateRequest(instance, updated))
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("spec.disks"))
+	})
+
+	It("should allow updating the metadata of an invalid import: ", func() {
+		instance.Spec.Source.Vmware = &v2vv1.VirtualMachineImportVmwareSourceSpec{}
+		updated := instance.DeepCopy()
+		updated.Finalizers = []string{"vmimport.v2v.kubevirt.io/cleanup"}
+
+		response := validator.Handle(context.TODO(), updateRequest(instance, updated))
+
+		Expect(response.Allowed).To(BeTrue())
+	})
+})
+
+var _ = Describe("Validating ResourceMapping", func() {
+	var validator *ResourceMappingValidator
+
+	BeforeEach(func() {
+		validator = &ResourceMappingValidator{}
+		Expect(validator.InjectDecoder(newDecoder())).To(Succeed())
+	})
+
+	newMapping := func(spec v2vv1.ResourceMappingSpec) *v2vv1.ResourceMapping {
+		return &v2vv1.ResourceMapping{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v2vv1.SchemeGroupVersion.String(), Kind: "ResourceMapping"},
+			ObjectMeta: metav1.ObjectMeta{Name: "mapping", Namespace: "test"},
+			Spec:       spec,
+		}
+	}
+
+	It("should allow distinct sources: ", func() {
+		red, blue := "red", "blue"
+		mapping := newMapping(v2vv1.ResourceMappingSpec{
+			VmwareMappings: &v2vv1.VmwareMappings{
+				NetworkMappings: &[]v2vv1.NetworkResourceMappingItem{
+					{Source: v2vv1.Source{Name: &red}, Target: v2vv1.ObjectIdentifier{Name: "pod"}},
+					{Source: v2vv1.Source{Name: &blue}, Target: v2vv1.ObjectIdentifier{Name: "pod"}},
+				},
+			},
+		})
+
+		response := validator.Handle(context.TODO(), createRequest(mapping))
+
+		Expect(response.Allowed).To(BeTrue())
+	})
+
+	It("should reject a network mapped twice by name: ", func() {
+		red := "red"
+		mapping := newMapping(v2vv1.ResourceMappingSpec{
+			VmwareMappings: &v2vv1.VmwareMappings{
+				NetworkMappings: &[]v2vv1.NetworkResourceMappingItem{
+					{Source: v2vv1.Source{Name: &red}, Target: v2vv1.ObjectIdentifier{Name: "pod"}},
+					{Source: v2vv1.Source{Name: &red}, Target: v2vv1.ObjectIdentifier{Name: "multus"}},
+				},
+			},
+		})
+
+		response := validator.Handle(context.TODO(), createRequest(mapping))
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("spec.vmware.networkMappings[1].source.name: Duplicate value: \"red\""))
+	})
+
+	It("should reject a disk mapped twice by ID: ", func() {
+		id := "123"
+		mapping := newMapping(v2vv1.ResourceMappingSpec{
+			OvirtMappings: &v2vv1.OvirtMappings{
+				DiskMappings: &[]v2vv1.StorageResourceMappingItem{
+					{Source: v2vv1.Source{ID: &id}, Target: v2vv1.ObjectIdentifier{Name: "fast"}},
+					{Source: v2vv1.Source{ID: &id}, Target: v2vv1.ObjectIdentifier{Name: "slow"}},
+				},
+			},
+		})
+
+		response := validator.Handle(context.TODO(), createRequest(mapping))
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("spec.ovirt.diskMappings[1].source.id"))
+	})
+})
+
+var _ = Describe("Serving certificate", func() {
+	newSecret := func(certPEM, keyPEM []byte) *corev1.Secret {
+		return &corev1.Secret{Data: map[string][]byte{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: keyPEM}}
+	}
+
+	It("should be valid for the webhook service and verifiable with the CA bundle: ", func() {
+		certPEM, keyPEM, err := GenerateCertificate("test")
+
+		Expect(err).To(BeNil())
+		secret := newSecret(certPEM, keyPEM)
+		certificates, err := cert.ParseCertsPEM(CABundleOf(secret))
+		Expect(err).To(BeNil())
+		Expect(certificates[0].DNSNames).To(ContainElement("vm-import-webhook.test.svc"))
+		Expect(NeedsNewCertificate(secret, time.Now())).To(BeFalse())
+	})
+
+	It("should be renewed when it expires soon: ", func() {
+		certPEM, keyPEM, err := GenerateCertificate("test")
+		Expect(err).To(BeNil())
+		certificates, err := cert.ParseCertsPEM(certPEM)
+		Expect(err).To(BeNil())
+
+		Expect(NeedsNewCertificate(newSecret(certPEM, keyPEM), certificates[0].NotAfter.Add(-CertificateRenewalPeriod/2))).To(BeTrue())
+	})
+
+	It("should be generated when the secret holds none: ", func() {
+		Expect(NeedsNewCertificate(&corev1.Secret{}, time.Now())).To(BeTrue())
+	})
+})
+
+var _ = Describe("Migrating the storage version", func() {
+	newCRD := func(name string, storedVersions ...string) *extv1.CustomResourceDefinition {
+		return &extv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: extv1.CustomResourceDefinitionSpec{
+				Versions: []extv1.CustomResourceDefinitionVersion{
+					{Name: "v1alpha1", Served: true},
+					{Name: "v1beta1", Served: true, Storage: true},
+				},
+			},
+			Status: extv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+		}
+	}
+
+	It("should rewrite the resources and drop the former stored versions: ", func() {
+		instance := &v2vv1.VirtualMachineImport{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"}}
+		client := fake.NewFakeClientWithScheme(newCRDScheme(),
+			newCRD("virtualmachineimports.v2v.kubevirt.io", "v1alpha1", "v1beta1"),
+			newCRD("resourcemappings.v2v.kubevirt.io", "v1beta1"),
+			instance,
+		)
+		migrator := &storageVersionMigrator{client: client, reader: client}
+
+		Expect(migrator.migrate()).To(Succeed())
+
+		crd := &extv1.CustomResourceDefinition{}
+		Expect(client.Get(context.TODO(), types.NamespacedName{Name: "virtualmachineimports.v2v.kubevirt.io"}, crd)).To(Succeed())
+		Expect(crd.Status.StoredVersions).To(Equal([]string{"v1beta1"}))
+		Expect(client.Get(context.TODO(), types.NamespacedName{Name: "test", Namespace: "test"}, instance)).To(Succeed())
+		Expect(instance.ResourceVersion).ToNot(BeEmpty())
+	})
+
+	It("should fail until the custom resource definitions exist", func() {
+		client := fake.NewFakeClientWithScheme(newCRDScheme())
+		migrator := &storageVersionMigrator{client: client, reader: client}
+
+		Expect(migrator.migrate()).ToNot(Succeed())
+	})
+})
+
+func newCRDScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	Expect(extv1.AddToScheme(scheme)).To(Succeed())
+	Expect(v2vv1.AddToScheme(scheme)).To(Succeed())
+	return scheme
+}
+
+func newDecoder() *admission.Decoder {
+	scheme := runtime.NewScheme()
+	Expect(v2vv1.AddToScheme(scheme)).To(Succeed())
+	decoder, err := admission.NewDecoder(scheme)
+	Expect(err).To(BeNil())
+	return decoder
+}
+
+func createRequest(obj runtime.Object) admission.Request {
+	return admission.Request{
+		AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: admissionv1beta1.Create,
+			Object:    runtime.RawExtension{Raw: marshal(obj)},
+		},
+	}
+}
+
+func updateRequest(old runtime.Object, obj runtime.Object) admission.Request {
+	return admission.Request{
+		AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: admissionv1beta1.Update,
+			Object:    runtime.RawExtension{Raw: marshal(obj)},
+			OldObject: runtime.RawExtension{Raw: marshal(old)},
+		},
+	}
+}
+
+func marshal(obj runtime.Object) []byte {
+	data, err := json.Marshal(obj)
+	Expect(err).To(BeNil())
+	return data
+}
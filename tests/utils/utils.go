@@ -21,7 +21,7 @@ func VirtualMachineImportCrWithName(provider string, vmID string, namespace stri
 		},
 		Spec: v2vv1.VirtualMachineImportSpec{
 			StartVM: &startVM,
-			ProviderCredentialsSecret: v2vv1.ObjectIdentifier{
+			ProviderCredentialsSecret: &v2vv1.ObjectIdentifier{
 				Name:      secretName,
 				Namespace: &namespace,
 			},
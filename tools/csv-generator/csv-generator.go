@@ -48,6 +48,10 @@ func main() {
 		if err != nil {
 			panic(err)
 		}
+		err = util.MarshallObject(vmioperator.CreateProvider(), os.Stdout)
+		if err != nil {
+			panic(err)
+		}
 		err = util.MarshallObject(vmioperator.CreateServiceAccount(*namespace), os.Stdout)
 		if err != nil {
 			panic(err)